@@ -0,0 +1,224 @@
+package blockfile
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stringintech/go-bitcoinkernel/internal/testutil"
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+)
+
+func TestReaderWriterRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		xorKey []byte
+	}{
+		{name: "plain", xorKey: nil},
+		{name: "obfuscated", xorKey: []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}},
+	}
+
+	records := [][]byte{{0xde, 0xad, 0xbe, 0xef}, bytes.Repeat([]byte{0x42}, 1000), {}}
+	prevHash := [32]byte{1, 2, 3}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var blockFile, undoFile bytes.Buffer
+			blockWriter, err := NewWriter(&blockFile, MagicRegtest, tt.xorKey)
+			if err != nil {
+				t.Fatalf("NewWriter() error = %v", err)
+			}
+			undoWriter, err := NewWriter(&undoFile, MagicRegtest, tt.xorKey)
+			if err != nil {
+				t.Fatalf("NewWriter() error = %v", err)
+			}
+			for _, record := range records {
+				if err := blockWriter.WriteBlock(record); err != nil {
+					t.Fatalf("WriteBlock() error = %v", err)
+				}
+				if err := undoWriter.WriteUndo(record, prevHash); err != nil {
+					t.Fatalf("WriteUndo() error = %v", err)
+				}
+			}
+
+			if tt.xorKey != nil && bytes.Contains(blockFile.Bytes(), MagicRegtest[:]) {
+				t.Error("Expected magic to be obfuscated")
+			}
+
+			// Core pre-allocates block and undo files with zeros, which are not
+			// obfuscated and must not be read as records
+			blockFile.Write(make([]byte, 64))
+			undoFile.Write(make([]byte, 64))
+
+			blockReader, err := NewReader(&blockFile, MagicRegtest, tt.xorKey)
+			if err != nil {
+				t.Fatalf("NewReader() error = %v", err)
+			}
+			undoReader, err := NewReader(&undoFile, MagicRegtest, tt.xorKey)
+			if err != nil {
+				t.Fatalf("NewReader() error = %v", err)
+			}
+			for i, record := range records {
+				block, err := blockReader.ReadBlock()
+				if err != nil {
+					t.Fatalf("ReadBlock() %d error = %v", i, err)
+				}
+				if !bytes.Equal(block, record) {
+					t.Errorf("ReadBlock() %d = %x, expected %x", i, block, record)
+				}
+
+				undo, checksum, err := undoReader.ReadUndo()
+				if err != nil {
+					t.Fatalf("ReadUndo() %d error = %v", i, err)
+				}
+				if !bytes.Equal(undo, record) {
+					t.Errorf("ReadUndo() %d = %x, expected %x", i, undo, record)
+				}
+				if err := VerifyUndoChecksum(prevHash, undo, checksum); err != nil {
+					t.Errorf("VerifyUndoChecksum() %d error = %v", i, err)
+				}
+				if err := VerifyUndoChecksum([32]byte{}, undo, checksum); !errors.Is(err, ErrChecksumMismatch) {
+					t.Errorf("Expected ErrChecksumMismatch for wrong previous block hash, got %v", err)
+				}
+			}
+
+			if _, err := blockReader.ReadBlock(); !errors.Is(err, io.EOF) {
+				t.Errorf("Expected io.EOF after last block, got %v", err)
+			}
+			if _, _, err := undoReader.ReadUndo(); !errors.Is(err, io.EOF) {
+				t.Errorf("Expected io.EOF after last undo record, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, MagicMainnet, nil)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	if err := writer.WriteBlock([]byte{1, 2, 3, 4}); err != nil {
+		t.Fatalf("WriteBlock() error = %v", err)
+	}
+	data := buf.Bytes()
+
+	t.Run("wrong magic", func(t *testing.T) {
+		r, _ := NewReader(bytes.NewReader(data), MagicRegtest, nil)
+		if _, err := r.ReadBlock(); !errors.Is(err, ErrBadMagic) {
+			t.Errorf("Expected ErrBadMagic, got %v", err)
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		r, _ := NewReader(bytes.NewReader(data[:len(data)-1]), MagicMainnet, nil)
+		if _, err := r.ReadBlock(); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+		}
+	})
+
+	t.Run("invalid xor key", func(t *testing.T) {
+		if _, err := NewReader(bytes.NewReader(data), MagicMainnet, []byte{1}); !errors.Is(err, ErrInvalidXorKeySize) {
+			t.Errorf("Expected ErrInvalidXorKeySize, got %v", err)
+		}
+	})
+}
+
+func TestDir(t *testing.T) {
+	blocks := testutil.RegtestBlocks(t, 10)
+	blocksDir := processBlocks(t, blocks)
+
+	dir, err := OpenDir(blocksDir, kernel.ChainTypeRegtest)
+	if err != nil {
+		t.Fatalf("OpenDir() error = %v", err)
+	}
+
+	t.Run("Blocks", func(t *testing.T) {
+		var hashes [][32]byte
+		for block, err := range dir.Blocks() {
+			if err != nil {
+				t.Fatalf("Blocks() error = %v", err)
+			}
+			hashes = append(hashes, block.Hash().Bytes())
+		}
+		// Genesis block followed by the processed blocks
+		if len(hashes) != len(blocks)+1 {
+			t.Fatalf("Expected %d blocks, got %d", len(blocks)+1, len(hashes))
+		}
+		for i, block := range blocks {
+			if hashes[i+1] != block.Hash().Bytes() {
+				t.Errorf("Block %d hash mismatch", i+1)
+			}
+		}
+	})
+
+	t.Run("SpentOutputs", func(t *testing.T) {
+		var records []*UndoRecord
+		for record, err := range dir.SpentOutputs() {
+			if err != nil {
+				t.Fatalf("SpentOutputs() error = %v", err)
+			}
+			records = append(records, record)
+		}
+		if len(records) != len(blocks) {
+			t.Fatalf("Expected %d undo records, got %d", len(blocks), len(records))
+		}
+		for i, record := range records {
+			prevHash := blocks[i].Header().PrevHash().Bytes()
+			if err := record.Verify(prevHash); err != nil {
+				t.Errorf("Verify() undo record %d error = %v", i, err)
+			}
+			if record.SpentOutputs.Count() != blocks[i].CountTransactions()-1 {
+				t.Errorf("Expected %d transaction spent outputs, got %d", blocks[i].CountTransactions()-1, record.SpentOutputs.Count())
+			}
+		}
+	})
+}
+
+func TestBootstrapFileImport(t *testing.T) {
+	blocks := testutil.RegtestBlocks(t, 10)
+
+	bootstrapPath := filepath.Join(t.TempDir(), "bootstrap.dat")
+	f, err := os.Create(bootstrapPath)
+	if err != nil {
+		t.Fatalf("Failed to create bootstrap file: %v", err)
+	}
+	writer, err := NewWriter(f, MagicRegtest, nil)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	for _, block := range blocks {
+		if err := writer.WriteKernelBlock(block); err != nil {
+			t.Fatalf("WriteKernelBlock() error = %v", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Failed to close bootstrap file: %v", err)
+	}
+
+	manager := testutil.NewChainstateManager(t, testutil.NewContext(t), t.TempDir())
+	if err := manager.ImportBlocks([]string{bootstrapPath}); err != nil {
+		t.Fatalf("ImportBlocks() error = %v", err)
+	}
+	if height := manager.GetActiveChain().GetHeight(); height != int32(len(blocks)) {
+		t.Errorf("Expected chain height %d after import, got %d", len(blocks), height)
+	}
+}
+
+// processBlocks processes blocks with a fresh chainstate manager and returns its
+// blocks directory once the manager has been destroyed.
+func processBlocks(t *testing.T, blocks []*kernel.Block) string {
+	t.Helper()
+	dir := t.TempDir()
+	manager := testutil.NewChainstateManager(t, testutil.NewContext(t), dir)
+	for i, block := range blocks {
+		if _, err := manager.ProcessBlock(block); err != nil {
+			t.Fatalf("ProcessBlock() failed for block %d: %v", i+1, err)
+		}
+	}
+	manager.Destroy()
+	return filepath.Join(dir, "blocks")
+}
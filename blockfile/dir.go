@@ -0,0 +1,187 @@
+package blockfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+)
+
+// Dir provides access to the block and undo files of a blocks directory without an
+// active chainstate.
+type Dir struct {
+	path   string
+	magic  Magic
+	xorKey []byte
+}
+
+// OpenDir opens the blocks directory at path, reading its XOR obfuscation key if present.
+//
+// Parameters:
+//   - path: Blocks directory, as passed to kernel.NewChainstateManager
+//   - chainType: Chain the blocks directory belongs to
+func OpenDir(path string, chainType kernel.ChainType) (*Dir, error) {
+	magic, err := MagicForChain(chainType)
+	if err != nil {
+		return nil, err
+	}
+	xorKey, err := ReadXorKey(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Dir{path: path, magic: magic, xorKey: xorKey}, nil
+}
+
+// XorKey returns the obfuscation key of the directory, or nil if files are not obfuscated.
+func (d *Dir) XorKey() []byte {
+	return d.xorKey
+}
+
+// BlockFilePath returns the path of the block file with the given number.
+func (d *Dir) BlockFilePath(n int) string {
+	return filepath.Join(d.path, fmt.Sprintf("blk%05d.dat", n))
+}
+
+// UndoFilePath returns the path of the undo file with the given number.
+func (d *Dir) UndoFilePath(n int) string {
+	return filepath.Join(d.path, fmt.Sprintf("rev%05d.dat", n))
+}
+
+// BlockFiles returns the paths of all block files in the directory in file number order.
+func (d *Dir) BlockFiles() ([]string, error) {
+	return d.files("blk")
+}
+
+// UndoFiles returns the paths of all undo files in the directory in file number order.
+func (d *Dir) UndoFiles() ([]string, error) {
+	return d.files("rev")
+}
+
+func (d *Dir) files(prefix string) ([]string, error) {
+	var paths []string
+	for n := 0; ; n++ {
+		path := filepath.Join(d.path, fmt.Sprintf("%s%05d.dat", prefix, n))
+		if _, err := os.Stat(path); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				break
+			}
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// Blocks returns an iterator over all blocks in the block files of the directory, in
+// the order they are stored on disk. This is not necessarily chain order.
+//
+// Iteration stops after the first error, which is yielded with a nil block.
+//
+// Example usage:
+//
+//	for block, err := range dir.Blocks() {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    // Process block
+//	}
+func (d *Dir) Blocks() iter.Seq2[*kernel.Block, error] {
+	return func(yield func(*kernel.Block, error) bool) {
+		paths, err := d.BlockFiles()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for _, path := range paths {
+			if !d.iterFile(path, func(r *Reader) (bool, error) {
+				raw, err := r.ReadBlock()
+				if err != nil {
+					return false, err
+				}
+				block, err := kernel.NewBlock(raw)
+				if err != nil {
+					return false, err
+				}
+				return yield(block, nil), nil
+			}, func(err error) { yield(nil, err) }) {
+				return
+			}
+		}
+	}
+}
+
+// UndoRecord is a block undo record read from an undo file.
+type UndoRecord struct {
+	SpentOutputs *kernel.BlockSpentOutputs
+	Checksum     [ChecksumSize]byte
+	raw          []byte
+}
+
+// Verify checks that the record's checksum commits to its undo data and the hash of
+// the previous block, given in internal byte order.
+func (u *UndoRecord) Verify(prevBlockHash [32]byte) error {
+	return VerifyUndoChecksum(prevBlockHash, u.raw, u.Checksum)
+}
+
+// SpentOutputs returns an iterator over all undo records in the undo files of the
+// directory, in the order they are stored on disk.
+//
+// Iteration stops after the first error, which is yielded with a nil record.
+func (d *Dir) SpentOutputs() iter.Seq2[*UndoRecord, error] {
+	return func(yield func(*UndoRecord, error) bool) {
+		paths, err := d.UndoFiles()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for _, path := range paths {
+			if !d.iterFile(path, func(r *Reader) (bool, error) {
+				raw, checksum, err := r.ReadUndo()
+				if err != nil {
+					return false, err
+				}
+				spentOutputs, err := kernel.NewBlockSpentOutputs(raw)
+				if err != nil {
+					return false, err
+				}
+				return yield(&UndoRecord{SpentOutputs: spentOutputs, Checksum: checksum, raw: raw}, nil), nil
+			}, func(err error) { yield(nil, err) }) {
+				return
+			}
+		}
+	}
+}
+
+// iterFile opens path and calls next until it returns false or an error. It returns
+// false if iteration should stop, reporting any error other than io.EOF to onError.
+func (d *Dir) iterFile(path string, next func(*Reader) (bool, error), onError func(error)) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		onError(err)
+		return false
+	}
+	defer f.Close()
+
+	r, err := NewReader(f, d.magic, d.xorKey)
+	if err != nil {
+		onError(err)
+		return false
+	}
+	for {
+		cont, err := next(r)
+		if errors.Is(err, io.EOF) {
+			return true
+		}
+		if err != nil {
+			onError(fmt.Errorf("%s at offset %d: %w", filepath.Base(path), r.Offset(), err))
+			return false
+		}
+		if !cont {
+			return false
+		}
+	}
+}
@@ -0,0 +1,244 @@
+// Package blockfile reads and writes the raw blk?????.dat and rev?????.dat files
+// that Bitcoin Core keeps in its blocks directory.
+//
+// Every record in these files is framed by the 4-byte network magic and a 4-byte
+// little-endian payload size. Block files contain consensus serialized blocks, undo
+// files contain serialized block undo data followed by a 32-byte checksum. Both may be
+// obfuscated with the 8-byte XOR key stored in the xor.dat file of the blocks directory.
+package blockfile
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+)
+
+const (
+	// XorKeySize is the size of the obfuscation key stored in xor.dat.
+	XorKeySize = 8
+
+	// ChecksumSize is the size of the checksum following each undo record.
+	ChecksumSize = 32
+
+	// maxRecordSize bounds the payload size of a single record, matching the
+	// maximum size of a block file.
+	maxRecordSize = 0x8000000
+
+	xorKeyFileName = "xor.dat"
+)
+
+var (
+	ErrBadMagic          = errors.New("unexpected network magic")
+	ErrRecordTooLarge    = errors.New("record size exceeds maximum block file size")
+	ErrChecksumMismatch  = errors.New("undo data checksum mismatch")
+	ErrInvalidXorKeySize = fmt.Errorf("xor key must be %d bytes", XorKeySize)
+)
+
+// Magic is the 4-byte network magic prefixing every record.
+type Magic [4]byte
+
+var (
+	MagicMainnet  = Magic{0xf9, 0xbe, 0xb4, 0xd9}
+	MagicTestnet  = Magic{0x0b, 0x11, 0x09, 0x07}
+	MagicTestnet4 = Magic{0x1c, 0x16, 0x3f, 0x28}
+	MagicSignet   = Magic{0x0a, 0x03, 0xcf, 0x40}
+	MagicRegtest  = Magic{0xfa, 0xbf, 0xb5, 0xda}
+)
+
+// MagicForChain returns the network magic of the given chain type.
+//
+// The signet magic is the one of the default signet; custom signets derive their
+// magic from the signet challenge.
+func MagicForChain(chainType kernel.ChainType) (Magic, error) {
+	switch chainType {
+	case kernel.ChainTypeMainnet:
+		return MagicMainnet, nil
+	case kernel.ChainTypeTestnet:
+		return MagicTestnet, nil
+	case kernel.ChainTypeTestnet4:
+		return MagicTestnet4, nil
+	case kernel.ChainTypeSignet:
+		return MagicSignet, nil
+	case kernel.ChainTypeRegtest:
+		return MagicRegtest, nil
+	}
+	return Magic{}, fmt.Errorf("unknown chain type %d", chainType)
+}
+
+// ReadXorKey reads the obfuscation key from the xor.dat file of a blocks directory.
+//
+// Returns a nil key if the file does not exist, in which case the files are not obfuscated.
+func ReadXorKey(blocksDir string) ([]byte, error) {
+	key, err := os.ReadFile(filepath.Join(blocksDir, xorKeyFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != XorKeySize {
+		return nil, ErrInvalidXorKeySize
+	}
+	return key, nil
+}
+
+// WriteXorKey writes the obfuscation key to the xor.dat file of a blocks directory.
+func WriteXorKey(blocksDir string, key []byte) error {
+	if len(key) != XorKeySize {
+		return ErrInvalidXorKeySize
+	}
+	return os.WriteFile(filepath.Join(blocksDir, xorKeyFileName), key, 0o644)
+}
+
+// xorStream applies the XOR obfuscation key to data located at the given file
+// offset. It is a no-op for nil or all-zero keys.
+type xorStream struct {
+	key    []byte
+	offset int64
+}
+
+func (x *xorStream) apply(data []byte) {
+	if len(x.key) != 0 {
+		for i := range data {
+			data[i] ^= x.key[(x.offset+int64(i))%XorKeySize]
+		}
+	}
+	x.offset += int64(len(data))
+}
+
+// Reader reads framed records from a block or undo file.
+type Reader struct {
+	r     io.Reader
+	magic Magic
+	xor   xorStream
+}
+
+// NewReader returns a reader for the records in r.
+//
+// Parameters:
+//   - r: Reader positioned at the start of the file
+//   - magic: Network magic expected in front of every record
+//   - xorKey: Obfuscation key of the blocks directory, or nil if the file is not obfuscated
+func NewReader(r io.Reader, magic Magic, xorKey []byte) (*Reader, error) {
+	if xorKey != nil && len(xorKey) != XorKeySize {
+		return nil, ErrInvalidXorKeySize
+	}
+	return &Reader{r: r, magic: magic, xor: xorStream{key: xorKey}}, nil
+}
+
+// Offset returns the file offset of the next record.
+func (r *Reader) Offset() int64 {
+	return r.xor.offset
+}
+
+func (r *Reader) read(buf []byte) error {
+	if _, err := io.ReadFull(r.r, buf); err != nil {
+		return err
+	}
+	r.xor.apply(buf)
+	return nil
+}
+
+// next reads the frame header of the next record and returns its payload size.
+//
+// Returns io.EOF at the end of the file or when reaching the zero-filled space that
+// Core pre-allocates at the end of block files. That space is not obfuscated, so
+// it is detected before the key is applied.
+func (r *Reader) next() (uint32, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r.r, header[:4]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return 0, io.EOF
+		}
+		return 0, err
+	}
+	if bytes.Equal(header[:4], make([]byte, 4)) {
+		return 0, io.EOF
+	}
+	r.xor.apply(header[:4])
+	if !bytes.Equal(header[:4], r.magic[:]) {
+		return 0, fmt.Errorf("%w %x at offset %d", ErrBadMagic, header[:4], r.xor.offset-4)
+	}
+	if err := r.read(header[4:]); err != nil {
+		return 0, noEOF(err)
+	}
+	size := binary.LittleEndian.Uint32(header[4:])
+	if size > maxRecordSize {
+		return 0, ErrRecordTooLarge
+	}
+	return size, nil
+}
+
+// ReadBlock reads the next consensus serialized block from a block file.
+//
+// Returns io.EOF when there are no more blocks.
+func (r *Reader) ReadBlock() ([]byte, error) {
+	size, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+	block := make([]byte, size)
+	if err := r.read(block); err != nil {
+		return nil, noEOF(err)
+	}
+	return block, nil
+}
+
+// ReadUndo reads the next serialized block undo record and its checksum from an undo file.
+//
+// The checksum commits to the hash of the previous block, so it can only be verified
+// once the block the undo data belongs to is known; see VerifyUndoChecksum.
+//
+// Returns io.EOF when there are no more records.
+func (r *Reader) ReadUndo() (undo []byte, checksum [ChecksumSize]byte, err error) {
+	size, err := r.next()
+	if err != nil {
+		return nil, checksum, err
+	}
+	undo = make([]byte, size)
+	if err := r.read(undo); err != nil {
+		return nil, checksum, noEOF(err)
+	}
+	if err := r.read(checksum[:]); err != nil {
+		return nil, checksum, noEOF(err)
+	}
+	return undo, checksum, nil
+}
+
+// UndoChecksum computes the checksum stored after block undo data, the double-SHA256
+// of the previous block hash followed by the undo data.
+//
+// Parameters:
+//   - prevBlockHash: Hash of the parent of the block the undo data belongs to, in internal byte order
+//   - undo: Serialized block undo data
+func UndoChecksum(prevBlockHash [32]byte, undo []byte) [ChecksumSize]byte {
+	h := sha256.New()
+	h.Write(prevBlockHash[:])
+	h.Write(undo)
+	first := h.Sum(nil)
+	return sha256.Sum256(first)
+}
+
+// VerifyUndoChecksum returns ErrChecksumMismatch if checksum does not commit to
+// prevBlockHash and undo.
+func VerifyUndoChecksum(prevBlockHash [32]byte, undo []byte, checksum [ChecksumSize]byte) error {
+	if UndoChecksum(prevBlockHash, undo) != checksum {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
+
+// noEOF turns an EOF in the middle of a record into io.ErrUnexpectedEOF.
+func noEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
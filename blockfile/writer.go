@@ -0,0 +1,95 @@
+package blockfile
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+)
+
+// Writer writes framed records to a block or undo file.
+//
+// Files passed to ChainstateManager.ImportBlocks are read without obfuscation, so
+// bootstrap files should be written with a nil XOR key. Files placed in a blocks
+// directory must use the key stored in its xor.dat file.
+type Writer struct {
+	w     io.Writer
+	magic Magic
+	xor   xorStream
+	buf   []byte
+}
+
+// NewWriter returns a writer appending records to w.
+//
+// Parameters:
+//   - w: Writer positioned at the start of the file
+//   - magic: Network magic written in front of every record
+//   - xorKey: Obfuscation key of the blocks directory, or nil to write an unobfuscated file
+func NewWriter(w io.Writer, magic Magic, xorKey []byte) (*Writer, error) {
+	if xorKey != nil && len(xorKey) != XorKeySize {
+		return nil, ErrInvalidXorKeySize
+	}
+	return &Writer{w: w, magic: magic, xor: xorStream{key: xorKey}}, nil
+}
+
+// Offset returns the file offset of the next record.
+func (w *Writer) Offset() int64 {
+	return w.xor.offset
+}
+
+func (w *Writer) write(parts ...[]byte) error {
+	w.buf = w.buf[:0]
+	for _, part := range parts {
+		w.buf = append(w.buf, part...)
+	}
+	if len(w.buf)-8 > maxRecordSize {
+		return ErrRecordTooLarge
+	}
+	w.xor.apply(w.buf)
+	_, err := w.w.Write(w.buf)
+	return err
+}
+
+func (w *Writer) header(size int) []byte {
+	header := make([]byte, 8)
+	copy(header, w.magic[:])
+	binary.LittleEndian.PutUint32(header[4:], uint32(size))
+	return header
+}
+
+// WriteBlock writes a consensus serialized block record.
+func (w *Writer) WriteBlock(rawBlock []byte) error {
+	return w.write(w.header(len(rawBlock)), rawBlock)
+}
+
+// WriteUndo writes a serialized block undo record followed by its checksum.
+//
+// Parameters:
+//   - undo: Serialized block undo data
+//   - prevBlockHash: Hash of the parent of the block the undo data belongs to, in internal byte order
+func (w *Writer) WriteUndo(undo []byte, prevBlockHash [32]byte) error {
+	checksum := UndoChecksum(prevBlockHash, undo)
+	return w.write(w.header(len(undo)), undo, checksum[:])
+}
+
+// WriteKernelBlock serializes and writes a kernel block.
+func (w *Writer) WriteKernelBlock(block *kernel.Block) error {
+	raw, err := block.Bytes()
+	if err != nil {
+		return err
+	}
+	return w.WriteBlock(raw)
+}
+
+// WriteKernelSpentOutputs serializes and writes the spent outputs of a block as an undo record.
+//
+// Parameters:
+//   - spentOutputs: Spent outputs of the block
+//   - prevBlockHash: Hash of the parent of the block the spent outputs belong to, in internal byte order
+func (w *Writer) WriteKernelSpentOutputs(spentOutputs *kernel.BlockSpentOutputs, prevBlockHash [32]byte) error {
+	raw, err := spentOutputs.Bytes()
+	if err != nil {
+		return err
+	}
+	return w.WriteUndo(raw, prevBlockHash)
+}
@@ -6,45 +6,70 @@
 
 #include <kernel/bitcoinkernel.h>
 
+#include <arith_uint256.h>
 #include <chain.h>
 #include <coins.h>
 #include <consensus/amount.h>
+#include <consensus/consensus.h>
+#include <consensus/merkle.h>
+#include <consensus/tx_check.h>
+#include <consensus/tx_verify.h>
 #include <consensus/validation.h>
+#include <crypto/muhash.h>
+#include <hash.h>
 #include <kernel/caches.h>
 #include <kernel/chainparams.h>
 #include <kernel/checks.h>
+#include <kernel/coinstats.h>
 #include <kernel/context.h>
 #include <kernel/cs_main.h>
+#include <kernel/mempool_entry.h>
+#include <kernel/mempool_options.h>
+#include <kernel/mempool_removal_reason.h>
 #include <kernel/notifications_interface.h>
 #include <kernel/warning.h>
 #include <logging.h>
 #include <node/blockstorage.h>
 #include <node/chainstate.h>
+#include <node/utxo_snapshot.h>
+#include <policy/feerate.h>
+#include <policy/policy.h>
+#include <pow.h>
 #include <primitives/block.h>
 #include <primitives/transaction.h>
 #include <script/interpreter.h>
 #include <script/script.h>
+#include <script/script_error.h>
+#include <script/solver.h>
 #include <serialize.h>
 #include <streams.h>
 #include <sync.h>
 #include <tinyformat.h>
+#include <txmempool.h>
 #include <uint256.h>
 #include <undo.h>
 #include <util/fs.h>
+#include <util/overflow.h>
 #include <util/result.h>
 #include <util/signalinterrupt.h>
+#include <util/strencodings.h>
 #include <util/task_runner.h>
+#include <util/time.h>
 #include <util/translation.h>
 #include <validation.h>
 #include <validationinterface.h>
 
+#include <algorithm>
 #include <cassert>
 #include <cstddef>
 #include <cstring>
 #include <exception>
 #include <functional>
 #include <list>
+#include <map>
 #include <memory>
+#include <optional>
+#include <set>
 #include <span>
 #include <string>
 #include <tuple>
@@ -142,6 +167,8 @@ struct Handle {
 struct btck_BlockTreeEntry: Handle<btck_BlockTreeEntry, CBlockIndex> {};
 struct btck_Block : Handle<btck_Block, std::shared_ptr<const CBlock>> {};
 struct btck_BlockValidationState : Handle<btck_BlockValidationState, BlockValidationState> {};
+struct btck_Transaction : Handle<btck_Transaction, std::shared_ptr<const CTransaction>> {};
+struct btck_MempoolEntry : Handle<btck_MempoolEntry, TxMempoolInfo> {};
 
 namespace {
 
@@ -322,8 +349,30 @@ public:
     {
         if (m_cbs.fatal_error) m_cbs.fatal_error(m_cbs.user_data, message.original.c_str(), message.original.length());
     }
+    void snapshotActivated(const CBlockIndex& base) override
+    {
+        if (m_cbs.snapshot_activated) m_cbs.snapshot_activated(m_cbs.user_data, btck_BlockTreeEntry::ref(&base));
+    }
+    void snapshotValidated(const CBlockIndex& base) override
+    {
+        if (m_cbs.snapshot_validated) m_cbs.snapshot_validated(m_cbs.user_data, btck_BlockTreeEntry::ref(&base));
+    }
 };
 
+btck_MempoolRemovalReason cast_mempool_removal_reason(MemPoolRemovalReason reason)
+{
+    switch (reason) {
+    case MemPoolRemovalReason::EXPIRY: return btck_MempoolRemovalReason_EXPIRY;
+    case MemPoolRemovalReason::SIZELIMIT: return btck_MempoolRemovalReason_SIZELIMIT;
+    case MemPoolRemovalReason::REORG: return btck_MempoolRemovalReason_REORG;
+    case MemPoolRemovalReason::BLOCK: return btck_MempoolRemovalReason_BLOCK;
+    case MemPoolRemovalReason::CONFLICT: return btck_MempoolRemovalReason_CONFLICT;
+    case MemPoolRemovalReason::REPLACED: return btck_MempoolRemovalReason_REPLACED;
+    case MemPoolRemovalReason::MANUAL: return btck_MempoolRemovalReason_MANUAL;
+    } // no default case, so the compiler can warn about missing cases
+    assert(false);
+}
+
 class KernelValidationInterface final : public CValidationInterface
 {
 public:
@@ -376,6 +425,24 @@ protected:
                                      btck_BlockTreeEntry::ref(pindex));
         }
     }
+
+    void TransactionAddedToMempool(const NewMempoolTransactionInfo& tx, uint64_t mempool_sequence) override
+    {
+        if (m_cbs.transaction_added_to_mempool) {
+            const TxMempoolInfo entry{tx.info.m_tx, GetTime<std::chrono::seconds>(), tx.info.m_fee, static_cast<int32_t>(tx.info.m_virtual_transaction_size), 0};
+            m_cbs.transaction_added_to_mempool(m_cbs.user_data, btck_MempoolEntry::ref(&entry), mempool_sequence);
+        }
+    }
+
+    void TransactionRemovedFromMempool(const CTransactionRef& tx, MemPoolRemovalReason reason, uint64_t mempool_sequence) override
+    {
+        if (m_cbs.transaction_removed_from_mempool) {
+            m_cbs.transaction_removed_from_mempool(m_cbs.user_data,
+                                                   btck_Transaction::create(tx),
+                                                   cast_mempool_removal_reason(reason),
+                                                   mempool_sequence);
+        }
+    }
 };
 
 struct ContextOptions {
@@ -424,7 +491,7 @@ public:
         }
         if (!m_notifications) {
             m_notifications = std::make_shared<KernelNotifications>(btck_NotificationInterfaceCallbacks{
-                nullptr, nullptr, nullptr, nullptr, nullptr, nullptr, nullptr, nullptr, nullptr});
+                nullptr, nullptr, nullptr, nullptr, nullptr, nullptr, nullptr, nullptr, nullptr, nullptr, nullptr});
         }
 
         if (!kernel::SanityChecks(*m_context)) {
@@ -447,6 +514,8 @@ struct ChainstateManagerOptions {
     node::BlockManager::Options m_blockman_options GUARDED_BY(m_mutex);
     std::shared_ptr<const Context> m_context;
     node::ChainstateLoadOptions m_chainstate_load_options GUARDED_BY(m_mutex);
+    kernel::CacheSizes m_cache_sizes GUARDED_BY(m_mutex){DEFAULT_KERNEL_CACHE};
+    std::optional<kernel::MemPoolOptions> m_mempool_options GUARDED_BY(m_mutex);
 
     ChainstateManagerOptions(const std::shared_ptr<const Context>& context, const fs::path& data_dir, const fs::path& blocks_dir)
         : m_chainman_options{ChainstateManager::Options{
@@ -468,18 +537,101 @@ struct ChainstateManagerOptions {
 };
 
 struct ChainMan {
+    //! Declared before the chainstate manager, so that it outlives the chainstates referencing it
+    std::unique_ptr<CTxMemPool> m_mempool;
     std::unique_ptr<ChainstateManager> m_chainman;
     std::shared_ptr<const Context> m_context;
+    bool m_coins_db_in_memory;
 
-    ChainMan(std::unique_ptr<ChainstateManager> chainman, std::shared_ptr<const Context> context)
-        : m_chainman(std::move(chainman)), m_context(std::move(context)) {}
+    ChainMan(std::unique_ptr<CTxMemPool> mempool, std::unique_ptr<ChainstateManager> chainman, std::shared_ptr<const Context> context, bool coins_db_in_memory)
+        : m_mempool(std::move(mempool)), m_chainman(std::move(chainman)), m_context(std::move(context)), m_coins_db_in_memory(coins_db_in_memory) {}
 };
 
+btck_ScriptError cast_script_error(ScriptError error)
+{
+    switch (error) {
+    case SCRIPT_ERR_OK: return btck_ScriptError_OK;
+    case SCRIPT_ERR_UNKNOWN_ERROR: return btck_ScriptError_UNKNOWN_ERROR;
+    case SCRIPT_ERR_EVAL_FALSE: return btck_ScriptError_EVAL_FALSE;
+    case SCRIPT_ERR_OP_RETURN: return btck_ScriptError_OP_RETURN;
+    case SCRIPT_ERR_SCRIPT_SIZE: return btck_ScriptError_SCRIPT_SIZE;
+    case SCRIPT_ERR_PUSH_SIZE: return btck_ScriptError_PUSH_SIZE;
+    case SCRIPT_ERR_OP_COUNT: return btck_ScriptError_OP_COUNT;
+    case SCRIPT_ERR_STACK_SIZE: return btck_ScriptError_STACK_SIZE;
+    case SCRIPT_ERR_SIG_COUNT: return btck_ScriptError_SIG_COUNT;
+    case SCRIPT_ERR_PUBKEY_COUNT: return btck_ScriptError_PUBKEY_COUNT;
+    case SCRIPT_ERR_VERIFY: return btck_ScriptError_VERIFY;
+    case SCRIPT_ERR_EQUALVERIFY: return btck_ScriptError_EQUALVERIFY;
+    case SCRIPT_ERR_CHECKMULTISIGVERIFY: return btck_ScriptError_CHECKMULTISIGVERIFY;
+    case SCRIPT_ERR_CHECKSIGVERIFY: return btck_ScriptError_CHECKSIGVERIFY;
+    case SCRIPT_ERR_NUMEQUALVERIFY: return btck_ScriptError_NUMEQUALVERIFY;
+    case SCRIPT_ERR_BAD_OPCODE: return btck_ScriptError_BAD_OPCODE;
+    case SCRIPT_ERR_DISABLED_OPCODE: return btck_ScriptError_DISABLED_OPCODE;
+    case SCRIPT_ERR_INVALID_STACK_OPERATION: return btck_ScriptError_INVALID_STACK_OPERATION;
+    case SCRIPT_ERR_INVALID_ALTSTACK_OPERATION: return btck_ScriptError_INVALID_ALTSTACK_OPERATION;
+    case SCRIPT_ERR_UNBALANCED_CONDITIONAL: return btck_ScriptError_UNBALANCED_CONDITIONAL;
+    case SCRIPT_ERR_NEGATIVE_LOCKTIME: return btck_ScriptError_NEGATIVE_LOCKTIME;
+    case SCRIPT_ERR_UNSATISFIED_LOCKTIME: return btck_ScriptError_UNSATISFIED_LOCKTIME;
+    case SCRIPT_ERR_SIG_HASHTYPE: return btck_ScriptError_SIG_HASHTYPE;
+    case SCRIPT_ERR_SIG_DER: return btck_ScriptError_SIG_DER;
+    case SCRIPT_ERR_MINIMALDATA: return btck_ScriptError_MINIMALDATA;
+    case SCRIPT_ERR_SIG_PUSHONLY: return btck_ScriptError_SIG_PUSHONLY;
+    case SCRIPT_ERR_SIG_HIGH_S: return btck_ScriptError_SIG_HIGH_S;
+    case SCRIPT_ERR_SIG_NULLDUMMY: return btck_ScriptError_SIG_NULLDUMMY;
+    case SCRIPT_ERR_PUBKEYTYPE: return btck_ScriptError_PUBKEYTYPE;
+    case SCRIPT_ERR_CLEANSTACK: return btck_ScriptError_CLEANSTACK;
+    case SCRIPT_ERR_MINIMALIF: return btck_ScriptError_MINIMALIF;
+    case SCRIPT_ERR_SIG_NULLFAIL: return btck_ScriptError_SIG_NULLFAIL;
+    case SCRIPT_ERR_DISCOURAGE_UPGRADABLE_NOPS: return btck_ScriptError_DISCOURAGE_UPGRADABLE_NOPS;
+    case SCRIPT_ERR_DISCOURAGE_UPGRADABLE_WITNESS_PROGRAM: return btck_ScriptError_DISCOURAGE_UPGRADABLE_WITNESS_PROGRAM;
+    case SCRIPT_ERR_DISCOURAGE_UPGRADABLE_TAPROOT_VERSION: return btck_ScriptError_DISCOURAGE_UPGRADABLE_TAPROOT_VERSION;
+    case SCRIPT_ERR_DISCOURAGE_OP_SUCCESS: return btck_ScriptError_DISCOURAGE_OP_SUCCESS;
+    case SCRIPT_ERR_DISCOURAGE_UPGRADABLE_PUBKEYTYPE: return btck_ScriptError_DISCOURAGE_UPGRADABLE_PUBKEYTYPE;
+    case SCRIPT_ERR_WITNESS_PROGRAM_WRONG_LENGTH: return btck_ScriptError_WITNESS_PROGRAM_WRONG_LENGTH;
+    case SCRIPT_ERR_WITNESS_PROGRAM_WITNESS_EMPTY: return btck_ScriptError_WITNESS_PROGRAM_WITNESS_EMPTY;
+    case SCRIPT_ERR_WITNESS_PROGRAM_MISMATCH: return btck_ScriptError_WITNESS_PROGRAM_MISMATCH;
+    case SCRIPT_ERR_WITNESS_MALLEATED: return btck_ScriptError_WITNESS_MALLEATED;
+    case SCRIPT_ERR_WITNESS_MALLEATED_P2SH: return btck_ScriptError_WITNESS_MALLEATED_P2SH;
+    case SCRIPT_ERR_WITNESS_UNEXPECTED: return btck_ScriptError_WITNESS_UNEXPECTED;
+    case SCRIPT_ERR_WITNESS_PUBKEYTYPE: return btck_ScriptError_WITNESS_PUBKEYTYPE;
+    case SCRIPT_ERR_SCHNORR_SIG_SIZE: return btck_ScriptError_SCHNORR_SIG_SIZE;
+    case SCRIPT_ERR_SCHNORR_SIG_HASHTYPE: return btck_ScriptError_SCHNORR_SIG_HASHTYPE;
+    case SCRIPT_ERR_SCHNORR_SIG: return btck_ScriptError_SCHNORR_SIG;
+    case SCRIPT_ERR_TAPROOT_WRONG_CONTROL_SIZE: return btck_ScriptError_TAPROOT_WRONG_CONTROL_SIZE;
+    case SCRIPT_ERR_TAPSCRIPT_VALIDATION_WEIGHT: return btck_ScriptError_TAPSCRIPT_VALIDATION_WEIGHT;
+    case SCRIPT_ERR_TAPSCRIPT_CHECKMULTISIG: return btck_ScriptError_TAPSCRIPT_CHECKMULTISIG;
+    case SCRIPT_ERR_TAPSCRIPT_MINIMALIF: return btck_ScriptError_TAPSCRIPT_MINIMALIF;
+    case SCRIPT_ERR_TAPSCRIPT_EMPTY_PUBKEY: return btck_ScriptError_TAPSCRIPT_EMPTY_PUBKEY;
+    case SCRIPT_ERR_OP_CODESEPARATOR: return btck_ScriptError_OP_CODESEPARATOR;
+    case SCRIPT_ERR_SIG_FINDANDDELETE: return btck_ScriptError_SIG_FINDANDDELETE;
+    case SCRIPT_ERR_ERROR_COUNT: break;
+    } // no default case, so the compiler can warn about missing cases
+    assert(false);
+}
+
+std::vector<std::vector<unsigned char>> ScriptSolutions(const CScript& script)
+{
+    std::vector<std::vector<unsigned char>> solutions;
+    if (Solver(script, solutions) == TxoutType::NULL_DATA) {
+        // The solver does not return the payload of data carrier outputs
+        opcodetype opcode;
+        std::vector<unsigned char> data;
+        CScript::const_iterator pc{script.begin() + 1};
+        while (pc < script.end() && script.GetOp(pc, opcode, data)) {
+            if (opcode >= OP_1 && opcode <= OP_16) {
+                data = {static_cast<unsigned char>(CScript::DecodeOP_N(opcode))};
+            }
+            solutions.push_back(data);
+        }
+    }
+    return solutions;
+}
+
 } // namespace
 
-struct btck_Transaction : Handle<btck_Transaction, std::shared_ptr<const CTransaction>> {};
 struct btck_TransactionOutput : Handle<btck_TransactionOutput, CTxOut> {};
 struct btck_ScriptPubkey : Handle<btck_ScriptPubkey, CScript> {};
+struct btck_PrecomputedTransactionData : Handle<btck_PrecomputedTransactionData, PrecomputedTransactionData> {};
 struct btck_LoggingConnection : Handle<btck_LoggingConnection, LoggingConnection> {};
 struct btck_ContextOptions : Handle<btck_ContextOptions, ContextOptions> {};
 struct btck_Context : Handle<btck_Context, std::shared_ptr<const Context>> {};
@@ -494,6 +646,43 @@ struct btck_BlockHash : Handle<btck_BlockHash, uint256> {};
 struct btck_TransactionInput : Handle<btck_TransactionInput, CTxIn> {};
 struct btck_TransactionOutPoint: Handle<btck_TransactionOutPoint, COutPoint> {};
 struct btck_Txid: Handle<btck_Txid, Txid> {};
+struct btck_BlockHeader : Handle<btck_BlockHeader, CBlockHeader> {};
+struct btck_CoinsCursor : Handle<btck_CoinsCursor, std::unique_ptr<CCoinsViewCursor>> {};
+
+struct UtxoSetStats {
+    kernel::CCoinsStats stats;
+    uint256 muhash;
+    uint256 hash_serialized;
+};
+
+struct btck_UtxoSetStats : Handle<btck_UtxoSetStats, UtxoSetStats> {};
+
+struct ChainTip {
+    const CBlockIndex* index;
+    btck_ChainTipStatus status;
+    int32_t branch_length;
+};
+
+struct btck_ChainTips : Handle<btck_ChainTips, std::vector<ChainTip>> {};
+struct btck_Mempool : Handle<btck_Mempool, CTxMemPool> {};
+struct btck_MempoolEntries : Handle<btck_MempoolEntries, std::vector<TxMempoolInfo>> {};
+struct btck_TxValidationState : Handle<btck_TxValidationState, TxValidationState> {};
+
+struct BlockTemplateOptions {
+    bool use_mempool{true};
+    uint64_t max_weight{DEFAULT_BLOCK_MAX_WEIGHT};
+    CFeeRate min_fee_rate{DEFAULT_BLOCK_MIN_TX_FEE};
+};
+
+struct BlockTemplate {
+    std::shared_ptr<const CBlock> block;
+    int64_t min_time;
+    int64_t max_time;
+    CAmount fees;
+};
+
+struct btck_BlockTemplateOptions : Handle<btck_BlockTemplateOptions, BlockTemplateOptions> {};
+struct btck_BlockTemplate : Handle<btck_BlockTemplate, BlockTemplate> {};
 
 btck_Transaction* btck_transaction_create(const void* raw_transaction, size_t raw_transaction_len)
 {
@@ -536,6 +725,108 @@ const btck_Txid* btck_transaction_get_txid(const btck_Transaction* transaction)
     return btck_Txid::ref(&btck_Transaction::get(transaction)->GetHash());
 }
 
+void btck_transaction_get_wtxid(const btck_Transaction* transaction, unsigned char output[32])
+{
+    std::memcpy(output, btck_Transaction::get(transaction)->GetWitnessHash().begin(), 32);
+}
+
+uint32_t btck_transaction_get_version(const btck_Transaction* transaction)
+{
+    return btck_Transaction::get(transaction)->version;
+}
+
+uint32_t btck_transaction_get_locktime(const btck_Transaction* transaction)
+{
+    return btck_Transaction::get(transaction)->nLockTime;
+}
+
+int btck_transaction_is_coinbase(const btck_Transaction* transaction)
+{
+    return btck_Transaction::get(transaction)->IsCoinBase() ? 1 : 0;
+}
+
+int btck_transaction_has_witness(const btck_Transaction* transaction)
+{
+    return btck_Transaction::get(transaction)->HasWitness() ? 1 : 0;
+}
+
+int64_t btck_transaction_get_weight(const btck_Transaction* transaction)
+{
+    return GetTransactionWeight(*btck_Transaction::get(transaction));
+}
+
+void btck_transaction_signature_hash_legacy(const btck_Transaction* transaction,
+                                            unsigned int input_index,
+                                            const void* script_code, size_t script_code_len,
+                                            int32_t hash_type,
+                                            unsigned char output[32])
+{
+    const CTransaction& tx{*btck_Transaction::get(transaction)};
+    assert(input_index < tx.vin.size());
+    auto code = std::span{reinterpret_cast<const uint8_t*>(script_code), script_code_len};
+    const uint256 hash{SignatureHash(CScript(code.begin(), code.end()), tx, input_index, hash_type, /*amount=*/0, SigVersion::BASE)};
+    std::memcpy(output, hash.begin(), 32);
+}
+
+void btck_transaction_signature_hash_witness_v0(const btck_Transaction* transaction,
+                                                unsigned int input_index,
+                                                const void* script_code, size_t script_code_len,
+                                                int64_t amount,
+                                                int32_t hash_type,
+                                                unsigned char output[32])
+{
+    const CTransaction& tx{*btck_Transaction::get(transaction)};
+    assert(input_index < tx.vin.size());
+    auto code = std::span{reinterpret_cast<const uint8_t*>(script_code), script_code_len};
+    const uint256 hash{SignatureHash(CScript(code.begin(), code.end()), tx, input_index, hash_type, amount, SigVersion::WITNESS_V0)};
+    std::memcpy(output, hash.begin(), 32);
+}
+
+int btck_transaction_signature_hash_taproot(const btck_Transaction* transaction,
+                                            unsigned int input_index,
+                                            const btck_TransactionOutput** spent_outputs_, size_t spent_outputs_len,
+                                            uint8_t hash_type,
+                                            const unsigned char* tapleaf_hash,
+                                            uint32_t codeseparator_position,
+                                            unsigned char output[32])
+{
+    const CTransaction& tx{*btck_Transaction::get(transaction)};
+    assert(input_index < tx.vin.size());
+    assert(spent_outputs_len == tx.vin.size());
+
+    std::vector<CTxOut> spent_outputs;
+    spent_outputs.reserve(spent_outputs_len);
+    for (size_t i = 0; i < spent_outputs_len; i++) {
+        spent_outputs.push_back(btck_TransactionOutput::get(spent_outputs_[i]));
+    }
+    PrecomputedTransactionData txdata;
+    txdata.Init(tx, std::move(spent_outputs), /*force=*/true);
+
+    ScriptExecutionData execdata;
+    const auto& witness_stack{tx.vin[input_index].scriptWitness.stack};
+    execdata.m_annex_present = witness_stack.size() >= 2 && !witness_stack.back().empty() && witness_stack.back()[0] == ANNEX_TAG;
+    if (execdata.m_annex_present) {
+        execdata.m_annex_hash = (HashWriter{} << witness_stack.back()).GetSHA256();
+    }
+    execdata.m_annex_init = true;
+
+    SigVersion sigversion{SigVersion::TAPROOT};
+    if (tapleaf_hash) {
+        sigversion = SigVersion::TAPSCRIPT;
+        execdata.m_tapleaf_hash = uint256{std::span<const unsigned char>{tapleaf_hash, 32}};
+        execdata.m_tapleaf_hash_init = true;
+        execdata.m_codeseparator_pos = codeseparator_position;
+        execdata.m_codeseparator_pos_init = true;
+    }
+
+    uint256 hash;
+    if (!SignatureHashSchnorr(hash, execdata, tx, input_index, hash_type, sigversion, txdata, MissingDataBehavior::FAIL)) {
+        return -1;
+    }
+    std::memcpy(output, hash.begin(), 32);
+    return 0;
+}
+
 btck_Transaction* btck_transaction_copy(const btck_Transaction* transaction)
 {
     return btck_Transaction::copy(transaction);
@@ -552,6 +843,13 @@ int btck_transaction_to_bytes(const btck_Transaction* transaction, btck_WriteByt
     }
 }
 
+int btck_transaction_check(const btck_Transaction* transaction, btck_TxValidationState* tx_validation_state)
+{
+    auto& state{btck_TxValidationState::get(tx_validation_state)};
+    state = TxValidationState{};
+    return CheckTransaction(*btck_Transaction::get(transaction), state) ? 0 : -1;
+}
+
 void btck_transaction_destroy(btck_Transaction* transaction)
 {
     delete transaction;
@@ -572,6 +870,69 @@ int btck_script_pubkey_to_bytes(const btck_ScriptPubkey* script_pubkey_, btck_Wr
     return writer(script_pubkey.data(), script_pubkey.size(), user_data);
 }
 
+btck_ScriptType btck_script_pubkey_get_type(const btck_ScriptPubkey* script_pubkey)
+{
+    std::vector<std::vector<unsigned char>> solutions;
+    switch (Solver(btck_ScriptPubkey::get(script_pubkey), solutions)) {
+    case TxoutType::NONSTANDARD: return btck_ScriptType_NONSTANDARD;
+    case TxoutType::ANCHOR: return btck_ScriptType_ANCHOR;
+    case TxoutType::PUBKEY: return btck_ScriptType_PUBKEY;
+    case TxoutType::PUBKEYHASH: return btck_ScriptType_PUBKEYHASH;
+    case TxoutType::SCRIPTHASH: return btck_ScriptType_SCRIPTHASH;
+    case TxoutType::MULTISIG: return btck_ScriptType_MULTISIG;
+    case TxoutType::NULL_DATA: return btck_ScriptType_NULL_DATA;
+    case TxoutType::WITNESS_V0_SCRIPTHASH: return btck_ScriptType_WITNESS_V0_SCRIPTHASH;
+    case TxoutType::WITNESS_V0_KEYHASH: return btck_ScriptType_WITNESS_V0_KEYHASH;
+    case TxoutType::WITNESS_V1_TAPROOT: return btck_ScriptType_WITNESS_V1_TAPROOT;
+    case TxoutType::WITNESS_UNKNOWN: return btck_ScriptType_WITNESS_UNKNOWN;
+    } // no default case, so the compiler can warn about missing cases
+    assert(false);
+}
+
+size_t btck_script_pubkey_count_solutions(const btck_ScriptPubkey* script_pubkey)
+{
+    return ScriptSolutions(btck_ScriptPubkey::get(script_pubkey)).size();
+}
+
+int btck_script_pubkey_solution_to_bytes(const btck_ScriptPubkey* script_pubkey, size_t solution_index, btck_WriteBytes writer, void* user_data)
+{
+    const auto solutions{ScriptSolutions(btck_ScriptPubkey::get(script_pubkey))};
+    assert(solution_index < solutions.size());
+    return writer(solutions[solution_index].data(), solutions[solution_index].size(), user_data);
+}
+
+int btck_script_pubkey_to_asm(const btck_ScriptPubkey* script_pubkey, btck_WriteBytes writer, void* user_data)
+{
+    // Mirrors ScriptToAsmStr, which is not part of the kernel library
+    const auto& script{btck_ScriptPubkey::get(script_pubkey)};
+    std::string str;
+    opcodetype opcode;
+    std::vector<unsigned char> vch;
+    CScript::const_iterator pc{script.begin()};
+    while (pc < script.end()) {
+        if (!str.empty()) {
+            str += " ";
+        }
+        if (!script.GetOp(pc, opcode, vch)) {
+            str += "[error]";
+            break;
+        }
+        if (0 <= opcode && opcode <= OP_PUSHDATA4) {
+            if (vch.size() <= 4) {
+                str += strprintf("%d", CScriptNum(vch, false).getint());
+            } else {
+                str += HexStr(vch);
+            }
+        } else {
+            str += GetOpName(opcode);
+        }
+    }
+    if (str.empty()) {
+        return 0;
+    }
+    return writer(str.data(), str.size(), user_data);
+}
+
 btck_ScriptPubkey* btck_script_pubkey_copy(const btck_ScriptPubkey* script_pubkey)
 {
     return btck_ScriptPubkey::copy(script_pubkey);
@@ -607,54 +968,222 @@ void btck_transaction_output_destroy(btck_TransactionOutput* output)
     delete output;
 }
 
-int btck_script_pubkey_verify(const btck_ScriptPubkey* script_pubkey,
-                              const int64_t amount,
-                              const btck_Transaction* tx_to,
-                              const btck_TransactionOutput** spent_outputs_, size_t spent_outputs_len,
-                              const unsigned int input_index,
-                              const btck_ScriptVerificationFlags flags,
-                              btck_ScriptVerifyStatus* status)
+namespace {
+struct ScriptTraceStep {
+    uint32_t opcode_position;
+    uint8_t opcode;
+    bool executed;
+    const std::vector<std::vector<unsigned char>>& stack;
+    const std::vector<std::vector<unsigned char>>& altstack;
+};
+} // namespace
+
+struct btck_ScriptTraceStep : Handle<btck_ScriptTraceStep, ScriptTraceStep> {};
+
+namespace {
+class CallbackScriptTracer : public ScriptExecutionTracer
+{
+private:
+    const btck_ScriptTraceCallbacks m_cbs;
+    //! Number of scripts evaluated with the base sigversion: the scriptSig, the
+    //! script pubkey and, for P2SH, the redeem script, in that order.
+    unsigned int m_base_scripts{0};
+
+public:
+    explicit CallbackScriptTracer(btck_ScriptTraceCallbacks cbs) : m_cbs{cbs} {}
+
+    void OnScriptStart(const CScript& script, SigVersion sigversion) override
+    {
+        btck_ScriptPhase phase{btck_ScriptPhase_SCRIPT_SIG};
+        switch (sigversion) {
+        case SigVersion::BASE:
+            if (m_base_scripts == 1) phase = btck_ScriptPhase_SCRIPT_PUBKEY;
+            if (m_base_scripts >= 2) phase = btck_ScriptPhase_REDEEM_SCRIPT;
+            ++m_base_scripts;
+            break;
+        case SigVersion::WITNESS_V0:
+            phase = btck_ScriptPhase_WITNESS_SCRIPT;
+            break;
+        case SigVersion::TAPSCRIPT:
+            phase = btck_ScriptPhase_TAPSCRIPT;
+            break;
+        case SigVersion::TAPROOT:
+            assert(false); // key path spends do not evaluate a script
+        }
+        m_cbs.script_start(m_cbs.user_data, phase, script.data(), script.size());
+    }
+
+    void OnStep(uint32_t opcode_pos, uint8_t opcode, bool executed,
+                const std::vector<std::vector<unsigned char>>& stack,
+                const std::vector<std::vector<unsigned char>>& altstack) override
+    {
+        const ScriptTraceStep step{opcode_pos, opcode, executed, stack, altstack};
+        m_cbs.step(m_cbs.user_data, btck_ScriptTraceStep::ref(&step));
+    }
+};
+
+//! Signature checker that forwards to another checker and reports script
+//! execution to the given tracer, if any.
+class TracingSignatureChecker : public DeferringSignatureChecker
+{
+private:
+    ScriptExecutionTracer* const m_tracer;
+
+public:
+    TracingSignatureChecker(const BaseSignatureChecker& checker, ScriptExecutionTracer* tracer)
+        : DeferringSignatureChecker{checker}, m_tracer{tracer} {}
+
+    ScriptExecutionTracer* GetTracer() const override
+    {
+        return m_tracer;
+    }
+};
+
+int VerifyScriptPubkey(const btck_ScriptPubkey* script_pubkey,
+                       const int64_t amount,
+                       const btck_Transaction* tx_to,
+                       const PrecomputedTransactionData& txdata,
+                       const unsigned int input_index,
+                       const btck_ScriptVerificationFlags flags,
+                       btck_ScriptVerifyStatus* status,
+                       btck_ScriptError* script_error,
+                       ScriptExecutionTracer* tracer)
 {
     // Assert that all specified flags are part of the interface before continuing
     assert((flags & ~btck_ScriptVerificationFlags_ALL) == 0);
 
+    if (script_error) *script_error = btck_ScriptError_OK;
+
     if (!is_valid_flag_combination(script_verify_flags::from_int(flags))) {
         if (status) *status = btck_ScriptVerifyStatus_ERROR_INVALID_FLAGS_COMBINATION;
         return 0;
     }
 
-    if (flags & btck_ScriptVerificationFlags_TAPROOT && spent_outputs_ == nullptr) {
+    if (flags & btck_ScriptVerificationFlags_TAPROOT && !txdata.m_spent_outputs_ready) {
         if (status) *status = btck_ScriptVerifyStatus_ERROR_SPENT_OUTPUTS_REQUIRED;
         return 0;
     }
 
     if (status) *status = btck_ScriptVerifyStatus_OK;
 
+    const CTransaction& tx{*btck_Transaction::get(tx_to)};
+    assert(input_index < tx.vin.size());
+
+    const TransactionSignatureChecker checker{&tx, input_index, amount, txdata, MissingDataBehavior::FAIL};
+    ScriptError error;
+    bool result = VerifyScript(tx.vin[input_index].scriptSig,
+                               btck_ScriptPubkey::get(script_pubkey),
+                               &tx.vin[input_index].scriptWitness,
+                               script_verify_flags::from_int(flags),
+                               TracingSignatureChecker{checker, tracer},
+                               &error);
+    if (script_error) *script_error = cast_script_error(error);
+    return result ? 1 : 0;
+}
+
+int VerifyScriptPubkey(const btck_ScriptPubkey* script_pubkey,
+                       const int64_t amount,
+                       const btck_Transaction* tx_to,
+                       const btck_TransactionOutput** spent_outputs_, size_t spent_outputs_len,
+                       const unsigned int input_index,
+                       const btck_ScriptVerificationFlags flags,
+                       btck_ScriptVerifyStatus* status,
+                       btck_ScriptError* script_error,
+                       ScriptExecutionTracer* tracer)
+{
     const CTransaction& tx{*btck_Transaction::get(tx_to)};
     std::vector<CTxOut> spent_outputs;
     if (spent_outputs_ != nullptr) {
         assert(spent_outputs_len == tx.vin.size());
-        spent_outputs.reserve(spent_outputs_len);
-        for (size_t i = 0; i < spent_outputs_len; i++) {
-            const CTxOut& tx_out{btck_TransactionOutput::get(spent_outputs_[i])};
-            spent_outputs.push_back(tx_out);
+        // The spent outputs are only needed for taproot signature hashes
+        if (flags & btck_ScriptVerificationFlags_TAPROOT) {
+            spent_outputs.reserve(spent_outputs_len);
+            for (size_t i = 0; i < spent_outputs_len; i++) {
+                spent_outputs.push_back(btck_TransactionOutput::get(spent_outputs_[i]));
+            }
         }
     }
 
-    assert(input_index < tx.vin.size());
-    PrecomputedTransactionData txdata{tx};
+    PrecomputedTransactionData txdata;
+    txdata.Init(tx, std::move(spent_outputs));
+    return VerifyScriptPubkey(script_pubkey, amount, tx_to, txdata, input_index, flags, status, script_error, tracer);
+}
+} // namespace
+
+int btck_script_pubkey_verify_with_error(const btck_ScriptPubkey* script_pubkey,
+                                         const int64_t amount,
+                                         const btck_Transaction* tx_to,
+                                         const btck_TransactionOutput** spent_outputs, size_t spent_outputs_len,
+                                         const unsigned int input_index,
+                                         const btck_ScriptVerificationFlags flags,
+                                         btck_ScriptVerifyStatus* status,
+                                         btck_ScriptError* script_error)
+{
+    return VerifyScriptPubkey(script_pubkey, amount, tx_to, spent_outputs, spent_outputs_len, input_index, flags, status, script_error, nullptr);
+}
 
-    if (spent_outputs_ != nullptr && flags & btck_ScriptVerificationFlags_TAPROOT) {
-        txdata.Init(tx, std::move(spent_outputs));
+int btck_script_pubkey_verify_with_trace(const btck_ScriptPubkey* script_pubkey,
+                                         const int64_t amount,
+                                         const btck_Transaction* tx_to,
+                                         const btck_TransactionOutput** spent_outputs, size_t spent_outputs_len,
+                                         const unsigned int input_index,
+                                         const btck_ScriptVerificationFlags flags,
+                                         btck_ScriptVerifyStatus* status,
+                                         btck_ScriptError* script_error,
+                                         btck_ScriptTraceCallbacks callbacks)
+{
+    assert(callbacks.script_start && callbacks.step);
+    CallbackScriptTracer tracer{callbacks};
+    return VerifyScriptPubkey(script_pubkey, amount, tx_to, spent_outputs, spent_outputs_len, input_index, flags, status, script_error, &tracer);
+}
+
+int btck_script_pubkey_verify(const btck_ScriptPubkey* script_pubkey,
+                              const int64_t amount,
+                              const btck_Transaction* tx_to,
+                              const btck_TransactionOutput** spent_outputs, size_t spent_outputs_len,
+                              const unsigned int input_index,
+                              const btck_ScriptVerificationFlags flags,
+                              btck_ScriptVerifyStatus* status)
+{
+    return btck_script_pubkey_verify_with_error(script_pubkey, amount, tx_to, spent_outputs, spent_outputs_len, input_index, flags, status, nullptr);
+}
+
+int btck_script_pubkey_verify_with_precomputed(const btck_ScriptPubkey* script_pubkey,
+                                               const int64_t amount,
+                                               const btck_Transaction* tx_to,
+                                               const btck_PrecomputedTransactionData* precomputed_txdata,
+                                               const unsigned int input_index,
+                                               const btck_ScriptVerificationFlags flags,
+                                               btck_ScriptVerifyStatus* status,
+                                               btck_ScriptError* script_error)
+{
+    return VerifyScriptPubkey(script_pubkey, amount, tx_to, btck_PrecomputedTransactionData::get(precomputed_txdata), input_index, flags, status, script_error, nullptr);
+}
+
+btck_PrecomputedTransactionData* btck_precomputed_transaction_data_create(const btck_Transaction* tx_to,
+                                                                          const btck_TransactionOutput** spent_outputs_, size_t spent_outputs_len)
+{
+    const CTransaction& tx{*btck_Transaction::get(tx_to)};
+    std::vector<CTxOut> spent_outputs;
+    if (spent_outputs_ != nullptr) {
+        if (spent_outputs_len != tx.vin.size()) {
+            LogError("Number of spent outputs %u does not match the number of inputs %u of the transaction", spent_outputs_len, tx.vin.size());
+            return nullptr;
+        }
+        spent_outputs.reserve(spent_outputs_len);
+        for (size_t i = 0; i < spent_outputs_len; i++) {
+            spent_outputs.push_back(btck_TransactionOutput::get(spent_outputs_[i]));
+        }
     }
 
-    bool result = VerifyScript(tx.vin[input_index].scriptSig,
-                               btck_ScriptPubkey::get(script_pubkey),
-                               &tx.vin[input_index].scriptWitness,
-                               script_verify_flags::from_int(flags),
-                               TransactionSignatureChecker(&tx, input_index, amount, txdata, MissingDataBehavior::FAIL),
-                               nullptr);
-    return result ? 1 : 0;
+    auto txdata{btck_PrecomputedTransactionData::create()};
+    btck_PrecomputedTransactionData::get(txdata).Init(tx, std::move(spent_outputs));
+    return txdata;
+}
+
+void btck_precomputed_transaction_data_destroy(btck_PrecomputedTransactionData* precomputed_txdata)
+{
+    delete precomputed_txdata;
 }
 
 btck_TransactionInput* btck_transaction_input_copy(const btck_TransactionInput* input)
@@ -667,11 +1196,39 @@ const btck_TransactionOutPoint* btck_transaction_input_get_out_point(const btck_
     return btck_TransactionOutPoint::ref(&btck_TransactionInput::get(input).prevout);
 }
 
+uint32_t btck_transaction_input_get_sequence(const btck_TransactionInput* input)
+{
+    return btck_TransactionInput::get(input).nSequence;
+}
+
+int btck_transaction_input_script_sig_to_bytes(const btck_TransactionInput* input, btck_WriteBytes writer, void* user_data)
+{
+    const auto& script_sig{btck_TransactionInput::get(input).scriptSig};
+    return writer(script_sig.data(), script_sig.size(), user_data);
+}
+
+size_t btck_transaction_input_count_witness_items(const btck_TransactionInput* input)
+{
+    return btck_TransactionInput::get(input).scriptWitness.stack.size();
+}
+
+int btck_transaction_input_witness_item_to_bytes(const btck_TransactionInput* input, size_t witness_index, btck_WriteBytes writer, void* user_data)
+{
+    const auto& stack{btck_TransactionInput::get(input).scriptWitness.stack};
+    assert(witness_index < stack.size());
+    return writer(stack[witness_index].data(), stack[witness_index].size(), user_data);
+}
+
 void btck_transaction_input_destroy(btck_TransactionInput* input)
 {
     delete input;
 }
 
+btck_TransactionOutPoint* btck_transaction_out_point_create(const unsigned char txid[32], uint32_t index)
+{
+    return btck_TransactionOutPoint::create(Txid::FromUint256(uint256{std::span<const unsigned char>{txid, 32}}), index);
+}
+
 btck_TransactionOutPoint* btck_transaction_out_point_copy(const btck_TransactionOutPoint* out_point)
 {
     return btck_TransactionOutPoint::copy(out_point);
@@ -788,6 +1345,33 @@ btck_ChainParameters* btck_chain_parameters_copy(const btck_ChainParameters* cha
     return btck_ChainParameters::copy(chain_parameters);
 }
 
+namespace {
+//! Exposes the protected assumeutxo entries of the chain parameters.
+struct AssumeutxoDataAccess : CChainParams {
+    static std::vector<AssumeutxoData>& Get(CChainParams& params)
+    {
+        return params.*(&AssumeutxoDataAccess::m_assumeutxo_data);
+    }
+};
+} // namespace
+
+int btck_chain_parameters_add_assumeutxo(btck_ChainParameters* chain_parameters, int32_t height, const btck_BlockHash* block_hash, const unsigned char hash_serialized[32], uint64_t chain_tx_count)
+{
+    auto& params{btck_ChainParameters::get(chain_parameters)};
+    const uint256& base_hash{btck_BlockHash::get(block_hash)};
+    if (params.AssumeutxoForHeight(height) || params.AssumeutxoForBlockhash(base_hash)) {
+        LogError("An assumeutxo entry for height %d or block %s already exists.", height, base_hash.ToString());
+        return -1;
+    }
+    AssumeutxoDataAccess::Get(params).push_back(AssumeutxoData{
+        .height = height,
+        .hash_serialized = AssumeutxoHash{uint256{std::span<const unsigned char>{hash_serialized, 32}}},
+        .m_chain_tx_count = chain_tx_count,
+        .blockhash = base_hash,
+    });
+    return 0;
+}
+
 void btck_chain_parameters_destroy(btck_ChainParameters* chain_parameters)
 {
     delete chain_parameters;
@@ -860,6 +1444,11 @@ const btck_BlockTreeEntry* btck_block_tree_entry_get_previous(const btck_BlockTr
     return btck_BlockTreeEntry::ref(btck_BlockTreeEntry::get(entry).pprev);
 }
 
+btck_BlockValidationState* btck_block_validation_state_create()
+{
+    return btck_BlockValidationState::create();
+}
+
 btck_ValidationMode btck_block_validation_state_get_validation_mode(const btck_BlockValidationState* block_validation_state_)
 {
     auto& block_validation_state = btck_BlockValidationState::get(block_validation_state_);
@@ -894,6 +1483,91 @@ btck_BlockValidationResult btck_block_validation_state_get_block_validation_resu
     assert(false);
 }
 
+int btck_block_validation_state_get_reject_reason(const btck_BlockValidationState* block_validation_state, btck_WriteBytes writer, void* user_data)
+{
+    const auto reject_reason{btck_BlockValidationState::get(block_validation_state).GetRejectReason()};
+    if (reject_reason.empty()) return 0;
+    return writer(reject_reason.data(), reject_reason.size(), user_data);
+}
+
+int btck_block_validation_state_get_debug_message(const btck_BlockValidationState* block_validation_state, btck_WriteBytes writer, void* user_data)
+{
+    const auto debug_message{btck_BlockValidationState::get(block_validation_state).GetDebugMessage()};
+    if (debug_message.empty()) return 0;
+    return writer(debug_message.data(), debug_message.size(), user_data);
+}
+
+void btck_block_validation_state_destroy(btck_BlockValidationState* block_validation_state)
+{
+    delete block_validation_state;
+}
+
+btck_TxValidationState* btck_tx_validation_state_create()
+{
+    return btck_TxValidationState::create();
+}
+
+btck_ValidationMode btck_tx_validation_state_get_validation_mode(const btck_TxValidationState* tx_validation_state_)
+{
+    auto& tx_validation_state = btck_TxValidationState::get(tx_validation_state_);
+    if (tx_validation_state.IsValid()) return btck_ValidationMode_VALID;
+    if (tx_validation_state.IsInvalid()) return btck_ValidationMode_INVALID;
+    return btck_ValidationMode_INTERNAL_ERROR;
+}
+
+btck_TxValidationResult btck_tx_validation_state_get_tx_validation_result(const btck_TxValidationState* tx_validation_state_)
+{
+    auto& tx_validation_state = btck_TxValidationState::get(tx_validation_state_);
+    switch (tx_validation_state.GetResult()) {
+    case TxValidationResult::TX_RESULT_UNSET:
+        return btck_TxValidationResult_UNSET;
+    case TxValidationResult::TX_CONSENSUS:
+        return btck_TxValidationResult_CONSENSUS;
+    case TxValidationResult::TX_INPUTS_NOT_STANDARD:
+        return btck_TxValidationResult_INPUTS_NOT_STANDARD;
+    case TxValidationResult::TX_NOT_STANDARD:
+        return btck_TxValidationResult_NOT_STANDARD;
+    case TxValidationResult::TX_MISSING_INPUTS:
+        return btck_TxValidationResult_MISSING_INPUTS;
+    case TxValidationResult::TX_PREMATURE_SPEND:
+        return btck_TxValidationResult_PREMATURE_SPEND;
+    case TxValidationResult::TX_WITNESS_MUTATED:
+        return btck_TxValidationResult_WITNESS_MUTATED;
+    case TxValidationResult::TX_WITNESS_STRIPPED:
+        return btck_TxValidationResult_WITNESS_STRIPPED;
+    case TxValidationResult::TX_CONFLICT:
+        return btck_TxValidationResult_CONFLICT;
+    case TxValidationResult::TX_MEMPOOL_POLICY:
+        return btck_TxValidationResult_MEMPOOL_POLICY;
+    case TxValidationResult::TX_NO_MEMPOOL:
+        return btck_TxValidationResult_NO_MEMPOOL;
+    case TxValidationResult::TX_RECONSIDERABLE:
+        return btck_TxValidationResult_RECONSIDERABLE;
+    case TxValidationResult::TX_UNKNOWN:
+        return btck_TxValidationResult_UNKNOWN;
+    } // no default case, so the compiler can warn about missing cases
+    assert(false);
+}
+
+int btck_tx_validation_state_get_reject_reason(const btck_TxValidationState* tx_validation_state, btck_WriteBytes writer, void* user_data)
+{
+    const auto reject_reason{btck_TxValidationState::get(tx_validation_state).GetRejectReason()};
+    if (reject_reason.empty()) return 0;
+    return writer(reject_reason.data(), reject_reason.size(), user_data);
+}
+
+int btck_tx_validation_state_get_debug_message(const btck_TxValidationState* tx_validation_state, btck_WriteBytes writer, void* user_data)
+{
+    const auto debug_message{btck_TxValidationState::get(tx_validation_state).GetDebugMessage()};
+    if (debug_message.empty()) return 0;
+    return writer(debug_message.data(), debug_message.size(), user_data);
+}
+
+void btck_tx_validation_state_destroy(btck_TxValidationState* tx_validation_state)
+{
+    delete tx_validation_state;
+}
+
 btck_ChainstateManagerOptions* btck_chainstate_manager_options_create(const btck_Context* context, const char* data_dir, size_t data_dir_len, const char* blocks_dir, size_t blocks_dir_len)
 {
     if (data_dir == nullptr || data_dir_len == 0 || blocks_dir == nullptr || blocks_dir_len == 0) {
@@ -954,13 +1628,91 @@ void btck_chainstate_manager_options_update_chainstate_db_in_memory(
     opts.m_chainstate_load_options.coins_db_in_memory = chainstate_db_in_memory == 1;
 }
 
-btck_ChainstateManager* btck_chainstate_manager_create(
-    const btck_ChainstateManagerOptions* chainman_opts)
+int btck_chainstate_manager_options_set_prune_target(btck_ChainstateManagerOptions* chainman_opts, uint64_t prune_target)
 {
+    if (prune_target != 0 && prune_target != node::BlockManager::PRUNE_TARGET_MANUAL && prune_target < MIN_DISK_SPACE_FOR_BLOCK_FILES) {
+        LogError("Prune target below the minimum of %d MiB.", MIN_DISK_SPACE_FOR_BLOCK_FILES / 1024 / 1024);
+        return -1;
+    }
     auto& opts{btck_ChainstateManagerOptions::get(chainman_opts)};
-    std::unique_ptr<ChainstateManager> chainman;
+    LOCK(opts.m_mutex);
+    opts.m_blockman_options.prune_target = prune_target;
+    return 0;
+}
+
+void btck_chainstate_manager_options_set_fast_prune(btck_ChainstateManagerOptions* chainman_opts, int fast_prune)
+{
+    auto& opts{btck_ChainstateManagerOptions::get(chainman_opts)};
+    LOCK(opts.m_mutex);
+    opts.m_blockman_options.fast_prune = fast_prune == 1;
+}
+
+void btck_chainstate_manager_options_set_block_tree_db_cache_size(btck_ChainstateManagerOptions* chainman_opts, size_t cache_size)
+{
+    auto& opts{btck_ChainstateManagerOptions::get(chainman_opts)};
+    LOCK(opts.m_mutex);
+    opts.m_cache_sizes.block_tree_db = cache_size;
+    opts.m_blockman_options.block_tree_db_params.cache_bytes = cache_size;
+}
+
+void btck_chainstate_manager_options_set_coins_db_cache_size(btck_ChainstateManagerOptions* chainman_opts, size_t cache_size)
+{
+    auto& opts{btck_ChainstateManagerOptions::get(chainman_opts)};
+    LOCK(opts.m_mutex);
+    opts.m_cache_sizes.coins_db = cache_size;
+}
+
+void btck_chainstate_manager_options_set_coins_cache_size(btck_ChainstateManagerOptions* chainman_opts, size_t cache_size)
+{
+    auto& opts{btck_ChainstateManagerOptions::get(chainman_opts)};
+    LOCK(opts.m_mutex);
+    opts.m_cache_sizes.coins = cache_size;
+}
+
+int btck_chainstate_manager_options_set_flush_interval(btck_ChainstateManagerOptions* chainman_opts, int64_t min_seconds, int64_t max_seconds)
+{
+    if (min_seconds <= 0 || max_seconds < min_seconds) {
+        LogError("Invalid flush interval between %d and %d seconds.", min_seconds, max_seconds);
+        return -1;
+    }
+    auto& opts{btck_ChainstateManagerOptions::get(chainman_opts)};
+    LOCK(opts.m_mutex);
+    opts.m_chainman_options.database_write_interval_min = std::chrono::seconds{min_seconds};
+    opts.m_chainman_options.database_write_interval_max = std::chrono::seconds{max_seconds};
+    return 0;
+}
+
+int btck_chainstate_manager_options_enable_mempool(btck_ChainstateManagerOptions* chainman_opts, int64_t max_size_bytes)
+{
+    if (max_size_bytes <= 0) {
+        LogError("Invalid mempool size of %d bytes.", max_size_bytes);
+        return -1;
+    }
+    auto& opts{btck_ChainstateManagerOptions::get(chainman_opts)};
+    LOCK(opts.m_mutex);
+    opts.m_mempool_options = kernel::MemPoolOptions{
+        .max_size_bytes = max_size_bytes,
+        .signals = opts.m_context->m_signals.get(),
+    };
+    return 0;
+}
+
+btck_ChainstateManager* btck_chainstate_manager_create(
+    const btck_ChainstateManagerOptions* chainman_opts)
+{
+    auto& opts{btck_ChainstateManagerOptions::get(chainman_opts)};
+    std::unique_ptr<CTxMemPool> mempool;
+    std::unique_ptr<ChainstateManager> chainman;
     try {
         LOCK(opts.m_mutex);
+        if (opts.m_mempool_options) {
+            bilingual_str mempool_error;
+            mempool = std::make_unique<CTxMemPool>(*opts.m_mempool_options, mempool_error);
+            if (!mempool_error.empty()) {
+                LogError("Failed to create mempool: %s", mempool_error.original);
+                return nullptr;
+            }
+        }
         chainman = std::make_unique<ChainstateManager>(*opts.m_context->m_interrupt, opts.m_chainman_options, opts.m_blockman_options);
     } catch (const std::exception& e) {
         LogError("Failed to create chainstate manager: %s", e.what());
@@ -968,9 +1720,9 @@ btck_ChainstateManager* btck_chainstate_manager_create(
     }
 
     try {
-        const auto chainstate_load_opts{WITH_LOCK(opts.m_mutex, return opts.m_chainstate_load_options)};
-
-        kernel::CacheSizes cache_sizes{DEFAULT_KERNEL_CACHE};
+        auto chainstate_load_opts{WITH_LOCK(opts.m_mutex, return opts.m_chainstate_load_options)};
+        chainstate_load_opts.mempool = mempool.get();
+        const auto cache_sizes{WITH_LOCK(opts.m_mutex, return opts.m_cache_sizes)};
         auto [status, chainstate_err]{node::LoadChainstate(*chainman, cache_sizes, chainstate_load_opts)};
         if (status != node::ChainstateLoadStatus::SUCCESS) {
             LogError("Failed to load chain state from your data directory: %s", chainstate_err.original);
@@ -994,7 +1746,8 @@ btck_ChainstateManager* btck_chainstate_manager_create(
         return nullptr;
     }
 
-    return btck_ChainstateManager::create(std::move(chainman), opts.m_context);
+    const bool coins_db_in_memory{WITH_LOCK(opts.m_mutex, return opts.m_chainstate_load_options.coins_db_in_memory)};
+    return btck_ChainstateManager::create(std::move(mempool), std::move(chainman), opts.m_context, coins_db_in_memory);
 }
 
 const btck_BlockTreeEntry* btck_chainstate_manager_get_block_tree_entry_by_hash(const btck_ChainstateManager* chainman, const btck_BlockHash* block_hash)
@@ -1008,6 +1761,73 @@ const btck_BlockTreeEntry* btck_chainstate_manager_get_block_tree_entry_by_hash(
     return btck_BlockTreeEntry::ref(block_index);
 }
 
+btck_Coin* btck_chainstate_manager_get_coin(const btck_ChainstateManager* chainman, const btck_TransactionOutPoint* out_point)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    LOCK(chainstate_manager.GetMutex());
+    std::optional<Coin> coin{chainstate_manager.ActiveChainstate().CoinsTip().GetCoin(btck_TransactionOutPoint::get(out_point))};
+    if (!coin) {
+        return nullptr;
+    }
+    return btck_Coin::create(std::move(*coin));
+}
+
+void btck_chainstate_manager_get_coins(const btck_ChainstateManager* chainman, const btck_TransactionOutPoint** out_points, size_t out_points_len, btck_Coin** coins)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    LOCK(chainstate_manager.GetMutex());
+    const CCoinsViewCache& coins_tip{chainstate_manager.ActiveChainstate().CoinsTip()};
+    for (size_t i = 0; i < out_points_len; i++) {
+        std::optional<Coin> coin{coins_tip.GetCoin(btck_TransactionOutPoint::get(out_points[i]))};
+        coins[i] = coin ? btck_Coin::create(std::move(*coin)) : nullptr;
+    }
+}
+
+int btck_chainstate_manager_flush(btck_ChainstateManager* chainman)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    LOCK(chainstate_manager.GetMutex());
+    for (Chainstate* chainstate : chainstate_manager.GetAll()) {
+        if (!chainstate->CanFlushToDisk()) {
+            continue;
+        }
+        BlockValidationState state;
+        if (!chainstate->FlushStateToDisk(state, FlushStateMode::ALWAYS)) {
+            chainstate_manager.GetNotifications().flushError(Untranslated(strprintf("Failed to flush chainstate: %s", state.ToString())));
+            return -1;
+        }
+    }
+    return 0;
+}
+
+int btck_chainstate_manager_prune_block_files(btck_ChainstateManager* chainman, int32_t height)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    if (!chainstate_manager.m_blockman.IsPruneMode()) {
+        LogError("Cannot prune blocks because pruning is not enabled.");
+        return -1;
+    }
+    Chainstate& chainstate{chainstate_manager.ActiveChainstate()};
+    int chain_height{WITH_LOCK(chainstate_manager.GetMutex(), return chainstate.m_chain.Height())};
+    if (chain_height < 0 || static_cast<uint64_t>(chain_height) < chainstate_manager.GetParams().PruneAfterHeight()) {
+        LogError("Blockchain is too short for pruning.");
+        return -1;
+    }
+    if (height <= 0 || height > chain_height) {
+        LogError("Prune height %d is out of range of the active chain.", height);
+        return -1;
+    }
+    PruneBlockFilesManual(chainstate, height);
+    return 0;
+}
+
+int btck_chainstate_manager_is_block_pruned(const btck_ChainstateManager* chainman, const btck_BlockTreeEntry* entry)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    LOCK(chainstate_manager.GetMutex());
+    return chainstate_manager.m_blockman.IsBlockPruned(btck_BlockTreeEntry::get(entry)) ? 1 : 0;
+}
+
 void btck_chainstate_manager_destroy(btck_ChainstateManager* chainman)
 {
     {
@@ -1092,11 +1912,95 @@ btck_BlockHash* btck_block_get_hash(const btck_Block* block)
     return btck_BlockHash::create(btck_Block::get(block)->GetHash());
 }
 
+btck_BlockHeader* btck_block_get_header(const btck_Block* block)
+{
+    return btck_BlockHeader::create(btck_Block::get(block)->GetBlockHeader());
+}
+
 void btck_block_destroy(btck_Block* block)
 {
     delete block;
 }
 
+btck_BlockHeader* btck_block_header_create(const void* raw_block_header, size_t raw_block_header_len)
+{
+    if (raw_block_header == nullptr && raw_block_header_len != 0) {
+        return nullptr;
+    }
+    CBlockHeader header;
+
+    DataStream stream{std::span{reinterpret_cast<const std::byte*>(raw_block_header), raw_block_header_len}};
+
+    try {
+        stream >> header;
+    } catch (...) {
+        LogDebug(BCLog::KERNEL, "Block header decode failed.");
+        return nullptr;
+    }
+    if (!stream.empty()) {
+        LogDebug(BCLog::KERNEL, "Block header decode failed: trailing data.");
+        return nullptr;
+    }
+
+    return btck_BlockHeader::create(header);
+}
+
+btck_BlockHeader* btck_block_header_copy(const btck_BlockHeader* block_header)
+{
+    return btck_BlockHeader::copy(block_header);
+}
+
+btck_BlockHash* btck_block_header_get_hash(const btck_BlockHeader* block_header)
+{
+    return btck_BlockHash::create(btck_BlockHeader::get(block_header).GetHash());
+}
+
+const btck_BlockHash* btck_block_header_get_prev_hash(const btck_BlockHeader* block_header)
+{
+    return btck_BlockHash::ref(&btck_BlockHeader::get(block_header).hashPrevBlock);
+}
+
+void btck_block_header_get_merkle_root(const btck_BlockHeader* block_header, unsigned char output[32])
+{
+    std::memcpy(output, btck_BlockHeader::get(block_header).hashMerkleRoot.begin(), 32);
+}
+
+int32_t btck_block_header_get_version(const btck_BlockHeader* block_header)
+{
+    return btck_BlockHeader::get(block_header).nVersion;
+}
+
+uint32_t btck_block_header_get_timestamp(const btck_BlockHeader* block_header)
+{
+    return btck_BlockHeader::get(block_header).nTime;
+}
+
+uint32_t btck_block_header_get_bits(const btck_BlockHeader* block_header)
+{
+    return btck_BlockHeader::get(block_header).nBits;
+}
+
+uint32_t btck_block_header_get_nonce(const btck_BlockHeader* block_header)
+{
+    return btck_BlockHeader::get(block_header).nNonce;
+}
+
+int btck_block_header_to_bytes(const btck_BlockHeader* block_header, btck_WriteBytes writer, void* user_data)
+{
+    try {
+        WriterStream ws{writer, user_data};
+        ws << btck_BlockHeader::get(block_header);
+        return 0;
+    } catch (...) {
+        return -1;
+    }
+}
+
+void btck_block_header_destroy(btck_BlockHeader* block_header)
+{
+    delete block_header;
+}
+
 btck_Block* btck_block_read(const btck_ChainstateManager* chainman, const btck_BlockTreeEntry* entry)
 {
     auto block{std::make_shared<CBlock>()};
@@ -1122,6 +2026,64 @@ int btck_block_tree_entry_equals(const btck_BlockTreeEntry* entry1, const btck_B
     return &btck_BlockTreeEntry::get(entry1) == &btck_BlockTreeEntry::get(entry2);
 }
 
+btck_BlockHeader* btck_block_tree_entry_get_block_header(const btck_BlockTreeEntry* entry)
+{
+    return btck_BlockHeader::create(btck_BlockTreeEntry::get(entry).GetBlockHeader());
+}
+
+void btck_block_tree_entry_get_chain_work(const btck_BlockTreeEntry* entry, unsigned char output[32])
+{
+    const uint256 chain_work{ArithToUint256(btck_BlockTreeEntry::get(entry).nChainWork)};
+    std::memcpy(output, chain_work.begin(), 32);
+}
+
+int64_t btck_block_tree_entry_get_median_time_past(const btck_BlockTreeEntry* entry)
+{
+    return btck_BlockTreeEntry::get(entry).GetMedianTimePast();
+}
+
+btck_BlockStatus btck_block_tree_entry_get_status(const btck_BlockTreeEntry* entry)
+{
+    const uint32_t status{WITH_LOCK(::cs_main, return btck_BlockTreeEntry::get(entry).nStatus)};
+    btck_BlockStatus result{0};
+    if (status & BLOCK_HAVE_DATA) result |= btck_BlockStatus_HAVE_DATA;
+    if (status & BLOCK_HAVE_UNDO) result |= btck_BlockStatus_HAVE_UNDO;
+    if (status & BLOCK_FAILED_VALID) result |= btck_BlockStatus_FAILED_VALID;
+    if (status & BLOCK_FAILED_CHILD) result |= btck_BlockStatus_FAILED_CHILD;
+    return result;
+}
+
+const btck_BlockTreeEntry* btck_block_tree_entry_find_fork(const btck_BlockTreeEntry* entry1, const btck_BlockTreeEntry* entry2)
+{
+    return btck_BlockTreeEntry::ref(LastCommonAncestor(&btck_BlockTreeEntry::get(entry1), &btck_BlockTreeEntry::get(entry2)));
+}
+
+const btck_BlockTreeEntry* btck_block_tree_entry_get_ancestor(const btck_BlockTreeEntry* entry, int32_t height)
+{
+    return btck_BlockTreeEntry::ref(btck_BlockTreeEntry::get(entry).GetAncestor(height));
+}
+
+btck_BlockValidity btck_block_tree_entry_get_validity(const btck_BlockTreeEntry* entry)
+{
+    LOCK(::cs_main);
+    return static_cast<btck_BlockValidity>(btck_BlockTreeEntry::get(entry).nStatus & BLOCK_VALID_MASK);
+}
+
+int btck_block_tree_entry_is_valid(const btck_BlockTreeEntry* entry, btck_BlockValidity validity)
+{
+    switch (validity) {
+    case btck_BlockValidity_UNKNOWN:
+    case btck_BlockValidity_TREE:
+    case btck_BlockValidity_TRANSACTIONS:
+    case btck_BlockValidity_CHAIN:
+    case btck_BlockValidity_SCRIPTS: {
+        LOCK(::cs_main);
+        return btck_BlockTreeEntry::get(entry).IsValid(static_cast<BlockStatus>(validity)) ? 1 : 0;
+    }
+    }
+    assert(false);
+}
+
 btck_BlockHash* btck_block_hash_create(const unsigned char block_hash[32])
 {
     return btck_BlockHash::create(std::span<const unsigned char>{block_hash, 32});
@@ -1161,6 +2123,40 @@ btck_BlockSpentOutputs* btck_block_spent_outputs_read(const btck_ChainstateManag
     return btck_BlockSpentOutputs::create(block_undo);
 }
 
+btck_BlockSpentOutputs* btck_block_spent_outputs_create(const void* raw_block_spent_outputs, size_t raw_block_spent_outputs_len)
+{
+    if (raw_block_spent_outputs == nullptr && raw_block_spent_outputs_len != 0) {
+        return nullptr;
+    }
+    auto block_undo{std::make_shared<CBlockUndo>()};
+
+    DataStream stream{std::span{reinterpret_cast<const std::byte*>(raw_block_spent_outputs), raw_block_spent_outputs_len}};
+
+    try {
+        stream >> *block_undo;
+    } catch (...) {
+        LogDebug(BCLog::KERNEL, "Block spent outputs decode failed.");
+        return nullptr;
+    }
+    if (!stream.empty()) {
+        LogDebug(BCLog::KERNEL, "Block spent outputs decode failed: trailing data.");
+        return nullptr;
+    }
+
+    return btck_BlockSpentOutputs::create(block_undo);
+}
+
+int btck_block_spent_outputs_to_bytes(const btck_BlockSpentOutputs* block_spent_outputs, btck_WriteBytes writer, void* user_data)
+{
+    try {
+        WriterStream ws{writer, user_data};
+        ws << *btck_BlockSpentOutputs::get(block_spent_outputs);
+        return 0;
+    } catch (...) {
+        return -1;
+    }
+}
+
 btck_BlockSpentOutputs* btck_block_spent_outputs_copy(const btck_BlockSpentOutputs* block_spent_outputs)
 {
     return btck_BlockSpentOutputs::copy(block_spent_outputs);
@@ -1230,38 +2226,914 @@ void btck_coin_destroy(btck_Coin* coin)
     delete coin;
 }
 
-int btck_chainstate_manager_process_block(
-    btck_ChainstateManager* chainman,
-    const btck_Block* block,
-    int* _new_block)
+namespace {
+//! Flush the active chainstate, so that its coins database reflects all connected blocks.
+Chainstate& FlushActiveChainstate(ChainstateManager& chainman) EXCLUSIVE_LOCKS_REQUIRED(::cs_main)
 {
-    bool new_block;
-    auto result = btck_ChainstateManager::get(chainman).m_chainman->ProcessNewBlock(btck_Block::get(block), /*force_processing=*/true, /*min_pow_checked=*/true, /*new_block=*/&new_block);
-    if (_new_block) {
-        *_new_block = new_block ? 1 : 0;
+    Chainstate& chainstate{chainman.ActiveChainstate()};
+    if (chainstate.CanFlushToDisk()) {
+        chainstate.ForceFlushStateToDisk();
     }
-    return result ? 0 : -1;
+    return chainstate;
 }
+} // namespace
 
-const btck_Chain* btck_chainstate_manager_get_active_chain(const btck_ChainstateManager* chainman)
+btck_CoinsCursor* btck_coins_cursor_create(btck_ChainstateManager* chainman)
 {
-    return btck_Chain::ref(&WITH_LOCK(btck_ChainstateManager::get(chainman).m_chainman->GetMutex(), return btck_ChainstateManager::get(chainman).m_chainman->ActiveChain()));
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    try {
+        LOCK(chainstate_manager.GetMutex());
+        std::unique_ptr<CCoinsViewCursor> cursor{FlushActiveChainstate(chainstate_manager).CoinsDB().Cursor()};
+        if (!cursor) {
+            LogError("Failed to create a cursor over the coins database.");
+            return nullptr;
+        }
+        return btck_CoinsCursor::create(std::move(cursor));
+    } catch (const std::exception& e) {
+        LogError("Failed to create coins cursor: %s", e.what());
+        return nullptr;
+    }
 }
 
-int btck_chain_get_height(const btck_Chain* chain)
+btck_BlockHash* btck_coins_cursor_get_block_hash(const btck_CoinsCursor* coins_cursor)
 {
-    LOCK(::cs_main);
-    return btck_Chain::get(chain).Height();
+    return btck_BlockHash::create(btck_CoinsCursor::get(coins_cursor)->GetBestBlock());
 }
 
-const btck_BlockTreeEntry* btck_chain_get_by_height(const btck_Chain* chain, int height)
+int btck_coins_cursor_next(btck_CoinsCursor* coins_cursor, btck_TransactionOutPoint** out_point, btck_Coin** coin)
 {
-    LOCK(::cs_main);
-    return btck_BlockTreeEntry::ref(btck_Chain::get(chain)[height]);
+    auto& cursor{*btck_CoinsCursor::get(coins_cursor)};
+    if (!cursor.Valid()) {
+        return 0;
+    }
+    COutPoint key;
+    Coin value;
+    if (!cursor.GetKey(key) || !cursor.GetValue(value)) {
+        LogError("Failed to read coin from the coins database.");
+        return -1;
+    }
+    cursor.Next();
+    *out_point = btck_TransactionOutPoint::create(key);
+    *coin = btck_Coin::create(std::move(value));
+    return 1;
 }
 
-int btck_chain_contains(const btck_Chain* chain, const btck_BlockTreeEntry* entry)
+void btck_coins_cursor_destroy(btck_CoinsCursor* coins_cursor)
 {
-    LOCK(::cs_main);
-    return btck_Chain::get(chain).Contains(&btck_BlockTreeEntry::get(entry)) ? 1 : 0;
+    delete coins_cursor;
+}
+
+btck_UtxoSetStats* btck_chainstate_manager_get_utxo_set_stats(btck_ChainstateManager* chainman)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    try {
+        UtxoSetStats result;
+        std::unique_ptr<CCoinsViewCursor> cursor;
+        {
+            // Cursors over the coins database iterate over a snapshot of it, so
+            // the lock is only required until the cursor is created.
+            LOCK(chainstate_manager.GetMutex());
+            CCoinsViewDB& coins_db{FlushActiveChainstate(chainstate_manager).CoinsDB()};
+            cursor = coins_db.Cursor();
+            const CBlockIndex* best_block{cursor ? chainstate_manager.m_blockman.LookupBlockIndex(cursor->GetBestBlock()) : nullptr};
+            if (!best_block) {
+                LogError("Failed to read the UTXO set.");
+                return nullptr;
+            }
+            result.stats = kernel::CCoinsStats{best_block->nHeight, best_block->GetBlockHash()};
+            result.stats.nDiskSize = coins_db.EstimateSize();
+        }
+
+        // Both hashes are computed in the same pass over the coins, applying
+        // them per transaction in output order like kernel::ComputeUTXOStats.
+        HashWriter hash_serialized{};
+        MuHash3072 muhash;
+        auto& stats{result.stats};
+        auto apply_outputs = [&](const Txid& txid, const std::map<uint32_t, Coin>& outputs) {
+            ++stats.nTransactions;
+            for (const auto& [n, coin] : outputs) {
+                const COutPoint outpoint{txid, n};
+                hash_serialized << outpoint << static_cast<uint32_t>((coin.nHeight << 1) + coin.fCoinBase) << coin.out;
+                kernel::ApplyCoinHash(muhash, outpoint, coin);
+                ++stats.nTransactionOutputs;
+                if (stats.total_amount) {
+                    stats.total_amount = CheckedAdd(*stats.total_amount, coin.out.nValue);
+                }
+                stats.nBogoSize += kernel::GetBogoSize(coin.out.scriptPubKey);
+            }
+        };
+
+        COutPoint key;
+        Coin coin;
+        Txid last_hash;
+        std::map<uint32_t, Coin> outputs;
+        for (; cursor->Valid(); cursor->Next()) {
+            if (!cursor->GetKey(key) || !cursor->GetValue(coin)) {
+                LogError("Failed to read coin from the coins database.");
+                return nullptr;
+            }
+            if (!outputs.empty() && key.hash != last_hash) {
+                apply_outputs(last_hash, outputs);
+                outputs.clear();
+            }
+            last_hash = key.hash;
+            outputs[key.n] = std::move(coin);
+            ++stats.coins_count;
+        }
+        if (!outputs.empty()) {
+            apply_outputs(last_hash, outputs);
+        }
+
+        result.hash_serialized = hash_serialized.GetHash();
+        muhash.Finalize(result.muhash);
+        return btck_UtxoSetStats::create(std::move(result));
+    } catch (const std::exception& e) {
+        LogError("Failed to compute UTXO set statistics: %s", e.what());
+        return nullptr;
+    }
+}
+
+int32_t btck_utxo_set_stats_get_height(const btck_UtxoSetStats* utxo_set_stats)
+{
+    return btck_UtxoSetStats::get(utxo_set_stats).stats.nHeight;
+}
+
+btck_BlockHash* btck_utxo_set_stats_get_block_hash(const btck_UtxoSetStats* utxo_set_stats)
+{
+    return btck_BlockHash::create(btck_UtxoSetStats::get(utxo_set_stats).stats.hashBlock);
+}
+
+uint64_t btck_utxo_set_stats_get_transaction_count(const btck_UtxoSetStats* utxo_set_stats)
+{
+    return btck_UtxoSetStats::get(utxo_set_stats).stats.nTransactions;
+}
+
+uint64_t btck_utxo_set_stats_get_coin_count(const btck_UtxoSetStats* utxo_set_stats)
+{
+    return btck_UtxoSetStats::get(utxo_set_stats).stats.coins_count;
+}
+
+int btck_utxo_set_stats_get_total_amount(const btck_UtxoSetStats* utxo_set_stats, int64_t* total_amount)
+{
+    const auto& amount{btck_UtxoSetStats::get(utxo_set_stats).stats.total_amount};
+    if (!amount) {
+        return -1;
+    }
+    *total_amount = *amount;
+    return 0;
+}
+
+uint64_t btck_utxo_set_stats_get_bogo_size(const btck_UtxoSetStats* utxo_set_stats)
+{
+    return btck_UtxoSetStats::get(utxo_set_stats).stats.nBogoSize;
+}
+
+uint64_t btck_utxo_set_stats_get_disk_size(const btck_UtxoSetStats* utxo_set_stats)
+{
+    return btck_UtxoSetStats::get(utxo_set_stats).stats.nDiskSize;
+}
+
+void btck_utxo_set_stats_get_muhash(const btck_UtxoSetStats* utxo_set_stats, unsigned char output[32])
+{
+    std::memcpy(output, btck_UtxoSetStats::get(utxo_set_stats).muhash.begin(), 32);
+}
+
+void btck_utxo_set_stats_get_hash_serialized(const btck_UtxoSetStats* utxo_set_stats, unsigned char output[32])
+{
+    std::memcpy(output, btck_UtxoSetStats::get(utxo_set_stats).hash_serialized.begin(), 32);
+}
+
+void btck_utxo_set_stats_destroy(btck_UtxoSetStats* utxo_set_stats)
+{
+    delete utxo_set_stats;
+}
+
+int btck_chainstate_manager_dump_snapshot(btck_ChainstateManager* chainman, btck_WriteBytes writer, void* user_data)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    try {
+        std::optional<kernel::CCoinsStats> stats;
+        std::unique_ptr<CCoinsViewCursor> cursor;
+        {
+            // Cursors over the coins database iterate over a snapshot of it, so
+            // the lock is only required until the cursor is created.
+            LOCK(chainstate_manager.GetMutex());
+            CCoinsViewDB& coins_db{FlushActiveChainstate(chainstate_manager).CoinsDB()};
+            stats = kernel::ComputeUTXOStats(kernel::CoinStatsHashType::NONE, &coins_db, chainstate_manager.m_blockman);
+            cursor = coins_db.Cursor();
+        }
+        if (!stats || !cursor) {
+            LogError("Failed to read the UTXO set.");
+            return -1;
+        }
+
+        WriterStream ws{writer, user_data};
+        ws << node::SnapshotMetadata{chainstate_manager.GetParams().MessageStart(), stats->hashBlock, stats->coins_count};
+
+        // Coins are grouped by transaction, making use of the coins database
+        // being sorted by out point.
+        COutPoint key;
+        Coin coin;
+        Txid last_hash;
+        std::vector<std::pair<uint32_t, Coin>> coins;
+        uint64_t written_coins_count{0};
+        auto write_coins = [&]() {
+            ws << last_hash;
+            WriteCompactSize(ws, coins.size());
+            for (const auto& [n, tx_coin] : coins) {
+                WriteCompactSize(ws, n);
+                ws << tx_coin;
+                ++written_coins_count;
+            }
+            coins.clear();
+        };
+        for (; cursor->Valid(); cursor->Next()) {
+            if (!cursor->GetKey(key) || !cursor->GetValue(coin)) {
+                LogError("Failed to read coin from the coins database.");
+                return -1;
+            }
+            if (!coins.empty() && key.hash != last_hash) {
+                write_coins();
+            }
+            last_hash = key.hash;
+            coins.emplace_back(key.n, std::move(coin));
+        }
+        if (!coins.empty()) {
+            write_coins();
+        }
+
+        if (written_coins_count != stats->coins_count) {
+            LogError("Wrote %d coins to the snapshot, expected %d.", written_coins_count, stats->coins_count);
+            return -1;
+        }
+        return 0;
+    } catch (const std::exception& e) {
+        LogError("Failed to dump snapshot: %s", e.what());
+        return -1;
+    }
+}
+
+namespace {
+int LoadSnapshotFile(btck_ChainstateManager* chainman, const fs::path& path)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    try {
+        AutoFile coins_file{fsbridge::fopen(path, "rb")};
+        if (coins_file.IsNull()) {
+            LogError("Failed to open snapshot file %s.", fs::PathToString(path));
+            return -1;
+        }
+        node::SnapshotMetadata metadata{chainstate_manager.GetParams().MessageStart()};
+        coins_file >> metadata;
+
+        auto result{chainstate_manager.ActivateSnapshot(coins_file, metadata, btck_ChainstateManager::get(chainman).m_coins_db_in_memory)};
+        if (!result) {
+            LogError("Failed to activate snapshot: %s", util::ErrorString(result).original);
+            return -1;
+        }
+        return 0;
+    } catch (const std::exception& e) {
+        LogError("Failed to load snapshot: %s", e.what());
+        return -1;
+    }
+}
+} // namespace
+
+int btck_chainstate_manager_load_snapshot(btck_ChainstateManager* chainman, const char* snapshot_path, size_t snapshot_path_len)
+{
+    return LoadSnapshotFile(chainman, fs::PathFromString({snapshot_path, snapshot_path_len}));
+}
+
+int btck_chainstate_manager_load_snapshot_from_reader(btck_ChainstateManager* chainman, btck_ReadBytes reader, void* user_data)
+{
+    const fs::path temppath{btck_ChainstateManager::get(chainman).m_chainman->m_options.datadir / "utxo-snapshot.dat.incomplete"};
+    auto copy_snapshot = [&]() {
+        AutoFile file{fsbridge::fopen(temppath, "wb")};
+        if (file.IsNull()) {
+            LogError("Failed to create temporary snapshot file %s.", fs::PathToString(temppath));
+            return false;
+        }
+        std::vector<std::byte> buffer(1 << 20);
+        size_t read;
+        do {
+            if (reader(buffer.data(), buffer.size(), &read, user_data) != 0) {
+                LogError("Failed to read snapshot data.");
+                (void)file.fclose();
+                return false;
+            }
+            file.write(std::span{buffer}.first(std::min(read, buffer.size())));
+        } while (read > 0);
+        if (file.fclose() != 0) {
+            LogError("Failed to write temporary snapshot file %s.", fs::PathToString(temppath));
+            return false;
+        }
+        return true;
+    };
+
+    int result{-1};
+    try {
+        if (copy_snapshot()) {
+            result = LoadSnapshotFile(chainman, temppath);
+        }
+    } catch (const std::exception& e) {
+        LogError("Failed to copy snapshot: %s", e.what());
+    }
+    std::error_code ec;
+    fs::remove(temppath, ec);
+    return result;
+}
+
+const btck_BlockTreeEntry* btck_chainstate_manager_get_snapshot_base_block(const btck_ChainstateManager* chainman)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    const CBlockIndex* base{WITH_LOCK(chainstate_manager.GetMutex(), return chainstate_manager.GetSnapshotBaseBlock())};
+    if (!base) {
+        return nullptr;
+    }
+    return btck_BlockTreeEntry::ref(base);
+}
+
+const btck_BlockTreeEntry* btck_chainstate_manager_get_background_validation_tip(const btck_ChainstateManager* chainman)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    const CBlockIndex* tip{WITH_LOCK(chainstate_manager.GetMutex(), return chainstate_manager.GetBackgroundSyncTip())};
+    if (!tip) {
+        return nullptr;
+    }
+    return btck_BlockTreeEntry::ref(tip);
+}
+
+int btck_chainstate_manager_process_block(
+    btck_ChainstateManager* chainman,
+    const btck_Block* block,
+    int* _new_block)
+{
+    bool new_block;
+    auto result = btck_ChainstateManager::get(chainman).m_chainman->ProcessNewBlock(btck_Block::get(block), /*force_processing=*/true, /*min_pow_checked=*/true, /*new_block=*/&new_block);
+    if (_new_block) {
+        *_new_block = new_block ? 1 : 0;
+    }
+    return result ? 0 : -1;
+}
+
+int btck_chainstate_manager_process_block_headers(
+    btck_ChainstateManager* chainman,
+    const btck_BlockHeader** block_headers,
+    size_t block_headers_len,
+    btck_BlockValidationState* block_validation_state)
+{
+    std::vector<CBlockHeader> headers;
+    headers.reserve(block_headers_len);
+    for (size_t i = 0; i < block_headers_len; ++i) {
+        headers.push_back(btck_BlockHeader::get(block_headers[i]));
+    }
+    auto& state{btck_BlockValidationState::get(block_validation_state)};
+    state = BlockValidationState{};
+    return btck_ChainstateManager::get(chainman).m_chainman->ProcessNewBlockHeaders(headers, /*min_pow_checked=*/true, state) ? 0 : -1;
+}
+
+int btck_chainstate_manager_invalidate_block(btck_ChainstateManager* chainman, const btck_BlockTreeEntry* entry)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    CBlockIndex* block_index{WITH_LOCK(chainstate_manager.GetMutex(), return chainstate_manager.m_blockman.LookupBlockIndex(btck_BlockTreeEntry::get(entry).GetBlockHash()))};
+    BlockValidationState state;
+    chainstate_manager.ActiveChainstate().InvalidateBlock(state, block_index);
+    if (state.IsValid()) {
+        chainstate_manager.ActiveChainstate().ActivateBestChain(state);
+    }
+    if (!state.IsValid()) {
+        LogError("Failed to invalidate block: %s", state.ToString());
+        return -1;
+    }
+    return 0;
+}
+
+int btck_chainstate_manager_reconsider_block(btck_ChainstateManager* chainman, const btck_BlockTreeEntry* entry)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    {
+        LOCK(chainstate_manager.GetMutex());
+        CBlockIndex* block_index{chainstate_manager.m_blockman.LookupBlockIndex(btck_BlockTreeEntry::get(entry).GetBlockHash())};
+        chainstate_manager.ActiveChainstate().ResetBlockFailureFlags(block_index);
+        chainstate_manager.RecalculateBestHeader();
+    }
+    BlockValidationState state;
+    chainstate_manager.ActiveChainstate().ActivateBestChain(state);
+    if (!state.IsValid()) {
+        LogError("Failed to reconsider block: %s", state.ToString());
+        return -1;
+    }
+    return 0;
+}
+
+int btck_chainstate_manager_precious_block(btck_ChainstateManager* chainman, const btck_BlockTreeEntry* entry)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    CBlockIndex* block_index{WITH_LOCK(chainstate_manager.GetMutex(), return chainstate_manager.m_blockman.LookupBlockIndex(btck_BlockTreeEntry::get(entry).GetBlockHash()))};
+    BlockValidationState state;
+    chainstate_manager.ActiveChainstate().PreciousBlock(state, block_index);
+    if (!state.IsValid()) {
+        LogError("Failed to mark block as precious: %s", state.ToString());
+        return -1;
+    }
+    return 0;
+}
+
+btck_ChainTips* btck_chainstate_manager_get_chain_tips(const btck_ChainstateManager* chainman)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    LOCK(chainstate_manager.GetMutex());
+    const CChain& active_chain{chainstate_manager.ActiveChain()};
+
+    // Tips are the blocks outside of the active chain that are not the parent
+    // of another block, plus the tip of the active chain itself.
+    std::set<const CBlockIndex*> orphans;
+    std::set<const CBlockIndex*> prevs;
+    for (const auto& [_, block_index] : chainstate_manager.BlockIndex()) {
+        if (!active_chain.Contains(&block_index)) {
+            orphans.insert(&block_index);
+            prevs.insert(block_index.pprev);
+        }
+    }
+    std::vector<const CBlockIndex*> tips;
+    for (const CBlockIndex* orphan : orphans) {
+        if (!prevs.contains(orphan)) tips.push_back(orphan);
+    }
+    if (active_chain.Tip()) tips.push_back(active_chain.Tip());
+    std::sort(tips.begin(), tips.end(), [](const CBlockIndex* a, const CBlockIndex* b) {
+        return a->nHeight != b->nHeight ? a->nHeight > b->nHeight : a > b;
+    });
+
+    std::vector<ChainTip> chain_tips;
+    chain_tips.reserve(tips.size());
+    for (const CBlockIndex* tip : tips) {
+        btck_ChainTipStatus status;
+        if (active_chain.Contains(tip)) {
+            status = btck_ChainTipStatus_ACTIVE;
+        } else if (tip->nStatus & BLOCK_FAILED_MASK) {
+            status = btck_ChainTipStatus_INVALID;
+        } else if (!tip->HaveNumChainTxs()) {
+            status = btck_ChainTipStatus_HEADERS_ONLY;
+        } else if (tip->IsValid(BLOCK_VALID_SCRIPTS)) {
+            status = btck_ChainTipStatus_VALID_FORK;
+        } else if (tip->IsValid(BLOCK_VALID_TREE)) {
+            status = btck_ChainTipStatus_VALID_HEADERS;
+        } else {
+            status = btck_ChainTipStatus_UNKNOWN;
+        }
+        chain_tips.push_back({tip, status, tip->nHeight - active_chain.FindFork(tip)->nHeight});
+    }
+    return btck_ChainTips::create(std::move(chain_tips));
+}
+
+size_t btck_chain_tips_count(const btck_ChainTips* chain_tips)
+{
+    return btck_ChainTips::get(chain_tips).size();
+}
+
+const btck_BlockTreeEntry* btck_chain_tips_get_entry_at(const btck_ChainTips* chain_tips, size_t index)
+{
+    return btck_BlockTreeEntry::ref(btck_ChainTips::get(chain_tips).at(index).index);
+}
+
+btck_ChainTipStatus btck_chain_tips_get_status_at(const btck_ChainTips* chain_tips, size_t index)
+{
+    return btck_ChainTips::get(chain_tips).at(index).status;
+}
+
+int32_t btck_chain_tips_get_branch_length_at(const btck_ChainTips* chain_tips, size_t index)
+{
+    return btck_ChainTips::get(chain_tips).at(index).branch_length;
+}
+
+void btck_chain_tips_destroy(btck_ChainTips* chain_tips)
+{
+    delete chain_tips;
+}
+
+const btck_Chain* btck_chainstate_manager_get_active_chain(const btck_ChainstateManager* chainman)
+{
+    return btck_Chain::ref(&WITH_LOCK(btck_ChainstateManager::get(chainman).m_chainman->GetMutex(), return btck_ChainstateManager::get(chainman).m_chainman->ActiveChain()));
+}
+
+const btck_BlockTreeEntry* btck_chainstate_manager_get_best_header(const btck_ChainstateManager* chainman)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    return btck_BlockTreeEntry::ref(WITH_LOCK(chainstate_manager.GetMutex(), return chainstate_manager.m_best_header));
+}
+
+btck_Mempool* btck_chainstate_manager_get_mempool(const btck_ChainstateManager* chainman)
+{
+    auto& mempool{btck_ChainstateManager::get(chainman).m_mempool};
+    if (!mempool) return nullptr;
+    return btck_Mempool::ref(mempool.get());
+}
+
+int btck_chainstate_manager_process_transaction(btck_ChainstateManager* chainman, const btck_Transaction* transaction, btck_TxValidationState* tx_validation_state)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    const auto& tx{btck_Transaction::get(transaction)};
+    auto& state{btck_TxValidationState::get(tx_validation_state)};
+    state = TxValidationState{};
+
+    auto& mempool{btck_ChainstateManager::get(chainman).m_mempool};
+    if (mempool && mempool->exists(tx->GetWitnessHash())) return 0;
+
+    const MempoolAcceptResult result{WITH_LOCK(::cs_main, return chainstate_manager.ProcessTransaction(tx))};
+    switch (result.m_result_type) {
+    case MempoolAcceptResult::ResultType::VALID:
+    case MempoolAcceptResult::ResultType::MEMPOOL_ENTRY:
+    // A transaction with the same txid but a different witness is in the mempool
+    case MempoolAcceptResult::ResultType::DIFFERENT_WITNESS:
+        return 0;
+    case MempoolAcceptResult::ResultType::INVALID:
+        state = result.m_state;
+        return -1;
+    } // no default case, so the compiler can warn about missing cases
+    assert(false);
+}
+
+int btck_chainstate_manager_check_transaction_inputs(const btck_ChainstateManager* chainman, const btck_Transaction* transaction, btck_TxValidationState* tx_validation_state)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    const CTransaction& tx{*btck_Transaction::get(transaction)};
+    auto& state{btck_TxValidationState::get(tx_validation_state)};
+    state = TxValidationState{};
+
+    // Follows the contextual checks of MemPoolAccept::PreChecks
+    if (tx.IsCoinBase()) {
+        state.Invalid(TxValidationResult::TX_CONSENSUS, "coinbase");
+        return -1;
+    }
+
+    LOCK(::cs_main);
+    Chainstate& chainstate{chainstate_manager.ActiveChainstate()};
+    CBlockIndex* tip{chainstate.m_chain.Tip()};
+    if (!tip) {
+        LogError("Cannot check transaction inputs without an active chain tip.");
+        state.Error("no-chain-tip");
+        return -1;
+    }
+    if (!CheckFinalTxAtTip(*tip, tx)) {
+        state.Invalid(TxValidationResult::TX_PREMATURE_SPEND, "non-final");
+        return -1;
+    }
+
+    CCoinsViewCache view{&chainstate.CoinsTip()};
+    for (const CTxIn& txin : tx.vin) {
+        if (!view.HaveCoin(txin.prevout)) {
+            state.Invalid(TxValidationResult::TX_MISSING_INPUTS, "bad-txns-inputs-missingorspent");
+            return -1;
+        }
+    }
+
+    const std::optional<LockPoints> lock_points{CalculateLockPointsAtTip(tip, view, tx)};
+    if (!lock_points.has_value() || !CheckSequenceLocksAtTip(tip, *lock_points)) {
+        state.Invalid(TxValidationResult::TX_PREMATURE_SPEND, "non-BIP68-final");
+        return -1;
+    }
+
+    CAmount fee;
+    if (!Consensus::CheckTxInputs(tx, state, view, tip->nHeight + 1, fee)) {
+        return -1;
+    }
+    return 0;
+}
+
+btck_BlockTemplate* btck_chainstate_manager_create_block_template(const btck_ChainstateManager* chainman_, const btck_ScriptPubkey* coinbase_output_script, const btck_BlockTemplateOptions* block_template_options)
+{
+    const auto& chainman{btck_ChainstateManager::get(chainman_)};
+    auto& chainstate_manager{*chainman.m_chainman};
+    const auto& options{btck_BlockTemplateOptions::get(block_template_options)};
+    const CTxMemPool* mempool{options.use_mempool ? chainman.m_mempool.get() : nullptr};
+    const auto& consensus_params{chainstate_manager.GetConsensus()};
+
+    // Follows node::BlockAssembler::CreateNewBlock, which is not part of the kernel library
+    auto block{std::make_shared<CBlock>()};
+    block->vtx.emplace_back(); // placeholder for the coinbase transaction
+    // Reserve space for the block header, transaction count and coinbase transaction
+    uint64_t block_weight{DEFAULT_BLOCK_RESERVED_WEIGHT};
+    int64_t block_sigops_cost{400};
+    CAmount fees{0};
+
+    LOCK(::cs_main);
+    CBlockIndex* tip{chainstate_manager.ActiveChain().Tip()};
+    if (!tip) {
+        LogError("Cannot create a block template without an active chain tip.");
+        return nullptr;
+    }
+    const int height{tip->nHeight + 1};
+    block->nVersion = chainstate_manager.m_versionbitscache.ComputeBlockVersion(tip, consensus_params);
+    const int64_t lock_time_cutoff{tip->GetMedianTimePast()};
+
+    if (mempool) {
+        // Limit the attempts to add chunks once the block is close to full
+        constexpr int64_t MAX_CONSECUTIVE_FAILURES{1000};
+        constexpr int32_t BLOCK_FULL_ENOUGH_WEIGHT_DELTA{4000};
+        int64_t consecutive_failures{0};
+
+        LOCK(mempool->cs);
+        mempool->StartBlockBuilding();
+        std::vector<CTxMemPoolEntry::CTxMemPoolEntryRef> chunk;
+        for (FeePerWeight chunk_feerate{mempool->GetBlockBuilderChunk(chunk)}; !chunk.empty(); chunk.clear(), chunk_feerate = mempool->GetBlockBuilderChunk(chunk)) {
+            // All remaining chunks have a lower fee rate
+            if (ToFeePerVSize(chunk_feerate) << options.min_fee_rate.GetFeePerVSize()) break;
+
+            int64_t chunk_sigops_cost{0};
+            bool chunk_final{true};
+            for (const CTxMemPoolEntry& entry : chunk) {
+                chunk_sigops_cost += entry.GetSigOpCost();
+                chunk_final = chunk_final && IsFinalTx(entry.GetTx(), height, lock_time_cutoff);
+            }
+            if (block_weight + chunk_feerate.size >= options.max_weight ||
+                block_sigops_cost + chunk_sigops_cost >= MAX_BLOCK_SIGOPS_COST || !chunk_final) {
+                mempool->SkipBuilderChunk();
+                if (++consecutive_failures > MAX_CONSECUTIVE_FAILURES && block_weight + BLOCK_FULL_ENOUGH_WEIGHT_DELTA > options.max_weight) break;
+                continue;
+            }
+            mempool->IncludeBuilderChunk();
+            consecutive_failures = 0;
+            for (const CTxMemPoolEntry& entry : chunk) {
+                block->vtx.emplace_back(entry.GetSharedTx());
+                block_weight += entry.GetTxWeight();
+                fees += entry.GetFee();
+            }
+            block_sigops_cost += chunk_sigops_cost;
+        }
+        mempool->StopBlockBuilding();
+    }
+
+    CMutableTransaction coinbase_tx;
+    coinbase_tx.vin.resize(1);
+    coinbase_tx.vin[0].prevout.SetNull();
+    coinbase_tx.vin[0].nSequence = CTxIn::MAX_SEQUENCE_NONFINAL; // Make sure timelock is enforced.
+    coinbase_tx.vin[0].scriptSig = CScript() << height << OP_0;
+    coinbase_tx.vout.resize(1);
+    coinbase_tx.vout[0].scriptPubKey = btck_ScriptPubkey::get(coinbase_output_script);
+    coinbase_tx.vout[0].nValue = fees + GetBlockSubsidy(height, consensus_params);
+    coinbase_tx.nLockTime = static_cast<uint32_t>(height - 1);
+    block->vtx[0] = MakeTransactionRef(std::move(coinbase_tx));
+    chainstate_manager.GenerateCoinbaseCommitment(*block, tip);
+
+    // Follows node::GetMinimumTime, which accounts for the BIP94 timewarp rule on all networks
+    int64_t min_time{tip->GetMedianTimePast() + 1};
+    if (height % consensus_params.DifficultyAdjustmentInterval() == 0) {
+        min_time = std::max<int64_t>(min_time, tip->GetBlockTime() - MAX_TIMEWARP);
+    }
+    const int64_t now{TicksSinceEpoch<std::chrono::seconds>(NodeClock::now())};
+
+    block->hashPrevBlock = tip->GetBlockHash();
+    block->nTime = static_cast<uint32_t>(std::max(min_time, now));
+    block->nBits = GetNextWorkRequired(tip, block.get(), consensus_params);
+    block->nNonce = 0;
+    block->hashMerkleRoot = BlockMerkleRoot(*block);
+
+    if (const auto state{TestBlockValidity(chainstate_manager.ActiveChainstate(), *block, /*check_pow=*/false, /*check_merkle_root=*/true)}; !state.IsValid()) {
+        LogError("Failed to create block template: %s", state.ToString());
+        return nullptr;
+    }
+    return btck_BlockTemplate::create(BlockTemplate{std::move(block), min_time, now + MAX_FUTURE_BLOCK_TIME, fees});
+}
+
+int btck_chainstate_manager_test_block_validity(const btck_ChainstateManager* chainman, const btck_Block* block, btck_BlockValidationState* block_validation_state)
+{
+    auto& chainstate_manager{*btck_ChainstateManager::get(chainman).m_chainman};
+    auto& state{btck_BlockValidationState::get(block_validation_state)};
+    LOCK(::cs_main);
+    state = TestBlockValidity(chainstate_manager.ActiveChainstate(), *btck_Block::get(block), /*check_pow=*/false, /*check_merkle_root=*/true);
+    return state.IsValid() ? 0 : -1;
+}
+
+namespace {
+TxMempoolInfo get_mempool_info(const CTxMemPoolEntry& entry)
+{
+    return TxMempoolInfo{entry.GetSharedTx(), entry.GetTime(), entry.GetFee(), entry.GetTxSize(), entry.GetModifiedFee() - entry.GetFee()};
+}
+
+//! Returns the infos of the entries in topological order, like CTxMemPool::infoAll
+std::vector<TxMempoolInfo> sorted_mempool_infos(const CTxMemPool& mempool, const CTxMemPool::setEntries& entries) EXCLUSIVE_LOCKS_REQUIRED(mempool.cs)
+{
+    std::set<Txid> txids;
+    for (const auto& it : entries) {
+        txids.insert(it->GetTx().GetHash());
+    }
+    std::vector<TxMempoolInfo> infos;
+    infos.reserve(entries.size());
+    for (auto& info : mempool.infoAll()) {
+        if (txids.contains(info.tx->GetHash())) infos.push_back(std::move(info));
+    }
+    return infos;
+}
+} // namespace
+
+size_t btck_mempool_size(const btck_Mempool* mempool)
+{
+    return btck_Mempool::get(mempool).size();
+}
+
+int btck_mempool_contains(const btck_Mempool* mempool, const btck_Txid* txid)
+{
+    return btck_Mempool::get(mempool).exists(btck_Txid::get(txid)) ? 1 : 0;
+}
+
+btck_MempoolEntry* btck_mempool_get_entry(const btck_Mempool* mempool_, const btck_Txid* txid)
+{
+    auto& mempool{btck_Mempool::get(mempool_)};
+    LOCK(mempool.cs);
+    const auto entry{mempool.GetEntry(btck_Txid::get(txid))};
+    if (!entry) return nullptr;
+    return btck_MempoolEntry::create(get_mempool_info(*entry));
+}
+
+btck_MempoolEntries* btck_mempool_get_entries(const btck_Mempool* mempool)
+{
+    return btck_MempoolEntries::create(btck_Mempool::get(mempool).infoAll());
+}
+
+btck_MempoolEntries* btck_mempool_get_ancestors(const btck_Mempool* mempool_, const btck_Txid* txid)
+{
+    auto& mempool{btck_Mempool::get(mempool_)};
+    LOCK(mempool.cs);
+    const auto it{mempool.GetIter(btck_Txid::get(txid))};
+    if (!it) return nullptr;
+    return btck_MempoolEntries::create(sorted_mempool_infos(mempool, mempool.CalculateMemPoolAncestors(**it)));
+}
+
+btck_MempoolEntries* btck_mempool_get_descendants(const btck_Mempool* mempool_, const btck_Txid* txid)
+{
+    auto& mempool{btck_Mempool::get(mempool_)};
+    LOCK(mempool.cs);
+    const auto it{mempool.GetIter(btck_Txid::get(txid))};
+    if (!it) return nullptr;
+    CTxMemPool::setEntries descendants;
+    mempool.CalculateDescendants(*it, descendants);
+    descendants.erase(*it);
+    return btck_MempoolEntries::create(sorted_mempool_infos(mempool, descendants));
+}
+
+int btck_mempool_remove_transaction(btck_Mempool* mempool_, const btck_Txid* txid)
+{
+    auto& mempool{btck_Mempool::get(mempool_)};
+    LOCK(mempool.cs);
+    const auto it{mempool.GetIter(btck_Txid::get(txid))};
+    if (!it) return -1;
+    mempool.removeRecursive((*it)->GetTx(), MemPoolRemovalReason::MANUAL);
+    return 0;
+}
+
+const btck_Transaction* btck_mempool_entry_get_transaction(const btck_MempoolEntry* mempool_entry)
+{
+    return btck_Transaction::ref(&btck_MempoolEntry::get(mempool_entry).tx);
+}
+
+int64_t btck_mempool_entry_get_fee(const btck_MempoolEntry* mempool_entry)
+{
+    return btck_MempoolEntry::get(mempool_entry).fee;
+}
+
+int32_t btck_mempool_entry_get_vsize(const btck_MempoolEntry* mempool_entry)
+{
+    return btck_MempoolEntry::get(mempool_entry).vsize;
+}
+
+int64_t btck_mempool_entry_get_time(const btck_MempoolEntry* mempool_entry)
+{
+    return btck_MempoolEntry::get(mempool_entry).m_time.count();
+}
+
+void btck_mempool_entry_destroy(btck_MempoolEntry* mempool_entry)
+{
+    delete mempool_entry;
+}
+
+size_t btck_mempool_entries_count(const btck_MempoolEntries* mempool_entries)
+{
+    return btck_MempoolEntries::get(mempool_entries).size();
+}
+
+const btck_MempoolEntry* btck_mempool_entries_get_at(const btck_MempoolEntries* mempool_entries, size_t index)
+{
+    return btck_MempoolEntry::ref(&btck_MempoolEntries::get(mempool_entries).at(index));
+}
+
+void btck_mempool_entries_destroy(btck_MempoolEntries* mempool_entries)
+{
+    delete mempool_entries;
+}
+
+btck_BlockTemplateOptions* btck_block_template_options_create()
+{
+    return btck_BlockTemplateOptions::create();
+}
+
+void btck_block_template_options_set_use_mempool(btck_BlockTemplateOptions* block_template_options, int use_mempool)
+{
+    btck_BlockTemplateOptions::get(block_template_options).use_mempool = use_mempool != 0;
+}
+
+int btck_block_template_options_set_max_weight(btck_BlockTemplateOptions* block_template_options, uint64_t max_weight)
+{
+    if (max_weight > MAX_BLOCK_WEIGHT) {
+        LogError("Block weight of %d exceeds the maximum of %d.", max_weight, MAX_BLOCK_WEIGHT);
+        return -1;
+    }
+    btck_BlockTemplateOptions::get(block_template_options).max_weight = max_weight;
+    return 0;
+}
+
+int btck_block_template_options_set_min_fee_rate(btck_BlockTemplateOptions* block_template_options, int64_t min_fee_rate)
+{
+    if (min_fee_rate < 0) {
+        LogError("Invalid negative minimum fee rate of %d sat/kvB.", min_fee_rate);
+        return -1;
+    }
+    btck_BlockTemplateOptions::get(block_template_options).min_fee_rate = CFeeRate{min_fee_rate};
+    return 0;
+}
+
+void btck_block_template_options_destroy(btck_BlockTemplateOptions* block_template_options)
+{
+    delete block_template_options;
+}
+
+const btck_Block* btck_block_template_get_block(const btck_BlockTemplate* block_template)
+{
+    return btck_Block::ref(&btck_BlockTemplate::get(block_template).block);
+}
+
+int64_t btck_block_template_get_min_time(const btck_BlockTemplate* block_template)
+{
+    return btck_BlockTemplate::get(block_template).min_time;
+}
+
+int64_t btck_block_template_get_max_time(const btck_BlockTemplate* block_template)
+{
+    return btck_BlockTemplate::get(block_template).max_time;
+}
+
+int64_t btck_block_template_get_fees(const btck_BlockTemplate* block_template)
+{
+    return btck_BlockTemplate::get(block_template).fees;
+}
+
+void btck_block_template_destroy(btck_BlockTemplate* block_template)
+{
+    delete block_template;
+}
+
+int btck_chain_get_height(const btck_Chain* chain)
+{
+    LOCK(::cs_main);
+    return btck_Chain::get(chain).Height();
+}
+
+const btck_BlockTreeEntry* btck_chain_get_by_height(const btck_Chain* chain, int height)
+{
+    LOCK(::cs_main);
+    return btck_BlockTreeEntry::ref(btck_Chain::get(chain)[height]);
+}
+
+int btck_chain_contains(const btck_Chain* chain, const btck_BlockTreeEntry* entry)
+{
+    LOCK(::cs_main);
+    return btck_Chain::get(chain).Contains(&btck_BlockTreeEntry::get(entry)) ? 1 : 0;
+}
+
+uint32_t btck_script_trace_step_get_opcode_position(const btck_ScriptTraceStep* step)
+{
+    return btck_ScriptTraceStep::get(step).opcode_position;
+}
+
+uint8_t btck_script_trace_step_get_opcode(const btck_ScriptTraceStep* step)
+{
+    return btck_ScriptTraceStep::get(step).opcode;
+}
+
+int btck_script_trace_step_is_executed(const btck_ScriptTraceStep* step)
+{
+    return btck_ScriptTraceStep::get(step).executed ? 1 : 0;
+}
+
+size_t btck_script_trace_step_count_stack_items(const btck_ScriptTraceStep* step)
+{
+    return btck_ScriptTraceStep::get(step).stack.size();
+}
+
+int btck_script_trace_step_stack_item_to_bytes(const btck_ScriptTraceStep* step, size_t index, btck_WriteBytes writer, void* user_data)
+{
+    const auto& stack{btck_ScriptTraceStep::get(step).stack};
+    assert(index < stack.size());
+    return writer(stack[index].data(), stack[index].size(), user_data);
+}
+
+size_t btck_script_trace_step_count_altstack_items(const btck_ScriptTraceStep* step)
+{
+    return btck_ScriptTraceStep::get(step).altstack.size();
+}
+
+int btck_script_trace_step_altstack_item_to_bytes(const btck_ScriptTraceStep* step, size_t index, btck_WriteBytes writer, void* user_data)
+{
+    const auto& altstack{btck_ScriptTraceStep::get(step).altstack};
+    assert(index < altstack.size());
+    return writer(altstack[index].data(), altstack[index].size(), user_data);
 }
@@ -284,6 +284,96 @@ typedef struct btck_TransactionOutPoint btck_TransactionOutPoint;
 
 typedef struct btck_Txid btck_Txid;
 
+/**
+ * Opaque data structure for holding a block header.
+ *
+ * Holds the version, previous block hash, merkle root, timestamp, compact
+ * difficulty target and nonce of a block.
+ */
+typedef struct btck_BlockHeader btck_BlockHeader;
+
+/**
+ * Opaque data structure for holding the interpreter state after a traced
+ * script execution step.
+ *
+ * Only valid for the duration of the step callback it is passed to.
+ */
+typedef struct btck_ScriptTraceStep btck_ScriptTraceStep;
+
+/**
+ * Opaque data structure for iterating over the UTXO set.
+ *
+ * Iterates over a snapshot of the coins database of the active chainstate
+ * taken when the cursor was created, ordered by out point.
+ */
+typedef struct btck_CoinsCursor btck_CoinsCursor;
+
+/**
+ * Opaque data structure for holding statistics of the UTXO set.
+ *
+ * Holds the coin count, total amount, size and hash commitments of the UTXO
+ * set at a certain block, as computed for Bitcoin Core's gettxoutsetinfo.
+ */
+typedef struct btck_UtxoSetStats btck_UtxoSetStats;
+
+/**
+ * Opaque data structure for holding the tips of all chains in the block tree.
+ *
+ * Holds the block tree entry of each tip together with its status and the
+ * length of its branch off the active chain, as reported by Bitcoin Core's
+ * getchaintips.
+ */
+typedef struct btck_ChainTips btck_ChainTips;
+
+/**
+ * Opaque data structure for holding the mempool of a chainstate manager.
+ *
+ * Holds unconfirmed transactions that are valid on top of the active chain and
+ * satisfy the local policy rules, as accepted by Bitcoin Core's mempool.
+ */
+typedef struct btck_Mempool btck_Mempool;
+
+/**
+ * Opaque data structure for holding a transaction of the mempool together with
+ * its fee, virtual size and the time it entered the mempool.
+ */
+typedef struct btck_MempoolEntry btck_MempoolEntry;
+
+/**
+ * Opaque data structure for holding a list of mempool entries.
+ */
+typedef struct btck_MempoolEntries btck_MempoolEntries;
+
+/**
+ * Opaque data structure for holding the result of validating a transaction.
+ *
+ * Holds whether the transaction is valid and, if not, the reason it was
+ * rejected.
+ */
+typedef struct btck_TxValidationState btck_TxValidationState;
+
+/**
+ * Opaque data structure for holding options for creating a block template.
+ */
+typedef struct btck_BlockTemplateOptions btck_BlockTemplateOptions;
+
+/**
+ * Opaque data structure for holding a block template.
+ *
+ * Holds a block on top of the active chain tip that is valid except for its
+ * proof of work, together with the bounds its timestamp may be changed within.
+ */
+typedef struct btck_BlockTemplate btck_BlockTemplate;
+
+/**
+ * Opaque data structure for holding the signature hash data of a transaction
+ * that is shared by the verification of all its inputs.
+ *
+ * Computing it once per transaction keeps verifying every input of a
+ * transaction linear in its size.
+ */
+typedef struct btck_PrecomputedTransactionData btck_PrecomputedTransactionData;
+
 /** Current sync state passed to tip changed callbacks. */
 typedef uint8_t btck_SynchronizationState;
 #define btck_SynchronizationState_INIT_REINDEX ((btck_SynchronizationState)(0))
@@ -318,6 +408,20 @@ typedef void (*btck_NotifyWarningSet)(void* user_data, btck_Warning warning, con
 typedef void (*btck_NotifyWarningUnset)(void* user_data, btck_Warning warning);
 typedef void (*btck_NotifyFlushError)(void* user_data, const char* message, size_t message_len);
 typedef void (*btck_NotifyFatalError)(void* user_data, const char* message, size_t message_len);
+typedef void (*btck_NotifySnapshotActivated)(void* user_data, const btck_BlockTreeEntry* base);
+typedef void (*btck_NotifySnapshotValidated)(void* user_data, const btck_BlockTreeEntry* base);
+
+/**
+ * Reason why a transaction was removed from the mempool.
+ */
+typedef uint8_t btck_MempoolRemovalReason;
+#define btck_MempoolRemovalReason_EXPIRY ((btck_MempoolRemovalReason)(0))    //!< expired from the mempool
+#define btck_MempoolRemovalReason_SIZELIMIT ((btck_MempoolRemovalReason)(1)) //!< removed to keep the mempool below its maximum size
+#define btck_MempoolRemovalReason_REORG ((btck_MempoolRemovalReason)(2))     //!< removed because it is no longer valid after a reorganization
+#define btck_MempoolRemovalReason_BLOCK ((btck_MempoolRemovalReason)(3))     //!< removed because it was included in a connected block
+#define btck_MempoolRemovalReason_CONFLICT ((btck_MempoolRemovalReason)(4))  //!< removed because it conflicts with a transaction of a connected block
+#define btck_MempoolRemovalReason_REPLACED ((btck_MempoolRemovalReason)(5))  //!< removed because it was replaced by a transaction paying a higher fee
+#define btck_MempoolRemovalReason_MANUAL ((btck_MempoolRemovalReason)(6))    //!< removed through @ref btck_mempool_remove_transaction
 
 /**
  * Function signatures for the validation interface.
@@ -326,12 +430,37 @@ typedef void (*btck_ValidationInterfaceBlockChecked)(void* user_data, btck_Block
 typedef void (*btck_ValidationInterfacePoWValidBlock)(void* user_data, btck_Block* block, const btck_BlockTreeEntry* entry);
 typedef void (*btck_ValidationInterfaceBlockConnected)(void* user_data, btck_Block* block, const btck_BlockTreeEntry* entry);
 typedef void (*btck_ValidationInterfaceBlockDisconnected)(void* user_data, btck_Block* block, const btck_BlockTreeEntry* entry);
+typedef void (*btck_ValidationInterfaceTransactionAddedToMempool)(void* user_data, const btck_MempoolEntry* entry, uint64_t mempool_sequence);
+typedef void (*btck_ValidationInterfaceTransactionRemovedFromMempool)(void* user_data, btck_Transaction* transaction, btck_MempoolRemovalReason reason, uint64_t mempool_sequence);
+
+/**
+ * The script being evaluated when tracing script execution.
+ */
+typedef uint8_t btck_ScriptPhase;
+#define btck_ScriptPhase_SCRIPT_SIG ((btck_ScriptPhase)(0))     //!< The scriptSig of the spending input.
+#define btck_ScriptPhase_SCRIPT_PUBKEY ((btck_ScriptPhase)(1))  //!< The script pubkey being spent.
+#define btck_ScriptPhase_REDEEM_SCRIPT ((btck_ScriptPhase)(2))  //!< The redeem script of a P2SH output.
+#define btck_ScriptPhase_WITNESS_SCRIPT ((btck_ScriptPhase)(3)) //!< The segwit v0 witness script, or the implied script of P2WPKH.
+#define btck_ScriptPhase_TAPSCRIPT ((btck_ScriptPhase)(4))      //!< The tapscript of a taproot script path spend.
+
+/**
+ * Function signatures for tracing script execution.
+ */
+typedef void (*btck_ScriptTraceScriptStart)(void* user_data, btck_ScriptPhase phase, const void* script, size_t script_len);
+typedef void (*btck_ScriptTraceStepDone)(void* user_data, const btck_ScriptTraceStep* step);
 
 /**
  * Function signature for serializing data.
  */
 typedef int (*btck_WriteBytes)(const void* bytes, size_t size, void* userdata);
 
+/**
+ * Function signature for reading serialized data. Fills up to size bytes of the
+ * buffer and sets read to the number of bytes filled, zero once the end of the
+ * data is reached. Returns 0 on success, non-zero on error.
+ */
+typedef int (*btck_ReadBytes)(void* buffer, size_t size, size_t* read, void* userdata);
+
 /**
  * Whether a validated data structure is valid, invalid, or an error was
  * encountered during processing.
@@ -355,6 +484,56 @@ typedef uint32_t btck_BlockValidationResult;
 #define btck_BlockValidationResult_TIME_FUTURE ((btck_BlockValidationResult)(7))     //!< block timestamp was > 2 hours in the future (or our clock is bad)
 #define btck_BlockValidationResult_HEADER_LOW_WORK ((btck_BlockValidationResult)(8)) //!< the block header may be on a too-little-work chain
 
+/**
+ * A granular "reason" why a transaction was invalid.
+ */
+typedef uint32_t btck_TxValidationResult;
+#define btck_TxValidationResult_UNSET ((btck_TxValidationResult)(0))               //!< initial value. Transaction has not yet been rejected
+#define btck_TxValidationResult_CONSENSUS ((btck_TxValidationResult)(1))           //!< invalid by consensus rules
+#define btck_TxValidationResult_INPUTS_NOT_STANDARD ((btck_TxValidationResult)(2)) //!< inputs failed policy rules
+#define btck_TxValidationResult_NOT_STANDARD ((btck_TxValidationResult)(3))        //!< otherwise didn't meet the local policy rules
+#define btck_TxValidationResult_MISSING_INPUTS ((btck_TxValidationResult)(4))      //!< transaction was missing some of its inputs
+#define btck_TxValidationResult_PREMATURE_SPEND ((btck_TxValidationResult)(5))     //!< spends a coinbase too early, or violates locktime/sequence locks
+#define btck_TxValidationResult_WITNESS_MUTATED ((btck_TxValidationResult)(6))     //!< witness may have been malleated, or present prior to segwit activation
+#define btck_TxValidationResult_WITNESS_STRIPPED ((btck_TxValidationResult)(7))    //!< transaction is missing a witness
+#define btck_TxValidationResult_CONFLICT ((btck_TxValidationResult)(8))            //!< already in the mempool or conflicts with a transaction in the chain
+#define btck_TxValidationResult_MEMPOOL_POLICY ((btck_TxValidationResult)(9))      //!< violated the mempool's fee, size, descendant or replacement limits
+#define btck_TxValidationResult_NO_MEMPOOL ((btck_TxValidationResult)(10))         //!< no mempool is available to validate the transaction
+#define btck_TxValidationResult_RECONSIDERABLE ((btck_TxValidationResult)(11))     //!< fails some policy, but might be acceptable as part of a package
+#define btck_TxValidationResult_UNKNOWN ((btck_TxValidationResult)(12))            //!< transaction was not validated
+
+/**
+ * The validity level a block tree entry has reached. Each level implies all
+ * levels before it.
+ */
+typedef uint8_t btck_BlockValidity;
+#define btck_BlockValidity_UNKNOWN ((btck_BlockValidity)(0))      //!< unused
+#define btck_BlockValidity_TREE ((btck_BlockValidity)(2))         //!< all parent headers found, difficulty matches, timestamp >= median previous
+#define btck_BlockValidity_TRANSACTIONS ((btck_BlockValidity)(3)) //!< only first tx is coinbase, 2 <= coinbase input script length <= 100, transactions valid, no duplicate txids, sigops, size, merkle root
+#define btck_BlockValidity_CHAIN ((btck_BlockValidity)(4))        //!< outputs do not overspend inputs, no double spends, coinbase output ok, no immature coinbase spends
+#define btck_BlockValidity_SCRIPTS ((btck_BlockValidity)(5))      //!< scripts & signatures ok
+
+/**
+ * Flags describing the data available for a block tree entry and whether it
+ * failed validation.
+ */
+typedef uint32_t btck_BlockStatus;
+#define btck_BlockStatus_HAVE_DATA ((btck_BlockStatus)(1U << 0))    //!< full block available in blk*.dat
+#define btck_BlockStatus_HAVE_UNDO ((btck_BlockStatus)(1U << 1))    //!< undo data available in rev*.dat
+#define btck_BlockStatus_FAILED_VALID ((btck_BlockStatus)(1U << 2)) //!< the block itself failed validation
+#define btck_BlockStatus_FAILED_CHILD ((btck_BlockStatus)(1U << 3)) //!< the block descends from a block that failed validation
+
+/**
+ * The status of a chain tip, as reported by Bitcoin Core's getchaintips.
+ */
+typedef uint8_t btck_ChainTipStatus;
+#define btck_ChainTipStatus_ACTIVE ((btck_ChainTipStatus)(0))        //!< the tip of the active chain
+#define btck_ChainTipStatus_VALID_FORK ((btck_ChainTipStatus)(1))    //!< fully validated, but not part of the active chain
+#define btck_ChainTipStatus_VALID_HEADERS ((btck_ChainTipStatus)(2)) //!< all blocks are available, but were never fully validated
+#define btck_ChainTipStatus_HEADERS_ONLY ((btck_ChainTipStatus)(3))  //!< not all blocks of the branch are available
+#define btck_ChainTipStatus_INVALID ((btck_ChainTipStatus)(4))       //!< the branch contains at least one invalid block
+#define btck_ChainTipStatus_UNKNOWN ((btck_ChainTipStatus)(5))       //!< none of the above
+
 /**
  * Holds the validation interface callbacks. The user data pointer may be used
  * to point to user-defined structures to make processing the validation
@@ -372,8 +551,21 @@ typedef struct {
                                                                   //!< and segwit merkle root.
     btck_ValidationInterfaceBlockConnected block_connected;       //!< Called when a block is valid and has now been connected to the best chain.
     btck_ValidationInterfaceBlockDisconnected block_disconnected; //!< Called during a re-org when a block has been removed from the best chain.
+    btck_ValidationInterfaceTransactionAddedToMempool transaction_added_to_mempool;         //!< Called when a transaction was added to the mempool.
+    btck_ValidationInterfaceTransactionRemovedFromMempool transaction_removed_from_mempool; //!< Called when a transaction was removed from the mempool for
+                                                                                            //!< any reason but its inclusion in a connected block.
 } btck_ValidationInterfaceCallbacks;
 
+/**
+ * A struct for holding the script trace callbacks. The callbacks are called
+ * synchronously from btck_script_pubkey_verify_with_trace.
+ */
+typedef struct {
+    void* user_data;                          //!< Holds a user-defined opaque structure that is passed to the trace callbacks.
+    btck_ScriptTraceScriptStart script_start; //!< Called before the evaluation of a script starts.
+    btck_ScriptTraceStepDone step;            //!< Called after each opcode was read and, if it is in an executed branch, executed.
+} btck_ScriptTraceCallbacks;
+
 /**
  * A struct for holding the kernel notification callbacks. The user data
  * pointer may be used to point to user-defined structures to make processing
@@ -398,6 +590,10 @@ typedef struct {
     btck_NotifyWarningUnset warning_unset;  //!< A previous condition leading to the issuance of a warning is no longer given.
     btck_NotifyFlushError flush_error;      //!< An error encountered when flushing data to disk.
     btck_NotifyFatalError fatal_error;      //!< An unrecoverable system error encountered by the library.
+    btck_NotifySnapshotActivated snapshot_activated; //!< A chainstate loaded from a UTXO snapshot based on the provided block
+                                                     //!< entry became the active chainstate.
+    btck_NotifySnapshotValidated snapshot_validated; //!< Background validation reached the base block of the UTXO snapshot and
+                                                     //!< confirmed its UTXO set.
 } btck_NotificationInterfaceCallbacks;
 
 /**
@@ -445,6 +641,82 @@ typedef uint8_t btck_ScriptVerifyStatus;
 #define btck_ScriptVerifyStatus_ERROR_INVALID_FLAGS_COMBINATION ((btck_ScriptVerifyStatus)(1)) //!< The flags were combined in an invalid way.
 #define btck_ScriptVerifyStatus_ERROR_SPENT_OUTPUTS_REQUIRED ((btck_ScriptVerifyStatus)(2))    //!< The taproot flag was set, so valid spent_outputs have to be provided.
 
+/**
+ * The reason a script failed to verify, mirroring the script interpreter's
+ * error codes.
+ */
+typedef uint8_t btck_ScriptError;
+#define btck_ScriptError_OK ((btck_ScriptError)(0))
+#define btck_ScriptError_UNKNOWN_ERROR ((btck_ScriptError)(1))
+#define btck_ScriptError_EVAL_FALSE ((btck_ScriptError)(2))
+#define btck_ScriptError_OP_RETURN ((btck_ScriptError)(3))
+#define btck_ScriptError_SCRIPT_SIZE ((btck_ScriptError)(4))
+#define btck_ScriptError_PUSH_SIZE ((btck_ScriptError)(5))
+#define btck_ScriptError_OP_COUNT ((btck_ScriptError)(6))
+#define btck_ScriptError_STACK_SIZE ((btck_ScriptError)(7))
+#define btck_ScriptError_SIG_COUNT ((btck_ScriptError)(8))
+#define btck_ScriptError_PUBKEY_COUNT ((btck_ScriptError)(9))
+#define btck_ScriptError_VERIFY ((btck_ScriptError)(10))
+#define btck_ScriptError_EQUALVERIFY ((btck_ScriptError)(11))
+#define btck_ScriptError_CHECKMULTISIGVERIFY ((btck_ScriptError)(12))
+#define btck_ScriptError_CHECKSIGVERIFY ((btck_ScriptError)(13))
+#define btck_ScriptError_NUMEQUALVERIFY ((btck_ScriptError)(14))
+#define btck_ScriptError_BAD_OPCODE ((btck_ScriptError)(15))
+#define btck_ScriptError_DISABLED_OPCODE ((btck_ScriptError)(16))
+#define btck_ScriptError_INVALID_STACK_OPERATION ((btck_ScriptError)(17))
+#define btck_ScriptError_INVALID_ALTSTACK_OPERATION ((btck_ScriptError)(18))
+#define btck_ScriptError_UNBALANCED_CONDITIONAL ((btck_ScriptError)(19))
+#define btck_ScriptError_NEGATIVE_LOCKTIME ((btck_ScriptError)(20))
+#define btck_ScriptError_UNSATISFIED_LOCKTIME ((btck_ScriptError)(21))
+#define btck_ScriptError_SIG_HASHTYPE ((btck_ScriptError)(22))
+#define btck_ScriptError_SIG_DER ((btck_ScriptError)(23))
+#define btck_ScriptError_MINIMALDATA ((btck_ScriptError)(24))
+#define btck_ScriptError_SIG_PUSHONLY ((btck_ScriptError)(25))
+#define btck_ScriptError_SIG_HIGH_S ((btck_ScriptError)(26))
+#define btck_ScriptError_SIG_NULLDUMMY ((btck_ScriptError)(27))
+#define btck_ScriptError_PUBKEYTYPE ((btck_ScriptError)(28))
+#define btck_ScriptError_CLEANSTACK ((btck_ScriptError)(29))
+#define btck_ScriptError_MINIMALIF ((btck_ScriptError)(30))
+#define btck_ScriptError_SIG_NULLFAIL ((btck_ScriptError)(31))
+#define btck_ScriptError_DISCOURAGE_UPGRADABLE_NOPS ((btck_ScriptError)(32))
+#define btck_ScriptError_DISCOURAGE_UPGRADABLE_WITNESS_PROGRAM ((btck_ScriptError)(33))
+#define btck_ScriptError_DISCOURAGE_UPGRADABLE_TAPROOT_VERSION ((btck_ScriptError)(34))
+#define btck_ScriptError_DISCOURAGE_OP_SUCCESS ((btck_ScriptError)(35))
+#define btck_ScriptError_DISCOURAGE_UPGRADABLE_PUBKEYTYPE ((btck_ScriptError)(36))
+#define btck_ScriptError_WITNESS_PROGRAM_WRONG_LENGTH ((btck_ScriptError)(37))
+#define btck_ScriptError_WITNESS_PROGRAM_WITNESS_EMPTY ((btck_ScriptError)(38))
+#define btck_ScriptError_WITNESS_PROGRAM_MISMATCH ((btck_ScriptError)(39))
+#define btck_ScriptError_WITNESS_MALLEATED ((btck_ScriptError)(40))
+#define btck_ScriptError_WITNESS_MALLEATED_P2SH ((btck_ScriptError)(41))
+#define btck_ScriptError_WITNESS_UNEXPECTED ((btck_ScriptError)(42))
+#define btck_ScriptError_WITNESS_PUBKEYTYPE ((btck_ScriptError)(43))
+#define btck_ScriptError_SCHNORR_SIG_SIZE ((btck_ScriptError)(44))
+#define btck_ScriptError_SCHNORR_SIG_HASHTYPE ((btck_ScriptError)(45))
+#define btck_ScriptError_SCHNORR_SIG ((btck_ScriptError)(46))
+#define btck_ScriptError_TAPROOT_WRONG_CONTROL_SIZE ((btck_ScriptError)(47))
+#define btck_ScriptError_TAPSCRIPT_VALIDATION_WEIGHT ((btck_ScriptError)(48))
+#define btck_ScriptError_TAPSCRIPT_CHECKMULTISIG ((btck_ScriptError)(49))
+#define btck_ScriptError_TAPSCRIPT_MINIMALIF ((btck_ScriptError)(50))
+#define btck_ScriptError_TAPSCRIPT_EMPTY_PUBKEY ((btck_ScriptError)(51))
+#define btck_ScriptError_OP_CODESEPARATOR ((btck_ScriptError)(52))
+#define btck_ScriptError_SIG_FINDANDDELETE ((btck_ScriptError)(53))
+
+/**
+ * The standard template a script pubkey matches, as identified by the template solver.
+ */
+typedef uint8_t btck_ScriptType;
+#define btck_ScriptType_NONSTANDARD ((btck_ScriptType)(0))           //!< The script does not match any standard template.
+#define btck_ScriptType_ANCHOR ((btck_ScriptType)(1))                //!< Anyone-can-spend pay-to-anchor output.
+#define btck_ScriptType_PUBKEY ((btck_ScriptType)(2))                //!< Pay to public key.
+#define btck_ScriptType_PUBKEYHASH ((btck_ScriptType)(3))            //!< Pay to public key hash.
+#define btck_ScriptType_SCRIPTHASH ((btck_ScriptType)(4))            //!< Pay to script hash (BIP16).
+#define btck_ScriptType_MULTISIG ((btck_ScriptType)(5))              //!< Bare multisig.
+#define btck_ScriptType_NULL_DATA ((btck_ScriptType)(6))             //!< Unspendable OP_RETURN output carrying data.
+#define btck_ScriptType_WITNESS_V0_SCRIPTHASH ((btck_ScriptType)(7)) //!< Pay to witness script hash (BIP141).
+#define btck_ScriptType_WITNESS_V0_KEYHASH ((btck_ScriptType)(8))    //!< Pay to witness public key hash (BIP141).
+#define btck_ScriptType_WITNESS_V1_TAPROOT ((btck_ScriptType)(9))    //!< Pay to taproot (BIP341).
+#define btck_ScriptType_WITNESS_UNKNOWN ((btck_ScriptType)(10))      //!< Witness program of an undefined witness version.
+
 /**
  * Script verification flags that may be composed with each other.
  */
@@ -564,6 +836,144 @@ BITCOINKERNEL_API size_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_count
 BITCOINKERNEL_API const btck_Txid* BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_get_txid(
     const btck_Transaction* transaction) BITCOINKERNEL_ARG_NONNULL(1);
 
+/**
+ * @brief Serializes the witness hash (wtxid) of a transaction. For
+ * transactions without witness data it is equal to the txid.
+ *
+ * @param[in] transaction Non-null.
+ * @param[out] output     The serialized wtxid.
+ */
+BITCOINKERNEL_API void btck_transaction_get_wtxid(
+    const btck_Transaction* transaction, unsigned char output[32]) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Get the version of a transaction.
+ *
+ * @param[in] transaction Non-null.
+ * @return                The transaction version.
+ */
+BITCOINKERNEL_API uint32_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_get_version(
+    const btck_Transaction* transaction) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the lock time (nLockTime) of a transaction.
+ *
+ * @param[in] transaction Non-null.
+ * @return                The transaction lock time.
+ */
+BITCOINKERNEL_API uint32_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_get_locktime(
+    const btck_Transaction* transaction) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Returns whether the transaction is a coinbase transaction.
+ *
+ * @param[in] transaction Non-null.
+ * @return                1 if the transaction is a coinbase, 0 otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_is_coinbase(
+    const btck_Transaction* transaction) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Returns whether any of the transaction's inputs carry witness data.
+ *
+ * @param[in] transaction Non-null.
+ * @return                1 if the transaction has witness data, 0 otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_has_witness(
+    const btck_Transaction* transaction) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the weight of a transaction as defined in BIP141.
+ *
+ * @param[in] transaction Non-null.
+ * @return                The transaction weight.
+ */
+BITCOINKERNEL_API int64_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_get_weight(
+    const btck_Transaction* transaction) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Computes the legacy signature hash of a transaction input, the message
+ * signed by signatures of non-segwit scripts.
+ *
+ * As required by consensus, a SIGHASH_SINGLE hash of an input without a
+ * corresponding output is the number one.
+ *
+ * @param[in] transaction     Non-null.
+ * @param[in] input_index     Index of the input being signed, must be in range.
+ * @param[in] script_code     The script code, i.e. the script pubkey or redeem script
+ *                            being spent, may be null if script_code_len is 0.
+ * @param[in] script_code_len Length of the script code.
+ * @param[in] hash_type       The sighash type.
+ * @param[out] output         The signature hash.
+ */
+BITCOINKERNEL_API void btck_transaction_signature_hash_legacy(
+    const btck_Transaction* transaction,
+    unsigned int input_index,
+    const void* script_code, size_t script_code_len,
+    int32_t hash_type,
+    unsigned char output[32]) BITCOINKERNEL_ARG_NONNULL(1, 6);
+
+/**
+ * @brief Computes the BIP143 signature hash of a segwit v0 transaction input.
+ *
+ * @param[in] transaction     Non-null.
+ * @param[in] input_index     Index of the input being signed, must be in range.
+ * @param[in] script_code     The BIP143 script code, may be null if script_code_len is 0.
+ * @param[in] script_code_len Length of the script code.
+ * @param[in] amount          Amount of the output spent by the input.
+ * @param[in] hash_type       The sighash type.
+ * @param[out] output         The signature hash.
+ */
+BITCOINKERNEL_API void btck_transaction_signature_hash_witness_v0(
+    const btck_Transaction* transaction,
+    unsigned int input_index,
+    const void* script_code, size_t script_code_len,
+    int64_t amount,
+    int32_t hash_type,
+    unsigned char output[32]) BITCOINKERNEL_ARG_NONNULL(1, 7);
+
+/**
+ * @brief Computes the BIP341 signature hash of a taproot transaction input,
+ * either for a key path spend or, if a tapleaf hash is given, for a BIP342
+ * script path spend. If the witness of the input ends with an annex, the
+ * annex is committed to.
+ *
+ * @param[in] transaction            Non-null.
+ * @param[in] input_index            Index of the input being signed, must be in range.
+ * @param[in] spent_outputs          Non-null, the outputs spent by the transaction, one per input.
+ * @param[in] spent_outputs_len      Number of spent outputs, must equal the number of inputs.
+ * @param[in] hash_type              The sighash type.
+ * @param[in] tapleaf_hash           Nullable, the tapleaf hash of the script being executed
+ *                                   for a script path spend, null for a key path spend.
+ * @param[in] codeseparator_position Opcode position of the last executed OP_CODESEPARATOR,
+ *                                   0xFFFFFFFF if none. Ignored for key path spends.
+ * @param[out] output                The signature hash.
+ * @return                           0 on success, -1 if the hash type is invalid or is
+ *                                   SIGHASH_SINGLE for an input without a corresponding output.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_signature_hash_taproot(
+    const btck_Transaction* transaction,
+    unsigned int input_index,
+    const btck_TransactionOutput** spent_outputs, size_t spent_outputs_len,
+    uint8_t hash_type,
+    const unsigned char* tapleaf_hash,
+    uint32_t codeseparator_position,
+    unsigned char output[32]) BITCOINKERNEL_ARG_NONNULL(1, 3, 8);
+
+/**
+ * @brief Check the transaction against the consensus rules that do not depend
+ * on the chain, e.g. that it has inputs and outputs, its output values are in
+ * range, it spends no output twice and it is not too large. Scripts are not
+ * verified.
+ *
+ * @param[in] transaction          Non-null.
+ * @param[out] tx_validation_state Non-null, set to the result of the check.
+ * @return                         0 if the transaction passed the checks, non-zero otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_check(
+    const btck_Transaction* transaction,
+    btck_TxValidationState* tx_validation_state) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
 /**
  * Destroy the transaction.
  */
@@ -622,6 +1032,92 @@ BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_script_pubkey_verify
     btck_ScriptVerificationFlags flags,
     btck_ScriptVerifyStatus* status) BITCOINKERNEL_ARG_NONNULL(1, 3);
 
+/**
+ * @brief Same as btck_script_pubkey_verify, but additionally reports why the
+ * script failed to verify.
+ *
+ * @param[in] script_pubkey     Non-null, script pubkey to be spent.
+ * @param[in] amount            Amount of the script pubkey's associated output. May be zero if
+ *                              the witness flag is not set.
+ * @param[in] tx_to             Non-null, transaction spending the script_pubkey.
+ * @param[in] spent_outputs     Nullable if the taproot flag is not set. Points to an array of
+ *                              outputs spent by the transaction.
+ * @param[in] spent_outputs_len Length of the spent_outputs array.
+ * @param[in] input_index       Index of the input in tx_to spending the script_pubkey.
+ * @param[in] flags             Bitfield of btck_ScriptVerificationFlags controlling validation constraints.
+ * @param[out] status           Nullable, will be set to an error code if the operation fails, or OK otherwise.
+ * @param[out] script_error     Nullable, will be set to the reason the script failed to verify, or
+ *                              OK if it is valid or the operation failed.
+ * @return                      1 if the script is valid, 0 otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_script_pubkey_verify_with_error(
+    const btck_ScriptPubkey* script_pubkey,
+    int64_t amount,
+    const btck_Transaction* tx_to,
+    const btck_TransactionOutput** spent_outputs, size_t spent_outputs_len,
+    unsigned int input_index,
+    btck_ScriptVerificationFlags flags,
+    btck_ScriptVerifyStatus* status,
+    btck_ScriptError* script_error) BITCOINKERNEL_ARG_NONNULL(1, 3);
+
+/**
+ * @brief Same as btck_script_pubkey_verify_with_error, but additionally
+ * reports every script evaluation step through the passed in callbacks. Meant
+ * for debugging, as it is considerably slower than plain verification.
+ *
+ * @param[in] script_pubkey     Non-null, script pubkey to be spent.
+ * @param[in] amount            Amount of the script pubkey's associated output. May be zero if
+ *                              the witness flag is not set.
+ * @param[in] tx_to             Non-null, transaction spending the script_pubkey.
+ * @param[in] spent_outputs     Nullable if the taproot flag is not set. Points to an array of
+ *                              outputs spent by the transaction.
+ * @param[in] spent_outputs_len Length of the spent_outputs array.
+ * @param[in] input_index       Index of the input in tx_to spending the script_pubkey.
+ * @param[in] flags             Bitfield of btck_ScriptVerificationFlags controlling validation constraints.
+ * @param[out] status           Nullable, will be set to an error code if the operation fails, or OK otherwise.
+ * @param[out] script_error     Nullable, will be set to the reason the script failed to verify, or
+ *                              OK if it is valid or the operation failed.
+ * @param[in] callbacks         The trace callbacks, both of which must be set.
+ * @return                      1 if the script is valid, 0 otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_script_pubkey_verify_with_trace(
+    const btck_ScriptPubkey* script_pubkey,
+    int64_t amount,
+    const btck_Transaction* tx_to,
+    const btck_TransactionOutput** spent_outputs, size_t spent_outputs_len,
+    unsigned int input_index,
+    btck_ScriptVerificationFlags flags,
+    btck_ScriptVerifyStatus* status,
+    btck_ScriptError* script_error,
+    btck_ScriptTraceCallbacks callbacks) BITCOINKERNEL_ARG_NONNULL(1, 3);
+
+/**
+ * @brief Same as btck_script_pubkey_verify_with_error, but uses the signature
+ * hash data precomputed for tx_to instead of computing it for this input
+ * only. The spent outputs are taken from the precomputed data.
+ *
+ * @param[in] script_pubkey      Non-null, script pubkey to be spent.
+ * @param[in] amount             Amount of the script pubkey's associated output. May be zero if
+ *                               the witness flag is not set.
+ * @param[in] tx_to              Non-null, transaction spending the script_pubkey.
+ * @param[in] precomputed_txdata Non-null, data precomputed for tx_to.
+ * @param[in] input_index        Index of the input in tx_to spending the script_pubkey.
+ * @param[in] flags              Bitfield of btck_ScriptVerificationFlags controlling validation constraints.
+ * @param[out] status            Nullable, will be set to an error code if the operation fails, or OK otherwise.
+ * @param[out] script_error      Nullable, will be set to the reason the script failed to verify, or
+ *                               OK if it is valid or the operation failed.
+ * @return                       1 if the script is valid, 0 otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_script_pubkey_verify_with_precomputed(
+    const btck_ScriptPubkey* script_pubkey,
+    int64_t amount,
+    const btck_Transaction* tx_to,
+    const btck_PrecomputedTransactionData* precomputed_txdata,
+    unsigned int input_index,
+    btck_ScriptVerificationFlags flags,
+    btck_ScriptVerifyStatus* status,
+    btck_ScriptError* script_error) BITCOINKERNEL_ARG_NONNULL(1, 3, 4);
+
 /**
  * @brief Serializes the script pubkey through the passed in callback to bytes.
  *
@@ -636,6 +1132,65 @@ BITCOINKERNEL_API int btck_script_pubkey_to_bytes(
     btck_WriteBytes writer,
     void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 2);
 
+/**
+ * @brief Get the standard template the script pubkey matches.
+ *
+ * @param[in] script_pubkey Non-null.
+ * @return                  The script type.
+ */
+BITCOINKERNEL_API btck_ScriptType BITCOINKERNEL_WARN_UNUSED_RESULT btck_script_pubkey_get_type(
+    const btck_ScriptPubkey* script_pubkey) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the number of data elements extracted from the script pubkey by
+ * the template solver. These are the public key for pay to public key, the
+ * hash for pay to (witness) public key and script hash, the required
+ * signature count followed by the public keys and the key count for bare
+ * multisig, the witness program for taproot, the witness version followed by
+ * the witness program for unknown witness versions, and the pushed payloads
+ * for null data scripts. Nonstandard and anchor scripts have no elements.
+ *
+ * @param[in] script_pubkey Non-null.
+ * @return                  The number of extracted data elements.
+ */
+BITCOINKERNEL_API size_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_script_pubkey_count_solutions(
+    const btck_ScriptPubkey* script_pubkey) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Writes the data element at the provided index extracted by the
+ * template solver through the passed in callback.
+ *
+ * @param[in] script_pubkey  Non-null.
+ * @param[in] solution_index The index of the data element, must be less than
+ *                           btck_script_pubkey_count_solutions.
+ * @param[in] writer         Non-null, callback to a write bytes function.
+ * @param[in] user_data      Holds a user-defined opaque structure that will be
+ *                           passed back through the writer callback.
+ * @return                   0 on success.
+ */
+BITCOINKERNEL_API int btck_script_pubkey_solution_to_bytes(
+    const btck_ScriptPubkey* script_pubkey,
+    size_t solution_index,
+    btck_WriteBytes writer,
+    void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 3);
+
+/**
+ * @brief Writes the human-readable disassembly of the script pubkey in the
+ * "asm" format used by Bitcoin Core's RPC interface through the passed in
+ * callback. Pushes of up to four bytes are shown as numbers, larger pushes in
+ * hex. Writes nothing for an empty script.
+ *
+ * @param[in] script_pubkey Non-null.
+ * @param[in] writer        Non-null, callback to a write bytes function.
+ * @param[in] user_data     Holds a user-defined opaque structure that will be
+ *                          passed back through the writer callback.
+ * @return                  0 on success.
+ */
+BITCOINKERNEL_API int btck_script_pubkey_to_asm(
+    const btck_ScriptPubkey* script_pubkey,
+    btck_WriteBytes writer,
+    void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
 /**
  * Destroy the script pubkey.
  */
@@ -643,6 +1198,34 @@ BITCOINKERNEL_API void btck_script_pubkey_destroy(btck_ScriptPubkey* script_pubk
 
 ///@}
 
+/** @name PrecomputedTransactionData
+ * Functions for working with precomputed transaction data.
+ */
+///@{
+
+/**
+ * @brief Precompute the signature hash data of a transaction for verifying
+ * its inputs with btck_script_pubkey_verify_with_precomputed. The result may
+ * be shared by concurrent verifications of the inputs of the transaction.
+ *
+ * @param[in] tx_to             Non-null, transaction whose inputs will be verified.
+ * @param[in] spent_outputs     Nullable if the taproot flag will not be set. Points to an array of
+ *                              outputs spent by the transaction.
+ * @param[in] spent_outputs_len Length of the spent_outputs array.
+ * @return                      The precomputed transaction data, or null if the number of spent
+ *                              outputs does not match the number of inputs.
+ */
+BITCOINKERNEL_API btck_PrecomputedTransactionData* BITCOINKERNEL_WARN_UNUSED_RESULT btck_precomputed_transaction_data_create(
+    const btck_Transaction* tx_to,
+    const btck_TransactionOutput** spent_outputs, size_t spent_outputs_len) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * Destroy the precomputed transaction data.
+ */
+BITCOINKERNEL_API void btck_precomputed_transaction_data_destroy(btck_PrecomputedTransactionData* precomputed_txdata);
+
+///@}
+
 /** @name TransactionOutput
  * Functions for working with transaction outputs.
  */
@@ -800,6 +1383,25 @@ BITCOINKERNEL_API btck_ChainParameters* BITCOINKERNEL_WARN_UNUSED_RESULT btck_ch
 BITCOINKERNEL_API btck_ChainParameters* BITCOINKERNEL_WARN_UNUSED_RESULT btck_chain_parameters_copy(
     const btck_ChainParameters* chain_parameters) BITCOINKERNEL_ARG_NONNULL(1);
 
+/**
+ * @brief Adds an assumeutxo entry to the chain parameters, so a UTXO snapshot
+ * based on the given block is recognized by chainstate managers created with
+ * these parameters. This is mostly useful for testing snapshots of custom chains.
+ *
+ * @param[in] chain_parameters Non-null.
+ * @param[in] height           Height of the snapshot base block.
+ * @param[in] block_hash       Non-null, hash of the snapshot base block.
+ * @param[in] hash_serialized  Non-null, expected hash_serialized_3 of the UTXO set at the base block.
+ * @param[in] chain_tx_count   Number of transactions in the chain up to and including the base block.
+ * @return                     0 on success, -1 if an entry for the height or block already exists.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chain_parameters_add_assumeutxo(
+    btck_ChainParameters* chain_parameters,
+    int32_t height,
+    const btck_BlockHash* block_hash,
+    const unsigned char hash_serialized[32],
+    uint64_t chain_tx_count) BITCOINKERNEL_ARG_NONNULL(1, 3, 4);
+
 /**
  * Destroy the chain parameters.
  */
@@ -944,37 +1546,119 @@ BITCOINKERNEL_API const btck_BlockHash* BITCOINKERNEL_WARN_UNUSED_RESULT btck_bl
 BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_tree_entry_equals(
     const btck_BlockTreeEntry* entry1, const btck_BlockTreeEntry* entry2) BITCOINKERNEL_ARG_NONNULL(1, 2);
 
-///@}
-
-/** @name ChainstateManagerOptions
- * Functions for working with chainstate manager options.
+/**
+ * @brief Return the block header associated with a block tree entry.
+ *
+ * @param[in] block_tree_entry Non-null.
+ * @return                     The block header.
  */
-///@{
+BITCOINKERNEL_API btck_BlockHeader* BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_tree_entry_get_block_header(
+    const btck_BlockTreeEntry* block_tree_entry) BITCOINKERNEL_ARG_NONNULL(1);
 
 /**
- * @brief Create options for the chainstate manager.
+ * @brief Serializes the total amount of work in the chain up to and including
+ * this block tree entry. The output is a 256-bit little-endian integer.
  *
- * @param[in] context          Non-null, the created options and through it the chainstate manager will
- *                             associate with this kernel context for the duration of their lifetimes.
- * @param[in] data_directory   Non-null, non-empty path string of the directory containing the
- *                             chainstate data. If the directory does not exist yet, it will be
- *                             created.
- * @param[in] blocks_directory Non-null, non-empty path string of the directory containing the block
- *                             data. If the directory does not exist yet, it will be created.
- * @return                     The allocated chainstate manager options, or null on error.
+ * @param[in] block_tree_entry Non-null.
+ * @param[out] output          The serialized chain work.
  */
-BITCOINKERNEL_API btck_ChainstateManagerOptions* BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_options_create(
-    const btck_Context* context,
-    const char* data_directory,
-    size_t data_directory_len,
-    const char* blocks_directory,
-    size_t blocks_directory_len) BITCOINKERNEL_ARG_NONNULL(1);
+BITCOINKERNEL_API void btck_block_tree_entry_get_chain_work(
+    const btck_BlockTreeEntry* block_tree_entry, unsigned char output[32]) BITCOINKERNEL_ARG_NONNULL(1, 2);
 
 /**
- * @brief Set the number of available worker threads used during validation.
+ * @brief Return the median timestamp of the block tree entry and its ten
+ * predecessors.
  *
- * @param[in] chainstate_manager_options Non-null, options to be set.
- * @param[in] worker_threads             The number of worker threads that should be spawned in the thread pool
+ * @param[in] block_tree_entry Non-null.
+ * @return                     The median time past.
+ */
+BITCOINKERNEL_API int64_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_tree_entry_get_median_time_past(
+    const btck_BlockTreeEntry* block_tree_entry) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Return the highest validity level the block tree entry has reached.
+ * This does not take into account whether the block was subsequently found to
+ * be invalid, see @ref btck_block_tree_entry_is_valid for that.
+ *
+ * @param[in] block_tree_entry Non-null.
+ * @return                     The validity level.
+ */
+BITCOINKERNEL_API btck_BlockValidity BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_tree_entry_get_validity(
+    const btck_BlockTreeEntry* block_tree_entry) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Check whether the block tree entry is valid up to the passed in
+ * validity level and has not failed validation.
+ *
+ * @param[in] block_tree_entry Non-null.
+ * @param[in] validity         The validity level to check against.
+ * @return                     1 if the block tree entry is valid up to the validity level, 0 otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_tree_entry_is_valid(
+    const btck_BlockTreeEntry* block_tree_entry, btck_BlockValidity validity) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Return the status flags of the block tree entry.
+ *
+ * @param[in] block_tree_entry Non-null.
+ * @return                     The status flags.
+ */
+BITCOINKERNEL_API btck_BlockStatus BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_tree_entry_get_status(
+    const btck_BlockTreeEntry* block_tree_entry) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Return the last common ancestor of two block tree entries of the same
+ * block tree, which is one of them if it is an ancestor of the other.
+ *
+ * @param[in] entry1 Non-null.
+ * @param[in] entry2 Non-null.
+ * @return           The block tree entry the branches of the two entries fork from.
+ */
+BITCOINKERNEL_API const btck_BlockTreeEntry* BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_tree_entry_find_fork(
+    const btck_BlockTreeEntry* entry1, const btck_BlockTreeEntry* entry2) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Return the ancestor of the block tree entry at the given height,
+ * which is the entry itself if the height is its own.
+ *
+ * @param[in] block_tree_entry Non-null.
+ * @param[in] height           Height of the ancestor.
+ * @return                     The ancestor, or null if the height is negative or above the entry's height.
+ */
+BITCOINKERNEL_API const btck_BlockTreeEntry* BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_tree_entry_get_ancestor(
+    const btck_BlockTreeEntry* block_tree_entry, int32_t height) BITCOINKERNEL_ARG_NONNULL(1);
+
+///@}
+
+/** @name ChainstateManagerOptions
+ * Functions for working with chainstate manager options.
+ */
+///@{
+
+/**
+ * @brief Create options for the chainstate manager.
+ *
+ * @param[in] context          Non-null, the created options and through it the chainstate manager will
+ *                             associate with this kernel context for the duration of their lifetimes.
+ * @param[in] data_directory   Non-null, non-empty path string of the directory containing the
+ *                             chainstate data. If the directory does not exist yet, it will be
+ *                             created.
+ * @param[in] blocks_directory Non-null, non-empty path string of the directory containing the block
+ *                             data. If the directory does not exist yet, it will be created.
+ * @return                     The allocated chainstate manager options, or null on error.
+ */
+BITCOINKERNEL_API btck_ChainstateManagerOptions* BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_options_create(
+    const btck_Context* context,
+    const char* data_directory,
+    size_t data_directory_len,
+    const char* blocks_directory,
+    size_t blocks_directory_len) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Set the number of available worker threads used during validation.
+ *
+ * @param[in] chainstate_manager_options Non-null, options to be set.
+ * @param[in] worker_threads             The number of worker threads that should be spawned in the thread pool
  *                                       used for validation. When set to 0 no parallel verification is done.
  *                                       The value range is clamped internally between 0 and 15.
  */
@@ -1017,6 +1701,98 @@ BITCOINKERNEL_API void btck_chainstate_manager_options_update_chainstate_db_in_m
     btck_ChainstateManagerOptions* chainstate_manager_options,
     int chainstate_db_in_memory) BITCOINKERNEL_ARG_NONNULL(1);
 
+/**
+ * @brief Sets the target size of the block and undo files on disk in the
+ * options. While the chainstate is flushed, the oldest block and undo files are
+ * deleted to stay below the target. Blocks close to the tip are always kept.
+ *
+ * @param[in] chainstate_manager_options Non-null, created by @ref btck_chainstate_manager_options_create.
+ * @param[in] prune_target               Target size in bytes, at least 550 MiB. 0 disables pruning, which is the
+ *                                       default. UINT64_MAX enables pruning, but only deletes files when
+ *                                       requested through @ref btck_chainstate_manager_prune_block_files.
+ * @return                               0 if the set was successful, non-zero if the target is below the minimum.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_options_set_prune_target(
+    btck_ChainstateManagerOptions* chainstate_manager_options,
+    uint64_t prune_target) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Sets whether block files are limited to 64 KiB instead of 128 MiB, as
+ * Bitcoin Core's test-only -fastprune does. Only meant for testing pruning on
+ * chains of small blocks, which otherwise all fit in a single block file that
+ * is never pruned.
+ *
+ * @param[in] chainstate_manager_options Non-null, created by @ref btck_chainstate_manager_options_create.
+ * @param[in] fast_prune                 Set to 1 to use small block files, 0 otherwise.
+ */
+BITCOINKERNEL_API void btck_chainstate_manager_options_set_fast_prune(
+    btck_ChainstateManagerOptions* chainstate_manager_options,
+    int fast_prune) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Sets the size of the block tree database cache in the options. The
+ * default is 2 MiB.
+ *
+ * @param[in] chainstate_manager_options Non-null, created by @ref btck_chainstate_manager_options_create.
+ * @param[in] cache_size                 Cache size in bytes.
+ */
+BITCOINKERNEL_API void btck_chainstate_manager_options_set_block_tree_db_cache_size(
+    btck_ChainstateManagerOptions* chainstate_manager_options,
+    size_t cache_size) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Sets the size of the coins database cache in the options. The
+ * default is 8 MiB.
+ *
+ * @param[in] chainstate_manager_options Non-null, created by @ref btck_chainstate_manager_options_create.
+ * @param[in] cache_size                 Cache size in bytes.
+ */
+BITCOINKERNEL_API void btck_chainstate_manager_options_set_coins_db_cache_size(
+    btck_ChainstateManagerOptions* chainstate_manager_options,
+    size_t cache_size) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Sets the size of the in-memory coins cache in the options. The coins
+ * cache holds the changes to the UTXO set since it was last written to disk,
+ * and is written to disk when it grows beyond this size. The default is about
+ * 440 MiB.
+ *
+ * @param[in] chainstate_manager_options Non-null, created by @ref btck_chainstate_manager_options_create.
+ * @param[in] cache_size                 Cache size in bytes.
+ */
+BITCOINKERNEL_API void btck_chainstate_manager_options_set_coins_cache_size(
+    btck_ChainstateManagerOptions* chainstate_manager_options,
+    size_t cache_size) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Sets the interval between periodic writes of the chainstate to disk
+ * in the options. The time of each write is picked randomly between the
+ * bounds. The defaults are 50 and 70 minutes.
+ *
+ * @param[in] chainstate_manager_options Non-null, created by @ref btck_chainstate_manager_options_create.
+ * @param[in] min_seconds                Minimum time between writes in seconds, must be positive.
+ * @param[in] max_seconds                Maximum time between writes in seconds, must not be below min_seconds.
+ * @return                               0 if the set was successful, non-zero if the bounds are invalid.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_options_set_flush_interval(
+    btck_ChainstateManagerOptions* chainstate_manager_options,
+    int64_t min_seconds,
+    int64_t max_seconds) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Enables the mempool of the chainstate manager in the options. The
+ * mempool applies the default policy rules of Bitcoin Core and is not persisted
+ * across restarts. It is disabled by default.
+ *
+ * @param[in] chainstate_manager_options Non-null, created by @ref btck_chainstate_manager_options_create.
+ * @param[in] max_size_bytes             Maximum memory usage of the mempool in bytes. Bitcoin Core's default
+ *                                       is 300 MB.
+ * @return                               0 if the set was successful, non-zero if the size is not positive.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_options_enable_mempool(
+    btck_ChainstateManagerOptions* chainstate_manager_options,
+    int64_t max_size_bytes) BITCOINKERNEL_ARG_NONNULL(1);
+
 /**
  * Destroy the chainstate manager options.
  */
@@ -1078,6 +1854,68 @@ BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_p
     const btck_Block* block,
     int* new_block) BITCOINKERNEL_ARG_NONNULL(1, 2, 3);
 
+/**
+ * @brief Validate the passed in block headers in order and add them to the
+ * block tree without their block data. Headers are checked for proof of work
+ * and against the contextual header rules of the chain they extend. Headers
+ * that are already known are skipped. An improved best header is reported
+ * through the `header_tip` notification.
+ *
+ * @param[in] chainstate_manager     Non-null.
+ * @param[in] block_headers          Non-null, headers to be validated, each extending a known header or a previous one.
+ * @param[in] block_headers_len      Number of headers.
+ * @param[out] block_validation_state Non-null, set to the result of validating the first invalid header.
+ * @return                           0 if all headers were accepted, non-zero if a header was invalid.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_process_block_headers(
+    btck_ChainstateManager* chainstate_manager,
+    const btck_BlockHeader** block_headers,
+    size_t block_headers_len,
+    btck_BlockValidationState* block_validation_state) BITCOINKERNEL_ARG_NONNULL(1, 2, 4);
+
+/**
+ * @brief Permanently mark the block of the block tree entry as invalid, as if
+ * it violated a consensus rule, and reorganize to the best chain not containing
+ * it. Blocks disconnected and connected during the reorganization are reported
+ * through the validation interface.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @param[in] block_tree_entry   Non-null, block to be marked invalid.
+ * @return                       0 if the block was marked invalid and the reorganization succeeded, non-zero on error.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_invalidate_block(
+    btck_ChainstateManager* chainstate_manager,
+    const btck_BlockTreeEntry* block_tree_entry) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Remove the invalidity status of the block of the block tree entry,
+ * its ancestors and its descendants, undoing previous calls to
+ * btck_chainstate_manager_invalidate_block, and reorganize to the best chain.
+ * Blocks that actually violate consensus rules are found invalid again when
+ * they are connected.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @param[in] block_tree_entry   Non-null, block to be reconsidered.
+ * @return                       0 if the reorganization succeeded, non-zero on error.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_reconsider_block(
+    btck_ChainstateManager* chainstate_manager,
+    const btck_BlockTreeEntry* block_tree_entry) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Treat the block of the block tree entry as if it was received before
+ * other blocks with the same work, and reorganize to it if it has as much
+ * work as the active tip. A later call overrides the effect of earlier ones.
+ * The effect is not persisted across restarts.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @param[in] block_tree_entry   Non-null, block to be preferred.
+ * @return                       0 if the reorganization succeeded, non-zero on error.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_precious_block(
+    btck_ChainstateManager* chainstate_manager,
+    const btck_BlockTreeEntry* block_tree_entry) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
 /**
  * @brief Returns the best known currently active chain. Its lifetime is
  * dependent on the chainstate manager. It can be thought of as a view on a
@@ -1095,6 +1933,98 @@ BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_p
 BITCOINKERNEL_API const btck_Chain* BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_get_active_chain(
     const btck_ChainstateManager* chainstate_manager) BITCOINKERNEL_ARG_NONNULL(1);
 
+/**
+ * @brief Returns the block tree entry of the header with the most work that is
+ * not known to be invalid. Its block data is not necessarily available, so it
+ * may be ahead of the tip of the active chain.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @return                       The best header, or null if no header is known yet.
+ */
+BITCOINKERNEL_API const btck_BlockTreeEntry* BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_get_best_header(
+    const btck_ChainstateManager* chainstate_manager) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Returns the mempool of the chainstate manager. Its lifetime is
+ * dependent on the chainstate manager.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @return                       The mempool, or null if it was not enabled in the options.
+ */
+BITCOINKERNEL_API btck_Mempool* BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_get_mempool(
+    const btck_ChainstateManager* chainstate_manager) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Validate the transaction against the consensus and policy rules on
+ * top of the active chain and the mempool, and add it to the mempool. Mempool
+ * transactions it replaces are removed. Added and removed transactions are
+ * reported through the validation interface.
+ *
+ * @param[in] chainstate_manager  Non-null.
+ * @param[in] transaction         Non-null, transaction to be added.
+ * @param[out] tx_validation_state Non-null, set to the result of validating the transaction.
+ * @return                        0 if the transaction was added or already in the mempool, also when the
+ *                                mempool holds it with a different witness, non-zero otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_process_transaction(
+    btck_ChainstateManager* chainstate_manager,
+    const btck_Transaction* transaction,
+    btck_TxValidationState* tx_validation_state) BITCOINKERNEL_ARG_NONNULL(1, 2, 3);
+
+/**
+ * @brief Check the transaction against the consensus rules that depend on the
+ * UTXO set of the active chain, as if it was included in the next block: its
+ * inputs exist and coinbase inputs are mature, its input values cover its
+ * output values, and its locktime and BIP68 sequence locks are satisfied. The
+ * mempool is not taken into account. The checks of @ref btck_transaction_check
+ * and script verification are not performed.
+ *
+ * @param[in] chainstate_manager   Non-null.
+ * @param[in] transaction          Non-null.
+ * @param[out] tx_validation_state Non-null, set to the result of the checks.
+ * @return                         0 if the transaction passed the checks, non-zero otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_check_transaction_inputs(
+    const btck_ChainstateManager* chainstate_manager,
+    const btck_Transaction* transaction,
+    btck_TxValidationState* tx_validation_state) BITCOINKERNEL_ARG_NONNULL(1, 2, 3);
+
+/**
+ * @brief Create a template for a block on top of the active chain tip. The
+ * block has the required version, bits and timestamp, a coinbase transaction
+ * committing to its height and paying the subsidy and fees to the coinbase
+ * output script, the witness commitment, and, if the mempool is enabled, the
+ * mempool transactions paying the highest fee rates. Its nonce is zero, so
+ * only its proof of work remains to be found.
+ *
+ * When the coinbase transaction is modified, e.g. to add an extra nonce, the
+ * merkle root of the block has to be recalculated.
+ *
+ * @param[in] chainstate_manager     Non-null.
+ * @param[in] coinbase_output_script Non-null, script the coinbase output pays to.
+ * @param[in] block_template_options Non-null, created by @ref btck_block_template_options_create.
+ * @return                           The block template, or null on error.
+ */
+BITCOINKERNEL_API btck_BlockTemplate* BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_create_block_template(
+    const btck_ChainstateManager* chainstate_manager,
+    const btck_ScriptPubkey* coinbase_output_script,
+    const btck_BlockTemplateOptions* block_template_options) BITCOINKERNEL_ARG_NONNULL(1, 2, 3);
+
+/**
+ * @brief Fully validate the block as the next block of the active chain,
+ * without connecting or storing it. The proof of work is not checked, so
+ * block templates can be validated before they are mined.
+ *
+ * @param[in] chainstate_manager     Non-null.
+ * @param[in] block                  Non-null, block whose previous block is the active chain tip.
+ * @param[out] block_validation_state Non-null, set to the result of the validation.
+ * @return                           0 if the block is valid, non-zero otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_test_block_validity(
+    const btck_ChainstateManager* chainstate_manager,
+    const btck_Block* block,
+    btck_BlockValidationState* block_validation_state) BITCOINKERNEL_ARG_NONNULL(1, 2, 3);
+
 /**
  * @brief Retrieve a block tree entry by its block hash.
  *
@@ -1107,6 +2037,87 @@ BITCOINKERNEL_API const btck_BlockTreeEntry* BITCOINKERNEL_WARN_UNUSED_RESULT bt
     const btck_ChainstateManager* chainstate_manager,
     const btck_BlockHash* block_hash) BITCOINKERNEL_ARG_NONNULL(1, 2);
 
+/**
+ * @brief Get the tips of all chains in the block tree of the chainstate
+ * manager, including the tip of the active chain and the tips of forks that
+ * were seen but not activated.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @return                       The chain tips, ordered by descending height.
+ */
+BITCOINKERNEL_API btck_ChainTips* BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_get_chain_tips(
+    const btck_ChainstateManager* chainstate_manager) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Look up an unspent coin in the UTXO set of the active chainstate. The
+ * lookup goes through the chainstate's coins cache, so it reflects blocks that
+ * have not been flushed to disk yet.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @param[in] out_point          Non-null, the out point of the coin.
+ * @return                       The coin, or null if the out point is not in the UTXO set.
+ */
+BITCOINKERNEL_API btck_Coin* BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_get_coin(
+    const btck_ChainstateManager* chainstate_manager,
+    const btck_TransactionOutPoint* out_point) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Look up multiple unspent coins in the UTXO set of the active
+ * chainstate. All lookups are done under a single lock, so the results are
+ * consistent with each other. Like btck_chainstate_manager_get_coin, the
+ * lookups go through the chainstate's coins cache.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @param[in] out_points         Non-null, array of non-null out points.
+ * @param[in] out_points_len     Length of the out points array.
+ * @param[out] coins             Non-null, array of out_points_len entries that is set
+ *                               to the coin of the out point at the same index, or null
+ *                               if that out point is not in the UTXO set. The coins are
+ *                               owned by the caller.
+ */
+BITCOINKERNEL_API void btck_chainstate_manager_get_coins(
+    const btck_ChainstateManager* chainstate_manager,
+    const btck_TransactionOutPoint** out_points,
+    size_t out_points_len,
+    btck_Coin** coins) BITCOINKERNEL_ARG_NONNULL(1, 2, 4);
+
+/**
+ * @brief Write the block index and the coins cache of all chainstates to disk
+ * and empty the coins cache. Failures are also reported through the
+ * flush_error notification.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @return                       0 if the chainstates were written successfully, non-zero on error.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_flush(
+    btck_ChainstateManager* chainstate_manager) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Delete the block and undo files that only contain blocks up to the
+ * given height of the active chain. Requires pruning to be enabled in the
+ * options the chainstate manager was created with. Blocks close to the tip
+ * are always kept, so the height is lowered accordingly.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @param[in] height             Height of the last block that may be pruned.
+ * @return                       0 if pruning was successful, non-zero if pruning is disabled, the chain
+ *                               is too short for pruning or the height is out of range.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_prune_block_files(
+    btck_ChainstateManager* chainstate_manager,
+    int32_t height) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Check whether the data of a block has been deleted by pruning.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @param[in] block_tree_entry   Non-null.
+ * @return                       1 if the block and undo data of the block were pruned, 0 otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_is_block_pruned(
+    const btck_ChainstateManager* chainstate_manager,
+    const btck_BlockTreeEntry* block_tree_entry) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
 /**
  * Destroy the chainstate manager.
  */
@@ -1195,6 +2206,15 @@ BITCOINKERNEL_API int btck_block_to_bytes(
     btck_WriteBytes writer,
     void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 2);
 
+/**
+ * @brief Return the header of a block.
+ *
+ * @param[in] block Non-null.
+ * @return          The block header.
+ */
+BITCOINKERNEL_API btck_BlockHeader* BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_get_header(
+    const btck_Block* block) BITCOINKERNEL_ARG_NONNULL(1);
+
 /**
  * Destroy the block.
  */
@@ -1202,70 +2222,223 @@ BITCOINKERNEL_API void btck_block_destroy(btck_Block* block);
 
 ///@}
 
-/** @name BlockValidationState
- * Functions for working with block validation states.
+/** @name BlockHeader
+ * Functions for working with block headers.
  */
 ///@{
 
 /**
- * Returns the validation mode from an opaque block validation state pointer.
+ * @brief Parse a serialized raw block header into a new block header object.
+ *
+ * @param[in] raw_block_header     Serialized block header.
+ * @param[in] raw_block_header_len Length of the serialized block header.
+ * @return                         The allocated block header, or null on error.
  */
-BITCOINKERNEL_API btck_ValidationMode btck_block_validation_state_get_validation_mode(
-    const btck_BlockValidationState* block_validation_state) BITCOINKERNEL_ARG_NONNULL(1);
+BITCOINKERNEL_API btck_BlockHeader* BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_header_create(
+    const void* raw_block_header, size_t raw_block_header_len);
 
 /**
- * Returns the validation result from an opaque block validation state pointer.
+ * @brief Copy a block header.
+ *
+ * @param[in] block_header Non-null.
+ * @return                 The copied block header.
  */
-BITCOINKERNEL_API btck_BlockValidationResult btck_block_validation_state_get_block_validation_result(
-    const btck_BlockValidationState* block_validation_state) BITCOINKERNEL_ARG_NONNULL(1);
+BITCOINKERNEL_API btck_BlockHeader* BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_header_copy(
+    const btck_BlockHeader* block_header) BITCOINKERNEL_ARG_NONNULL(1);
 
-///@}
-
-/** @name Chain
- * Functions for working with the chain
+/**
+ * @brief Calculate and return the hash of a block header.
+ *
+ * @param[in] block_header Non-null.
+ * @return                 The block hash.
  */
-///@{
+BITCOINKERNEL_API btck_BlockHash* BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_header_get_hash(
+    const btck_BlockHeader* block_header) BITCOINKERNEL_ARG_NONNULL(1);
 
 /**
- * @brief Return the height of the tip of the chain.
+ * @brief Get the hash of the previous block. The returned block hash is not
+ * owned and depends on the lifetime of the block header.
  *
- * @param[in] chain Non-null.
- * @return          The current height.
+ * @param[in] block_header Non-null.
+ * @return                 The previous block hash.
  */
-BITCOINKERNEL_API int32_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_chain_get_height(
-    const btck_Chain* chain) BITCOINKERNEL_ARG_NONNULL(1);
+BITCOINKERNEL_API const btck_BlockHash* BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_header_get_prev_hash(
+    const btck_BlockHeader* block_header) BITCOINKERNEL_ARG_NONNULL(1);
 
 /**
- * @brief Retrieve a block tree entry by its height in the currently active chain.
- * Once retrieved there is no guarantee that it remains in the active chain.
+ * @brief Serializes the merkle root committed to by the block header.
  *
- * @param[in] chain        Non-null.
- * @param[in] block_height Height in the chain of the to be retrieved block tree entry.
- * @return                 The block tree entry at a certain height in the currently active chain, or null
- *                         if the height is out of bounds.
+ * @param[in] block_header Non-null.
+ * @param[out] output      The serialized merkle root.
  */
-BITCOINKERNEL_API const btck_BlockTreeEntry* BITCOINKERNEL_WARN_UNUSED_RESULT btck_chain_get_by_height(
-    const btck_Chain* chain,
-    int block_height) BITCOINKERNEL_ARG_NONNULL(1);
+BITCOINKERNEL_API void btck_block_header_get_merkle_root(
+    const btck_BlockHeader* block_header, unsigned char output[32]) BITCOINKERNEL_ARG_NONNULL(1, 2);
 
 /**
- * @brief Return true if the passed in chain contains the block tree entry.
- *
- * @param[in] chain            Non-null.
- * @param[in] block_tree_entry Non-null.
- * @return                     1 if the block_tree_entry is in the chain, 0 otherwise.
+ * @brief Get the version of the block header.
  *
+ * @param[in] block_header Non-null.
+ * @return                 The block version.
  */
-BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chain_contains(
-    const btck_Chain* chain,
-    const btck_BlockTreeEntry* block_tree_entry) BITCOINKERNEL_ARG_NONNULL(1, 2);
-
-///@}
+BITCOINKERNEL_API int32_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_header_get_version(
+    const btck_BlockHeader* block_header) BITCOINKERNEL_ARG_NONNULL(1);
 
-/** @name BlockSpentOutputs
- * Functions for working with block spent outputs.
+/**
+ * @brief Get the timestamp of the block header.
+ *
+ * @param[in] block_header Non-null.
+ * @return                 The block timestamp in seconds since the unix epoch.
  */
-///@{
+BITCOINKERNEL_API uint32_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_header_get_timestamp(
+    const btck_BlockHeader* block_header) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the compact difficulty target of the block header.
+ *
+ * @param[in] block_header Non-null.
+ * @return                 The nBits value.
+ */
+BITCOINKERNEL_API uint32_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_header_get_bits(
+    const btck_BlockHeader* block_header) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the nonce of the block header.
+ *
+ * @param[in] block_header Non-null.
+ * @return                 The nonce.
+ */
+BITCOINKERNEL_API uint32_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_header_get_nonce(
+    const btck_BlockHeader* block_header) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Serializes the block header through the passed in callback to bytes.
+ *
+ * @param[in] block_header Non-null.
+ * @param[in] writer       Non-null, callback to a write bytes function.
+ * @param[in] user_data    Holds a user-defined opaque structure that will be
+ *                         passed back through the writer callback.
+ * @return                 0 on success.
+ */
+BITCOINKERNEL_API int btck_block_header_to_bytes(
+    const btck_BlockHeader* block_header,
+    btck_WriteBytes writer,
+    void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * Destroy the block header.
+ */
+BITCOINKERNEL_API void btck_block_header_destroy(btck_BlockHeader* block_header);
+
+///@}
+
+/** @name BlockValidationState
+ * Functions for working with block validation states.
+ */
+///@{
+
+/**
+ * @brief Create a new block validation state in the valid mode, to be passed to
+ * functions reporting a validation result through it.
+ *
+ * @return The block validation state.
+ */
+BITCOINKERNEL_API btck_BlockValidationState* BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_validation_state_create();
+
+/**
+ * Returns the validation mode from an opaque block validation state pointer.
+ */
+BITCOINKERNEL_API btck_ValidationMode btck_block_validation_state_get_validation_mode(
+    const btck_BlockValidationState* block_validation_state) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * Returns the validation result from an opaque block validation state pointer.
+ */
+BITCOINKERNEL_API btck_BlockValidationResult btck_block_validation_state_get_block_validation_result(
+    const btck_BlockValidationState* block_validation_state) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Writes the short reject reason of an invalid block (e.g. "bad-txns-inputs-missingorspent")
+ * through the passed in writer. Nothing is written if the block was not rejected.
+ *
+ * @param[in] block_validation_state Non-null.
+ * @param[in] writer                 Non-null, callback to a write bytes function.
+ * @param[in] user_data              Holds a user-defined opaque structure that will be
+ *                                   passed back through the writer callback.
+ * @return                           0 on success.
+ */
+BITCOINKERNEL_API int btck_block_validation_state_get_reject_reason(
+    const btck_BlockValidationState* block_validation_state,
+    btck_WriteBytes writer,
+    void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Writes the debug message of an invalid block, carrying additional
+ * details on the rejection, through the passed in writer. Nothing is written
+ * if there is no debug message.
+ *
+ * @param[in] block_validation_state Non-null.
+ * @param[in] writer                 Non-null, callback to a write bytes function.
+ * @param[in] user_data              Holds a user-defined opaque structure that will be
+ *                                   passed back through the writer callback.
+ * @return                           0 on success.
+ */
+BITCOINKERNEL_API int btck_block_validation_state_get_debug_message(
+    const btck_BlockValidationState* block_validation_state,
+    btck_WriteBytes writer,
+    void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * Destroy the block validation state.
+ */
+BITCOINKERNEL_API void btck_block_validation_state_destroy(btck_BlockValidationState* block_validation_state);
+
+///@}
+
+/** @name Chain
+ * Functions for working with the chain
+ */
+///@{
+
+/**
+ * @brief Return the height of the tip of the chain.
+ *
+ * @param[in] chain Non-null.
+ * @return          The current height.
+ */
+BITCOINKERNEL_API int32_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_chain_get_height(
+    const btck_Chain* chain) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Retrieve a block tree entry by its height in the currently active chain.
+ * Once retrieved there is no guarantee that it remains in the active chain.
+ *
+ * @param[in] chain        Non-null.
+ * @param[in] block_height Height in the chain of the to be retrieved block tree entry.
+ * @return                 The block tree entry at a certain height in the currently active chain, or null
+ *                         if the height is out of bounds.
+ */
+BITCOINKERNEL_API const btck_BlockTreeEntry* BITCOINKERNEL_WARN_UNUSED_RESULT btck_chain_get_by_height(
+    const btck_Chain* chain,
+    int block_height) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Return true if the passed in chain contains the block tree entry.
+ *
+ * @param[in] chain            Non-null.
+ * @param[in] block_tree_entry Non-null.
+ * @return                     1 if the block_tree_entry is in the chain, 0 otherwise.
+ *
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chain_contains(
+    const btck_Chain* chain,
+    const btck_BlockTreeEntry* block_tree_entry) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+///@}
+
+/** @name BlockSpentOutputs
+ * Functions for working with block spent outputs.
+ */
+///@{
 
 /**
  * @brief Reads the block spent coins data the passed in block tree entry points to from
@@ -1279,6 +2452,32 @@ BITCOINKERNEL_API btck_BlockSpentOutputs* BITCOINKERNEL_WARN_UNUSED_RESULT btck_
     const btck_ChainstateManager* chainstate_manager,
     const btck_BlockTreeEntry* block_tree_entry) BITCOINKERNEL_ARG_NONNULL(1, 2);
 
+/**
+ * @brief Parse serialized block undo data, as stored in the rev*.dat files,
+ * into block spent outputs.
+ *
+ * @param[in] raw_block_spent_outputs     Non-null, serialized block undo data.
+ * @param[in] raw_block_spent_outputs_len Length of the serialized block undo data.
+ * @return                                The allocated block spent outputs, or null on error.
+ */
+BITCOINKERNEL_API btck_BlockSpentOutputs* BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_spent_outputs_create(
+    const void* raw_block_spent_outputs, size_t raw_block_spent_outputs_len);
+
+/**
+ * @brief Serializes the block spent outputs in the block undo data format
+ * used by the rev*.dat files.
+ *
+ * @param[in] block_spent_outputs Non-null.
+ * @param[in] writer              Non-null, callback to a write bytes function.
+ * @param[in] user_data           Holds a user-defined opaque structure that will be
+ *                                passed back through the writer callback.
+ * @return                        0 on success.
+ */
+BITCOINKERNEL_API int btck_block_spent_outputs_to_bytes(
+    const btck_BlockSpentOutputs* block_spent_outputs,
+    btck_WriteBytes writer,
+    void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
 /**
  * @brief Copy a block's spent outputs.
  *
@@ -1387,6 +2586,56 @@ BITCOINKERNEL_API btck_TransactionInput* BITCOINKERNEL_WARN_UNUSED_RESULT btck_t
 BITCOINKERNEL_API const btck_TransactionOutPoint* BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_input_get_out_point(
     const btck_TransactionInput* transaction_input) BITCOINKERNEL_ARG_NONNULL(1);
 
+/**
+ * @brief Get the sequence number (nSequence) of the transaction input.
+ *
+ * @param[in] transaction_input Non-null.
+ * @return                      The sequence number.
+ */
+BITCOINKERNEL_API uint32_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_input_get_sequence(
+    const btck_TransactionInput* transaction_input) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Serializes the script sig of the transaction input through the passed
+ * in callback to bytes.
+ *
+ * @param[in] transaction_input Non-null.
+ * @param[in] writer            Non-null, callback to a write bytes function.
+ * @param[in] user_data         Holds a user-defined opaque structure that will be
+ *                              passed back through the writer callback.
+ * @return                      0 on success.
+ */
+BITCOINKERNEL_API int btck_transaction_input_script_sig_to_bytes(
+    const btck_TransactionInput* transaction_input,
+    btck_WriteBytes writer,
+    void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Get the number of items on the witness stack of the transaction input.
+ *
+ * @param[in] transaction_input Non-null.
+ * @return                      The number of witness stack items.
+ */
+BITCOINKERNEL_API size_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_input_count_witness_items(
+    const btck_TransactionInput* transaction_input) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Serializes the witness stack item at the provided index through the
+ * passed in callback to bytes.
+ *
+ * @param[in] transaction_input Non-null.
+ * @param[in] witness_index     The index of the witness stack item.
+ * @param[in] writer            Non-null, callback to a write bytes function.
+ * @param[in] user_data         Holds a user-defined opaque structure that will be
+ *                              passed back through the writer callback.
+ * @return                      0 on success.
+ */
+BITCOINKERNEL_API int btck_transaction_input_witness_item_to_bytes(
+    const btck_TransactionInput* transaction_input,
+    size_t witness_index,
+    btck_WriteBytes writer,
+    void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 3);
+
 /**
  * Destroy the transaction input.
  */
@@ -1399,6 +2648,16 @@ BITCOINKERNEL_API void btck_transaction_input_destroy(btck_TransactionInput* tra
  */
 ///@{
 
+/**
+ * @brief Create a transaction out point from a txid and an output index.
+ *
+ * @param[in] txid  Non-null, 32 bytes of the txid in internal byte order.
+ * @param[in] index The output index.
+ * @return          The transaction out point.
+ */
+BITCOINKERNEL_API btck_TransactionOutPoint* BITCOINKERNEL_WARN_UNUSED_RESULT btck_transaction_out_point_create(
+    const unsigned char txid[32], uint32_t index) BITCOINKERNEL_ARG_NONNULL(1);
+
 /**
  * @brief Copy a transaction out point.
  *
@@ -1526,6 +2785,631 @@ BITCOINKERNEL_API void btck_coin_destroy(btck_Coin* coin);
 
 ///@}
 
+/** @name CoinsCursor
+ * Functions for iterating over the UTXO set.
+ */
+///@{
+
+/**
+ * @brief Create a cursor over the UTXO set of the active chainstate. The
+ * chainstate is flushed to disk first, so that the cursor covers all blocks
+ * connected so far. Blocks processed after the cursor was created are not
+ * reflected in its results. The cursor must be destroyed before the chainstate
+ * manager.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @return                       The cursor, or null if flushing the chainstate failed.
+ */
+BITCOINKERNEL_API btck_CoinsCursor* BITCOINKERNEL_WARN_UNUSED_RESULT btck_coins_cursor_create(
+    btck_ChainstateManager* chainstate_manager) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the hash of the block the UTXO set of the cursor corresponds to.
+ *
+ * @param[in] coins_cursor Non-null.
+ * @return                 The block hash.
+ */
+BITCOINKERNEL_API btck_BlockHash* BITCOINKERNEL_WARN_UNUSED_RESULT btck_coins_cursor_get_block_hash(
+    const btck_CoinsCursor* coins_cursor) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Read the coin at the current position of the cursor and advance it.
+ *
+ * @param[in] coins_cursor Non-null.
+ * @param[out] out_point   Non-null, set to the out point of the coin if one was read.
+ * @param[out] coin        Non-null, set to the coin if one was read.
+ * @return                 1 if a coin was read, 0 if the cursor is exhausted,
+ *                         -1 if the coins database could not be read.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_coins_cursor_next(
+    btck_CoinsCursor* coins_cursor,
+    btck_TransactionOutPoint** out_point,
+    btck_Coin** coin) BITCOINKERNEL_ARG_NONNULL(1, 2, 3);
+
+/**
+ * Destroy the coins cursor.
+ */
+BITCOINKERNEL_API void btck_coins_cursor_destroy(btck_CoinsCursor* coins_cursor);
+
+///@}
+
+/** @name UtxoSetStats
+ * Functions for working with UTXO set statistics.
+ */
+///@{
+
+/**
+ * @brief Compute statistics of the UTXO set of the active chainstate, including
+ * both its MuHash and its hash_serialized_3 commitment. The chainstate is
+ * flushed to disk first. Both commitments are computed in a single pass over a
+ * snapshot of the coins database, so block processing is only blocked while
+ * flushing.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @return                       The statistics, or null on error.
+ */
+BITCOINKERNEL_API btck_UtxoSetStats* BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_get_utxo_set_stats(
+    btck_ChainstateManager* chainstate_manager) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the height of the block the statistics correspond to.
+ *
+ * @param[in] utxo_set_stats Non-null.
+ * @return                   The block height.
+ */
+BITCOINKERNEL_API int32_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_utxo_set_stats_get_height(
+    const btck_UtxoSetStats* utxo_set_stats) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the hash of the block the statistics correspond to.
+ *
+ * @param[in] utxo_set_stats Non-null.
+ * @return                   The block hash.
+ */
+BITCOINKERNEL_API btck_BlockHash* BITCOINKERNEL_WARN_UNUSED_RESULT btck_utxo_set_stats_get_block_hash(
+    const btck_UtxoSetStats* utxo_set_stats) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the number of transactions with unspent outputs.
+ *
+ * @param[in] utxo_set_stats Non-null.
+ * @return                   The transaction count.
+ */
+BITCOINKERNEL_API uint64_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_utxo_set_stats_get_transaction_count(
+    const btck_UtxoSetStats* utxo_set_stats) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the number of unspent outputs.
+ *
+ * @param[in] utxo_set_stats Non-null.
+ * @return                   The coin count.
+ */
+BITCOINKERNEL_API uint64_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_utxo_set_stats_get_coin_count(
+    const btck_UtxoSetStats* utxo_set_stats) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the total amount of all unspent outputs.
+ *
+ * @param[in] utxo_set_stats Non-null.
+ * @param[out] total_amount  Non-null, set to the total amount in satoshis.
+ * @return                   0 on success, -1 if the total amount overflowed.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_utxo_set_stats_get_total_amount(
+    const btck_UtxoSetStats* utxo_set_stats, int64_t* total_amount) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Get the database-independent size metric of the UTXO set ("bogosize").
+ *
+ * @param[in] utxo_set_stats Non-null.
+ * @return                   The bogosize.
+ */
+BITCOINKERNEL_API uint64_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_utxo_set_stats_get_bogo_size(
+    const btck_UtxoSetStats* utxo_set_stats) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the estimated size of the coins database on disk.
+ *
+ * @param[in] utxo_set_stats Non-null.
+ * @return                   The disk size in bytes.
+ */
+BITCOINKERNEL_API uint64_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_utxo_set_stats_get_disk_size(
+    const btck_UtxoSetStats* utxo_set_stats) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the MuHash commitment of the UTXO set.
+ *
+ * @param[in] utxo_set_stats Non-null.
+ * @param[out] output        The 32-byte MuHash digest.
+ */
+BITCOINKERNEL_API void btck_utxo_set_stats_get_muhash(
+    const btck_UtxoSetStats* utxo_set_stats, unsigned char output[32]) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Get the hash_serialized_3 commitment of the UTXO set, the hash used
+ * by assumeutxo snapshots.
+ *
+ * @param[in] utxo_set_stats Non-null.
+ * @param[out] output        The 32-byte hash.
+ */
+BITCOINKERNEL_API void btck_utxo_set_stats_get_hash_serialized(
+    const btck_UtxoSetStats* utxo_set_stats, unsigned char output[32]) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * Destroy the UTXO set statistics.
+ */
+BITCOINKERNEL_API void btck_utxo_set_stats_destroy(btck_UtxoSetStats* utxo_set_stats);
+
+///@}
+
+/** @name ChainTips
+ * Functions for working with chain tips.
+ */
+///@{
+
+/**
+ * @brief Get the number of chain tips.
+ *
+ * @param[in] chain_tips Non-null.
+ * @return               The number of chain tips.
+ */
+BITCOINKERNEL_API size_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_chain_tips_count(
+    const btck_ChainTips* chain_tips) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the block tree entry of the chain tip at the specified index. Its
+ * lifetime is dependent on the chainstate manager the chain tips were
+ * retrieved from.
+ *
+ * @param[in] chain_tips Non-null.
+ * @param[in] index      Index of the chain tip, must be smaller than the count.
+ * @return               The block tree entry of the chain tip.
+ */
+BITCOINKERNEL_API const btck_BlockTreeEntry* BITCOINKERNEL_WARN_UNUSED_RESULT btck_chain_tips_get_entry_at(
+    const btck_ChainTips* chain_tips, size_t index) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the status of the chain tip at the specified index.
+ *
+ * @param[in] chain_tips Non-null.
+ * @param[in] index      Index of the chain tip, must be smaller than the count.
+ * @return               The status of the chain tip.
+ */
+BITCOINKERNEL_API btck_ChainTipStatus BITCOINKERNEL_WARN_UNUSED_RESULT btck_chain_tips_get_status_at(
+    const btck_ChainTips* chain_tips, size_t index) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the length of the branch connecting the chain tip at the specified
+ * index to the active chain, which is 0 for the active tip.
+ *
+ * @param[in] chain_tips Non-null.
+ * @param[in] index      Index of the chain tip, must be smaller than the count.
+ * @return               The branch length.
+ */
+BITCOINKERNEL_API int32_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_chain_tips_get_branch_length_at(
+    const btck_ChainTips* chain_tips, size_t index) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * Destroy the chain tips.
+ */
+BITCOINKERNEL_API void btck_chain_tips_destroy(btck_ChainTips* chain_tips);
+
+///@}
+
+/** @name Mempool
+ * Functions for working with the mempool.
+ */
+///@{
+
+/**
+ * @brief Returns the number of transactions in the mempool.
+ *
+ * @param[in] mempool Non-null.
+ * @return            The number of transactions.
+ */
+BITCOINKERNEL_API size_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_mempool_size(
+    const btck_Mempool* mempool) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Checks whether a transaction with the txid is in the mempool.
+ *
+ * @param[in] mempool Non-null.
+ * @param[in] txid    Non-null.
+ * @return            1 if the transaction is in the mempool, 0 otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_mempool_contains(
+    const btck_Mempool* mempool,
+    const btck_Txid* txid) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Returns the mempool entry of the transaction with the txid.
+ *
+ * @param[in] mempool Non-null.
+ * @param[in] txid    Non-null.
+ * @return            The mempool entry, or null if the transaction is not in the mempool.
+ */
+BITCOINKERNEL_API btck_MempoolEntry* BITCOINKERNEL_WARN_UNUSED_RESULT btck_mempool_get_entry(
+    const btck_Mempool* mempool,
+    const btck_Txid* txid) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Returns the entries of all transactions in the mempool, ordered such
+ * that parents come before their children.
+ *
+ * @param[in] mempool Non-null.
+ * @return            The mempool entries.
+ */
+BITCOINKERNEL_API btck_MempoolEntries* BITCOINKERNEL_WARN_UNUSED_RESULT btck_mempool_get_entries(
+    const btck_Mempool* mempool) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Returns the entries of the in-mempool ancestors of the transaction
+ * with the txid, not including the transaction itself, ordered such that
+ * parents come before their children.
+ *
+ * @param[in] mempool Non-null.
+ * @param[in] txid    Non-null.
+ * @return            The mempool entries, or null if the transaction is not in the mempool.
+ */
+BITCOINKERNEL_API btck_MempoolEntries* BITCOINKERNEL_WARN_UNUSED_RESULT btck_mempool_get_ancestors(
+    const btck_Mempool* mempool,
+    const btck_Txid* txid) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Returns the entries of the in-mempool descendants of the transaction
+ * with the txid, not including the transaction itself, ordered such that
+ * parents come before their children.
+ *
+ * @param[in] mempool Non-null.
+ * @param[in] txid    Non-null.
+ * @return            The mempool entries, or null if the transaction is not in the mempool.
+ */
+BITCOINKERNEL_API btck_MempoolEntries* BITCOINKERNEL_WARN_UNUSED_RESULT btck_mempool_get_descendants(
+    const btck_Mempool* mempool,
+    const btck_Txid* txid) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Remove the transaction with the txid and all its descendants from the
+ * mempool. The removals are reported through the validation interface with
+ * the manual removal reason.
+ *
+ * @param[in] mempool Non-null.
+ * @param[in] txid    Non-null.
+ * @return            0 if the transaction was removed, non-zero if it is not in the mempool.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_mempool_remove_transaction(
+    btck_Mempool* mempool,
+    const btck_Txid* txid) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+///@}
+
+/** @name MempoolEntry
+ * Functions for working with mempool entries.
+ */
+///@{
+
+/**
+ * @brief Get the transaction of the mempool entry. The returned transaction is
+ * a reference to the transaction held by the entry and only valid for its
+ * lifetime.
+ *
+ * @param[in] mempool_entry Non-null.
+ * @return                  The transaction.
+ */
+BITCOINKERNEL_API const btck_Transaction* BITCOINKERNEL_WARN_UNUSED_RESULT btck_mempool_entry_get_transaction(
+    const btck_MempoolEntry* mempool_entry) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the fee the transaction of the mempool entry pays.
+ *
+ * @param[in] mempool_entry Non-null.
+ * @return                  The fee in satoshis.
+ */
+BITCOINKERNEL_API int64_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_mempool_entry_get_fee(
+    const btck_MempoolEntry* mempool_entry) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the virtual size of the transaction of the mempool entry, as used
+ * for its fee rate.
+ *
+ * @param[in] mempool_entry Non-null.
+ * @return                  The virtual size in vbytes.
+ */
+BITCOINKERNEL_API int32_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_mempool_entry_get_vsize(
+    const btck_MempoolEntry* mempool_entry) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the time the transaction of the mempool entry entered the mempool.
+ *
+ * @param[in] mempool_entry Non-null.
+ * @return                  The time in seconds since the unix epoch.
+ */
+BITCOINKERNEL_API int64_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_mempool_entry_get_time(
+    const btck_MempoolEntry* mempool_entry) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * Destroy the mempool entry.
+ */
+BITCOINKERNEL_API void btck_mempool_entry_destroy(btck_MempoolEntry* mempool_entry);
+
+///@}
+
+/** @name MempoolEntries
+ * Functions for working with lists of mempool entries.
+ */
+///@{
+
+/**
+ * @brief Returns the number of mempool entries in the list.
+ *
+ * @param[in] mempool_entries Non-null.
+ * @return                    The number of entries.
+ */
+BITCOINKERNEL_API size_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_mempool_entries_count(
+    const btck_MempoolEntries* mempool_entries) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Returns the mempool entry at the index. The returned entry is only
+ * valid for the lifetime of the list.
+ *
+ * @param[in] mempool_entries Non-null.
+ * @param[in] index           Index of the entry, must be smaller than the count.
+ * @return                    The mempool entry.
+ */
+BITCOINKERNEL_API const btck_MempoolEntry* BITCOINKERNEL_WARN_UNUSED_RESULT btck_mempool_entries_get_at(
+    const btck_MempoolEntries* mempool_entries, size_t index) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * Destroy the list of mempool entries.
+ */
+BITCOINKERNEL_API void btck_mempool_entries_destroy(btck_MempoolEntries* mempool_entries);
+
+///@}
+
+/** @name TxValidationState
+ * Functions for working with transaction validation states.
+ */
+///@{
+
+/**
+ * @brief Create a new transaction validation state in the valid mode, to be
+ * passed to functions reporting a validation result through it.
+ *
+ * @return The transaction validation state.
+ */
+BITCOINKERNEL_API btck_TxValidationState* BITCOINKERNEL_WARN_UNUSED_RESULT btck_tx_validation_state_create();
+
+/**
+ * Returns the validation mode from an opaque transaction validation state pointer.
+ */
+BITCOINKERNEL_API btck_ValidationMode btck_tx_validation_state_get_validation_mode(
+    const btck_TxValidationState* tx_validation_state) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * Returns the validation result from an opaque transaction validation state pointer.
+ */
+BITCOINKERNEL_API btck_TxValidationResult btck_tx_validation_state_get_tx_validation_result(
+    const btck_TxValidationState* tx_validation_state) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Writes the short reject reason of an invalid transaction (e.g.
+ * "bad-txns-inputs-missingorspent") through the passed in writer. Nothing is
+ * written if the transaction was not rejected.
+ *
+ * @param[in] tx_validation_state Non-null.
+ * @param[in] writer              Non-null, callback to a write bytes function.
+ * @param[in] user_data           Holds a user-defined opaque structure that will be
+ *                                passed back through the writer callback.
+ * @return                        0 on success.
+ */
+BITCOINKERNEL_API int btck_tx_validation_state_get_reject_reason(
+    const btck_TxValidationState* tx_validation_state,
+    btck_WriteBytes writer,
+    void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Writes the debug message of an invalid transaction, carrying
+ * additional details on the rejection, through the passed in writer. Nothing
+ * is written if there is no debug message.
+ *
+ * @param[in] tx_validation_state Non-null.
+ * @param[in] writer              Non-null, callback to a write bytes function.
+ * @param[in] user_data           Holds a user-defined opaque structure that will be
+ *                                passed back through the writer callback.
+ * @return                        0 on success.
+ */
+BITCOINKERNEL_API int btck_tx_validation_state_get_debug_message(
+    const btck_TxValidationState* tx_validation_state,
+    btck_WriteBytes writer,
+    void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * Destroy the transaction validation state.
+ */
+BITCOINKERNEL_API void btck_tx_validation_state_destroy(btck_TxValidationState* tx_validation_state);
+
+///@}
+
+/** @name BlockTemplateOptions
+ * Functions for working with block template options.
+ */
+///@{
+
+/**
+ * @brief Creates options for creating a block template. By default, mempool
+ * transactions are included up to the maximum block weight if they pay at
+ * least 1 satoshi per kvB.
+ *
+ * @return The allocated block template options.
+ */
+BITCOINKERNEL_API btck_BlockTemplateOptions* BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_template_options_create();
+
+/**
+ * @brief Sets whether mempool transactions are included in the block template.
+ *
+ * @param[in] block_template_options Non-null, created by @ref btck_block_template_options_create.
+ * @param[in] use_mempool            Set 0 to create a block containing only the coinbase transaction.
+ */
+BITCOINKERNEL_API void btck_block_template_options_set_use_mempool(
+    btck_BlockTemplateOptions* block_template_options,
+    int use_mempool) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Sets the maximum weight of the block template.
+ *
+ * @param[in] block_template_options Non-null, created by @ref btck_block_template_options_create.
+ * @param[in] max_weight             Maximum block weight, not above the consensus limit of 4000000.
+ * @return                           0 on success, non-zero if the weight is above the consensus limit.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_template_options_set_max_weight(
+    btck_BlockTemplateOptions* block_template_options,
+    uint64_t max_weight) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Sets the minimum fee rate of the mempool transactions included in the
+ * block template.
+ *
+ * @param[in] block_template_options Non-null, created by @ref btck_block_template_options_create.
+ * @param[in] min_fee_rate           Minimum fee rate in satoshis per kvB, not negative.
+ * @return                           0 on success, non-zero if the fee rate is negative.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_template_options_set_min_fee_rate(
+    btck_BlockTemplateOptions* block_template_options,
+    int64_t min_fee_rate) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * Destroy the block template options.
+ */
+BITCOINKERNEL_API void btck_block_template_options_destroy(btck_BlockTemplateOptions* block_template_options);
+
+///@}
+
+/** @name BlockTemplate
+ * Functions for working with block templates.
+ */
+///@{
+
+/**
+ * @brief Returns the block of the block template. Its lifetime is dependent
+ * on the block template.
+ *
+ * @param[in] block_template Non-null.
+ * @return                   The block.
+ */
+BITCOINKERNEL_API const btck_Block* BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_template_get_block(
+    const btck_BlockTemplate* block_template) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Returns the earliest timestamp the block may have, i.e. one second
+ * after the median time of the past blocks, respecting the timewarp rule.
+ *
+ * @param[in] block_template Non-null.
+ * @return                   The minimum block timestamp in seconds since the epoch.
+ */
+BITCOINKERNEL_API int64_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_template_get_min_time(
+    const btck_BlockTemplate* block_template) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Returns the latest timestamp the block may have when it is validated
+ * at the time the template was created, i.e. two hours in the future.
+ *
+ * @param[in] block_template Non-null.
+ * @return                   The maximum block timestamp in seconds since the epoch.
+ */
+BITCOINKERNEL_API int64_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_template_get_max_time(
+    const btck_BlockTemplate* block_template) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Returns the sum of the fees of the transactions in the block,
+ * excluding the coinbase transaction.
+ *
+ * @param[in] block_template Non-null.
+ * @return                   The fees in satoshis.
+ */
+BITCOINKERNEL_API int64_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_block_template_get_fees(
+    const btck_BlockTemplate* block_template) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * Destroy the block template.
+ */
+BITCOINKERNEL_API void btck_block_template_destroy(btck_BlockTemplate* block_template);
+
+///@}
+
+/** @name Snapshot
+ * Functions for dumping and loading assumeutxo UTXO set snapshots.
+ */
+///@{
+
+/**
+ * @brief Write the UTXO set of the active chainstate as a snapshot in the
+ * format of Bitcoin Core's dumptxoutset. The chainstate is flushed to disk
+ * first. Block processing is only blocked until the coins database has been
+ * counted, the coins are then written from a consistent view of it.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @param[in] writer             Non-null, function called with the serialized snapshot data.
+ * @param[in] user_data          Holds a user-defined opaque structure that is passed to the writer.
+ * @return                       0 if the snapshot was written successfully, non-zero on error.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_dump_snapshot(
+    btck_ChainstateManager* chainstate_manager,
+    btck_WriteBytes writer,
+    void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Load a UTXO snapshot in the format of Bitcoin Core's dumptxoutset and
+ * activate a chainstate based on it. The base block of the snapshot must be
+ * one of the assumeutxo entries of the chain parameters, and its header must
+ * already be known. Blocks processed afterwards extend the snapshot
+ * chainstate, while the blocks up to its base are validated in the background.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @param[in] snapshot_path      Non-null, path of the snapshot file.
+ * @param[in] snapshot_path_len  Length of the path.
+ * @return                       0 if the snapshot chainstate was activated, non-zero on error.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_load_snapshot(
+    btck_ChainstateManager* chainstate_manager,
+    const char* snapshot_path,
+    size_t snapshot_path_len) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Load a UTXO snapshot like @ref btck_chainstate_manager_load_snapshot,
+ * reading it through the reader callback. As snapshots are activated from a
+ * file, the data is copied to a temporary file in the data directory of the
+ * chainstate manager, which is removed again once the snapshot is loaded.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @param[in] reader             Non-null, function called to read the serialized snapshot data.
+ * @param[in] user_data          Holds a user-defined opaque structure that is passed to the reader.
+ * @return                       0 if the snapshot chainstate was activated, non-zero on error.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_chainstate_manager_load_snapshot_from_reader(
+    btck_ChainstateManager* chainstate_manager,
+    btck_ReadBytes reader,
+    void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 2);
+
+/**
+ * @brief Get the base block of the UTXO snapshot the chainstate manager uses.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @return                       The block tree entry of the base block, or null if no snapshot was loaded.
+ */
+BITCOINKERNEL_API const btck_BlockTreeEntry* btck_chainstate_manager_get_snapshot_base_block(
+    const btck_ChainstateManager* chainstate_manager) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the tip of the background chainstate validating the UTXO snapshot.
+ *
+ * @param[in] chainstate_manager Non-null.
+ * @return                       The block tree entry of the background tip, or null if no background
+ *                               validation is in progress.
+ */
+BITCOINKERNEL_API const btck_BlockTreeEntry* btck_chainstate_manager_get_background_validation_tip(
+    const btck_ChainstateManager* chainstate_manager) BITCOINKERNEL_ARG_NONNULL(1);
+
+///@}
+
 /** @name BlockHash
  * Functions for working with block hashes.
  */
@@ -1572,6 +3456,96 @@ BITCOINKERNEL_API void btck_block_hash_destroy(btck_BlockHash* block_hash);
 
 ///@}
 
+/** @name ScriptTraceStep
+ * Functions for inspecting a traced script execution step.
+ */
+///@{
+
+/**
+ * @brief Get the position of the step's opcode within the evaluated script,
+ * counted in opcodes.
+ *
+ * @param[in] step Non-null.
+ * @return         The opcode position.
+ */
+BITCOINKERNEL_API uint32_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_script_trace_step_get_opcode_position(
+    const btck_ScriptTraceStep* step) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the opcode of the step. Data pushes report their push opcode.
+ *
+ * @param[in] step Non-null.
+ * @return         The opcode.
+ */
+BITCOINKERNEL_API uint8_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_script_trace_step_get_opcode(
+    const btck_ScriptTraceStep* step) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Returns whether the opcode is in an executed branch. Opcodes in
+ * branches not taken are skipped, except for conditionals.
+ *
+ * @param[in] step Non-null.
+ * @return         1 if the opcode is in an executed branch, 0 otherwise.
+ */
+BITCOINKERNEL_API int BITCOINKERNEL_WARN_UNUSED_RESULT btck_script_trace_step_is_executed(
+    const btck_ScriptTraceStep* step) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Get the number of items on the main stack after the step.
+ *
+ * @param[in] step Non-null.
+ * @return         The number of stack items.
+ */
+BITCOINKERNEL_API size_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_script_trace_step_count_stack_items(
+    const btck_ScriptTraceStep* step) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Writes the main stack item at the provided index, counted from the
+ * bottom of the stack, through the passed in callback.
+ *
+ * @param[in] step      Non-null.
+ * @param[in] index     The index of the stack item, must be less than
+ *                      btck_script_trace_step_count_stack_items.
+ * @param[in] writer    Non-null, callback to a write bytes function.
+ * @param[in] user_data Holds a user-defined opaque structure that will be
+ *                      passed back through the writer callback.
+ * @return              0 on success.
+ */
+BITCOINKERNEL_API int btck_script_trace_step_stack_item_to_bytes(
+    const btck_ScriptTraceStep* step,
+    size_t index,
+    btck_WriteBytes writer,
+    void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 3);
+
+/**
+ * @brief Get the number of items on the alternate stack after the step.
+ *
+ * @param[in] step Non-null.
+ * @return         The number of alternate stack items.
+ */
+BITCOINKERNEL_API size_t BITCOINKERNEL_WARN_UNUSED_RESULT btck_script_trace_step_count_altstack_items(
+    const btck_ScriptTraceStep* step) BITCOINKERNEL_ARG_NONNULL(1);
+
+/**
+ * @brief Writes the alternate stack item at the provided index, counted from
+ * the bottom of the stack, through the passed in callback.
+ *
+ * @param[in] step      Non-null.
+ * @param[in] index     The index of the stack item, must be less than
+ *                      btck_script_trace_step_count_altstack_items.
+ * @param[in] writer    Non-null, callback to a write bytes function.
+ * @param[in] user_data Holds a user-defined opaque structure that will be
+ *                      passed back through the writer callback.
+ * @return              0 on success.
+ */
+BITCOINKERNEL_API int btck_script_trace_step_altstack_item_to_bytes(
+    const btck_ScriptTraceStep* step,
+    size_t index,
+    btck_WriteBytes writer,
+    void* user_data) BITCOINKERNEL_ARG_NONNULL(1, 3);
+
+///@}
+
 #ifdef __cplusplus
 } // extern "C"
 #endif // __cplusplus
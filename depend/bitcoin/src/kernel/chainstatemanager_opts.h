@@ -22,6 +22,12 @@ class CChainParams;
 class ValidationSignals;
 
 static constexpr auto DEFAULT_MAX_TIP_AGE{24h};
+/** Default time window to wait between writing blocks/block index and chainstate to disk.
+ *  Randomize writing time inside the window to prevent a situation where the
+ *  network over time settles into a few cohorts of synchronized writers.
+*/
+static constexpr auto DEFAULT_DATABASE_WRITE_INTERVAL_MIN{50min};
+static constexpr auto DEFAULT_DATABASE_WRITE_INTERVAL_MAX{70min};
 
 namespace kernel {
 
@@ -40,6 +46,9 @@ struct ChainstateManagerOpts {
     std::optional<uint256> assumed_valid_block{};
     //! If the tip is older than this, the node is considered to be in initial block download.
     std::chrono::seconds max_tip_age{DEFAULT_MAX_TIP_AGE};
+    //! The chainstate is written to disk periodically, after a random time between these bounds.
+    std::chrono::seconds database_write_interval_min{DEFAULT_DATABASE_WRITE_INTERVAL_MIN};
+    std::chrono::seconds database_write_interval_max{DEFAULT_DATABASE_WRITE_INTERVAL_MAX};
     DBOptions coins_db{};
     CoinsViewOptions coins_view{};
     Notifications& notifications;
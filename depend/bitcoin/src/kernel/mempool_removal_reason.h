@@ -17,6 +17,7 @@ enum class MemPoolRemovalReason {
     BLOCK,       //!< Removed for block
     CONFLICT,    //!< Removed for conflict with in-block transaction
     REPLACED,    //!< Removed for replacement
+    MANUAL,      //!< Removed on request of the kernel library user
 };
 
 std::string RemovalReasonToString(const MemPoolRemovalReason& r) noexcept;
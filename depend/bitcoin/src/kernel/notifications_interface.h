@@ -43,6 +43,18 @@ public:
     virtual void warningSet(Warning id, const bilingual_str& message) {}
     virtual void warningUnset(Warning id) {}
 
+    //! The snapshot activated notification is sent once a chainstate loaded
+    //! from an assumeutxo snapshot based on the given block has become the
+    //! active chainstate. The blocks up to its base are validated in the
+    //! background from then on.
+    virtual void snapshotActivated(const CBlockIndex& base) {}
+
+    //! The snapshot validated notification is sent once the background
+    //! chainstate has reached the base block of the assumeutxo snapshot and
+    //! its UTXO set matched the one of the snapshot. A snapshot failing
+    //! validation is reported through the fatal error notification instead.
+    virtual void snapshotValidated(const CBlockIndex& base) {}
+
     //! The flush error notification is sent to notify the user that an error
     //! occurred while flushing block data to disk. Kernel code may ignore flush
     //! errors that don't affect the immediate operation it is trying to
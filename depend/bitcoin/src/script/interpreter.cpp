@@ -433,6 +433,8 @@ bool EvalScript(std::vector<std::vector<unsigned char> >& stack, const CScript&
     uint32_t opcode_pos = 0;
     execdata.m_codeseparator_pos = 0xFFFFFFFFUL;
     execdata.m_codeseparator_pos_init = true;
+    ScriptExecutionTracer* const tracer{checker.GetTracer()};
+    if (tracer) tracer->OnScriptStart(script, sigversion);
 
     try
     {
@@ -1218,6 +1220,8 @@ bool EvalScript(std::vector<std::vector<unsigned char> >& stack, const CScript&
                     return set_error(serror, SCRIPT_ERR_BAD_OPCODE);
             }
 
+            if (tracer) tracer->OnStep(opcode_pos, static_cast<uint8_t>(opcode), fExec, stack, altstack);
+
             // Size limits
             if (stack.size() + altstack.size() > MAX_STACK_SIZE)
                 return set_error(serror, SCRIPT_ERR_STACK_SIZE);
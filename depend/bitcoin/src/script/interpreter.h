@@ -271,6 +271,21 @@ public:
 template <class T>
 uint256 SignatureHash(const CScript& scriptCode, const T& txTo, unsigned int nIn, int32_t nHashType, const CAmount& amount, SigVersion sigversion, const PrecomputedTransactionData* cache = nullptr, SigHashCache* sighash_cache = nullptr);
 
+/** Observes the interpreter state while scripts are evaluated, for debugging purposes only. */
+class ScriptExecutionTracer
+{
+public:
+    /** Called before the evaluation of a script starts. */
+    virtual void OnScriptStart(const CScript& script, SigVersion sigversion) = 0;
+
+    /** Called after each opcode was read and, if it is in an executed branch, executed. */
+    virtual void OnStep(uint32_t opcode_pos, uint8_t opcode, bool executed,
+                        const std::vector<std::vector<unsigned char>>& stack,
+                        const std::vector<std::vector<unsigned char>>& altstack) = 0;
+
+    virtual ~ScriptExecutionTracer() = default;
+};
+
 class BaseSignatureChecker
 {
 public:
@@ -294,6 +309,12 @@ public:
          return false;
     }
 
+    /** Tracer notified during script evaluation, or nullptr if execution is not traced. */
+    virtual ScriptExecutionTracer* GetTracer() const
+    {
+        return nullptr;
+    }
+
     virtual ~BaseSignatureChecker() = default;
 };
 
@@ -362,6 +383,10 @@ public:
     {
         return m_checker.CheckSequence(nSequence);
     }
+    ScriptExecutionTracer* GetTracer() const override
+    {
+        return m_checker.GetTracer();
+    }
 };
 
 /** Compute the BIP341 tapleaf hash from leaf version & script. */
@@ -90,12 +90,6 @@ using node::SnapshotMetadata;
 
 /** Size threshold for warning about slow UTXO set flush to disk. */
 static constexpr size_t WARN_FLUSH_COINS_SIZE = 1 << 30; // 1 GiB
-/** Time window to wait between writing blocks/block index and chainstate to disk.
- *  Randomize writing time inside the window to prevent a situation where the
- *  network over time settles into a few cohorts of synchronized writers.
-*/
-static constexpr auto DATABASE_WRITE_INTERVAL_MIN{50min};
-static constexpr auto DATABASE_WRITE_INTERVAL_MAX{70min};
 /** Maximum age of our tip for us to be considered current for fee estimation */
 static constexpr std::chrono::hours MAX_FEE_ESTIMATION_TIP_AGE{3};
 const std::vector<std::string> CHECKLEVEL_DOC {
@@ -2841,8 +2835,9 @@ bool Chainstate::FlushStateToDisk(
         }
 
         if (should_write || m_next_write == NodeClock::time_point::max()) {
-            constexpr auto range{DATABASE_WRITE_INTERVAL_MAX - DATABASE_WRITE_INTERVAL_MIN};
-            m_next_write = FastRandomContext().rand_uniform_delay(NodeClock::now() + DATABASE_WRITE_INTERVAL_MIN, range);
+            const auto& opts{m_chainman.m_options};
+            const auto range{opts.database_write_interval_max - opts.database_write_interval_min};
+            m_next_write = FastRandomContext().rand_uniform_delay(NodeClock::now() + opts.database_write_interval_min, range);
         }
     }
     if (full_flush_completed && m_chainman.m_options.signals) {
@@ -5789,7 +5784,7 @@ util::Result<CBlockIndex*> ChainstateManager::ActivateSnapshot(
 
     // Transfer possession of the mempool to the snapshot chainstate.
     // Mempool is empty at this point because we're still in IBD.
-    Assert(m_active_chainstate->m_mempool->size() == 0);
+    Assert(!m_active_chainstate->m_mempool || m_active_chainstate->m_mempool->size() == 0);
     Assert(!m_snapshot_chainstate->m_mempool);
     m_snapshot_chainstate->m_mempool = m_active_chainstate->m_mempool;
     m_active_chainstate->m_mempool = nullptr;
@@ -5801,6 +5796,7 @@ util::Result<CBlockIndex*> ChainstateManager::ActivateSnapshot(
         m_snapshot_chainstate->CoinsTip().DynamicMemoryUsage() / (1000 * 1000));
 
     this->MaybeRebalanceCaches();
+    GetNotifications().snapshotActivated(*snapshot_start_block);
     return snapshot_start_block;
 }
 
@@ -6172,6 +6168,7 @@ SnapshotCompletionResult ChainstateManager::MaybeCompleteSnapshotValidation()
 
     m_ibd_chainstate->m_disabled = true;
     this->MaybeRebalanceCaches();
+    GetNotifications().snapshotValidated(index_new);
 
     return SnapshotCompletionResult::SUCCESS;
 }
@@ -0,0 +1,59 @@
+// Package fixtures provides the test data shared by the tests of this module.
+//
+// It does not depend on the kernel package, so that the kernel package's own
+// tests can use it as well.
+package fixtures
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// readRegtestBlocks reads data/regtest/blocks.txt once, relative to this source
+// file so that it does not depend on the working directory of the test.
+var readRegtestBlocks = sync.OnceValues(func() ([][]byte, error) {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		return nil, fmt.Errorf("failed to locate the fixtures package")
+	}
+	blocksData, err := os.ReadFile(filepath.Join(filepath.Dir(file), "..", "..", "data", "regtest", "blocks.txt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blocks file: %w", err)
+	}
+	lines := strings.Fields(string(blocksData))
+	blocks := make([][]byte, len(lines))
+	for i, line := range lines {
+		if blocks[i], err = hex.DecodeString(line); err != nil {
+			return nil, fmt.Errorf("failed to decode block %d hex: %w", i+1, err)
+		}
+	}
+	return blocks, nil
+})
+
+// RegtestBlocks returns the serialized blocks of data/regtest/blocks.txt in
+// order, starting at height 1. The blocks build on the regtest genesis block.
+func RegtestBlocks(tb testing.TB) [][]byte {
+	tb.Helper()
+	blocks, err := readRegtestBlocks()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return blocks
+}
+
+// RegtestBlock returns the serialized block at the given height of
+// data/regtest/blocks.txt.
+func RegtestBlock(tb testing.TB, height int) []byte {
+	tb.Helper()
+	blocks := RegtestBlocks(tb)
+	if height < 1 || height > len(blocks) {
+		tb.Fatalf("No block at height %d in blocks.txt", height)
+	}
+	return blocks[height-1]
+}
@@ -0,0 +1,65 @@
+// Package testutil provides the kernel fixtures shared by the tests of the
+// packages built on top of the kernel package.
+package testutil
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stringintech/go-bitcoinkernel/internal/fixtures"
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+)
+
+// NewContext returns a regtest context with the given options, which is destroyed
+// when the test finishes.
+func NewContext(tb testing.TB, options ...kernel.ContextOption) *kernel.Context {
+	tb.Helper()
+	ctx, err := kernel.NewContext(append([]kernel.ContextOption{kernel.WithChainType(kernel.ChainTypeRegtest)}, options...)...)
+	if err != nil {
+		tb.Fatalf("NewContext() error = %v", err)
+	}
+	tb.Cleanup(ctx.Destroy)
+	return ctx
+}
+
+// NewChainstateManager returns a chainstate manager with in-memory databases and
+// its data and block files under dir, which is destroyed when the test finishes.
+// The given options are appended to the defaults. The databases are initialized,
+// so that the manager only knows the genesis block.
+func NewChainstateManager(tb testing.TB, ctx *kernel.Context, dir string, options ...kernel.ChainstateManagerOption) *kernel.ChainstateManager {
+	tb.Helper()
+	defaults := []kernel.ChainstateManagerOption{
+		kernel.WithBlockTreeDBInMemory(true),
+		kernel.WithChainstateDBInMemory(),
+		kernel.WithWipeDBs(true, true),
+	}
+	manager, err := kernel.NewChainstateManager(ctx, filepath.Join(dir, "data"), filepath.Join(dir, "blocks"), append(defaults, options...)...)
+	if err != nil {
+		tb.Fatalf("NewChainstateManager() error = %v", err)
+	}
+	tb.Cleanup(manager.Destroy)
+
+	// Initialize empty databases
+	if err := manager.ImportBlocks(nil); err != nil {
+		tb.Fatalf("ImportBlocks() error = %v", err)
+	}
+	return manager
+}
+
+// RegtestBlocks returns the first count blocks of data/regtest/blocks.txt.
+func RegtestBlocks(tb testing.TB, count int) []*kernel.Block {
+	tb.Helper()
+	data := fixtures.RegtestBlocks(tb)
+	if count > len(data) {
+		tb.Fatalf("Requested %d blocks, blocks.txt has %d", count, len(data))
+	}
+	blocks := make([]*kernel.Block, count)
+	for i := range blocks {
+		block, err := kernel.NewBlock(data[i])
+		if err != nil {
+			tb.Fatalf("NewBlock() error = %v", err)
+		}
+		blocks[i] = block
+	}
+	return blocks
+}
@@ -0,0 +1,320 @@
+package kernel
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrAddressUnsupportedScript = &AddressError{"Script pubkey has no address representation"}
+	ErrAddressInvalidChecksum   = &AddressError{"Invalid checksum"}
+	ErrAddressInvalidEncoding   = &AddressError{"Invalid encoding"}
+	ErrAddressWrongNetwork      = &AddressError{"Address belongs to a different network"}
+	ErrAddressInvalidProgram    = &AddressError{"Invalid witness program"}
+)
+
+// addressParams holds the address encoding parameters of a chain.
+type addressParams struct {
+	pubkeyHashPrefix byte
+	scriptHashPrefix byte
+	bech32HRP        string
+}
+
+func (t ChainType) addressParams() addressParams {
+	switch t {
+	case ChainTypeMainnet:
+		return addressParams{pubkeyHashPrefix: 0x00, scriptHashPrefix: 0x05, bech32HRP: "bc"}
+	case ChainTypeTestnet, ChainTypeTestnet4, ChainTypeSignet:
+		return addressParams{pubkeyHashPrefix: 0x6f, scriptHashPrefix: 0xc4, bech32HRP: "tb"}
+	case ChainTypeRegtest:
+		return addressParams{pubkeyHashPrefix: 0x6f, scriptHashPrefix: 0xc4, bech32HRP: "bcrt"}
+	default:
+		panic("Invalid chain type")
+	}
+}
+
+// Address returns the address of the script pubkey on the given chain.
+//
+// Supported script types are P2PKH and P2SH (base58check), P2WPKH and P2WSH (bech32)
+// and P2TR (bech32m).
+//
+// Returns ErrAddressUnsupportedScript for any other script, or an error if the
+// serialization fails.
+func (s *scriptPubkeyApi) Address(chainType ChainType) (string, error) {
+	script, err := s.Bytes()
+	if err != nil {
+		return "", err
+	}
+	params := chainType.addressParams()
+
+	switch {
+	case len(script) == 25 && script[0] == 0x76 && script[1] == 0xa9 && script[2] == 0x14 &&
+		script[23] == 0x88 && script[24] == 0xac:
+		// OP_DUP OP_HASH160 <20 bytes> OP_EQUALVERIFY OP_CHECKSIG
+		return base58CheckEncode(params.pubkeyHashPrefix, script[3:23]), nil
+	case len(script) == 23 && script[0] == 0xa9 && script[1] == 0x14 && script[22] == 0x87:
+		// OP_HASH160 <20 bytes> OP_EQUAL
+		return base58CheckEncode(params.scriptHashPrefix, script[2:22]), nil
+	case len(script) == 22 && script[0] == 0x00 && script[1] == 0x14,
+		len(script) == 34 && script[0] == 0x00 && script[1] == 0x20:
+		// OP_0 <20 or 32 bytes>
+		return segwitEncode(params.bech32HRP, 0, script[2:])
+	case len(script) == 34 && script[0] == 0x51 && script[1] == 0x20:
+		// OP_1 <32 bytes>
+		return segwitEncode(params.bech32HRP, 1, script[2:])
+	}
+	return "", ErrAddressUnsupportedScript
+}
+
+// ScriptPubkeyFromAddress decodes a P2PKH, P2SH, P2WPKH, P2WSH or P2TR address of the
+// given chain into its script pubkey.
+//
+// Returns an *AddressError if the address is malformed, uses an unsupported type or
+// belongs to a different chain.
+func ScriptPubkeyFromAddress(address string, chainType ChainType) (*ScriptPubkey, error) {
+	params := chainType.addressParams()
+
+	lower := strings.ToLower(address)
+	if sep := strings.LastIndexByte(lower, '1'); sep > 0 && isBech32HRP(lower[:sep]) {
+		if lower[:sep] != params.bech32HRP {
+			return nil, ErrAddressWrongNetwork
+		}
+		version, program, err := segwitDecode(params.bech32HRP, address)
+		if err != nil {
+			return nil, err
+		}
+		script := make([]byte, 0, 2+len(program))
+		if version == 0 {
+			script = append(script, 0x00)
+		} else {
+			script = append(script, 0x50+version)
+		}
+		script = append(script, byte(len(program)))
+		script = append(script, program...)
+		return NewScriptPubkey(script), nil
+	}
+
+	version, payload, err := base58CheckDecode(address)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) != 20 {
+		return nil, ErrAddressInvalidEncoding
+	}
+	switch version {
+	case params.pubkeyHashPrefix:
+		script := append([]byte{0x76, 0xa9, 0x14}, payload...)
+		return NewScriptPubkey(append(script, 0x88, 0xac)), nil
+	case params.scriptHashPrefix:
+		script := append([]byte{0xa9, 0x14}, payload...)
+		return NewScriptPubkey(append(script, 0x87)), nil
+	}
+	return nil, ErrAddressWrongNetwork
+}
+
+// isBech32HRP reports whether hrp is the human-readable part of any supported chain.
+func isBech32HRP(hrp string) bool {
+	for _, chainType := range []ChainType{ChainTypeMainnet, ChainTypeTestnet, ChainTypeRegtest} {
+		if chainType.addressParams().bech32HRP == hrp {
+			return true
+		}
+	}
+	return false
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+func base58Checksum(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:4]
+}
+
+func base58CheckEncode(version byte, payload []byte) string {
+	data := append([]byte{version}, payload...)
+	data = append(data, base58Checksum(data)...)
+
+	n := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	var encoded []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	// Leading zero bytes are encoded as leading '1's
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, base58Alphabet[0])
+	}
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
+
+func base58CheckDecode(address string) (version byte, payload []byte, err error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range []byte(address) {
+		digit := strings.IndexByte(base58Alphabet, c)
+		if digit < 0 {
+			return 0, nil, ErrAddressInvalidEncoding
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+	var zeros int
+	for zeros < len(address) && address[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	data := append(make([]byte, zeros), n.Bytes()...)
+	if len(data) < 5 {
+		return 0, nil, ErrAddressInvalidEncoding
+	}
+	body, checksum := data[:len(data)-4], data[len(data)-4:]
+	if !bytes.Equal(base58Checksum(body), checksum) {
+		return 0, nil, ErrAddressInvalidChecksum
+	}
+	return body[0], body[1:], nil
+}
+
+const (
+	bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	// bech32Const and bech32mConst are the checksum constants of BIP173 and BIP350.
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := range 5 {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := range len(hrp) {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := range len(hrp) {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+// convertBits regroups data from fromBits-bit to toBits-bit groups.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, bool) {
+	var acc, bits uint
+	maxv := uint(1)<<toBits - 1
+	var out []byte
+	for _, b := range data {
+		if uint(b)>>fromBits != 0 {
+			return nil, false
+		}
+		acc = acc<<fromBits | uint(b)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(toBits-bits)&maxv))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxv != 0 {
+		return nil, false
+	}
+	return out, true
+}
+
+// segwitEncode encodes a witness program as a bech32 (version 0) or bech32m (version 1+) address.
+func segwitEncode(hrp string, version byte, program []byte) (string, error) {
+	data, ok := convertBits(program, 8, 5, true)
+	if !ok {
+		return "", ErrAddressInvalidProgram
+	}
+	data = append([]byte{version}, data...)
+
+	checksumConst := uint32(bech32Const)
+	if version != 0 {
+		checksumConst = bech32mConst
+	}
+	values := append(bech32HRPExpand(hrp), data...)
+	polymod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ checksumConst
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, d := range data {
+		sb.WriteByte(bech32Charset[d])
+	}
+	for i := range 6 {
+		sb.WriteByte(bech32Charset[(polymod>>(5*(5-i)))&31])
+	}
+	return sb.String(), nil
+}
+
+// segwitDecode decodes a bech32 or bech32m address with the expected human-readable
+// part into its witness version and program, enforcing the BIP173 and BIP350 rules.
+func segwitDecode(hrp string, address string) (byte, []byte, error) {
+	if len(address) > 90 {
+		return 0, nil, ErrAddressInvalidEncoding
+	}
+	lower := strings.ToLower(address)
+	if lower != address && strings.ToUpper(address) != address {
+		return 0, nil, ErrAddressInvalidEncoding
+	}
+	sep := strings.LastIndexByte(lower, '1')
+	if sep < 1 || sep+7 > len(lower) || lower[:sep] != hrp {
+		return 0, nil, ErrAddressInvalidEncoding
+	}
+	data := make([]byte, 0, len(lower)-sep-1)
+	for _, c := range []byte(lower[sep+1:]) {
+		d := strings.IndexByte(bech32Charset, c)
+		if d < 0 {
+			return 0, nil, ErrAddressInvalidEncoding
+		}
+		data = append(data, byte(d))
+	}
+	if len(data) < 7 {
+		return 0, nil, ErrAddressInvalidProgram
+	}
+
+	version := data[0]
+	checksumConst := uint32(bech32Const)
+	if version != 0 {
+		checksumConst = bech32mConst
+	}
+	if bech32Polymod(append(bech32HRPExpand(hrp), data...)) != checksumConst {
+		return 0, nil, ErrAddressInvalidChecksum
+	}
+
+	program, ok := convertBits(data[1:len(data)-6], 5, 8, false)
+	if !ok {
+		return 0, nil, ErrAddressInvalidProgram
+	}
+	switch {
+	case version == 0 && (len(program) == 20 || len(program) == 32):
+	case version == 1 && len(program) == 32:
+	default:
+		return 0, nil, ErrAddressInvalidProgram
+	}
+	return version, program, nil
+}
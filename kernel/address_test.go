@@ -0,0 +1,164 @@
+package kernel
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestScriptPubkeyAddress(t *testing.T) {
+	tests := []struct {
+		name      string
+		scriptHex string
+		chainType ChainType
+		address   string
+	}{
+		{
+			name:      "p2pkh_mainnet",
+			scriptHex: "76a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac",
+			chainType: ChainTypeMainnet,
+			address:   "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+		},
+		{
+			name:      "p2pkh_testnet",
+			scriptHex: "76a914243f1394f44554f4ce3fd68649c19adc483ce92488ac",
+			chainType: ChainTypeTestnet,
+			address:   "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn",
+		},
+		{
+			name:      "p2sh_mainnet",
+			scriptHex: "a914748284390f9e263a4b766a75d0633c50426eb87587",
+			chainType: ChainTypeMainnet,
+			address:   "3CK4fEwbMP7heJarmU4eqA3sMbVJyEnU3V",
+		},
+		{
+			name:      "p2wpkh_mainnet",
+			scriptHex: "0014751e76e8199196d454941c45d1b3a323f1433bd6",
+			chainType: ChainTypeMainnet,
+			address:   "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		},
+		{
+			name:      "p2wsh_testnet",
+			scriptHex: "00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262",
+			chainType: ChainTypeTestnet,
+			address:   "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7",
+		},
+		{
+			name:      "p2tr_mainnet",
+			scriptHex: "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			chainType: ChainTypeMainnet,
+			address:   "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0",
+		},
+		{
+			name:      "p2wpkh_regtest",
+			scriptHex: "0014751e76e8199196d454941c45d1b3a323f1433bd6",
+			chainType: ChainTypeRegtest,
+			address:   "bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scriptBytes, err := hex.DecodeString(tt.scriptHex)
+			if err != nil {
+				t.Fatalf("Failed to decode script hex: %v", err)
+			}
+			scriptPubkey := NewScriptPubkey(scriptBytes)
+			defer scriptPubkey.Destroy()
+
+			address, err := scriptPubkey.Address(tt.chainType)
+			if err != nil {
+				t.Fatalf("Address() error = %v", err)
+			}
+			if address != tt.address {
+				t.Errorf("Expected address %s, got %s", tt.address, address)
+			}
+
+			decoded, err := ScriptPubkeyFromAddress(tt.address, tt.chainType)
+			if err != nil {
+				t.Fatalf("ScriptPubkeyFromAddress() error = %v", err)
+			}
+			defer decoded.Destroy()
+			data, err := decoded.Bytes()
+			if err != nil {
+				t.Fatalf("Bytes() error = %v", err)
+			}
+			if hex.EncodeToString(data) != tt.scriptHex {
+				t.Errorf("Expected script %s, got %x", tt.scriptHex, data)
+			}
+		})
+	}
+}
+
+func TestScriptPubkeyAddressUnsupported(t *testing.T) {
+	// OP_RETURN output
+	scriptPubkey := NewScriptPubkey([]byte{0x6a, 0x01, 0x00})
+	defer scriptPubkey.Destroy()
+
+	_, err := scriptPubkey.Address(ChainTypeMainnet)
+	if !errors.Is(err, ErrAddressUnsupportedScript) {
+		t.Errorf("Expected ErrAddressUnsupportedScript, got %v", err)
+	}
+}
+
+func TestScriptPubkeyFromAddressErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		address   string
+		chainType ChainType
+		wantErr   error
+	}{
+		{
+			name:      "wrong_network_bech32",
+			address:   "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+			chainType: ChainTypeRegtest,
+			wantErr:   ErrAddressWrongNetwork,
+		},
+		{
+			name:      "wrong_network_base58",
+			address:   "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+			chainType: ChainTypeTestnet,
+			wantErr:   ErrAddressWrongNetwork,
+		},
+		{
+			name:      "bad_base58_checksum",
+			address:   "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb",
+			chainType: ChainTypeMainnet,
+			wantErr:   ErrAddressInvalidChecksum,
+		},
+		{
+			name:      "bad_bech32_checksum",
+			address:   "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5",
+			chainType: ChainTypeMainnet,
+			wantErr:   ErrAddressInvalidChecksum,
+		},
+		{
+			// Taproot program encoded with bech32 instead of bech32m
+			name:      "p2tr_with_bech32_checksum",
+			address:   "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd",
+			chainType: ChainTypeMainnet,
+			wantErr:   ErrAddressInvalidChecksum,
+		},
+		{
+			name:      "mixed_case",
+			address:   "bc1qW508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+			chainType: ChainTypeMainnet,
+			wantErr:   ErrAddressInvalidEncoding,
+		},
+		{
+			name:      "invalid_base58_character",
+			address:   "0A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+			chainType: ChainTypeMainnet,
+			wantErr:   ErrAddressInvalidEncoding,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ScriptPubkeyFromAddress(tt.address, tt.chainType)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
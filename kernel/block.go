@@ -47,6 +47,11 @@ func (b *Block) Hash() *BlockHash {
 	return newBlockHash(C.btck_block_get_hash((*C.btck_Block)(b.ptr)), true)
 }
 
+// Header returns the header of this block.
+func (b *Block) Header() *BlockHeader {
+	return newBlockHeader(check(C.btck_block_get_header((*C.btck_Block)(b.ptr))), true)
+}
+
 // Bytes returns the consensus serialized representation of the block.
 //
 // Returns an error if the serialization fails.
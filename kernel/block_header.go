@@ -0,0 +1,108 @@
+package kernel
+
+/*
+#include "bitcoinkernel.h"
+*/
+import "C"
+import (
+	"time"
+	"unsafe"
+)
+
+type blockHeaderCFuncs struct{}
+
+func (blockHeaderCFuncs) destroy(ptr unsafe.Pointer) {
+	C.btck_block_header_destroy((*C.btck_BlockHeader)(ptr))
+}
+
+func (blockHeaderCFuncs) copy(ptr unsafe.Pointer) unsafe.Pointer {
+	return unsafe.Pointer(C.btck_block_header_copy((*C.btck_BlockHeader)(ptr)))
+}
+
+// BlockHeader holds the version, previous block hash, merkle root, timestamp,
+// compact difficulty target and nonce of a block.
+type BlockHeader struct {
+	*handle
+}
+
+func newBlockHeader(ptr *C.btck_BlockHeader, fromOwned bool) *BlockHeader {
+	h := newHandle(unsafe.Pointer(ptr), blockHeaderCFuncs{}, fromOwned)
+	return &BlockHeader{handle: h}
+}
+
+// NewBlockHeader creates a new block header from its raw 80-byte serialization.
+//
+// Parameters:
+//   - rawBlockHeader: Serialized block header data in Bitcoin's consensus format
+//
+// Returns an error if the header data is malformed or has trailing bytes.
+func NewBlockHeader(rawBlockHeader []byte) (*BlockHeader, error) {
+	ptr := C.btck_block_header_create(unsafe.Pointer(unsafe.SliceData(rawBlockHeader)), C.size_t(len(rawBlockHeader)))
+	if ptr == nil {
+		return nil, &InternalError{"Failed to create block header from bytes"}
+	}
+	return newBlockHeader(ptr, true), nil
+}
+
+// Hash calculates and returns the hash of the block this header belongs to.
+func (bh *BlockHeader) Hash() *BlockHash {
+	return newBlockHash(C.btck_block_header_get_hash((*C.btck_BlockHeader)(bh.ptr)), true)
+}
+
+// PrevHash returns the hash of the previous block.
+//
+// The returned BlockHashView is a non-owned pointer valid for the lifetime of this header.
+func (bh *BlockHeader) PrevHash() *BlockHashView {
+	ptr := C.btck_block_header_get_prev_hash((*C.btck_BlockHeader)(bh.ptr))
+	return newBlockHashView(check(ptr))
+}
+
+// MerkleRoot returns the 32-byte merkle root of the block's transactions.
+func (bh *BlockHeader) MerkleRoot() [32]byte {
+	var output [32]C.uchar
+	C.btck_block_header_get_merkle_root((*C.btck_BlockHeader)(bh.ptr), &output[0])
+	return *(*[32]byte)(unsafe.Pointer(&output[0]))
+}
+
+// Version returns the block version.
+func (bh *BlockHeader) Version() int32 {
+	return int32(C.btck_block_header_get_version((*C.btck_BlockHeader)(bh.ptr)))
+}
+
+// Timestamp returns the block timestamp in seconds since the unix epoch.
+func (bh *BlockHeader) Timestamp() uint32 {
+	return uint32(C.btck_block_header_get_timestamp((*C.btck_BlockHeader)(bh.ptr)))
+}
+
+// Time returns the block timestamp as a time.Time.
+func (bh *BlockHeader) Time() time.Time {
+	return time.Unix(int64(bh.Timestamp()), 0)
+}
+
+// Bits returns the compact representation of the block's difficulty target.
+func (bh *BlockHeader) Bits() uint32 {
+	return uint32(C.btck_block_header_get_bits((*C.btck_BlockHeader)(bh.ptr)))
+}
+
+// Nonce returns the block nonce.
+func (bh *BlockHeader) Nonce() uint32 {
+	return uint32(C.btck_block_header_get_nonce((*C.btck_BlockHeader)(bh.ptr)))
+}
+
+// Bytes returns the 80-byte consensus serialized representation of the header.
+//
+// Returns an error if the serialization fails.
+func (bh *BlockHeader) Bytes() ([]byte, error) {
+	bytes, ok := writeToBytes(func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+		return C.btck_block_header_to_bytes((*C.btck_BlockHeader)(bh.ptr), writer, userData)
+	})
+	if !ok {
+		return nil, &SerializationError{"Failed to serialize block header"}
+	}
+	return bytes, nil
+}
+
+// Copy creates a copy of the block header.
+func (bh *BlockHeader) Copy() *BlockHeader {
+	return newBlockHeader((*C.btck_BlockHeader)(bh.ptr), false)
+}
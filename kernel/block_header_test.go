@@ -0,0 +1,105 @@
+package kernel
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// genesisHeaderHex is the serialized mainnet genesis block header
+const genesisHeaderHex = "0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c"
+
+func TestInvalidBlockHeaderData(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"invalid bytes", []byte{0x00, 0x01, 0x02}},
+		{"nil slice", nil},
+		{"trailing bytes", append(make([]byte, 80), 0x00)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBlockHeader(tt.data)
+			var internalErr *InternalError
+			if !errors.As(err, &internalErr) {
+				t.Errorf("Expected InternalError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBlockHeader(t *testing.T) {
+	headerBytes, err := hex.DecodeString(genesisHeaderHex)
+	if err != nil {
+		t.Fatalf("Failed to decode header hex: %v", err)
+	}
+
+	header, err := NewBlockHeader(headerBytes)
+	if err != nil {
+		t.Fatalf("NewBlockHeader() error = %v", err)
+	}
+	defer header.Destroy()
+
+	t.Run("Hash", func(t *testing.T) {
+		hash := header.Hash()
+		defer hash.Destroy()
+
+		expectedHash := "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"
+		if hash.String() != expectedHash {
+			t.Errorf("Expected hash %s, got %s", expectedHash, hash.String())
+		}
+	})
+
+	t.Run("PrevHash", func(t *testing.T) {
+		if header.PrevHash().Bytes() != [32]byte{} {
+			t.Errorf("Expected null previous hash, got %s", header.PrevHash().String())
+		}
+	})
+
+	t.Run("MerkleRoot", func(t *testing.T) {
+		merkleRoot := header.MerkleRoot()
+		expectedMerkleRoot := "3ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a"
+		if hex.EncodeToString(merkleRoot[:]) != expectedMerkleRoot {
+			t.Errorf("Expected merkle root %s, got %x", expectedMerkleRoot, merkleRoot)
+		}
+	})
+
+	t.Run("Fields", func(t *testing.T) {
+		if header.Version() != 1 {
+			t.Errorf("Expected version 1, got %d", header.Version())
+		}
+		if header.Timestamp() != 1231006505 {
+			t.Errorf("Expected timestamp 1231006505, got %d", header.Timestamp())
+		}
+		if header.Time().Unix() != 1231006505 {
+			t.Errorf("Expected time 1231006505, got %d", header.Time().Unix())
+		}
+		if header.Bits() != 0x1d00ffff {
+			t.Errorf("Expected bits 0x1d00ffff, got %#x", header.Bits())
+		}
+		if header.Nonce() != 2083236893 {
+			t.Errorf("Expected nonce 2083236893, got %d", header.Nonce())
+		}
+	})
+
+	t.Run("Bytes", func(t *testing.T) {
+		data, err := header.Bytes()
+		if err != nil {
+			t.Fatalf("BlockHeader.Bytes() error = %v", err)
+		}
+		if hex.EncodeToString(data) != genesisHeaderHex {
+			t.Errorf("Expected data hex %s, got %x", genesisHeaderHex, data)
+		}
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		headerCopy := header.Copy()
+		defer headerCopy.Destroy()
+
+		if headerCopy.Nonce() != header.Nonce() {
+			t.Errorf("Copied header nonce %d, expected %d", headerCopy.Nonce(), header.Nonce())
+		}
+	})
+}
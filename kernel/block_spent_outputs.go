@@ -36,6 +36,34 @@ func newBlockSpentOutputs(ptr *C.btck_BlockSpentOutputs, fromOwned bool) *BlockS
 	return &BlockSpentOutputs{handle: h}
 }
 
+// NewBlockSpentOutputs creates block spent outputs from serialized block undo data,
+// as stored in the rev*.dat files of the blocks directory.
+//
+// Parameters:
+//   - rawBlockSpentOutputs: Serialized block undo data
+//
+// Returns an error if the data is malformed or has trailing bytes.
+func NewBlockSpentOutputs(rawBlockSpentOutputs []byte) (*BlockSpentOutputs, error) {
+	ptr := C.btck_block_spent_outputs_create(unsafe.Pointer(unsafe.SliceData(rawBlockSpentOutputs)), C.size_t(len(rawBlockSpentOutputs)))
+	if ptr == nil {
+		return nil, &InternalError{"Failed to create block spent outputs from bytes"}
+	}
+	return newBlockSpentOutputs(ptr, true), nil
+}
+
+// Bytes returns the block spent outputs serialized in the block undo data format.
+//
+// Returns an error if the serialization fails.
+func (bso *BlockSpentOutputs) Bytes() ([]byte, error) {
+	bytes, ok := writeToBytes(func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+		return C.btck_block_spent_outputs_to_bytes((*C.btck_BlockSpentOutputs)(bso.ptr), writer, userData)
+	})
+	if !ok {
+		return nil, &SerializationError{"Failed to serialize block spent outputs"}
+	}
+	return bytes, nil
+}
+
 // Count returns the number of transaction spent outputs contained in this block's spent outputs.
 func (bso *BlockSpentOutputs) Count() uint64 {
 	return uint64(C.btck_block_spent_outputs_count((*C.btck_BlockSpentOutputs)(bso.ptr)))
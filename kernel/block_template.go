@@ -0,0 +1,121 @@
+package kernel
+
+/*
+#include "bitcoinkernel.h"
+*/
+import "C"
+import (
+	"runtime"
+	"time"
+	"unsafe"
+)
+
+type blockTemplateCFuncs struct{}
+
+func (blockTemplateCFuncs) destroy(ptr unsafe.Pointer) {
+	C.btck_block_template_destroy((*C.btck_BlockTemplate)(ptr))
+}
+
+// BlockTemplateOption is a functional option for configuring block templates.
+type BlockTemplateOption func(*C.btck_BlockTemplateOptions) error
+
+// WithoutMempoolTransactions returns a BlockTemplateOption that creates a block
+// containing only the coinbase transaction.
+func WithoutMempoolTransactions() BlockTemplateOption {
+	return func(opts *C.btck_BlockTemplateOptions) error {
+		C.btck_block_template_options_set_use_mempool(opts, C.int(0))
+		return nil
+	}
+}
+
+// WithMaxBlockWeight returns a BlockTemplateOption that limits the weight of the
+// block. The default is the consensus limit of 4000000.
+//
+// Returns an error if the weight is above the consensus limit.
+func WithMaxBlockWeight(weight uint64) BlockTemplateOption {
+	return func(opts *C.btck_BlockTemplateOptions) error {
+		if C.btck_block_template_options_set_max_weight(opts, C.uint64_t(weight)) != 0 {
+			return &InternalError{"Invalid maximum block weight"}
+		}
+		return nil
+	}
+}
+
+// WithMinFeeRate returns a BlockTemplateOption that sets the minimum fee rate of
+// the mempool transactions included in the block, in satoshis per kvB. The
+// default is 1.
+//
+// Returns an error if the fee rate is negative.
+func WithMinFeeRate(satPerKvB int64) BlockTemplateOption {
+	return func(opts *C.btck_BlockTemplateOptions) error {
+		if C.btck_block_template_options_set_min_fee_rate(opts, C.int64_t(satPerKvB)) != 0 {
+			return &InternalError{"Invalid minimum fee rate"}
+		}
+		return nil
+	}
+}
+
+// BlockTemplate is a block on top of the active chain tip that is valid except
+// for its proof of work.
+type BlockTemplate struct {
+	// Block has the required version, bits and timestamp, a coinbase transaction
+	// committing to its height and the witness commitment, and a zero nonce. The
+	// merkle root has to be recalculated when the coinbase transaction is modified.
+	Block   *Block
+	MinTime time.Time // Earliest timestamp the block may have
+	MaxTime time.Time // Latest timestamp the block may have when validated at creation of the template
+	Fees    int64     // Fees of the transactions in the block in satoshis, excluding the coinbase
+}
+
+// CreateBlockTemplate creates a template for a block on top of the active chain
+// tip whose coinbase pays the subsidy and fees to coinbaseScript. If the mempool
+// is enabled, the mempool transactions paying the highest fee rates are included.
+//
+// Parameters:
+//   - coinbaseScript: Script the coinbase output pays to
+//   - options: Options for the block template, see BlockTemplateOption
+//
+// Returns an error if an option is invalid or the template cannot be created.
+func (cm *ChainstateManager) CreateBlockTemplate(coinbaseScript *ScriptPubkey, options ...BlockTemplateOption) (*BlockTemplate, error) {
+	opts := check(C.btck_block_template_options_create())
+	defer C.btck_block_template_options_destroy(opts)
+	for _, option := range options {
+		if err := option(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	ptr := C.btck_chainstate_manager_create_block_template((*C.btck_ChainstateManager)(cm.ptr), (*C.btck_ScriptPubkey)(coinbaseScript.handle.ptr), opts)
+	runtime.KeepAlive(coinbaseScript)
+	if ptr == nil {
+		return nil, &InternalError{"Failed to create block template"}
+	}
+	h := newUniqueHandle(unsafe.Pointer(ptr), blockTemplateCFuncs{})
+	defer h.Destroy()
+	cTemplate := (*C.btck_BlockTemplate)(h.ptr)
+
+	return &BlockTemplate{
+		Block:   newBlock(C.btck_block_template_get_block(cTemplate), false),
+		MinTime: time.Unix(int64(C.btck_block_template_get_min_time(cTemplate)), 0),
+		MaxTime: time.Unix(int64(C.btck_block_template_get_max_time(cTemplate)), 0),
+		Fees:    int64(C.btck_block_template_get_fees(cTemplate)),
+	}, nil
+}
+
+// TestBlockValidity fully validates the block as the next block of the active
+// chain, without connecting or storing it. The proof of work is not checked, so
+// block templates can be validated before they are mined.
+//
+// Returns a *BlockValidationError if the block is invalid, including when its
+// previous block is not the active chain tip.
+func (cm *ChainstateManager) TestBlockValidity(block *Block) error {
+	statePtr := C.btck_block_validation_state_create()
+	defer C.btck_block_validation_state_destroy(statePtr)
+
+	result := C.btck_chainstate_manager_test_block_validity((*C.btck_ChainstateManager)(cm.ptr), (*C.btck_Block)(block.ptr), statePtr)
+	runtime.KeepAlive(block)
+	if result != 0 {
+		return newBlockValidationError(&BlockValidationState{ptr: statePtr})
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package kernel_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+	"github.com/stringintech/go-bitcoinkernel/regtest"
+	"github.com/stringintech/go-bitcoinkernel/wire"
+)
+
+func TestCreateBlockTemplate(t *testing.T) {
+	suite := kernel.ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 10,
+	}
+	suite.Setup(t)
+	chain := suite.Manager.GetActiveChain()
+	tipHash := chain.GetByHeight(10).Hash()
+
+	coinbaseScript := kernel.NewScriptPubkey(anyoneCanSpendScript)
+	defer coinbaseScript.Destroy()
+	template, err := suite.Manager.CreateBlockTemplate(coinbaseScript)
+	if err != nil {
+		t.Fatalf("CreateBlockTemplate() error = %v", err)
+	}
+	defer template.Block.Destroy()
+	if err := suite.Manager.TestBlockValidity(template.Block); err != nil {
+		t.Errorf("TestBlockValidity() error = %v", err)
+	}
+
+	msg, err := template.Block.ToWire()
+	if err != nil {
+		t.Fatalf("ToWire() error = %v", err)
+	}
+	if msg.Header.PrevBlock != wire.Hash(tipHash.Bytes()) {
+		t.Errorf("Expected template on top of the tip, got previous block %s", msg.Header.PrevBlock)
+	}
+	if msg.Header.Bits != 0x207fffff {
+		t.Errorf("Expected regtest bits 0x207fffff, got %#x", msg.Header.Bits)
+	}
+	if blockTime := msg.Header.Time(); blockTime.Before(template.MinTime) || blockTime.After(template.MaxTime) {
+		t.Errorf("Expected block time between %v and %v, got %v", template.MinTime, template.MaxTime, blockTime)
+	}
+	if len(msg.Transactions) != 1 || template.Fees != 0 {
+		t.Fatalf("Expected only a coinbase transaction without fees, got %d transactions with fees %d", len(msg.Transactions), template.Fees)
+	}
+	coinbase := msg.Transactions[0]
+	// The height is committed to as the first push of the coinbase script, OP_11 for height 11
+	if coinbase.TxIn[0].SignatureScript[0] != 0x5b {
+		t.Errorf("Expected coinbase script to start with OP_11, got %x", coinbase.TxIn[0].SignatureScript)
+	}
+	if !bytes.Equal(coinbase.TxOut[0].PkScript, anyoneCanSpendScript) || coinbase.TxOut[0].Value != 50*100_000_000 {
+		t.Errorf("Expected coinbase paying the subsidy to the coinbase script, got %d to %x", coinbase.TxOut[0].Value, coinbase.TxOut[0].PkScript)
+	}
+	if len(coinbase.TxOut) != 2 || !bytes.HasPrefix(coinbase.TxOut[1].PkScript, []byte{0x6a, 0x24, 0xaa, 0x21, 0xa9, 0xed}) {
+		t.Error("Expected witness commitment as second coinbase output")
+	}
+
+	// Claiming more than the subsidy is rejected
+	invalidMsg, err := template.Block.ToWire()
+	if err != nil {
+		t.Fatalf("ToWire() error = %v", err)
+	}
+	invalidMsg.Transactions[0].TxOut[0].Value++
+	invalidMsg.UpdateMerkleRoot()
+	invalid, err := kernel.NewBlockFromWire(invalidMsg)
+	if err != nil {
+		t.Fatalf("NewBlockFromWire() error = %v", err)
+	}
+	defer invalid.Destroy()
+	var validationErr *kernel.BlockValidationError
+	if err := suite.Manager.TestBlockValidity(invalid); !errors.As(err, &validationErr) || validationErr.RejectReason != "bad-cb-amount" {
+		t.Errorf("Expected *BlockValidationError with reason bad-cb-amount, got %v", err)
+	}
+	if chain.GetHeight() != 10 {
+		t.Errorf("Expected TestBlockValidity not to connect blocks, got height %d", chain.GetHeight())
+	}
+
+	// The template only lacks the proof of work
+	regtest.Mine(msg)
+	mined, err := kernel.NewBlockFromWire(msg)
+	if err != nil {
+		t.Fatalf("NewBlockFromWire() error = %v", err)
+	}
+	defer mined.Destroy()
+	if _, err := suite.Manager.ProcessBlock(mined); err != nil {
+		t.Fatalf("ProcessBlock() error = %v", err)
+	}
+	if chain.GetHeight() != 11 {
+		t.Fatalf("Expected mined template to be connected at height 11, got height %d", chain.GetHeight())
+	}
+	if err := suite.Manager.TestBlockValidity(template.Block); !errors.As(err, &validationErr) || validationErr.RejectReason != "inconclusive-not-best-prevblk" {
+		t.Errorf("Expected *BlockValidationError for a block not on the tip, got %v", err)
+	}
+
+	if _, err := suite.Manager.CreateBlockTemplate(coinbaseScript, kernel.WithMaxBlockWeight(4_000_001)); err == nil {
+		t.Error("Expected error for a block weight above the consensus limit")
+	}
+	if _, err := suite.Manager.CreateBlockTemplate(coinbaseScript, kernel.WithMinFeeRate(-1)); err == nil {
+		t.Error("Expected error for a negative fee rate")
+	}
+}
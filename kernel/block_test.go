@@ -54,6 +54,19 @@ func TestBlock(t *testing.T) {
 		}
 	})
 
+	t.Run("Header", func(t *testing.T) {
+		header := block.Header()
+		defer header.Destroy()
+
+		data, err := header.Bytes()
+		if err != nil {
+			t.Fatalf("BlockHeader.Bytes() error = %v", err)
+		}
+		if hex.EncodeToString(data) != genesisHex[:160] {
+			t.Errorf("Expected header hex %s, got %x", genesisHex[:160], data)
+		}
+	})
+
 	t.Run("Bytes", func(t *testing.T) {
 		data, err := block.Bytes()
 		if err != nil {
@@ -4,6 +4,10 @@ package kernel
 #include "bitcoinkernel.h"
 */
 import "C"
+import (
+	"math/big"
+	"unsafe"
+)
 
 // BlockTreeEntry represents a pointer to an element in the block index currently
 // in memory of the chainstate manager.
@@ -41,6 +45,30 @@ func (bi *BlockTreeEntry) Previous() *BlockTreeEntry {
 	return prevIndex
 }
 
+// Ancestor returns the ancestor of this block at the given height, which is the
+// entry itself if the height is its own.
+//
+// Returns nil if the height is negative or above the height of this block. The
+// returned entry is a non-owned pointer valid for the lifetime of the chainstate
+// manager.
+func (bi *BlockTreeEntry) Ancestor(height int32) *BlockTreeEntry {
+	ptr := C.btck_block_tree_entry_get_ancestor(bi.ptr, C.int32_t(height))
+	if ptr == nil {
+		return nil
+	}
+	return &BlockTreeEntry{ptr: ptr}
+}
+
+// FindFork returns the last common ancestor of this block and other, which is
+// one of them if it is an ancestor of the other. Both entries must belong to the
+// block tree of the same chainstate manager.
+//
+// The returned entry is a non-owned pointer valid for the lifetime of the
+// chainstate manager.
+func (bi *BlockTreeEntry) FindFork(other *BlockTreeEntry) *BlockTreeEntry {
+	return &BlockTreeEntry{ptr: C.btck_block_tree_entry_find_fork(bi.ptr, other.ptr)}
+}
+
 // Equals compares two block tree entries for equality.
 // Returns true if both entries point to the same block in the tree.
 func (bi *BlockTreeEntry) Equals(other *BlockTreeEntry) bool {
@@ -49,3 +77,83 @@ func (bi *BlockTreeEntry) Equals(other *BlockTreeEntry) bool {
 	}
 	return C.btck_block_tree_entry_equals(bi.ptr, other.ptr) != 0
 }
+
+// Header returns the block header associated with this block tree entry.
+//
+// The header is kept in memory by the chainstate manager, so no disk access is needed.
+func (bi *BlockTreeEntry) Header() *BlockHeader {
+	ptr := C.btck_block_tree_entry_get_block_header(bi.ptr)
+	return newBlockHeader(check(ptr), true)
+}
+
+// ChainWork returns the total amount of work in the chain up to and including this block.
+func (bi *BlockTreeEntry) ChainWork() *big.Int {
+	var output [32]C.uchar
+	C.btck_block_tree_entry_get_chain_work(bi.ptr, &output[0])
+	work := *(*[32]byte)(unsafe.Pointer(&output[0]))
+	return new(big.Int).SetBytes(ReverseBytes(work[:]))
+}
+
+// MedianTimePast returns the median timestamp of this block and its ten predecessors,
+// in seconds since the unix epoch.
+func (bi *BlockTreeEntry) MedianTimePast() int64 {
+	return int64(C.btck_block_tree_entry_get_median_time_past(bi.ptr))
+}
+
+// Validity returns the highest validity level this block has reached.
+//
+// The returned level does not reflect whether the block later failed validation;
+// use IsValid for that.
+func (bi *BlockTreeEntry) Validity() BlockValidity {
+	return BlockValidity(C.btck_block_tree_entry_get_validity(bi.ptr))
+}
+
+// IsValid returns true if this block is valid up to the given validity level
+// and has not failed validation.
+func (bi *BlockTreeEntry) IsValid(validity BlockValidity) bool {
+	return C.btck_block_tree_entry_is_valid(bi.ptr, validity.c()) != 0
+}
+
+// BlockValidity is the validity level a block tree entry has reached. Each level
+// implies all levels before it.
+type BlockValidity C.btck_BlockValidity
+
+const (
+	BlockValidityUnknown      BlockValidity = C.btck_BlockValidity_UNKNOWN      // Unused
+	BlockValidityTree         BlockValidity = C.btck_BlockValidity_TREE         // All parent headers found, difficulty matches, timestamp >= median previous
+	BlockValidityTransactions BlockValidity = C.btck_BlockValidity_TRANSACTIONS // Transactions are valid and match the merkle root
+	BlockValidityChain        BlockValidity = C.btck_BlockValidity_CHAIN        // Outputs do not overspend inputs, no double spends, no immature coinbase spends
+	BlockValidityScripts      BlockValidity = C.btck_BlockValidity_SCRIPTS      // Scripts and signatures are valid
+)
+
+func (v BlockValidity) c() C.btck_BlockValidity {
+	switch v {
+	case BlockValidityUnknown, BlockValidityTree, BlockValidityTransactions, BlockValidityChain, BlockValidityScripts:
+		return C.btck_BlockValidity(v)
+	default:
+		panic("Invalid block validity")
+	}
+}
+
+// Status returns the data availability and validation failure flags of this block.
+func (bi *BlockTreeEntry) Status() BlockStatus {
+	return BlockStatus(C.btck_block_tree_entry_get_status(bi.ptr))
+}
+
+// BlockStatus is a set of flags describing which data of a block is stored and
+// whether the block failed validation.
+type BlockStatus C.btck_BlockStatus
+
+const (
+	BlockStatusHaveData    BlockStatus = C.btck_BlockStatus_HAVE_DATA    // Full block is available in the block files
+	BlockStatusHaveUndo    BlockStatus = C.btck_BlockStatus_HAVE_UNDO    // Undo data is available in the undo files
+	BlockStatusFailedValid BlockStatus = C.btck_BlockStatus_FAILED_VALID // The block itself failed validation
+	BlockStatusFailedChild BlockStatus = C.btck_BlockStatus_FAILED_CHILD // The block descends from a block that failed validation
+
+	BlockStatusFailed = BlockStatusFailedValid | BlockStatusFailedChild // The block or one of its ancestors failed validation
+)
+
+// Has returns true if all flags of other are set in s.
+func (s BlockStatus) Has(other BlockStatus) bool {
+	return s&other == other
+}
@@ -68,3 +68,105 @@ func TestBlockTreeEntryEquals(t *testing.T) {
 		t.Error("Entry should not equal nil")
 	}
 }
+
+func TestBlockTreeEntryHeader(t *testing.T) {
+	suite := ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 20,
+	}
+	suite.Setup(t)
+
+	chain := suite.Manager.GetActiveChain()
+	tip := chain.GetByHeight(chain.GetHeight())
+
+	block, err := suite.Manager.ReadBlock(tip)
+	if err != nil {
+		t.Fatalf("ReadBlock() error = %v", err)
+	}
+	defer block.Destroy()
+
+	blockHeader := block.Header()
+	defer blockHeader.Destroy()
+
+	entryHeader := tip.Header()
+	defer entryHeader.Destroy()
+
+	entryHeaderHash := entryHeader.Hash()
+	defer entryHeaderHash.Destroy()
+	if !entryHeaderHash.Equals(tip.Hash()) {
+		t.Errorf("Header hash %s does not match entry hash %s", entryHeaderHash.String(), tip.Hash().String())
+	}
+	if !entryHeader.PrevHash().Equals(tip.Previous().Hash()) {
+		t.Error("Header previous hash does not match previous entry hash")
+	}
+	if entryHeader.Timestamp() != blockHeader.Timestamp() {
+		t.Errorf("Entry header timestamp %d, block header timestamp %d", entryHeader.Timestamp(), blockHeader.Timestamp())
+	}
+	if entryHeader.MerkleRoot() != blockHeader.MerkleRoot() {
+		t.Error("Entry header merkle root does not match block header merkle root")
+	}
+}
+
+func TestBlockTreeEntryChainWork(t *testing.T) {
+	suite := ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 20,
+	}
+	suite.Setup(t)
+
+	chain := suite.Manager.GetActiveChain()
+
+	var prev *BlockTreeEntry
+	for entry := range chain.Entries() {
+		if entry.ChainWork().Sign() <= 0 {
+			t.Fatalf("Expected positive chain work at height %d", entry.Height())
+		}
+		if prev != nil && entry.ChainWork().Cmp(prev.ChainWork()) <= 0 {
+			t.Fatalf("Expected chain work to increase at height %d", entry.Height())
+		}
+		prev = entry
+	}
+}
+
+func TestBlockTreeEntryMedianTimePast(t *testing.T) {
+	suite := ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 20,
+	}
+	suite.Setup(t)
+
+	chain := suite.Manager.GetActiveChain()
+
+	genesis := chain.GetByHeight(0)
+	genesisHeader := genesis.Header()
+	defer genesisHeader.Destroy()
+	if genesis.MedianTimePast() != int64(genesisHeader.Timestamp()) {
+		t.Errorf("Expected genesis median time past %d, got %d", genesisHeader.Timestamp(), genesis.MedianTimePast())
+	}
+
+	tip := chain.GetByHeight(chain.GetHeight())
+	tipHeader := tip.Header()
+	defer tipHeader.Destroy()
+	if tip.MedianTimePast() > int64(tipHeader.Timestamp()) {
+		t.Errorf("Expected median time past %d to not exceed tip timestamp %d", tip.MedianTimePast(), tipHeader.Timestamp())
+	}
+	if tip.MedianTimePast() < genesis.MedianTimePast() {
+		t.Errorf("Expected median time past %d to not be before genesis %d", tip.MedianTimePast(), genesis.MedianTimePast())
+	}
+}
+
+func TestBlockTreeEntryValidity(t *testing.T) {
+	suite := ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 2,
+	}
+	suite.Setup(t)
+
+	chain := suite.Manager.GetActiveChain()
+	tip := chain.GetByHeight(chain.GetHeight())
+
+	if tip.Validity() != BlockValidityScripts {
+		t.Errorf("Expected validity %d, got %d", BlockValidityScripts, tip.Validity())
+	}
+	for _, validity := range []BlockValidity{BlockValidityTree, BlockValidityTransactions, BlockValidityChain, BlockValidityScripts} {
+		if !tip.IsValid(validity) {
+			t.Errorf("Expected tip to be valid up to %d", validity)
+		}
+	}
+}
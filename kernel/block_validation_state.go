@@ -4,6 +4,9 @@ package kernel
 #include "bitcoinkernel.h"
 */
 import "C"
+import (
+	"unsafe"
+)
 
 // BlockValidationState holds the state of a block during validation.
 //
@@ -34,6 +37,23 @@ func (bvs *BlockValidationState) ValidationResult() BlockValidationResult {
 	return BlockValidationResult(result)
 }
 
+// RejectReason returns the short reject reason if the block was rejected, e.g.
+// "bad-txns-inputs-missingorspent". Returns an empty string otherwise.
+func (bvs *BlockValidationState) RejectReason() string {
+	bytes, _ := writeToBytes(func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+		return C.btck_block_validation_state_get_reject_reason(bvs.ptr, writer, userData)
+	})
+	return string(bytes)
+}
+
+// DebugMessage returns additional details on why the block was rejected, if any.
+func (bvs *BlockValidationState) DebugMessage() string {
+	bytes, _ := writeToBytes(func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+		return C.btck_block_validation_state_get_debug_message(bvs.ptr, writer, userData)
+	})
+	return string(bytes)
+}
+
 // ValidationMode indicates whether a validated data structure is valid, invalid,
 // or an error was encountered during processing.
 type ValidationMode C.btck_ValidationMode
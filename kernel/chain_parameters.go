@@ -49,6 +49,24 @@ func (cp *ChainParameters) Copy() *ChainParameters {
 	return newChainParameters((*C.btck_ChainParameters)(cp.ptr), false)
 }
 
+// AddAssumeutxo registers a UTXO snapshot based on the block at the given height,
+// so that LoadSnapshot accepts it in chainstate managers created with these
+// parameters. This is mostly useful for testing snapshots of custom chains.
+//
+// Parameters:
+//   - height: Height of the snapshot base block
+//   - blockHash: Hash of the snapshot base block
+//   - hashSerialized: Expected hash_serialized_3 of the UTXO set, see UTXOSetStats
+//   - chainTxCount: Number of transactions in the chain up to and including the base block
+func (cp *ChainParameters) AddAssumeutxo(height int32, blockHash BlockHashLike, hashSerialized [32]byte, chainTxCount uint64) error {
+	result := C.btck_chain_parameters_add_assumeutxo((*C.btck_ChainParameters)(cp.ptr), C.int32_t(height),
+		blockHash.blockHashPtr(), (*C.uchar)(unsafe.Pointer(&hashSerialized[0])), C.uint64_t(chainTxCount))
+	if result != 0 {
+		return &InternalError{"Assumeutxo entry already exists"}
+	}
+	return nil
+}
+
 type ChainType C.btck_ChainType
 
 const (
@@ -0,0 +1,73 @@
+package kernel
+
+/*
+#include "bitcoinkernel.h"
+*/
+import "C"
+import "unsafe"
+
+type chainTipsCFuncs struct{}
+
+func (chainTipsCFuncs) destroy(ptr unsafe.Pointer) {
+	C.btck_chain_tips_destroy((*C.btck_ChainTips)(ptr))
+}
+
+// ChainTipStatus describes the state of the branch leading to a chain tip, as
+// reported by Bitcoin Core's getchaintips.
+type ChainTipStatus C.btck_ChainTipStatus
+
+const (
+	ChainTipStatusActive       ChainTipStatus = C.btck_ChainTipStatus_ACTIVE        // Tip of the active chain
+	ChainTipStatusValidFork    ChainTipStatus = C.btck_ChainTipStatus_VALID_FORK    // Fully validated, but not part of the active chain
+	ChainTipStatusValidHeaders ChainTipStatus = C.btck_ChainTipStatus_VALID_HEADERS // All blocks are available, but were never fully validated
+	ChainTipStatusHeadersOnly  ChainTipStatus = C.btck_ChainTipStatus_HEADERS_ONLY  // Not all blocks of the branch are available
+	ChainTipStatusInvalid      ChainTipStatus = C.btck_ChainTipStatus_INVALID       // The branch contains at least one invalid block
+	ChainTipStatusUnknown      ChainTipStatus = C.btck_ChainTipStatus_UNKNOWN       // None of the above
+)
+
+// String returns the name Bitcoin Core uses for the status, e.g. "valid-fork".
+func (s ChainTipStatus) String() string {
+	switch s {
+	case ChainTipStatusActive:
+		return "active"
+	case ChainTipStatusValidFork:
+		return "valid-fork"
+	case ChainTipStatusValidHeaders:
+		return "valid-headers"
+	case ChainTipStatusHeadersOnly:
+		return "headers-only"
+	case ChainTipStatusInvalid:
+		return "invalid"
+	default:
+		return "unknown"
+	}
+}
+
+// ChainTip is the last block of a branch of the block tree.
+type ChainTip struct {
+	Entry        *BlockTreeEntry // Last block of the branch
+	BranchLength int32           // Number of blocks between the tip and its fork point with the active chain
+	Status       ChainTipStatus  // State of the branch
+}
+
+// ChainTips returns the tips of all branches in the block tree, including the tip
+// of the active chain, ordered by descending height.
+//
+// The returned BlockTreeEntry values are non-owned pointers valid for the lifetime
+// of this chainstate manager.
+func (cm *ChainstateManager) ChainTips() []ChainTip {
+	ptr := C.btck_chainstate_manager_get_chain_tips((*C.btck_ChainstateManager)(cm.ptr))
+	h := newUniqueHandle(unsafe.Pointer(check(ptr)), chainTipsCFuncs{})
+	defer h.Destroy()
+	cTips := (*C.btck_ChainTips)(h.ptr)
+
+	tips := make([]ChainTip, C.btck_chain_tips_count(cTips))
+	for i := range tips {
+		tips[i] = ChainTip{
+			Entry:        &BlockTreeEntry{ptr: C.btck_chain_tips_get_entry_at(cTips, C.size_t(i))},
+			BranchLength: int32(C.btck_chain_tips_get_branch_length_at(cTips, C.size_t(i))),
+			Status:       ChainTipStatus(C.btck_chain_tips_get_status_at(cTips, C.size_t(i))),
+		}
+	}
+	return tips
+}
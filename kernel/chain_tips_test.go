@@ -0,0 +1,182 @@
+package kernel_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+	"github.com/stringintech/go-bitcoinkernel/regtest"
+	"github.com/stringintech/go-bitcoinkernel/wire"
+)
+
+// processedEntry returns the block tree entry of a block processed by the
+// chainstate manager.
+func processedEntry(t *testing.T, manager *kernel.ChainstateManager, msg *wire.MsgBlock) *kernel.BlockTreeEntry {
+	t.Helper()
+	hash := kernel.NewBlockHash(msg.Header.BlockHash())
+	defer hash.Destroy()
+	entry := manager.GetBlockTreeEntryByHash(hash)
+	if entry == nil {
+		t.Fatalf("Expected block %s in the block tree", msg.Header.BlockHash())
+	}
+	return entry
+}
+
+func TestChainTips(t *testing.T) {
+	suite := kernel.ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 10,
+	}
+	suite.Setup(t)
+	gen := regtest.NewGenerator(suite.Manager)
+	chain := suite.Manager.GetActiveChain()
+	tip := chain.GetByHeight(10)
+
+	tips := suite.Manager.ChainTips()
+	if len(tips) != 1 {
+		t.Fatalf("Expected 1 chain tip, got %d", len(tips))
+	}
+	if !tips[0].Entry.Equals(tip) || tips[0].Status != kernel.ChainTipStatusActive || tips[0].BranchLength != 0 {
+		t.Errorf("Expected active tip at height 10 with branch length 0, got %s tip at height %d with branch length %d",
+			tips[0].Status, tips[0].Entry.Height(), tips[0].BranchLength)
+	}
+
+	status := chain.GetByHeight(5).Status()
+	if !status.Has(kernel.BlockStatusHaveData|kernel.BlockStatusHaveUndo) || status&kernel.BlockStatusFailed != 0 {
+		t.Errorf("Expected connected block to have data and undo data, got status %#x", status)
+	}
+
+	// A competing block at the height of the tip has the same work and is stored
+	// without being connected
+	fork, err := gen.GenerateOn(chain.GetByHeight(9), 1)
+	if err != nil {
+		t.Fatalf("GenerateOn() error = %v", err)
+	}
+	forkHash := fork[0].Hash()
+	defer forkHash.Destroy()
+	forkEntry := suite.Manager.GetBlockTreeEntryByHash(forkHash)
+	if forkEntry == nil {
+		t.Fatal("Expected fork block in the block tree")
+	}
+	if status := forkEntry.Status(); !status.Has(kernel.BlockStatusHaveData) || status.Has(kernel.BlockStatusHaveUndo) {
+		t.Errorf("Expected unconnected block to have data but no undo data, got status %#x", status)
+	}
+
+	// A block claiming too much in its coinbase fails when connected
+	invalid, err := gen.BuildBlock(tip)
+	if err != nil {
+		t.Fatalf("BuildBlock() error = %v", err)
+	}
+	invalid.Transactions[0].TxOut[0].Value++
+	regtest.Mine(invalid)
+	var validationErr *kernel.BlockValidationError
+	if _, err := gen.Submit(invalid); !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *BlockValidationError, got %v", err)
+	}
+	invalidEntry := processedEntry(t, suite.Manager, invalid)
+	if !invalidEntry.Status().Has(kernel.BlockStatusFailedValid) {
+		t.Errorf("Expected invalid block to have failed validation, got status %#x", invalidEntry.Status())
+	}
+	if !chain.GetByHeight(10).Equals(tip) || chain.GetHeight() != 10 {
+		t.Error("Expected active chain to be unchanged")
+	}
+
+	tips = suite.Manager.ChainTips()
+	if len(tips) != 3 {
+		t.Fatalf("Expected 3 chain tips, got %d", len(tips))
+	}
+	if !tips[0].Entry.Equals(invalidEntry) || tips[0].Status != kernel.ChainTipStatusInvalid || tips[0].BranchLength != 1 {
+		t.Errorf("Expected invalid tip with branch length 1 first, got %s tip at height %d with branch length %d",
+			tips[0].Status, tips[0].Entry.Height(), tips[0].BranchLength)
+	}
+	for _, chainTip := range tips[1:] {
+		switch {
+		case chainTip.Entry.Equals(tip):
+			if chainTip.Status != kernel.ChainTipStatusActive || chainTip.BranchLength != 0 {
+				t.Errorf("Expected active tip with branch length 0, got %s with branch length %d", chainTip.Status, chainTip.BranchLength)
+			}
+		case chainTip.Entry.Equals(forkEntry):
+			if chainTip.Status != kernel.ChainTipStatusValidHeaders || chainTip.BranchLength != 1 {
+				t.Errorf("Expected valid-headers fork with branch length 1, got %s with branch length %d", chainTip.Status, chainTip.BranchLength)
+			}
+		default:
+			t.Errorf("Unexpected chain tip at height %d", chainTip.Entry.Height())
+		}
+	}
+
+	if fork := forkEntry.FindFork(invalidEntry); !fork.Equals(chain.GetByHeight(9)) {
+		t.Errorf("Expected fork point at height 9, got height %d", fork.Height())
+	}
+	if fork := chain.GetByHeight(3).FindFork(tip); !fork.Equals(chain.GetByHeight(3)) {
+		t.Errorf("Expected an ancestor to be its own fork point, got height %d", fork.Height())
+	}
+}
+
+func TestInvalidateBlock(t *testing.T) {
+	var connected, disconnected []int32
+	suite := kernel.ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 10,
+		ValidationCallbacks: &kernel.ValidationInterfaceCallbacks{
+			OnBlockConnected: func(_ *kernel.Block, entry *kernel.BlockTreeEntry) {
+				connected = append(connected, entry.Height())
+			},
+			OnBlockDisconnected: func(_ *kernel.Block, entry *kernel.BlockTreeEntry) {
+				disconnected = append(disconnected, entry.Height())
+			},
+		},
+	}
+	suite.Setup(t)
+	chain := suite.Manager.GetActiveChain()
+	tip, invalidated := chain.GetByHeight(10), chain.GetByHeight(8)
+
+	expectReorg := func(t *testing.T, wantDisconnected, wantConnected []int32, wantTip *kernel.BlockTreeEntry) {
+		t.Helper()
+		if !slices.Equal(disconnected, wantDisconnected) || !slices.Equal(connected, wantConnected) {
+			t.Errorf("Expected disconnected %v and connected %v, got %v and %v", wantDisconnected, wantConnected, disconnected, connected)
+		}
+		if !chain.GetByHeight(chain.GetHeight()).Equals(wantTip) {
+			t.Errorf("Expected tip at height %d, got height %d", wantTip.Height(), chain.GetHeight())
+		}
+		connected, disconnected = nil, nil
+	}
+	connected = nil
+
+	if err := suite.Manager.InvalidateBlock(invalidated); err != nil {
+		t.Fatalf("InvalidateBlock() error = %v", err)
+	}
+	expectReorg(t, []int32{10, 9, 8}, nil, chain.GetByHeight(7))
+	if !invalidated.Status().Has(kernel.BlockStatusFailedValid) || !tip.Status().Has(kernel.BlockStatusFailedChild) {
+		t.Errorf("Expected invalidated block and its descendants to be marked failed, got %#x and %#x", invalidated.Status(), tip.Status())
+	}
+	if tips := suite.Manager.ChainTips(); len(tips) != 2 || !tips[0].Entry.Equals(tip) || tips[0].Status != kernel.ChainTipStatusInvalid || tips[0].BranchLength != 3 {
+		t.Errorf("Expected invalid tip at height 10 with branch length 3, got %v", tips)
+	}
+
+	if err := suite.Manager.ReconsiderBlock(invalidated); err != nil {
+		t.Fatalf("ReconsiderBlock() error = %v", err)
+	}
+	expectReorg(t, nil, []int32{8, 9, 10}, tip)
+	if tip.Status()&kernel.BlockStatusFailed != 0 {
+		t.Errorf("Expected reconsidered block to not be marked failed, got %#x", tip.Status())
+	}
+
+	// A block with the same work as the tip only becomes active once it is precious
+	fork, err := regtest.NewGenerator(suite.Manager).GenerateOn(chain.GetByHeight(9), 1)
+	if err != nil {
+		t.Fatalf("GenerateOn() error = %v", err)
+	}
+	forkHash := fork[0].Hash()
+	defer forkHash.Destroy()
+	forkEntry := suite.Manager.GetBlockTreeEntryByHash(forkHash)
+	expectReorg(t, nil, nil, tip)
+
+	if err := suite.Manager.PreciousBlock(forkEntry); err != nil {
+		t.Fatalf("PreciousBlock() error = %v", err)
+	}
+	expectReorg(t, []int32{10}, []int32{10}, forkEntry)
+
+	if err := suite.Manager.PreciousBlock(tip); err != nil {
+		t.Fatalf("PreciousBlock() error = %v", err)
+	}
+	expectReorg(t, []int32{10}, []int32{10}, tip)
+}
@@ -6,6 +6,9 @@ package kernel
 */
 import "C"
 import (
+	"context"
+	"runtime"
+	"sync"
 	"unsafe"
 )
 
@@ -19,11 +22,12 @@ func (chainstateManagerCFuncs) destroy(ptr unsafe.Pointer) {
 // retrieving data from the chain.
 type ChainstateManager struct {
 	*uniqueHandle
+	context *Context
 }
 
-func newChainstateManager(ptr *C.btck_ChainstateManager) *ChainstateManager {
+func newChainstateManager(ptr *C.btck_ChainstateManager, context *Context) *ChainstateManager {
 	h := newUniqueHandle(unsafe.Pointer(ptr), chainstateManagerCFuncs{})
-	return &ChainstateManager{uniqueHandle: h}
+	return &ChainstateManager{uniqueHandle: h, context: context}
 }
 
 // NewChainstateManager creates a new chainstate manager for validation and chain queries.
@@ -75,7 +79,7 @@ func NewChainstateManager(context *Context, dataDir, blocksDir string, options .
 	if ptr == nil {
 		return nil, &InternalError{"Failed to create chainstate manager"}
 	}
-	return newChainstateManager(ptr), nil
+	return newChainstateManager(ptr, context.Copy()), nil
 }
 
 // ReadBlock reads the block from disk that the block tree entry points to.
@@ -83,10 +87,14 @@ func NewChainstateManager(context *Context, dataDir, blocksDir string, options .
 // Parameters:
 //   - blockTreeEntry: Block index entry obtained from GetBlockTreeEntryByHash or chain queries
 //
-// Returns an error if the block cannot be read from disk.
+// Returns ErrBlockPruned if the block data has been deleted by pruning, or an
+// error if the block cannot be read from disk.
 func (cm *ChainstateManager) ReadBlock(blockTreeEntry *BlockTreeEntry) (*Block, error) {
 	ptr := C.btck_block_read((*C.btck_ChainstateManager)(cm.ptr), blockTreeEntry.ptr)
 	if ptr == nil {
+		if cm.IsBlockPruned(blockTreeEntry) {
+			return nil, ErrBlockPruned
+		}
 		return nil, &InternalError{"Failed to read block"}
 	}
 	return newBlock(ptr, true), nil
@@ -97,10 +105,14 @@ func (cm *ChainstateManager) ReadBlock(blockTreeEntry *BlockTreeEntry) (*Block,
 // Parameters:
 //   - blockTreeEntry: Block index entry for the block whose spent outputs to read
 //
-// Returns an error if the undo data cannot be read from disk.
+// Returns ErrBlockPruned if the undo data has been deleted by pruning, or an
+// error if the undo data cannot be read from disk.
 func (cm *ChainstateManager) ReadBlockSpentOutputs(blockTreeEntry *BlockTreeEntry) (*BlockSpentOutputs, error) {
 	ptr := C.btck_block_spent_outputs_read((*C.btck_ChainstateManager)(cm.ptr), blockTreeEntry.ptr)
 	if ptr == nil {
+		if cm.IsBlockPruned(blockTreeEntry) {
+			return nil, ErrBlockPruned
+		}
 		return nil, &InternalError{"Failed to read block spent outputs"}
 	}
 	return newBlockSpentOutputs(ptr, true), nil
@@ -109,24 +121,159 @@ func (cm *ChainstateManager) ReadBlockSpentOutputs(blockTreeEntry *BlockTreeEntr
 // ProcessBlock processes and validates the passed in block with the chainstate
 // manager. Processing first does checks on the block, and if these passed,
 // saves it to disk. It then validates the block against the utxo set. If it is
-// valid, the chain is extended with it. The ok return value is not indicative of
-// the block's validity. Detailed information on the validity of the block can
-// be retrieved by registering the block_checked callback in the validation
-// interface.
+// valid, the chain is extended with it.
 //
 // Parameters:
 //   - block: Block to validate and potentially add to the chain
 //
-// Returns ok=true if processing the block was successful (will also return true for valid,
-// but duplicate blocks) and newBlock=true if this block was not processed before. Note that
-// newBlock might also be true if processing was attempted before, but the block was found
-// invalid before its data was persisted.
-func (cm *ChainstateManager) ProcessBlock(block *Block) (ok bool, newBlock bool) {
+// Returns newBlock=true if this block was not processed before. Note that newBlock
+// might also be true if processing was attempted before, but the block was found
+// invalid before its data was persisted. Valid but duplicate blocks return a nil error.
+//
+// Returns a *BlockValidationError carrying the validation mode, result and Core's
+// reject reason if the block was found invalid, or an *InternalError if processing
+// failed without a validation result.
+func (cm *ChainstateManager) ProcessBlock(block *Block) (newBlock bool, err error) {
+	hash := block.Hash()
+	defer hash.Destroy()
+	hashBytes := hash.Bytes()
+
+	// Capture the validation result reported for this block while it is processed
+	var mu sync.Mutex
+	var validationErr *BlockValidationError
+	remove := cm.context.listeners.blockChecked.add(func(event blockCheckedEvent) {
+		state := event.state
+		if state.ValidationMode() == ValidationStateValid {
+			return
+		}
+		checkedHash := event.block.Hash()
+		defer checkedHash.Destroy()
+		if checkedHash.Bytes() != hashBytes {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if validationErr == nil {
+			validationErr = newBlockValidationError(state)
+		}
+	})
+	defer remove()
+
 	var newBlockInt C.int
 	result := C.btck_chainstate_manager_process_block((*C.btck_ChainstateManager)(cm.ptr), (*C.btck_Block)(block.ptr), &newBlockInt)
-	ok = result == 0
 	newBlock = newBlockInt == 1
-	return
+
+	mu.Lock()
+	defer mu.Unlock()
+	if validationErr != nil {
+		return newBlock, validationErr
+	}
+	if result != 0 {
+		return newBlock, &InternalError{"Failed to process block"}
+	}
+	return newBlock, nil
+}
+
+// ProcessHeaders validates the headers in order and adds them to the block tree
+// without their block data, as done during a headers-first sync. Headers are
+// checked for proof of work and against the contextual header rules of the chain
+// they extend, each header must extend a known header or a previous one. Headers
+// that are already known are skipped.
+//
+// An improved best header is reported through the OnHeaderTip notification
+// callback. The block data of accepted headers can be passed to ProcessBlock later.
+//
+// Parameters:
+//   - headers: Block headers to validate and add to the block tree
+//
+// Returns a *BlockValidationError carrying Core's reject reason for the first
+// invalid header. Headers before it remain in the block tree.
+func (cm *ChainstateManager) ProcessHeaders(headers []*BlockHeader) error {
+	if len(headers) == 0 {
+		return nil
+	}
+	cHeaders := make([]*C.btck_BlockHeader, len(headers))
+	for i, header := range headers {
+		cHeaders[i] = (*C.btck_BlockHeader)(header.ptr)
+	}
+	statePtr := C.btck_block_validation_state_create()
+	defer C.btck_block_validation_state_destroy(statePtr)
+
+	result := C.btck_chainstate_manager_process_block_headers((*C.btck_ChainstateManager)(cm.ptr), &cHeaders[0], C.size_t(len(cHeaders)), statePtr)
+	runtime.KeepAlive(headers)
+	if result != 0 {
+		return newBlockValidationError(&BlockValidationState{ptr: statePtr})
+	}
+	return nil
+}
+
+// InvalidateBlock permanently marks the block of blockTreeEntry as invalid, as if
+// it violated a consensus rule, and reorganizes to the best chain not containing
+// it. Like Bitcoin Core's invalidateblock, this also marks all descendants of the
+// block as invalid.
+//
+// Blocks disconnected and connected during the reorganization are reported through
+// the OnBlockDisconnected and OnBlockConnected validation interface callbacks.
+//
+// Returns an error if the block could not be invalidated or the reorganization failed.
+func (cm *ChainstateManager) InvalidateBlock(blockTreeEntry *BlockTreeEntry) error {
+	if C.btck_chainstate_manager_invalidate_block((*C.btck_ChainstateManager)(cm.ptr), blockTreeEntry.ptr) != 0 {
+		return &InternalError{"Failed to invalidate block"}
+	}
+	return nil
+}
+
+// ReconsiderBlock removes the invalidity status of the block of blockTreeEntry, its
+// ancestors and its descendants, undoing previous calls to InvalidateBlock, and
+// reorganizes to the best chain. Blocks that actually violate consensus rules are
+// found invalid again when they are connected.
+//
+// Blocks disconnected and connected during the reorganization are reported through
+// the OnBlockDisconnected and OnBlockConnected validation interface callbacks.
+//
+// Returns an error if the reorganization failed.
+func (cm *ChainstateManager) ReconsiderBlock(blockTreeEntry *BlockTreeEntry) error {
+	if C.btck_chainstate_manager_reconsider_block((*C.btck_ChainstateManager)(cm.ptr), blockTreeEntry.ptr) != 0 {
+		return &InternalError{"Failed to reconsider block"}
+	}
+	return nil
+}
+
+// PreciousBlock treats the block of blockTreeEntry as if it was received before
+// other blocks with the same work, and reorganizes to it if its chain has as much
+// work as the active chain. A later call overrides the effect of earlier ones. The
+// preference is not persisted across restarts.
+//
+// Blocks disconnected and connected during the reorganization are reported through
+// the OnBlockDisconnected and OnBlockConnected validation interface callbacks.
+//
+// Returns an error if the reorganization failed.
+func (cm *ChainstateManager) PreciousBlock(blockTreeEntry *BlockTreeEntry) error {
+	if C.btck_chainstate_manager_precious_block((*C.btck_ChainstateManager)(cm.ptr), blockTreeEntry.ptr) != 0 {
+		return &InternalError{"Failed to mark block as precious"}
+	}
+	return nil
+}
+
+// CheckTransactionInputs checks the transaction against the consensus rules that
+// depend on the UTXO set of the active chain, as if it was included in the next
+// block: its inputs exist and coinbase inputs are mature, its input values cover
+// its output values, and its locktime and BIP68 sequence locks are satisfied.
+//
+// Transactions in the mempool are not taken into account. The checks of
+// CheckTransaction and script verification are not performed.
+//
+// Returns a *TransactionValidationError if the transaction violates a rule.
+func (cm *ChainstateManager) CheckTransactionInputs(tx *Transaction) error {
+	statePtr := C.btck_tx_validation_state_create()
+	defer C.btck_tx_validation_state_destroy(statePtr)
+
+	result := C.btck_chainstate_manager_check_transaction_inputs((*C.btck_ChainstateManager)(cm.ptr), (*C.btck_Transaction)(tx.handle.ptr), statePtr)
+	runtime.KeepAlive(tx)
+	if result != 0 {
+		return newTransactionValidationError(&TxValidationState{ptr: statePtr})
+	}
+	return nil
 }
 
 // GetActiveChain returns the currently active best-known chain.
@@ -140,6 +287,22 @@ func (cm *ChainstateManager) GetActiveChain() *Chain {
 	return &Chain{C.btck_chainstate_manager_get_active_chain((*C.btck_ChainstateManager)(cm.ptr))}
 }
 
+// BestHeader returns the block tree entry of the header with the most work that
+// is not known to be invalid. Its block data is not necessarily available, so it
+// may be ahead of the tip of the active chain. The best header chain can be walked
+// with BlockTreeEntry.Ancestor and BlockTreeEntry.Previous.
+//
+// Returns nil if no header is known yet, i.e. before the block index is loaded.
+// The returned BlockTreeEntry is a non-owned pointer valid for the lifetime of
+// this chainstate manager.
+func (cm *ChainstateManager) BestHeader() *BlockTreeEntry {
+	ptr := C.btck_chainstate_manager_get_best_header((*C.btck_ChainstateManager)(cm.ptr))
+	if ptr == nil {
+		return nil
+	}
+	return &BlockTreeEntry{ptr: ptr}
+}
+
 // GetBlockTreeEntryByHash retrieves a block tree entry by its block hash.
 //
 // Parameters:
@@ -156,6 +319,92 @@ func (cm *ChainstateManager) GetBlockTreeEntryByHash(blockHash BlockHashLike) *B
 	return &BlockTreeEntry{ptr: ptr}
 }
 
+// GetCoin looks up an unspent output in the UTXO set of the active chainstate.
+//
+// The lookup goes through the chainstate's coins cache, so it reflects blocks that
+// have not been flushed to disk yet.
+//
+// Parameters:
+//   - outPoint: Out point of the output to look up
+//
+// Returns nil if the output does not exist or has been spent.
+func (cm *ChainstateManager) GetCoin(outPoint TransactionOutPointLike) *Coin {
+	ptr := C.btck_chainstate_manager_get_coin((*C.btck_ChainstateManager)(cm.ptr), outPoint.outPointPtr())
+	if ptr == nil {
+		return nil
+	}
+	return newCoin(ptr, true)
+}
+
+// GetCoins looks up multiple unspent outputs in the UTXO set of the active
+// chainstate. The lookups are done under a single lock, so the results are
+// consistent with each other even while blocks are being processed.
+//
+// Parameters:
+//   - outPoints: Out points of the outputs to look up
+//
+// Returns a slice with the coin of each out point at the same index, or nil for
+// out points that do not exist or have been spent.
+func (cm *ChainstateManager) GetCoins(outPoints []TransactionOutPointLike) []*Coin {
+	if len(outPoints) == 0 {
+		return nil
+	}
+	cOutPoints := make([]*C.btck_TransactionOutPoint, len(outPoints))
+	for i, outPoint := range outPoints {
+		cOutPoints[i] = outPoint.outPointPtr()
+	}
+	cCoins := make([]*C.btck_Coin, len(outPoints))
+	C.btck_chainstate_manager_get_coins((*C.btck_ChainstateManager)(cm.ptr), &cOutPoints[0], C.size_t(len(cOutPoints)), &cCoins[0])
+	runtime.KeepAlive(outPoints)
+
+	coins := make([]*Coin, len(outPoints))
+	for i, ptr := range cCoins {
+		if ptr != nil {
+			coins[i] = newCoin(ptr, true)
+		}
+	}
+	return coins
+}
+
+// Flush writes the block index and the coins cache of the chainstates to disk
+// and empties the coins cache. The chainstate is also written to disk
+// periodically while blocks are processed, see WithFlushInterval.
+//
+// Returns an error if writing failed, which is also reported through the
+// OnFlushError notification callback.
+func (cm *ChainstateManager) Flush() error {
+	if C.btck_chainstate_manager_flush((*C.btck_ChainstateManager)(cm.ptr)) != 0 {
+		return &InternalError{"Failed to flush chainstate"}
+	}
+	return nil
+}
+
+// PruneBlockFilesUpTo deletes the block and undo files that only contain blocks
+// up to the given height of the active chain, as done by Bitcoin Core's
+// pruneblockchain. Blocks close to the tip are always kept, so the height is
+// lowered accordingly.
+//
+// Pruning must have been enabled with WithPruneTarget. Reading the data of a
+// pruned block returns ErrBlockPruned.
+//
+// Parameters:
+//   - height: Height of the last block that may be pruned
+//
+// Returns an error if pruning is disabled, the chain is too short for pruning or
+// the height is out of range of the active chain.
+func (cm *ChainstateManager) PruneBlockFilesUpTo(height int32) error {
+	if C.btck_chainstate_manager_prune_block_files((*C.btck_ChainstateManager)(cm.ptr), C.int32_t(height)) != 0 {
+		return &InternalError{"Failed to prune block files"}
+	}
+	return nil
+}
+
+// IsBlockPruned reports whether the block and undo data of the block the block
+// tree entry points to have been deleted by pruning.
+func (cm *ChainstateManager) IsBlockPruned(blockTreeEntry *BlockTreeEntry) bool {
+	return C.btck_chainstate_manager_is_block_pruned((*C.btck_ChainstateManager)(cm.ptr), blockTreeEntry.ptr) != 0
+}
+
 // ImportBlocks triggers a reindex and/or imports block files from the filesystem.
 //
 // This starts a reindex if the wipe options were previously set via ChainstateManagerOptions.
@@ -202,3 +451,96 @@ func (cm *ChainstateManager) ImportBlocks(blockFilePaths []string) error {
 	}
 	return nil
 }
+
+// ImportBlocksContext is like ImportBlocks but can be cancelled through ctx and
+// reports progress on the given channel.
+//
+// When ctx is cancelled or its deadline expires, the kernel context the chainstate
+// manager was created with is interrupted (see Context.Interrupt) and ctx.Err() is
+// returned once the import has stopped. Note that an interrupted kernel context stays
+// interrupted, so further long-running operations on it will stop early as well.
+//
+// Progress events are derived from the block tip and progress notifications of the
+// kernel context and are delivered in addition to any NotificationCallbacks set with
+// WithNotifications. Sends on the channel do not block: events are dropped when the
+// channel is not ready, so callers should use a buffered channel. The channel is not
+// closed and no events are sent to it after this method returns.
+//
+// Parameters:
+//   - ctx: Context controlling cancellation of the import
+//   - blockFilePaths: Array of full filesystem paths to block files to import (can be empty)
+//   - progress: Channel receiving progress events (can be nil)
+//
+// Returns ctx.Err() if the import was cancelled, or an error if the import fails.
+func (cm *ChainstateManager) ImportBlocksContext(ctx context.Context, blockFilePaths []string, progress chan<- ImportProgress) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	done := false
+	remove := cm.context.listeners.progress.add(func(event ImportProgress) {
+		// A ctx cancelled by a callback is noticed before the import continues,
+		// rather than once the goroutine of context.AfterFunc runs
+		if ctx.Err() != nil {
+			_ = cm.context.Interrupt()
+		}
+		if progress == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if done {
+			return
+		}
+		select {
+		case progress <- event:
+		default:
+		}
+	})
+	defer func() {
+		remove()
+		mu.Lock()
+		done = true
+		mu.Unlock()
+	}()
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = cm.context.Interrupt()
+	})
+	defer stop()
+
+	err := cm.ImportBlocks(blockFilePaths)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// ImportProgressKind identifies the notification an ImportProgress event was derived from.
+type ImportProgressKind int
+
+const (
+	// ImportProgressTask is reported for progress notifications of long-running tasks
+	// such as reindexing or loading blocks from disk.
+	ImportProgressTask ImportProgressKind = iota
+	// ImportProgressBlockTip is reported whenever the chain tip changes.
+	ImportProgressBlockTip
+)
+
+// ImportProgress is a progress event reported by ImportBlocksContext.
+type ImportProgress struct {
+	Kind ImportProgressKind
+
+	// Title, Percent and Resumable are set for ImportProgressTask events.
+	Title     string
+	Percent   int
+	Resumable bool
+
+	// SyncState, Height, BlockHash and VerificationProgress are set for
+	// ImportProgressBlockTip events. BlockHash is in internal byte order.
+	SyncState            SynchronizationState
+	Height               int32
+	BlockHash            [32]byte
+	VerificationProgress float64
+}
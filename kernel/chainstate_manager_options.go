@@ -4,6 +4,18 @@ package kernel
 #include "bitcoinkernel.h"
 */
 import "C"
+import (
+	"math"
+	"time"
+)
+
+const (
+	// MinPruneTarget is the smallest prune target accepted by WithPruneTarget.
+	MinPruneTarget uint64 = 550 * 1024 * 1024
+	// PruneTargetManual enables pruning without a target size, so that block
+	// files are only deleted by ChainstateManager.PruneBlockFilesUpTo.
+	PruneTargetManual uint64 = math.MaxUint64
+)
 
 // ChainstateManagerOption is a functional option for configuring chainstate manager.
 type ChainstateManagerOption func(*C.btck_ChainstateManagerOptions) error
@@ -68,3 +80,109 @@ func WithChainstateDBInMemory() ChainstateManagerOption {
 		return nil
 	}
 }
+
+// WithPruneTarget returns a ChainstateManagerOption that enables pruning of the
+// block and undo files. While the chainstate is flushed, the oldest files are
+// deleted to keep their total size below the target. Blocks close to the tip
+// are always kept.
+//
+// Parameters:
+//   - bytes: Target size of the block and undo files, at least MinPruneTarget, or
+//     PruneTargetManual to only prune through ChainstateManager.PruneBlockFilesUpTo.
+//     0 disables pruning.
+//
+// Returns an error if the target is below MinPruneTarget.
+func WithPruneTarget(bytes uint64) ChainstateManagerOption {
+	return func(opts *C.btck_ChainstateManagerOptions) error {
+		if C.btck_chainstate_manager_options_set_prune_target(opts, C.uint64_t(bytes)) != 0 {
+			return &InternalError{"Prune target below the minimum"}
+		}
+		return nil
+	}
+}
+
+// WithFastPrune returns a ChainstateManagerOption that limits block files to
+// 64 KiB, as Bitcoin Core's test-only -fastprune does. Blocks are only pruned
+// a whole block file at a time, so this lets tests prune short chains of small
+// blocks. It is not meant for production use.
+func WithFastPrune() ChainstateManagerOption {
+	return func(opts *C.btck_ChainstateManagerOptions) error {
+		C.btck_chainstate_manager_options_set_fast_prune(opts, C.int(1))
+		return nil
+	}
+}
+
+// WithBlockTreeDBCacheSize returns a ChainstateManagerOption that sets the size
+// of the block tree database cache. The default is 2 MiB.
+//
+// Parameters:
+//   - bytes: Cache size in bytes
+func WithBlockTreeDBCacheSize(bytes uint64) ChainstateManagerOption {
+	return func(opts *C.btck_ChainstateManagerOptions) error {
+		C.btck_chainstate_manager_options_set_block_tree_db_cache_size(opts, C.size_t(bytes))
+		return nil
+	}
+}
+
+// WithCoinsDBCacheSize returns a ChainstateManagerOption that sets the size of
+// the coins database cache. The default is 8 MiB.
+//
+// Parameters:
+//   - bytes: Cache size in bytes
+func WithCoinsDBCacheSize(bytes uint64) ChainstateManagerOption {
+	return func(opts *C.btck_ChainstateManagerOptions) error {
+		C.btck_chainstate_manager_options_set_coins_db_cache_size(opts, C.size_t(bytes))
+		return nil
+	}
+}
+
+// WithCoinsCacheSize returns a ChainstateManagerOption that sets the size of the
+// in-memory coins cache. The cache holds the changes to the UTXO set since it was
+// last written to disk and is written to disk when it grows beyond this size, so
+// a larger cache speeds up initial sync. The default is about 440 MiB.
+//
+// Parameters:
+//   - bytes: Cache size in bytes
+func WithCoinsCacheSize(bytes uint64) ChainstateManagerOption {
+	return func(opts *C.btck_ChainstateManagerOptions) error {
+		C.btck_chainstate_manager_options_set_coins_cache_size(opts, C.size_t(bytes))
+		return nil
+	}
+}
+
+// WithFlushInterval returns a ChainstateManagerOption that sets the interval
+// between periodic writes of the chainstate to disk. The time of each write is
+// picked randomly between minInterval and maxInterval, to avoid many nodes writing at the same
+// time. The defaults are 50 and 70 minutes.
+//
+// Parameters:
+//   - minInterval: Minimum time between writes, at least one second
+//   - maxInterval: Maximum time between writes, not below minInterval
+//
+// Returns an error if the bounds are invalid.
+func WithFlushInterval(minInterval, maxInterval time.Duration) ChainstateManagerOption {
+	return func(opts *C.btck_ChainstateManagerOptions) error {
+		result := C.btck_chainstate_manager_options_set_flush_interval(opts, C.int64_t(minInterval/time.Second), C.int64_t(maxInterval/time.Second))
+		if result != 0 {
+			return &InternalError{"Invalid flush interval"}
+		}
+		return nil
+	}
+}
+
+// WithMempool returns a ChainstateManagerOption that enables the mempool of the
+// chainstate manager, see ChainstateManager.Mempool.
+//
+// Parameters:
+//   - maxSizeBytes: Maximum memory usage of the mempool, e.g. DefaultMempoolMaxSize
+//
+// Returns an error if maxSizeBytes is not positive.
+func WithMempool(maxSizeBytes int64) ChainstateManagerOption {
+	return func(opts *C.btck_ChainstateManagerOptions) error {
+		result := C.btck_chainstate_manager_options_enable_mempool(opts, C.int64_t(maxSizeBytes))
+		if result != 0 {
+			return &InternalError{"Invalid mempool size"}
+		}
+		return nil
+	}
+}
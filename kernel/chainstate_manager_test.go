@@ -1,13 +1,16 @@
 package kernel
 
 import (
-	"encoding/hex"
+	"context"
 	"errors"
 	"os"
 	"path/filepath"
 	"slices"
-	"strings"
 	"testing"
+	"time"
+
+	"github.com/stringintech/go-bitcoinkernel/internal/fixtures"
+	"github.com/stringintech/go-bitcoinkernel/wire"
 )
 
 func TestChainstateManager(t *testing.T) {
@@ -22,6 +25,10 @@ func TestChainstateManager(t *testing.T) {
 	t.Run("block undo", suite.TestBlockSpentOutputs)
 	t.Run("transaction spent outputs", suite.TestTransactionSpentOutputs)
 	t.Run("get block tree entry by hash", suite.TestGetBlockTreeEntryByHash)
+	t.Run("get coin", suite.TestGetCoin)
+	t.Run("utxo set", suite.TestUTXOSet)
+	t.Run("snapshot", suite.TestSnapshot)
+	t.Run("verify block scripts", suite.TestVerifyBlockScripts)
 }
 
 func (s *ChainstateManagerTestSuite) TestBlockSpentOutputs(t *testing.T) {
@@ -219,13 +226,565 @@ func (s *ChainstateManagerTestSuite) TestGetBlockTreeEntryByHash(t *testing.T) {
 	}
 }
 
+func (s *ChainstateManagerTestSuite) TestGetCoin(t *testing.T) {
+	chain := s.Manager.GetActiveChain()
+	tipIndex := chain.GetByHeight(chain.GetHeight())
+	tipBlock, err := s.Manager.ReadBlock(tipIndex)
+	if err != nil {
+		t.Fatalf("ReadBlock() error = %v", err)
+	}
+	defer tipBlock.Destroy()
+
+	// The coinbase output of the tip is immature and therefore unspent
+	coinbase, err := tipBlock.GetTransactionAt(0)
+	if err != nil {
+		t.Fatalf("GetTransactionAt(0) error = %v", err)
+	}
+	coinbaseOutput, err := coinbase.GetOutput(0)
+	if err != nil {
+		t.Fatalf("GetOutput(0) error = %v", err)
+	}
+	unspent := NewTransactionOutPoint(coinbase.GetTxid().Bytes(), 0)
+	defer unspent.Destroy()
+
+	coin := s.Manager.GetCoin(unspent)
+	if coin == nil {
+		t.Fatal("GetCoin() returned nil for the tip coinbase output")
+	}
+	defer coin.Destroy()
+	if coin.ConfirmationHeight() != uint32(tipIndex.Height()) {
+		t.Errorf("Expected confirmation height %d, got %d", tipIndex.Height(), coin.ConfirmationHeight())
+	}
+	if !coin.IsCoinbase() {
+		t.Error("Expected coin to be a coinbase coin")
+	}
+	if coin.GetOutput().Amount() != coinbaseOutput.Amount() {
+		t.Errorf("Expected amount %d, got %d", coinbaseOutput.Amount(), coin.GetOutput().Amount())
+	}
+
+	// An output spent by block 202 is no longer in the UTXO set
+	block, err := s.Manager.ReadBlock(chain.GetByHeight(202))
+	if err != nil {
+		t.Fatalf("ReadBlock() error = %v", err)
+	}
+	defer block.Destroy()
+	spender, err := block.GetTransactionAt(1)
+	if err != nil {
+		t.Fatalf("GetTransactionAt(1) error = %v", err)
+	}
+	input, err := spender.GetInput(0)
+	if err != nil {
+		t.Fatalf("GetInput(0) error = %v", err)
+	}
+	spent := input.GetOutPoint()
+	if coin := s.Manager.GetCoin(spent); coin != nil {
+		t.Error("Expected GetCoin() to return nil for a spent output")
+	}
+
+	missing := NewTransactionOutPoint([32]byte{}, 0)
+	defer missing.Destroy()
+
+	coins := s.Manager.GetCoins([]TransactionOutPointLike{unspent, spent, missing})
+	if len(coins) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(coins))
+	}
+	if coins[0] == nil || coins[0].ConfirmationHeight() != uint32(tipIndex.Height()) {
+		t.Error("Expected GetCoins() to return the tip coinbase coin")
+	}
+	if coins[1] != nil || coins[2] != nil {
+		t.Error("Expected GetCoins() to return nil for spent and missing outputs")
+	}
+	for _, c := range coins {
+		if c != nil {
+			c.Destroy()
+		}
+	}
+	if coins := s.Manager.GetCoins(nil); coins != nil {
+		t.Errorf("Expected no results for no out points, got %d", len(coins))
+	}
+}
+
+func TestImportBlocksContext(t *testing.T) {
+	suite := ChainstateManagerTestSuite{}
+	suite.Setup(t)
+	// Release the chainstate manager so the block files can be reindexed by a new one
+	suite.Manager.Destroy()
+
+	newReindexManager := func(t *testing.T, options ...ContextOption) *ChainstateManager {
+		t.Helper()
+		kernelCtx, err := NewContext(append([]ContextOption{WithChainType(ChainTypeRegtest)}, options...)...)
+		if err != nil {
+			t.Fatalf("NewContext() error = %v", err)
+		}
+		t.Cleanup(func() { kernelCtx.Destroy() })
+
+		manager, err := NewChainstateManager(kernelCtx, suite.DataDir, suite.BlocksDir,
+			WithBlockTreeDBInMemory(true),
+			WithChainstateDBInMemory(),
+			WithWipeDBs(true, true),
+		)
+		if err != nil {
+			t.Fatalf("NewChainstateManager() error = %v", err)
+		}
+		t.Cleanup(func() { manager.Destroy() })
+		return manager
+	}
+
+	t.Run("progress", func(t *testing.T) {
+		manager := newReindexManager(t)
+		progress := make(chan ImportProgress, 1024)
+		if err := manager.ImportBlocksContext(context.Background(), nil, progress); err != nil {
+			t.Fatalf("ImportBlocksContext() error = %v", err)
+		}
+		close(progress)
+
+		var maxHeight int32
+		for event := range progress {
+			if event.Kind == ImportProgressBlockTip && event.Height > maxHeight {
+				maxHeight = event.Height
+			}
+		}
+		if maxHeight != suite.ImportedBlocksCount {
+			t.Errorf("Expected block tip events up to height %d, got %d", suite.ImportedBlocksCount, maxHeight)
+		}
+		if height := manager.GetActiveChain().GetHeight(); height != suite.ImportedBlocksCount {
+			t.Errorf("Expected chain height %d after reindex, got %d", suite.ImportedBlocksCount, height)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Cancel once the first blocks are connected. Blocks are connected in
+		// batches of 32, so the import is interrupted well before the tip.
+		var cancelledAt int32
+		manager := newReindexManager(t, WithNotifications(&NotificationCallbacks{
+			OnBlockTip: func(_ SynchronizationState, entry *BlockTreeEntry, _ float64) {
+				if cancelledAt == 0 && entry.Height() > 0 {
+					cancelledAt = entry.Height()
+					cancel()
+				}
+			},
+		}))
+		err := manager.ImportBlocksContext(ctx, nil, nil)
+		if !errors.Is(err, context.Canceled) || err != ctx.Err() {
+			t.Errorf("Expected ctx.Err(), got %v", err)
+		}
+		if cancelledAt == 0 {
+			t.Fatal("Expected the import to be cancelled while connecting blocks")
+		}
+		if height := manager.GetActiveChain().GetHeight(); height != cancelledAt || height >= suite.ImportedBlocksCount {
+			t.Errorf("Expected chain to stop at height %d below %d, got %d", cancelledAt, suite.ImportedBlocksCount, height)
+		}
+	})
+
+	t.Run("cancelled before import", func(t *testing.T) {
+		manager := newReindexManager(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := manager.ImportBlocksContext(ctx, nil, nil); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+		if height := manager.GetActiveChain().GetHeight(); height > 0 {
+			t.Errorf("Expected no blocks to be imported, got height %d", height)
+		}
+	})
+}
+
+func TestProcessBlockValidationError(t *testing.T) {
+	suite := ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 1,
+	}
+	suite.Setup(t)
+
+	t.Run("duplicate block", func(t *testing.T) {
+		block := readRegtestBlock(t, 1)
+		defer block.Destroy()
+
+		newBlock, err := suite.Manager.ProcessBlock(block)
+		if err != nil {
+			t.Fatalf("ProcessBlock() error = %v", err)
+		}
+		if newBlock {
+			t.Error("Expected newBlock=false for duplicate block")
+		}
+	})
+
+	t.Run("missing previous block", func(t *testing.T) {
+		block := readRegtestBlock(t, 3)
+		defer block.Destroy()
+
+		_, err := suite.Manager.ProcessBlock(block)
+		var validationErr *BlockValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected *BlockValidationError, got %v", err)
+		}
+		if validationErr.Mode != ValidationStateInvalid {
+			t.Errorf("Expected mode %v, got %v", ValidationStateInvalid, validationErr.Mode)
+		}
+		if validationErr.Result != BlockMissingPrev {
+			t.Errorf("Expected result %v, got %v", BlockMissingPrev, validationErr.Result)
+		}
+		if validationErr.RejectReason != "prev-blk-not-found" {
+			t.Errorf("Expected reject reason prev-blk-not-found, got %q", validationErr.RejectReason)
+		}
+	})
+
+	t.Run("mutated block", func(t *testing.T) {
+		block := readRegtestBlock(t, 2)
+		defer block.Destroy()
+
+		// Change a transaction without updating the merkle root in the header
+		msg, err := block.ToWire()
+		if err != nil {
+			t.Fatalf("ToWire() error = %v", err)
+		}
+		msg.Transactions[0].TxOut[0].Value--
+		mutated, err := NewBlockFromWire(msg)
+		if err != nil {
+			t.Fatalf("NewBlockFromWire() error = %v", err)
+		}
+		defer mutated.Destroy()
+
+		_, err = suite.Manager.ProcessBlock(mutated)
+		var validationErr *BlockValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected *BlockValidationError, got %v", err)
+		}
+		if validationErr.Result != BlockMutated {
+			t.Errorf("Expected result %v, got %v", BlockMutated, validationErr.Result)
+		}
+		if validationErr.RejectReason != "bad-txnmrklroot" {
+			t.Errorf("Expected reject reason bad-txnmrklroot, got %q", validationErr.RejectReason)
+		}
+	})
+}
+
+func TestPruning(t *testing.T) {
+	suite := ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 10,
+	}
+	suite.Setup(t)
+
+	if err := suite.Manager.PruneBlockFilesUpTo(5); err == nil {
+		t.Error("Expected PruneBlockFilesUpTo() to fail without pruning enabled")
+	}
+
+	kernelCtx, err := NewContext(WithChainType(ChainTypeRegtest))
+	if err != nil {
+		t.Fatalf("NewContext() error = %v", err)
+	}
+	defer kernelCtx.Destroy()
+
+	tempDir := t.TempDir()
+	dataDir, blocksDir := filepath.Join(tempDir, "data"), filepath.Join(tempDir, "blocks")
+	if _, err := NewChainstateManager(kernelCtx, dataDir, blocksDir, WithPruneTarget(MinPruneTarget-1)); err == nil {
+		t.Error("Expected a prune target below MinPruneTarget to be rejected")
+	}
+
+	manager, err := NewChainstateManager(kernelCtx, dataDir, blocksDir,
+		WithBlockTreeDBInMemory(true),
+		WithChainstateDBInMemory(),
+		WithPruneTarget(PruneTargetManual),
+	)
+	if err != nil {
+		t.Fatalf("NewChainstateManager() error = %v", err)
+	}
+	defer manager.Destroy()
+	if err := manager.ImportBlocks(nil); err != nil {
+		t.Fatalf("ImportBlocks() error = %v", err)
+	}
+	for height := 1; height <= 10; height++ {
+		block := readRegtestBlock(t, height)
+		_, err := manager.ProcessBlock(block)
+		block.Destroy()
+		if err != nil {
+			t.Fatalf("ProcessBlock() error = %v", err)
+		}
+	}
+
+	// Regtest blocks are only pruned once the chain is 1000 blocks long
+	if err := manager.PruneBlockFilesUpTo(5); err == nil {
+		t.Error("Expected PruneBlockFilesUpTo() to fail for a short chain")
+	}
+	entry := manager.GetActiveChain().GetByHeight(5)
+	if manager.IsBlockPruned(entry) {
+		t.Error("Expected block to not be pruned")
+	}
+	block, err := manager.ReadBlock(entry)
+	if err != nil {
+		t.Fatalf("ReadBlock() error = %v", err)
+	}
+	block.Destroy()
+}
+
+func TestCacheAndFlushOptions(t *testing.T) {
+	var flushErrors []string
+	kernelCtx, err := NewContext(
+		WithChainType(ChainTypeRegtest),
+		WithNotifications(&NotificationCallbacks{
+			OnFlushError: func(message string) { flushErrors = append(flushErrors, message) },
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewContext() error = %v", err)
+	}
+	defer kernelCtx.Destroy()
+
+	tempDir := t.TempDir()
+	dataDir, blocksDir := filepath.Join(tempDir, "data"), filepath.Join(tempDir, "blocks")
+	for _, interval := range [][2]time.Duration{{0, time.Minute}, {2 * time.Minute, time.Minute}} {
+		if _, err := NewChainstateManager(kernelCtx, dataDir, blocksDir, WithFlushInterval(interval[0], interval[1])); err == nil {
+			t.Errorf("Expected flush interval %v to be rejected", interval)
+		}
+	}
+
+	manager, err := NewChainstateManager(kernelCtx, dataDir, blocksDir,
+		WithBlockTreeDBCacheSize(1<<20),
+		WithCoinsDBCacheSize(1<<20),
+		WithCoinsCacheSize(4<<20),
+		WithFlushInterval(time.Second, 2*time.Second),
+	)
+	if err != nil {
+		t.Fatalf("NewChainstateManager() error = %v", err)
+	}
+	defer manager.Destroy()
+	if err := manager.ImportBlocks(nil); err != nil {
+		t.Fatalf("ImportBlocks() error = %v", err)
+	}
+	for height := 1; height <= 10; height++ {
+		block := readRegtestBlock(t, height)
+		_, err := manager.ProcessBlock(block)
+		block.Destroy()
+		if err != nil {
+			t.Fatalf("ProcessBlock() error = %v", err)
+		}
+	}
+
+	if err := manager.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if len(flushErrors) != 0 {
+		t.Errorf("Expected no flush errors, got %v", flushErrors)
+	}
+	if height := manager.GetActiveChain().GetHeight(); height != 10 {
+		t.Errorf("Expected chain height 10 after flushing, got %d", height)
+	}
+}
+
+func TestProcessHeaders(t *testing.T) {
+	var headerTipHeight int64
+	suite := ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 5,
+		NotificationCallbacks: &NotificationCallbacks{
+			OnHeaderTip: func(_ SynchronizationState, height int64, _ int64, _ bool) { headerTipHeight = height },
+		},
+	}
+	suite.Setup(t)
+	chain := suite.Manager.GetActiveChain()
+
+	if !suite.Manager.BestHeader().Equals(chain.GetByHeight(5)) {
+		t.Error("Expected best header to be the active tip before processing headers")
+	}
+
+	var headers []*BlockHeader
+	for height := 6; height <= 10; height++ {
+		block := readRegtestBlock(t, height)
+		headers = append(headers, block.Header())
+		block.Destroy()
+	}
+	defer func() {
+		for _, header := range headers {
+			header.Destroy()
+		}
+	}()
+	if err := suite.Manager.ProcessHeaders(headers); err != nil {
+		t.Fatalf("ProcessHeaders() error = %v", err)
+	}
+	// Known headers are skipped
+	if err := suite.Manager.ProcessHeaders(headers[:2]); err != nil {
+		t.Fatalf("ProcessHeaders() of known headers error = %v", err)
+	}
+
+	bestHeader := suite.Manager.BestHeader()
+	if bestHeader.Height() != 10 || headerTipHeight != 10 {
+		t.Errorf("Expected best header and header tip at height 10, got %d and %d", bestHeader.Height(), headerTipHeight)
+	}
+	if chain.GetHeight() != 5 {
+		t.Errorf("Expected active chain to stay at height 5, got %d", chain.GetHeight())
+	}
+	if status := bestHeader.Status(); status.Has(BlockStatusHaveData) {
+		t.Errorf("Expected header-only entry without data, got status %#x", status)
+	}
+	if !bestHeader.Ancestor(5).Equals(chain.GetByHeight(5)) || bestHeader.Ancestor(11) != nil {
+		t.Error("Expected best header chain to extend the active chain")
+	}
+	tips := suite.Manager.ChainTips()
+	if len(tips) != 2 || !tips[0].Entry.Equals(bestHeader) || tips[0].Status != ChainTipStatusHeadersOnly || tips[0].BranchLength != 5 {
+		t.Errorf("Expected headers-only tip at height 10 with branch length 5, got %v", tips)
+	}
+
+	t.Run("missing previous header", func(t *testing.T) {
+		block := readRegtestBlock(t, 12)
+		defer block.Destroy()
+		header := block.Header()
+		defer header.Destroy()
+
+		err := suite.Manager.ProcessHeaders([]*BlockHeader{header})
+		var validationErr *BlockValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected *BlockValidationError, got %v", err)
+		}
+		if validationErr.Result != BlockMissingPrev || validationErr.RejectReason != "prev-blk-not-found" {
+			t.Errorf("Expected prev-blk-not-found, got %v %q", validationErr.Result, validationErr.RejectReason)
+		}
+	})
+
+	t.Run("insufficient proof of work", func(t *testing.T) {
+		block := readRegtestBlock(t, 11)
+		defer block.Destroy()
+		msg, err := block.ToWire()
+		if err != nil {
+			t.Fatalf("ToWire() error = %v", err)
+		}
+		// A header mined for the regtest target practically never meets the
+		// minimum difficulty of mainnet
+		msg.Header.Bits = 0x1d00ffff
+		raw, err := msg.Header.Bytes()
+		if err != nil {
+			t.Fatalf("Bytes() error = %v", err)
+		}
+		header, err := NewBlockHeader(raw)
+		if err != nil {
+			t.Fatalf("NewBlockHeader() error = %v", err)
+		}
+		defer header.Destroy()
+
+		err = suite.Manager.ProcessHeaders([]*BlockHeader{header})
+		var validationErr *BlockValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected *BlockValidationError, got %v", err)
+		}
+		if validationErr.Result != BlockInvalidHeader || validationErr.RejectReason != "high-hash" {
+			t.Errorf("Expected high-hash, got %v %q", validationErr.Result, validationErr.RejectReason)
+		}
+	})
+
+	// Blocks of accepted headers extend the active chain once their data arrives
+	for height := 6; height <= 10; height++ {
+		block := readRegtestBlock(t, height)
+		_, err := suite.Manager.ProcessBlock(block)
+		block.Destroy()
+		if err != nil {
+			t.Fatalf("ProcessBlock() error = %v", err)
+		}
+	}
+	if !chain.GetByHeight(10).Equals(bestHeader) {
+		t.Error("Expected active chain to reach the best header")
+	}
+}
+
+func TestCheckTransactionInputs(t *testing.T) {
+	suite := ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 110,
+	}
+	suite.Setup(t)
+
+	coinbaseAt := func(height int) *wire.MsgTx {
+		block := readRegtestBlock(t, height)
+		defer block.Destroy()
+		msg, err := block.ToWire()
+		if err != nil {
+			t.Fatalf("ToWire() error = %v", err)
+		}
+		return msg.Transactions[0]
+	}
+	// spend returns a transaction spending the first output of prev with the
+	// given fee; scripts are not verified, so the input needs no signature
+	spend := func(prev *wire.MsgTx, fee int64) *wire.MsgTx {
+		msg := wire.NewMsgTx(2)
+		msg.AddTxIn(wire.NewTxIn(wire.NewOutPoint(prev.TxHash(), 0), nil, nil))
+		msg.AddTxOut(wire.NewTxOut(prev.TxOut[0].Value-fee, prev.TxOut[0].PkScript))
+		return msg
+	}
+	matureCoinbase, immatureCoinbase := coinbaseAt(1), coinbaseAt(50)
+
+	tests := []struct {
+		name         string
+		tx           *wire.MsgTx
+		result       TxValidationResult
+		rejectReason string
+	}{
+		{"valid", spend(matureCoinbase, 1000), TxResultUnset, ""},
+		{"coinbase", matureCoinbase, TxConsensus, "coinbase"},
+		{"missing input", func() *wire.MsgTx {
+			// The second coinbase output is the unspendable witness commitment
+			msg := spend(matureCoinbase, 1000)
+			msg.TxIn[0].PreviousOutPoint.Index = 1
+			return msg
+		}(), TxMissingInputs, "bad-txns-inputs-missingorspent"},
+		{"immature coinbase", spend(immatureCoinbase, 1000), TxPrematureSpend, "bad-txns-premature-spend-of-coinbase"},
+		{"outputs above inputs", spend(matureCoinbase, -1), TxConsensus, "bad-txns-in-belowout"},
+		{"locktime", func() *wire.MsgTx {
+			msg := spend(matureCoinbase, 1000)
+			msg.LockTime = 200
+			msg.TxIn[0].Sequence = 0
+			return msg
+		}(), TxPrematureSpend, "non-final"},
+		{"relative locktime", func() *wire.MsgTx {
+			msg := spend(matureCoinbase, 1000)
+			msg.TxIn[0].Sequence = 200
+			return msg
+		}(), TxPrematureSpend, "non-BIP68-final"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := NewTransactionFromWire(tt.tx)
+			if err != nil {
+				t.Fatalf("NewTransactionFromWire() error = %v", err)
+			}
+			defer tx.Destroy()
+
+			err = suite.Manager.CheckTransactionInputs(tx)
+			if tt.rejectReason == "" {
+				if err != nil {
+					t.Errorf("CheckTransactionInputs() error = %v", err)
+				}
+				return
+			}
+			var validationErr *TransactionValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected *TransactionValidationError, got %v", err)
+			}
+			if validationErr.Result != tt.result || validationErr.RejectReason != tt.rejectReason {
+				t.Errorf("Expected result %d with reason %q, got result %d with reason %q",
+					tt.result, tt.rejectReason, validationErr.Result, validationErr.RejectReason)
+			}
+		})
+	}
+}
+
+// readRegtestBlock reads the block at the given height from data/regtest/blocks.txt.
+func readRegtestBlock(t *testing.T, height int) *Block {
+	t.Helper()
+	block, err := NewBlock(fixtures.RegtestBlock(t, height))
+	if err != nil {
+		t.Fatalf("NewBlock() error = %v", err)
+	}
+	return block
+}
+
 type ChainstateManagerTestSuite struct {
 	MaxBlockHeightToImport int32 // leave zero to load all blocks
 	NotificationCallbacks  *NotificationCallbacks
 	ValidationCallbacks    *ValidationInterfaceCallbacks
+	ManagerOptions         []ChainstateManagerOption // appended to the default options
 
 	Manager             *ChainstateManager
 	ImportedBlocksCount int32
+	DataDir             string
+	BlocksDir           string
 }
 
 func (s *ChainstateManagerTestSuite) Setup(t *testing.T) {
@@ -259,12 +818,13 @@ func (s *ChainstateManagerTestSuite) Setup(t *testing.T) {
 	}
 	t.Cleanup(func() { ctx.Destroy() })
 
-	manager, err := NewChainstateManager(ctx, dataDir, blocksDir,
+	managerOpts := []ChainstateManagerOption{
 		WithWorkerThreads(1),
 		WithBlockTreeDBInMemory(true),
 		WithChainstateDBInMemory(),
 		WithWipeDBs(true, true),
-	)
+	}
+	manager, err := NewChainstateManager(ctx, dataDir, blocksDir, append(managerOpts, s.ManagerOptions...)...)
 	if err != nil {
 		t.Fatalf("NewChainstateManager() error = %v", err)
 	}
@@ -277,49 +837,21 @@ func (s *ChainstateManagerTestSuite) Setup(t *testing.T) {
 	}
 
 	// Load block data from data/regtest/blocks.txt
-	wd, err := os.Getwd()
-	if err != nil {
-		t.Fatalf("Failed to get working directory: %v", err)
-	}
-	projectRoot := filepath.Dir(wd)
-	blocksFile := filepath.Join(projectRoot, "data", "regtest", "blocks.txt")
-
-	blocksData, err := os.ReadFile(blocksFile)
-	if err != nil {
-		t.Fatalf("Failed to read blocks file: %v", err)
-	}
-
-	var blockLines []string
-	for _, line := range strings.Split(string(blocksData), "\n") {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			blockLines = append(blockLines, line)
-		}
-		if s.MaxBlockHeightToImport != 0 && len(blockLines) >= int(s.MaxBlockHeightToImport) {
-			break
-		}
-	}
-	if len(blockLines) == 0 {
-		t.Fatal("No block data found in blocks.txt")
+	blocks := fixtures.RegtestBlocks(t)
+	if s.MaxBlockHeightToImport != 0 {
+		blocks = blocks[:min(int(s.MaxBlockHeightToImport), len(blocks))]
 	}
 
-	for i := 0; i < len(blockLines); i++ {
-		blockHex := blockLines[i]
-
-		blockBytes, err := hex.DecodeString(blockHex)
-		if err != nil {
-			t.Fatalf("Failed to decode block %d hex: %v", i+1, err)
-		}
-
+	for i, blockBytes := range blocks {
 		block, err := NewBlock(blockBytes)
 		if err != nil {
 			t.Fatalf("NewBlockFromRaw() failed for block %d: %v", i+1, err)
 		}
 		defer block.Destroy()
 
-		ok, newBlock := manager.ProcessBlock(block)
-		if !ok {
-			t.Fatalf("ProcessBlock() failed for block %d", i+1)
+		newBlock, err := manager.ProcessBlock(block)
+		if err != nil {
+			t.Fatalf("ProcessBlock() failed for block %d: %v", i+1, err)
 		}
 		if !newBlock {
 			t.Fatalf("ProcessBlock() returned newBlock=false for block %d (block data was already on disk)", i+1)
@@ -327,5 +859,7 @@ func (s *ChainstateManagerTestSuite) Setup(t *testing.T) {
 	}
 
 	s.Manager = manager
-	s.ImportedBlocksCount = int32(len(blockLines))
+	s.ImportedBlocksCount = int32(len(blocks))
+	s.DataDir = dataDir
+	s.BlocksDir = blocksDir
 }
@@ -5,6 +5,7 @@ package kernel
 */
 import "C"
 import (
+	"sync"
 	"unsafe"
 )
 
@@ -27,11 +28,64 @@ func (contextCFuncs) copy(ptr unsafe.Pointer) unsafe.Pointer {
 // A constructed context can be safely used from multiple threads.
 type Context struct {
 	*handle
+	listeners *contextListeners
 }
 
-func newContext(ptr *C.btck_Context, fromOwned bool) *Context {
+func newContext(ptr *C.btck_Context, fromOwned bool, listeners *contextListeners) *Context {
 	h := newHandle(unsafe.Pointer(ptr), contextCFuncs{}, fromOwned)
-	return &Context{handle: h}
+	return &Context{handle: h, listeners: listeners}
+}
+
+// contextListeners holds the listeners that receive events of a context in
+// addition to the callbacks set through its options, e.g. for the duration of
+// ImportBlocksContext and ProcessBlock. They are shared by the context and its
+// copies.
+type contextListeners struct {
+	progress     listenerSet[ImportProgress]
+	blockChecked listenerSet[blockCheckedEvent]
+}
+
+// listenerSet is a set of functions receiving events of type T.
+type listenerSet[T any] struct {
+	mu        sync.Mutex
+	listeners map[uint64]func(T)
+	nextID    uint64
+}
+
+// add registers fn to receive events and returns a function that unregisters it.
+func (s *listenerSet[T]) add(fn func(T)) (remove func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listeners == nil {
+		s.listeners = make(map[uint64]func(T))
+	}
+	id := s.nextID
+	s.nextID++
+	s.listeners[id] = fn
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.listeners, id)
+	}
+}
+
+// notify passes the event built by makeEvent to all registered listeners. The
+// event is only built if there are any.
+func (s *listenerSet[T]) notify(makeEvent func() T) {
+	s.mu.Lock()
+	listeners := make([]func(T), 0, len(s.listeners))
+	for _, fn := range s.listeners {
+		listeners = append(listeners, fn)
+	}
+	s.mu.Unlock()
+
+	if len(listeners) == 0 {
+		return
+	}
+	event := makeEvent()
+	for _, fn := range listeners {
+		fn(event)
+	}
 }
 
 // NewContext creates a new kernel context.
@@ -60,8 +114,22 @@ func NewContext(options ...ContextOption) (*Context, error) {
 	defer C.btck_context_options_destroy(optsPtr)
 
 	// Apply all functional options
+	opts := &contextOptions{ptr: optsPtr, listeners: &contextListeners{}}
 	for _, opt := range options {
-		if err := opt(optsPtr); err != nil {
+		if err := opt(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	// Always install notifications and validation interface callbacks so that progress
+	// and validation results can be observed, e.g. by ImportBlocksContext and ProcessBlock
+	if opts.notifications == nil {
+		if err := WithNotifications(&NotificationCallbacks{})(opts); err != nil {
+			return nil, err
+		}
+	}
+	if opts.validationCallbacks == nil {
+		if err := WithValidationInterface(&ValidationInterfaceCallbacks{})(opts); err != nil {
 			return nil, err
 		}
 	}
@@ -71,7 +139,7 @@ func NewContext(options ...ContextOption) (*Context, error) {
 	if ptr == nil {
 		return nil, &InternalError{"Failed to create context"}
 	}
-	return newContext(ptr, true), nil
+	return newContext(ptr, true, opts.listeners), nil
 }
 
 // Interrupt halts long-running validation functions like reindexing or block import.
@@ -90,5 +158,5 @@ func (ctx *Context) Interrupt() error {
 // The context is reference-counted internally, so this operation is efficient and does
 // not duplicate the underlying data.
 func (ctx *Context) Copy() *Context {
-	return newContext((*C.btck_Context)(ctx.ptr), false)
+	return newContext((*C.btck_Context)(ctx.ptr), false, ctx.listeners)
 }
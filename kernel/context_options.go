@@ -13,10 +13,14 @@ extern void go_notify_warning_set_bridge(void* user_data, btck_Warning warning,
 extern void go_notify_warning_unset_bridge(void* user_data, btck_Warning warning);
 extern void go_notify_flush_error_bridge(void* user_data, const char* message, size_t message_len);
 extern void go_notify_fatal_error_bridge(void* user_data, const char* message, size_t message_len);
+extern void go_notify_snapshot_activated_bridge(void* user_data, btck_BlockTreeEntry* base);
+extern void go_notify_snapshot_validated_bridge(void* user_data, btck_BlockTreeEntry* base);
 extern void go_validation_interface_block_checked_bridge(void* user_data, btck_Block* block, const btck_BlockValidationState* state);
 extern void go_validation_interface_pow_valid_block_bridge(void* user_data, const btck_BlockTreeEntry* entry, btck_Block* block);
 extern void go_validation_interface_block_connected_bridge(void* user_data, btck_Block* block, const btck_BlockTreeEntry* entry);
 extern void go_validation_interface_block_disconnected_bridge(void* user_data, btck_Block* block, const btck_BlockTreeEntry* entry);
+extern void go_validation_interface_transaction_added_to_mempool_bridge(void* user_data, const btck_MempoolEntry* entry, uint64_t mempool_sequence);
+extern void go_validation_interface_transaction_removed_from_mempool_bridge(void* user_data, btck_Transaction* transaction, btck_MempoolRemovalReason reason, uint64_t mempool_sequence);
 
 extern void go_delete_handle(void* user_data);
 */
@@ -27,7 +31,16 @@ import (
 )
 
 // ContextOption is a functional option for configuring context options.
-type ContextOption func(*C.btck_ContextOptions) error
+type ContextOption func(*contextOptions) error
+
+// contextOptions wraps the C context options together with the Go state that the
+// resulting Context needs to keep track of.
+type contextOptions struct {
+	ptr                 *C.btck_ContextOptions
+	listeners           *contextListeners
+	notifications       *NotificationCallbacks
+	validationCallbacks *ValidationInterfaceCallbacks
+}
 
 // WithChainType returns a ContextOption that sets the chain parameters for the context.
 // The context will be configured for these chain parameters.
@@ -35,13 +48,25 @@ type ContextOption func(*C.btck_ContextOptions) error
 // Parameters:
 //   - chainType: The type of chain (ChainTypeMainnet, ChainTypeTestnet, ChainTypeRegtest, etc.)
 func WithChainType(chainType ChainType) ContextOption {
-	return func(opts *C.btck_ContextOptions) error {
+	return func(opts *contextOptions) error {
 		chainParams, err := NewChainParameters(chainType)
 		if err != nil {
 			return err
 		}
 		defer chainParams.Destroy()
-		C.btck_context_options_set_chainparams(opts, (*C.btck_ChainParameters)(chainParams.ptr))
+		C.btck_context_options_set_chainparams(opts.ptr, (*C.btck_ChainParameters)(chainParams.ptr))
+		return nil
+	}
+}
+
+// WithChainParameters returns a ContextOption that sets custom chain parameters for
+// the context. The parameters are copied, so the caller may destroy them afterwards.
+//
+// Parameters:
+//   - chainParams: Chain parameters, e.g. created with NewChainParameters
+func WithChainParameters(chainParams *ChainParameters) ContextOption {
+	return func(opts *contextOptions) error {
+		C.btck_context_options_set_chainparams(opts.ptr, (*C.btck_ChainParameters)(chainParams.ptr))
 		return nil
 	}
 }
@@ -52,19 +77,22 @@ func WithChainType(chainType ChainType) ContextOption {
 // Parameters:
 //   - callbacks: Notification callbacks to set
 func WithNotifications(callbacks *NotificationCallbacks) ContextOption {
-	return func(opts *C.btck_ContextOptions) error {
+	return func(opts *contextOptions) error {
 		notificationCallbacks := C.btck_NotificationInterfaceCallbacks{
-			user_data:         unsafe.Pointer(cgo.NewHandle(callbacks)),
-			user_data_destroy: C.btck_DestroyCallback(C.go_delete_handle),
-			block_tip:         C.btck_NotifyBlockTip(C.go_notify_block_tip_bridge),
-			header_tip:        C.btck_NotifyHeaderTip(C.go_notify_header_tip_bridge),
-			progress:          C.btck_NotifyProgress(C.go_notify_progress_bridge),
-			warning_set:       C.btck_NotifyWarningSet(C.go_notify_warning_set_bridge),
-			warning_unset:     C.btck_NotifyWarningUnset(C.go_notify_warning_unset_bridge),
-			flush_error:       C.btck_NotifyFlushError(C.go_notify_flush_error_bridge),
-			fatal_error:       C.btck_NotifyFatalError(C.go_notify_fatal_error_bridge),
+			user_data:          unsafe.Pointer(cgo.NewHandle(&notificationsBridge{callbacks: callbacks, listeners: opts.listeners})),
+			user_data_destroy:  C.btck_DestroyCallback(C.go_delete_handle),
+			block_tip:          C.btck_NotifyBlockTip(C.go_notify_block_tip_bridge),
+			header_tip:         C.btck_NotifyHeaderTip(C.go_notify_header_tip_bridge),
+			progress:           C.btck_NotifyProgress(C.go_notify_progress_bridge),
+			warning_set:        C.btck_NotifyWarningSet(C.go_notify_warning_set_bridge),
+			warning_unset:      C.btck_NotifyWarningUnset(C.go_notify_warning_unset_bridge),
+			flush_error:        C.btck_NotifyFlushError(C.go_notify_flush_error_bridge),
+			fatal_error:        C.btck_NotifyFatalError(C.go_notify_fatal_error_bridge),
+			snapshot_activated: C.btck_NotifySnapshotActivated(C.go_notify_snapshot_activated_bridge),
+			snapshot_validated: C.btck_NotifySnapshotValidated(C.go_notify_snapshot_validated_bridge),
 		}
-		C.btck_context_options_set_notifications(opts, notificationCallbacks)
+		C.btck_context_options_set_notifications(opts.ptr, notificationCallbacks)
+		opts.notifications = callbacks
 		return nil
 	}
 }
@@ -76,16 +104,19 @@ func WithNotifications(callbacks *NotificationCallbacks) ContextOption {
 // Parameters:
 //   - callbacks: The callbacks used for passing validation information to the user
 func WithValidationInterface(callbacks *ValidationInterfaceCallbacks) ContextOption {
-	return func(opts *C.btck_ContextOptions) error {
+	return func(opts *contextOptions) error {
 		validationCallbacks := C.btck_ValidationInterfaceCallbacks{
-			user_data:          unsafe.Pointer(cgo.NewHandle(callbacks)),
-			user_data_destroy:  C.btck_DestroyCallback(C.go_delete_handle),
-			block_checked:      C.btck_ValidationInterfaceBlockChecked(C.go_validation_interface_block_checked_bridge),
-			pow_valid_block:    C.btck_ValidationInterfacePoWValidBlock(C.go_validation_interface_pow_valid_block_bridge),
-			block_connected:    C.btck_ValidationInterfaceBlockConnected(C.go_validation_interface_block_connected_bridge),
-			block_disconnected: C.btck_ValidationInterfaceBlockDisconnected(C.go_validation_interface_block_disconnected_bridge),
+			user_data:                        unsafe.Pointer(cgo.NewHandle(&validationInterfaceBridge{callbacks: callbacks, listeners: opts.listeners})),
+			user_data_destroy:                C.btck_DestroyCallback(C.go_delete_handle),
+			block_checked:                    C.btck_ValidationInterfaceBlockChecked(C.go_validation_interface_block_checked_bridge),
+			pow_valid_block:                  C.btck_ValidationInterfacePoWValidBlock(C.go_validation_interface_pow_valid_block_bridge),
+			block_connected:                  C.btck_ValidationInterfaceBlockConnected(C.go_validation_interface_block_connected_bridge),
+			block_disconnected:               C.btck_ValidationInterfaceBlockDisconnected(C.go_validation_interface_block_disconnected_bridge),
+			transaction_added_to_mempool:     C.btck_ValidationInterfaceTransactionAddedToMempool(C.go_validation_interface_transaction_added_to_mempool_bridge),
+			transaction_removed_from_mempool: C.btck_ValidationInterfaceTransactionRemovedFromMempool(C.go_validation_interface_transaction_removed_from_mempool_bridge),
 		}
-		C.btck_context_options_set_validation_interface(opts, validationCallbacks)
+		C.btck_context_options_set_validation_interface(opts.ptr, validationCallbacks)
+		opts.validationCallbacks = callbacks
 		return nil
 	}
 }
@@ -5,6 +5,14 @@ var (
 
 	ErrKernelIndexOutOfBounds = &kernelError{"Index out of bounds"}
 
+	// ErrBlockPruned is returned when reading block or undo data that has been
+	// deleted by pruning.
+	ErrBlockPruned = &kernelError{"Block data has been pruned"}
+
+	// ErrNotInMempool is returned when a transaction is looked up in the mempool
+	// but is not part of it.
+	ErrNotInMempool = &kernelError{"Transaction not in mempool"}
+
 	ErrVerifyScriptVerifyTxInputIndex            = &ScriptVerifyError{"Transaction input index out of range"}
 	ErrVerifyScriptVerifyInvalidFlags            = &ScriptVerifyError{"Invalid script verification flags"}
 	ErrVerifyScriptVerifyInvalidFlagsCombination = &ScriptVerifyError{"Invalid combination of script verification flags"}
@@ -66,3 +74,93 @@ func (e *ScriptVerifyError) Error() string {
 }
 
 func (e *ScriptVerifyError) isKernelError() {}
+
+// ScriptExecutionError is returned by ScriptPubkey.Verify when the script was
+// evaluated but failed to verify.
+type ScriptExecutionError struct {
+	Code ScriptErrorCode
+}
+
+func (e *ScriptExecutionError) Error() string {
+	return "Script execution failed: " + e.Code.String()
+}
+
+func (e *ScriptExecutionError) isKernelError() {}
+
+// BlockValidationError is returned by ChainstateManager.ProcessBlock when a block
+// fails validation.
+type BlockValidationError struct {
+	Mode         ValidationMode
+	Result       BlockValidationResult
+	RejectReason string // Short reject reason, e.g. "bad-txns-inputs-missingorspent"
+	DebugMessage string // Additional details on the rejection, may be empty
+}
+
+func newBlockValidationError(state *BlockValidationState) *BlockValidationError {
+	return &BlockValidationError{
+		Mode:         state.ValidationMode(),
+		Result:       state.ValidationResult(),
+		RejectReason: state.RejectReason(),
+		DebugMessage: state.DebugMessage(),
+	}
+}
+
+func (e *BlockValidationError) Error() string {
+	msg := "Block validation failed: " + e.RejectReason
+	if e.DebugMessage != "" {
+		msg += " (" + e.DebugMessage + ")"
+	}
+	return msg
+}
+
+func (e *BlockValidationError) isKernelError() {}
+
+// TransactionValidationError is returned when a transaction fails validation,
+// e.g. by Mempool.AcceptTransaction.
+type TransactionValidationError struct {
+	Mode         ValidationMode
+	Result       TxValidationResult
+	RejectReason string // Short reject reason, e.g. "txn-mempool-conflict"
+	DebugMessage string // Additional details on the rejection, may be empty
+}
+
+func newTransactionValidationError(state *TxValidationState) *TransactionValidationError {
+	return &TransactionValidationError{
+		Mode:         state.ValidationMode(),
+		Result:       state.ValidationResult(),
+		RejectReason: state.RejectReason(),
+		DebugMessage: state.DebugMessage(),
+	}
+}
+
+func (e *TransactionValidationError) Error() string {
+	msg := "Transaction validation failed: " + e.RejectReason
+	if e.DebugMessage != "" {
+		msg += " (" + e.DebugMessage + ")"
+	}
+	return msg
+}
+
+func (e *TransactionValidationError) isKernelError() {}
+
+// AddressError is returned when an address cannot be encoded or decoded.
+type AddressError struct {
+	Msg string
+}
+
+func (e *AddressError) Error() string {
+	return "Invalid address: " + e.Msg
+}
+
+func (e *AddressError) isKernelError() {}
+
+// SighashError is returned when a signature hash cannot be computed.
+type SighashError struct {
+	Msg string
+}
+
+func (e *SighashError) Error() string {
+	return "Signature hash computation failed: " + e.Msg
+}
+
+func (e *SighashError) isKernelError() {}
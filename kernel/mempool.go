@@ -0,0 +1,193 @@
+package kernel
+
+/*
+#include "bitcoinkernel.h"
+*/
+import "C"
+import (
+	"iter"
+	"runtime"
+	"time"
+	"unsafe"
+)
+
+type mempoolEntriesCFuncs struct{}
+
+func (mempoolEntriesCFuncs) destroy(ptr unsafe.Pointer) {
+	C.btck_mempool_entries_destroy((*C.btck_MempoolEntries)(ptr))
+}
+
+// DefaultMempoolMaxSize is the default maximum memory usage of the mempool in bytes.
+const DefaultMempoolMaxSize = 300_000_000
+
+// MempoolRemovalReason describes why a transaction was removed from the mempool.
+type MempoolRemovalReason C.btck_MempoolRemovalReason
+
+const (
+	MempoolRemovalExpiry    MempoolRemovalReason = C.btck_MempoolRemovalReason_EXPIRY    // Expired from the mempool
+	MempoolRemovalSizeLimit MempoolRemovalReason = C.btck_MempoolRemovalReason_SIZELIMIT // Removed to keep the mempool below its maximum size
+	MempoolRemovalReorg     MempoolRemovalReason = C.btck_MempoolRemovalReason_REORG     // No longer valid after a reorganization
+	MempoolRemovalBlock     MempoolRemovalReason = C.btck_MempoolRemovalReason_BLOCK     // Included in a connected block
+	MempoolRemovalConflict  MempoolRemovalReason = C.btck_MempoolRemovalReason_CONFLICT  // Conflicts with a transaction of a connected block
+	MempoolRemovalReplaced  MempoolRemovalReason = C.btck_MempoolRemovalReason_REPLACED  // Replaced by a transaction paying a higher fee
+	MempoolRemovalManual    MempoolRemovalReason = C.btck_MempoolRemovalReason_MANUAL    // Removed through Mempool.Remove
+)
+
+// String returns the name Bitcoin Core uses for the removal reason, e.g. "sizelimit".
+func (r MempoolRemovalReason) String() string {
+	switch r {
+	case MempoolRemovalExpiry:
+		return "expiry"
+	case MempoolRemovalSizeLimit:
+		return "sizelimit"
+	case MempoolRemovalReorg:
+		return "reorg"
+	case MempoolRemovalBlock:
+		return "block"
+	case MempoolRemovalConflict:
+		return "conflict"
+	case MempoolRemovalReplaced:
+		return "replaced"
+	case MempoolRemovalManual:
+		return "manual"
+	default:
+		return "unknown"
+	}
+}
+
+// MempoolEntry describes a transaction in the mempool.
+type MempoolEntry struct {
+	Transaction *Transaction
+	Fee         int64     // Fee paid by the transaction in satoshis
+	VSize       int32     // Virtual size of the transaction in vbytes
+	Time        time.Time // Time the transaction entered the mempool
+}
+
+func newMempoolEntry(ptr *C.btck_MempoolEntry) *MempoolEntry {
+	return &MempoolEntry{
+		Transaction: newTransactionView(C.btck_mempool_entry_get_transaction(ptr)).Copy(),
+		Fee:         int64(C.btck_mempool_entry_get_fee(ptr)),
+		VSize:       int32(C.btck_mempool_entry_get_vsize(ptr)),
+		Time:        time.Unix(int64(C.btck_mempool_entry_get_time(ptr)), 0),
+	}
+}
+
+func newMempoolEntries(ptr *C.btck_MempoolEntries) []*MempoolEntry {
+	h := newUniqueHandle(unsafe.Pointer(check(ptr)), mempoolEntriesCFuncs{})
+	defer h.Destroy()
+	cEntries := (*C.btck_MempoolEntries)(h.ptr)
+
+	entries := make([]*MempoolEntry, C.btck_mempool_entries_count(cEntries))
+	for i := range entries {
+		entries[i] = newMempoolEntry(C.btck_mempool_entries_get_at(cEntries, C.size_t(i)))
+	}
+	return entries
+}
+
+// Mempool holds the unconfirmed transactions that are valid on top of the active
+// chain of a chainstate manager. It is enabled through WithMempool.
+//
+// Transactions added to and removed from the mempool are reported through the
+// OnTransactionAddedToMempool and OnTransactionRemovedFromMempool validation
+// interface callbacks.
+type Mempool struct {
+	ptr     *C.btck_Mempool
+	manager *ChainstateManager
+}
+
+// Mempool returns the mempool of the chainstate manager, or nil if it was not
+// enabled through WithMempool. It is valid for the lifetime of the chainstate manager.
+func (cm *ChainstateManager) Mempool() *Mempool {
+	ptr := C.btck_chainstate_manager_get_mempool((*C.btck_ChainstateManager)(cm.ptr))
+	if ptr == nil {
+		return nil
+	}
+	return &Mempool{ptr: ptr, manager: cm}
+}
+
+// AcceptTransaction validates the transaction against the consensus and policy
+// rules on top of the active chain and the mempool, and adds it to the mempool.
+// Mempool transactions it replaces are removed.
+//
+// Returns nil if the transaction was added or is already in the mempool, also
+// when the mempool holds it with a different witness, and a
+// *TransactionValidationError if it was rejected.
+func (m *Mempool) AcceptTransaction(tx *Transaction) error {
+	statePtr := C.btck_tx_validation_state_create()
+	defer C.btck_tx_validation_state_destroy(statePtr)
+
+	result := C.btck_chainstate_manager_process_transaction((*C.btck_ChainstateManager)(m.manager.ptr), (*C.btck_Transaction)(tx.handle.ptr), statePtr)
+	runtime.KeepAlive(tx)
+	if result != 0 {
+		return newTransactionValidationError(&TxValidationState{ptr: statePtr})
+	}
+	return nil
+}
+
+// Remove removes the transaction with the given txid and all its in-mempool
+// descendants from the mempool.
+//
+// Returns ErrNotInMempool if the transaction is not in the mempool.
+func (m *Mempool) Remove(txid TxidLike) error {
+	if C.btck_mempool_remove_transaction(m.ptr, txid.txidPtr()) != 0 {
+		return ErrNotInMempool
+	}
+	return nil
+}
+
+// Contains returns whether the transaction with the given txid is in the mempool.
+func (m *Mempool) Contains(txid TxidLike) bool {
+	return C.btck_mempool_contains(m.ptr, txid.txidPtr()) != 0
+}
+
+// Size returns the number of transactions in the mempool.
+func (m *Mempool) Size() int {
+	return int(C.btck_mempool_size(m.ptr))
+}
+
+// Get returns the entry of the transaction with the given txid, or nil if it is
+// not in the mempool.
+func (m *Mempool) Get(txid TxidLike) *MempoolEntry {
+	ptr := C.btck_mempool_get_entry(m.ptr, txid.txidPtr())
+	if ptr == nil {
+		return nil
+	}
+	defer C.btck_mempool_entry_destroy(ptr)
+	return newMempoolEntry(ptr)
+}
+
+// Entries returns an iterator over a snapshot of the mempool, yielding parents
+// before their children.
+func (m *Mempool) Entries() iter.Seq[*MempoolEntry] {
+	return func(yield func(*MempoolEntry) bool) {
+		for _, entry := range newMempoolEntries(C.btck_mempool_get_entries(m.ptr)) {
+			if !yield(entry) {
+				return
+			}
+		}
+	}
+}
+
+// Ancestors returns the in-mempool ancestors of the transaction with the given
+// txid, not including the transaction itself, ordered with parents before their children.
+//
+// Returns ErrNotInMempool if the transaction is not in the mempool.
+func (m *Mempool) Ancestors(txid TxidLike) ([]*MempoolEntry, error) {
+	ptr := C.btck_mempool_get_ancestors(m.ptr, txid.txidPtr())
+	if ptr == nil {
+		return nil, ErrNotInMempool
+	}
+	return newMempoolEntries(ptr), nil
+}
+
+// Descendants returns the in-mempool descendants of the transaction with the
+// given txid, not including the transaction itself, ordered with parents before their children.
+//
+// Returns ErrNotInMempool if the transaction is not in the mempool.
+func (m *Mempool) Descendants(txid TxidLike) ([]*MempoolEntry, error) {
+	ptr := C.btck_mempool_get_descendants(m.ptr, txid.txidPtr())
+	if ptr == nil {
+		return nil, ErrNotInMempool
+	}
+	return newMempoolEntries(ptr), nil
+}
@@ -0,0 +1,206 @@
+package kernel_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+	"github.com/stringintech/go-bitcoinkernel/regtest"
+	"github.com/stringintech/go-bitcoinkernel/wire"
+)
+
+// anyoneCanSpendScript is a P2WSH output script of the witness script OP_TRUE,
+// which is spent by providing the witness script only.
+var anyoneCanSpendScript = func() []byte {
+	scriptHash := sha256.Sum256([]byte{0x51})
+	return append([]byte{0x00, 0x20}, scriptHash[:]...)
+}()
+
+// newAnyoneCanSpendTx returns a transaction spending output 0 of prev, which
+// must pay to anyoneCanSpendScript, and paying value minus fee back to it.
+func newAnyoneCanSpendTx(t *testing.T, prev *wire.MsgTx, fee int64) (*wire.MsgTx, *kernel.Transaction) {
+	t.Helper()
+	msg := wire.NewMsgTx(2)
+	msg.AddTxIn(wire.NewTxIn(wire.NewOutPoint(prev.TxHash(), 0), nil, [][]byte{{0x51}}))
+	msg.AddTxOut(wire.NewTxOut(prev.TxOut[0].Value-fee, anyoneCanSpendScript))
+	raw, err := msg.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	tx, err := kernel.NewTransaction(raw)
+	if err != nil {
+		t.Fatalf("NewTransaction() error = %v", err)
+	}
+	return msg, tx
+}
+
+type removedTx struct {
+	txid   [32]byte
+	reason kernel.MempoolRemovalReason
+}
+
+func TestMempool(t *testing.T) {
+	var added [][32]byte
+	var removed []removedTx
+	suite := kernel.ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 1,
+		ValidationCallbacks: &kernel.ValidationInterfaceCallbacks{
+			OnTransactionAddedToMempool: func(entry *kernel.MempoolEntry, sequence uint64) {
+				added = append(added, entry.Transaction.GetTxid().Bytes())
+			},
+			OnTransactionRemovedFromMempool: func(tx *kernel.Transaction, reason kernel.MempoolRemovalReason, sequence uint64) {
+				removed = append(removed, removedTx{tx.GetTxid().Bytes(), reason})
+			},
+		},
+		ManagerOptions: []kernel.ChainstateManagerOption{kernel.WithMempool(kernel.DefaultMempoolMaxSize)},
+	}
+	suite.Setup(t)
+	mempool := suite.Manager.Mempool()
+	if mempool == nil {
+		t.Fatal("Expected mempool to be enabled")
+	}
+
+	// Mine a chain whose coinbases pay to an anyone-can-spend output and let the
+	// first of them mature
+	gen := regtest.NewGenerator(suite.Manager, regtest.WithCoinbaseScript(anyoneCanSpendScript))
+	blocks, err := gen.Generate(regtest.CoinbaseMaturity)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	first, err := blocks[0].ToWire()
+	if err != nil {
+		t.Fatalf("ToWire() error = %v", err)
+	}
+	coinbase := first.Transactions[0]
+
+	parentMsg, parent := newAnyoneCanSpendTx(t, coinbase, 10_000)
+	defer parent.Destroy()
+	_, child := newAnyoneCanSpendTx(t, parentMsg, 10_000)
+	defer child.Destroy()
+	for _, tx := range []*kernel.Transaction{parent, child, parent} {
+		if err := mempool.AcceptTransaction(tx); err != nil {
+			t.Fatalf("AcceptTransaction() error = %v", err)
+		}
+	}
+	if mempool.Size() != 2 || !mempool.Contains(parent.GetTxid()) || !mempool.Contains(child.GetTxid()) {
+		t.Fatalf("Expected parent and child in mempool of size 2, got size %d", mempool.Size())
+	}
+
+	entry := mempool.Get(parent.GetTxid())
+	if entry == nil {
+		t.Fatal("Expected mempool entry for parent")
+	}
+	if entry.Fee != 10_000 || int64(entry.VSize) != parent.VSize() || entry.Transaction.GetTxid().Bytes() != parent.GetTxid().Bytes() {
+		t.Errorf("Expected entry with fee 10000 and vsize %d, got fee %d and vsize %d", parent.VSize(), entry.Fee, entry.VSize)
+	}
+
+	txids := func(entries []*kernel.MempoolEntry) [][32]byte {
+		var ids [][32]byte
+		for _, e := range entries {
+			ids = append(ids, e.Transaction.GetTxid().Bytes())
+		}
+		return ids
+	}
+	parentTxid, childTxid := parent.GetTxid().Bytes(), child.GetTxid().Bytes()
+	if got := txids(slices.Collect(mempool.Entries())); !slices.Equal(got, [][32]byte{parentTxid, childTxid}) {
+		t.Errorf("Expected entries parent, child, got %x", got)
+	}
+	if ancestors, err := mempool.Ancestors(child.GetTxid()); err != nil || !slices.Equal(txids(ancestors), [][32]byte{parentTxid}) {
+		t.Errorf("Expected parent as only ancestor of child, got %x (err %v)", txids(ancestors), err)
+	}
+	if descendants, err := mempool.Descendants(parent.GetTxid()); err != nil || !slices.Equal(txids(descendants), [][32]byte{childTxid}) {
+		t.Errorf("Expected child as only descendant of parent, got %x (err %v)", txids(descendants), err)
+	}
+	if ancestors, err := mempool.Ancestors(parent.GetTxid()); err != nil || len(ancestors) != 0 {
+		t.Errorf("Expected no ancestors of parent, got %d (err %v)", len(ancestors), err)
+	}
+
+	// Spending an unknown output is rejected
+	orphanMsg := parentMsg.Copy()
+	orphanMsg.TxIn[0].PreviousOutPoint.Index = 1
+	_, orphan := newAnyoneCanSpendTx(t, orphanMsg, 0)
+	defer orphan.Destroy()
+	var validationErr *kernel.TransactionValidationError
+	if err := mempool.AcceptTransaction(orphan); !errors.As(err, &validationErr) || validationErr.Result != kernel.TxMissingInputs {
+		t.Errorf("Expected *TransactionValidationError with missing inputs, got %v", err)
+	}
+
+	// A conflicting transaction paying a higher fee replaces parent and child
+	_, replacement := newAnyoneCanSpendTx(t, coinbase, 50_000)
+	defer replacement.Destroy()
+	if err := mempool.AcceptTransaction(replacement); err != nil {
+		t.Fatalf("AcceptTransaction() replacement error = %v", err)
+	}
+	if mempool.Size() != 1 || !mempool.Contains(replacement.GetTxid()) || mempool.Contains(parent.GetTxid()) {
+		t.Errorf("Expected only replacement in mempool, got size %d", mempool.Size())
+	}
+	if mempool.Get(child.GetTxid()) != nil {
+		t.Error("Expected no mempool entry for replaced child")
+	}
+	if _, err := mempool.Descendants(child.GetTxid()); !errors.Is(err, kernel.ErrNotInMempool) {
+		t.Errorf("Expected ErrNotInMempool, got %v", err)
+	}
+
+	// Block templates include the mempool transactions unless disabled
+	coinbaseScript := kernel.NewScriptPubkey(anyoneCanSpendScript)
+	defer coinbaseScript.Destroy()
+	for _, tc := range []struct {
+		options []kernel.BlockTemplateOption
+		txs     int
+		fees    int64
+	}{
+		{nil, 2, 50_000},
+		{[]kernel.BlockTemplateOption{kernel.WithoutMempoolTransactions()}, 1, 0},
+		{[]kernel.BlockTemplateOption{kernel.WithMinFeeRate(1_000_000)}, 1, 0},
+	} {
+		template, err := suite.Manager.CreateBlockTemplate(coinbaseScript, tc.options...)
+		if err != nil {
+			t.Fatalf("CreateBlockTemplate() error = %v", err)
+		}
+		msg, err := template.Block.ToWire()
+		if err != nil {
+			t.Fatalf("ToWire() error = %v", err)
+		}
+		if len(msg.Transactions) != tc.txs || template.Fees != tc.fees {
+			t.Errorf("Expected %d transactions with fees %d, got %d with fees %d", tc.txs, tc.fees, len(msg.Transactions), template.Fees)
+		}
+		template.Block.Destroy()
+	}
+
+	replacementTxid := replacement.GetTxid().Bytes()
+	if err := mempool.Remove(replacement.GetTxid()); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := mempool.Remove(replacement.GetTxid()); !errors.Is(err, kernel.ErrNotInMempool) {
+		t.Errorf("Expected ErrNotInMempool, got %v", err)
+	}
+	if mempool.Size() != 0 {
+		t.Errorf("Expected empty mempool, got size %d", mempool.Size())
+	}
+
+	if !slices.Equal(added, [][32]byte{parentTxid, childTxid, replacementTxid}) {
+		t.Errorf("Expected added events for parent, child and replacement, got %x", added)
+	}
+	if len(removed) != 3 {
+		t.Fatalf("Expected 3 removed events, got %d", len(removed))
+	}
+	slices.SortFunc(removed[:2], func(a, b removedTx) int { return slices.Compare(a.txid[:], b.txid[:]) })
+	wantRemoved := []removedTx{{parentTxid, kernel.MempoolRemovalReplaced}, {childTxid, kernel.MempoolRemovalReplaced}}
+	slices.SortFunc(wantRemoved, func(a, b removedTx) int { return slices.Compare(a.txid[:], b.txid[:]) })
+	wantRemoved = append(wantRemoved, removedTx{replacementTxid, kernel.MempoolRemovalManual})
+	if !slices.Equal(removed, wantRemoved) {
+		t.Errorf("Expected removed events %v, got %v", wantRemoved, removed)
+	}
+}
+
+func TestMempoolDisabled(t *testing.T) {
+	suite := kernel.ChainstateManagerTestSuite{
+		MaxBlockHeightToImport: 1,
+	}
+	suite.Setup(t)
+	if suite.Manager.Mempool() != nil {
+		t.Error("Expected no mempool without WithMempool")
+	}
+}
@@ -18,6 +18,21 @@ type NotificationCallbacks struct {
 	OnWarningUnset func(warning Warning)
 	OnFlushError   func(message string)
 	OnFatalError   func(message string)
+
+	// OnSnapshotActivated is called once a chainstate loaded with LoadSnapshot
+	// became the active chainstate, with the base block of the snapshot.
+	OnSnapshotActivated func(base *BlockTreeEntry)
+	// OnSnapshotValidated is called once background validation reached the base
+	// block of the snapshot and confirmed its UTXO set. A snapshot failing
+	// validation is reported through OnFatalError instead.
+	OnSnapshotValidated func(base *BlockTreeEntry)
+}
+
+// notificationsBridge is passed to the notification bridges, which dispatch each
+// notification to the user-supplied callbacks and the listeners of the context.
+type notificationsBridge struct {
+	callbacks *NotificationCallbacks
+	listeners *contextListeners
 }
 
 // SynchronizationState represents the current sync state passed to tip changed callbacks.
@@ -39,20 +54,29 @@ const (
 
 //export go_notify_block_tip_bridge
 func go_notify_block_tip_bridge(user_data unsafe.Pointer, state C.btck_SynchronizationState, entry *C.btck_BlockTreeEntry, verification_progress C.double) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*NotificationCallbacks)
+	bridge := cgo.Handle(user_data).Value().(*notificationsBridge)
+	callbacks := bridge.callbacks
 
+	goState := SynchronizationState(state)
+	goEntry := &BlockTreeEntry{ptr: (*C.btck_BlockTreeEntry)(unsafe.Pointer(entry))}
 	if callbacks.OnBlockTip != nil {
-		goState := SynchronizationState(state)
-		goEntry := &BlockTreeEntry{ptr: (*C.btck_BlockTreeEntry)(unsafe.Pointer(entry))}
 		callbacks.OnBlockTip(goState, goEntry, float64(verification_progress))
 	}
+	bridge.listeners.progress.notify(func() ImportProgress {
+		return ImportProgress{
+			Kind:                 ImportProgressBlockTip,
+			SyncState:            goState,
+			Height:               goEntry.Height(),
+			BlockHash:            goEntry.Hash().Bytes(),
+			VerificationProgress: float64(verification_progress),
+		}
+	})
 }
 
 //export go_notify_header_tip_bridge
 func go_notify_header_tip_bridge(user_data unsafe.Pointer, state C.btck_SynchronizationState, height C.int64_t, timestamp C.int64_t, presync C.int) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*NotificationCallbacks)
+	bridge := cgo.Handle(user_data).Value().(*notificationsBridge)
+	callbacks := bridge.callbacks
 
 	if callbacks.OnHeaderTip != nil {
 		goState := SynchronizationState(state)
@@ -62,19 +86,27 @@ func go_notify_header_tip_bridge(user_data unsafe.Pointer, state C.btck_Synchron
 
 //export go_notify_progress_bridge
 func go_notify_progress_bridge(user_data unsafe.Pointer, title *C.char, title_len C.size_t, progress_percent C.int, resume_possible C.int) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*NotificationCallbacks)
+	bridge := cgo.Handle(user_data).Value().(*notificationsBridge)
+	callbacks := bridge.callbacks
 
+	goTitle := C.GoStringN(title, C.int(title_len))
 	if callbacks.OnProgress != nil {
-		goTitle := C.GoStringN(title, C.int(title_len))
 		callbacks.OnProgress(goTitle, int(progress_percent), resume_possible != 0)
 	}
+	bridge.listeners.progress.notify(func() ImportProgress {
+		return ImportProgress{
+			Kind:      ImportProgressTask,
+			Title:     goTitle,
+			Percent:   int(progress_percent),
+			Resumable: resume_possible != 0,
+		}
+	})
 }
 
 //export go_notify_warning_set_bridge
 func go_notify_warning_set_bridge(user_data unsafe.Pointer, warning C.btck_Warning, message *C.char, message_len C.size_t) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*NotificationCallbacks)
+	bridge := cgo.Handle(user_data).Value().(*notificationsBridge)
+	callbacks := bridge.callbacks
 
 	if callbacks.OnWarningSet != nil {
 		goWarning := Warning(warning)
@@ -85,8 +117,8 @@ func go_notify_warning_set_bridge(user_data unsafe.Pointer, warning C.btck_Warni
 
 //export go_notify_warning_unset_bridge
 func go_notify_warning_unset_bridge(user_data unsafe.Pointer, warning C.btck_Warning) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*NotificationCallbacks)
+	bridge := cgo.Handle(user_data).Value().(*notificationsBridge)
+	callbacks := bridge.callbacks
 
 	if callbacks.OnWarningUnset != nil {
 		goWarning := Warning(warning)
@@ -96,8 +128,8 @@ func go_notify_warning_unset_bridge(user_data unsafe.Pointer, warning C.btck_War
 
 //export go_notify_flush_error_bridge
 func go_notify_flush_error_bridge(user_data unsafe.Pointer, message *C.char, message_len C.size_t) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*NotificationCallbacks)
+	bridge := cgo.Handle(user_data).Value().(*notificationsBridge)
+	callbacks := bridge.callbacks
 
 	if callbacks.OnFlushError != nil {
 		goMessage := C.GoStringN(message, C.int(message_len))
@@ -107,11 +139,31 @@ func go_notify_flush_error_bridge(user_data unsafe.Pointer, message *C.char, mes
 
 //export go_notify_fatal_error_bridge
 func go_notify_fatal_error_bridge(user_data unsafe.Pointer, message *C.char, message_len C.size_t) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*NotificationCallbacks)
+	bridge := cgo.Handle(user_data).Value().(*notificationsBridge)
+	callbacks := bridge.callbacks
 
 	if callbacks.OnFatalError != nil {
 		goMessage := C.GoStringN(message, C.int(message_len))
 		callbacks.OnFatalError(goMessage)
 	}
 }
+
+//export go_notify_snapshot_activated_bridge
+func go_notify_snapshot_activated_bridge(user_data unsafe.Pointer, base *C.btck_BlockTreeEntry) {
+	bridge := cgo.Handle(user_data).Value().(*notificationsBridge)
+	callbacks := bridge.callbacks
+
+	if callbacks.OnSnapshotActivated != nil {
+		callbacks.OnSnapshotActivated(&BlockTreeEntry{ptr: base})
+	}
+}
+
+//export go_notify_snapshot_validated_bridge
+func go_notify_snapshot_validated_bridge(user_data unsafe.Pointer, base *C.btck_BlockTreeEntry) {
+	bridge := cgo.Handle(user_data).Value().(*notificationsBridge)
+	callbacks := bridge.callbacks
+
+	if callbacks.OnSnapshotValidated != nil {
+		callbacks.OnSnapshotValidated(&BlockTreeEntry{ptr: base})
+	}
+}
@@ -40,3 +40,36 @@ func TestNotificationCallbacks(t *testing.T) {
 		t.Errorf("Expected last header height 5, got %d", lastHeaderHeight)
 	}
 }
+
+func TestNotificationCallbacksSharedByContexts(t *testing.T) {
+	var tips []int32
+	callbacks := &NotificationCallbacks{
+		OnBlockTip: func(_ SynchronizationState, entry *BlockTreeEntry, _ float64) {
+			tips = append(tips, entry.Height())
+		},
+	}
+	first := ChainstateManagerTestSuite{MaxBlockHeightToImport: 1, NotificationCallbacks: callbacks}
+	first.Setup(t)
+	second := ChainstateManagerTestSuite{MaxBlockHeightToImport: 1, NotificationCallbacks: callbacks}
+	second.Setup(t)
+
+	// Listeners belong to a context, even if its callbacks are shared
+	var events []ImportProgress
+	remove := first.Manager.context.listeners.progress.add(func(event ImportProgress) {
+		events = append(events, event)
+	})
+	defer remove()
+
+	block := readRegtestBlock(t, 2)
+	defer block.Destroy()
+	tips = nil
+	if _, err := second.Manager.ProcessBlock(block); err != nil {
+		t.Fatalf("ProcessBlock() error = %v", err)
+	}
+	if len(tips) != 1 || tips[0] != 2 {
+		t.Errorf("Expected OnBlockTip for height 2, got %v", tips)
+	}
+	if len(events) != 0 {
+		t.Errorf("Expected no events for a listener of another context, got %d", len(events))
+	}
+}
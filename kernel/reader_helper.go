@@ -0,0 +1,56 @@
+package kernel
+
+/*
+#include "bitcoinkernel.h"
+#include <stdlib.h>
+
+extern int go_io_reader_callback_bridge(void* buffer, size_t size, size_t* read, void* userdata);
+*/
+import "C"
+import (
+	"errors"
+	"io"
+	"runtime/cgo"
+	"unsafe"
+)
+
+// ioReaderCallbackData holds the reader that bytes are read from and the first
+// error it returned other than io.EOF
+type ioReaderCallbackData struct {
+	r   io.Reader
+	err error
+}
+
+//export go_io_reader_callback_bridge
+func go_io_reader_callback_bridge(buffer unsafe.Pointer, size C.size_t, read *C.size_t, userdata unsafe.Pointer) C.int {
+	data := cgo.Handle(userdata).Value().(*ioReaderCallbackData)
+	*read = 0
+	if size == 0 {
+		return 0
+	}
+	// The reader must not retain the slice, it is a view of C memory
+	cBuffer := unsafe.Slice((*byte)(buffer), int(size))
+	for {
+		n, err := data.r.Read(cBuffer)
+		*read = C.size_t(n)
+		if err != nil && !errors.Is(err, io.EOF) {
+			data.err = err
+			return -1
+		}
+		// A read of zero bytes only signals the end of the data at io.EOF
+		if n > 0 || err != nil {
+			return 0
+		}
+	}
+}
+
+// readFromReader passes a reader callback reading from r to readerFunc. It returns
+// the error of r if reading was aborted because of it.
+func readFromReader(r io.Reader, readerFunc func(C.btck_ReadBytes, unsafe.Pointer) C.int) (ok bool, err error) {
+	callbackData := &ioReaderCallbackData{r: r}
+	handle := cgo.NewHandle(callbackData)
+	defer handle.Delete()
+
+	result := readerFunc((C.btck_ReadBytes)(C.go_io_reader_callback_bridge), unsafe.Pointer(handle))
+	return result == 0, callbackData.err
+}
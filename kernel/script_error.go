@@ -0,0 +1,182 @@
+package kernel
+
+/*
+#include "bitcoinkernel.h"
+*/
+import "C"
+
+// ScriptErrorCode identifies the reason a script failed to verify. The codes mirror
+// the errors of Bitcoin Core's script interpreter.
+type ScriptErrorCode C.btck_ScriptError
+
+const (
+	ScriptErrorOK                                 ScriptErrorCode = C.btck_ScriptError_OK
+	ScriptErrorUnknownError                       ScriptErrorCode = C.btck_ScriptError_UNKNOWN_ERROR
+	ScriptErrorEvalFalse                          ScriptErrorCode = C.btck_ScriptError_EVAL_FALSE
+	ScriptErrorOpReturn                           ScriptErrorCode = C.btck_ScriptError_OP_RETURN
+	ScriptErrorScriptSize                         ScriptErrorCode = C.btck_ScriptError_SCRIPT_SIZE
+	ScriptErrorPushSize                           ScriptErrorCode = C.btck_ScriptError_PUSH_SIZE
+	ScriptErrorOpCount                            ScriptErrorCode = C.btck_ScriptError_OP_COUNT
+	ScriptErrorStackSize                          ScriptErrorCode = C.btck_ScriptError_STACK_SIZE
+	ScriptErrorSigCount                           ScriptErrorCode = C.btck_ScriptError_SIG_COUNT
+	ScriptErrorPubkeyCount                        ScriptErrorCode = C.btck_ScriptError_PUBKEY_COUNT
+	ScriptErrorVerify                             ScriptErrorCode = C.btck_ScriptError_VERIFY
+	ScriptErrorEqualVerify                        ScriptErrorCode = C.btck_ScriptError_EQUALVERIFY
+	ScriptErrorCheckMultisigVerify                ScriptErrorCode = C.btck_ScriptError_CHECKMULTISIGVERIFY
+	ScriptErrorCheckSigVerify                     ScriptErrorCode = C.btck_ScriptError_CHECKSIGVERIFY
+	ScriptErrorNumEqualVerify                     ScriptErrorCode = C.btck_ScriptError_NUMEQUALVERIFY
+	ScriptErrorBadOpcode                          ScriptErrorCode = C.btck_ScriptError_BAD_OPCODE
+	ScriptErrorDisabledOpcode                     ScriptErrorCode = C.btck_ScriptError_DISABLED_OPCODE
+	ScriptErrorInvalidStackOperation              ScriptErrorCode = C.btck_ScriptError_INVALID_STACK_OPERATION
+	ScriptErrorInvalidAltstackOperation           ScriptErrorCode = C.btck_ScriptError_INVALID_ALTSTACK_OPERATION
+	ScriptErrorUnbalancedConditional              ScriptErrorCode = C.btck_ScriptError_UNBALANCED_CONDITIONAL
+	ScriptErrorNegativeLocktime                   ScriptErrorCode = C.btck_ScriptError_NEGATIVE_LOCKTIME
+	ScriptErrorUnsatisfiedLocktime                ScriptErrorCode = C.btck_ScriptError_UNSATISFIED_LOCKTIME
+	ScriptErrorSigHashType                        ScriptErrorCode = C.btck_ScriptError_SIG_HASHTYPE
+	ScriptErrorSigDER                             ScriptErrorCode = C.btck_ScriptError_SIG_DER
+	ScriptErrorMinimalData                        ScriptErrorCode = C.btck_ScriptError_MINIMALDATA
+	ScriptErrorSigPushOnly                        ScriptErrorCode = C.btck_ScriptError_SIG_PUSHONLY
+	ScriptErrorSigHighS                           ScriptErrorCode = C.btck_ScriptError_SIG_HIGH_S
+	ScriptErrorSigNullDummy                       ScriptErrorCode = C.btck_ScriptError_SIG_NULLDUMMY
+	ScriptErrorPubkeyType                         ScriptErrorCode = C.btck_ScriptError_PUBKEYTYPE
+	ScriptErrorCleanStack                         ScriptErrorCode = C.btck_ScriptError_CLEANSTACK
+	ScriptErrorMinimalIf                          ScriptErrorCode = C.btck_ScriptError_MINIMALIF
+	ScriptErrorSigNullFail                        ScriptErrorCode = C.btck_ScriptError_SIG_NULLFAIL
+	ScriptErrorDiscourageUpgradableNOPs           ScriptErrorCode = C.btck_ScriptError_DISCOURAGE_UPGRADABLE_NOPS
+	ScriptErrorDiscourageUpgradableWitnessProgram ScriptErrorCode = C.btck_ScriptError_DISCOURAGE_UPGRADABLE_WITNESS_PROGRAM
+	ScriptErrorDiscourageUpgradableTaprootVersion ScriptErrorCode = C.btck_ScriptError_DISCOURAGE_UPGRADABLE_TAPROOT_VERSION
+	ScriptErrorDiscourageOpSuccess                ScriptErrorCode = C.btck_ScriptError_DISCOURAGE_OP_SUCCESS
+	ScriptErrorDiscourageUpgradablePubkeyType     ScriptErrorCode = C.btck_ScriptError_DISCOURAGE_UPGRADABLE_PUBKEYTYPE
+	ScriptErrorWitnessProgramWrongLength          ScriptErrorCode = C.btck_ScriptError_WITNESS_PROGRAM_WRONG_LENGTH
+	ScriptErrorWitnessProgramWitnessEmpty         ScriptErrorCode = C.btck_ScriptError_WITNESS_PROGRAM_WITNESS_EMPTY
+	ScriptErrorWitnessProgramMismatch             ScriptErrorCode = C.btck_ScriptError_WITNESS_PROGRAM_MISMATCH
+	ScriptErrorWitnessMalleated                   ScriptErrorCode = C.btck_ScriptError_WITNESS_MALLEATED
+	ScriptErrorWitnessMalleatedP2SH               ScriptErrorCode = C.btck_ScriptError_WITNESS_MALLEATED_P2SH
+	ScriptErrorWitnessUnexpected                  ScriptErrorCode = C.btck_ScriptError_WITNESS_UNEXPECTED
+	ScriptErrorWitnessPubkeyType                  ScriptErrorCode = C.btck_ScriptError_WITNESS_PUBKEYTYPE
+	ScriptErrorSchnorrSigSize                     ScriptErrorCode = C.btck_ScriptError_SCHNORR_SIG_SIZE
+	ScriptErrorSchnorrSigHashType                 ScriptErrorCode = C.btck_ScriptError_SCHNORR_SIG_HASHTYPE
+	ScriptErrorSchnorrSig                         ScriptErrorCode = C.btck_ScriptError_SCHNORR_SIG
+	ScriptErrorTaprootWrongControlSize            ScriptErrorCode = C.btck_ScriptError_TAPROOT_WRONG_CONTROL_SIZE
+	ScriptErrorTapscriptValidationWeight          ScriptErrorCode = C.btck_ScriptError_TAPSCRIPT_VALIDATION_WEIGHT
+	ScriptErrorTapscriptCheckMultisig             ScriptErrorCode = C.btck_ScriptError_TAPSCRIPT_CHECKMULTISIG
+	ScriptErrorTapscriptMinimalIf                 ScriptErrorCode = C.btck_ScriptError_TAPSCRIPT_MINIMALIF
+	ScriptErrorTapscriptEmptyPubkey               ScriptErrorCode = C.btck_ScriptError_TAPSCRIPT_EMPTY_PUBKEY
+	ScriptErrorOpCodeSeparator                    ScriptErrorCode = C.btck_ScriptError_OP_CODESEPARATOR
+	ScriptErrorSigFindAndDelete                   ScriptErrorCode = C.btck_ScriptError_SIG_FINDANDDELETE
+)
+
+// String returns Bitcoin Core's description of the script error.
+func (c ScriptErrorCode) String() string {
+	switch c {
+	case ScriptErrorOK:
+		return "No error"
+	case ScriptErrorUnknownError:
+		return "unknown error"
+	case ScriptErrorEvalFalse:
+		return "Script evaluated without error but finished with a false/empty top stack element"
+	case ScriptErrorOpReturn:
+		return "OP_RETURN was encountered"
+	case ScriptErrorScriptSize:
+		return "Script is too big"
+	case ScriptErrorPushSize:
+		return "Push value size limit exceeded"
+	case ScriptErrorOpCount:
+		return "Operation limit exceeded"
+	case ScriptErrorStackSize:
+		return "Stack size limit exceeded"
+	case ScriptErrorSigCount:
+		return "Signature count negative or greater than pubkey count"
+	case ScriptErrorPubkeyCount:
+		return "Pubkey count negative or limit exceeded"
+	case ScriptErrorVerify:
+		return "Script failed an OP_VERIFY operation"
+	case ScriptErrorEqualVerify:
+		return "Script failed an OP_EQUALVERIFY operation"
+	case ScriptErrorCheckMultisigVerify:
+		return "Script failed an OP_CHECKMULTISIGVERIFY operation"
+	case ScriptErrorCheckSigVerify:
+		return "Script failed an OP_CHECKSIGVERIFY operation"
+	case ScriptErrorNumEqualVerify:
+		return "Script failed an OP_NUMEQUALVERIFY operation"
+	case ScriptErrorBadOpcode:
+		return "Opcode missing or not understood"
+	case ScriptErrorDisabledOpcode:
+		return "Attempted to use a disabled opcode"
+	case ScriptErrorInvalidStackOperation:
+		return "Operation not valid with the current stack size"
+	case ScriptErrorInvalidAltstackOperation:
+		return "Operation not valid with the current altstack size"
+	case ScriptErrorUnbalancedConditional:
+		return "Invalid OP_IF construction"
+	case ScriptErrorNegativeLocktime:
+		return "Negative locktime"
+	case ScriptErrorUnsatisfiedLocktime:
+		return "Locktime requirement not satisfied"
+	case ScriptErrorSigHashType:
+		return "Signature hash type missing or not understood"
+	case ScriptErrorSigDER:
+		return "Non-canonical DER signature"
+	case ScriptErrorMinimalData:
+		return "Data push larger than necessary"
+	case ScriptErrorSigPushOnly:
+		return "Only push operators allowed in signatures"
+	case ScriptErrorSigHighS:
+		return "Non-canonical signature: S value is unnecessarily high"
+	case ScriptErrorSigNullDummy:
+		return "Dummy CHECKMULTISIG argument must be zero"
+	case ScriptErrorPubkeyType:
+		return "Public key is neither compressed or uncompressed"
+	case ScriptErrorCleanStack:
+		return "Stack size must be exactly one after execution"
+	case ScriptErrorMinimalIf:
+		return "OP_IF/NOTIF argument must be minimal"
+	case ScriptErrorSigNullFail:
+		return "Signature must be zero for failed CHECK(MULTI)SIG operation"
+	case ScriptErrorDiscourageUpgradableNOPs:
+		return "NOPx reserved for soft-fork upgrades"
+	case ScriptErrorDiscourageUpgradableWitnessProgram:
+		return "Witness version reserved for soft-fork upgrades"
+	case ScriptErrorDiscourageUpgradableTaprootVersion:
+		return "Taproot version reserved for soft-fork upgrades"
+	case ScriptErrorDiscourageOpSuccess:
+		return "OP_SUCCESSx reserved for soft-fork upgrades"
+	case ScriptErrorDiscourageUpgradablePubkeyType:
+		return "Public key version reserved for soft-fork upgrades"
+	case ScriptErrorWitnessProgramWrongLength:
+		return "Witness program has incorrect length"
+	case ScriptErrorWitnessProgramWitnessEmpty:
+		return "Witness program was passed an empty witness"
+	case ScriptErrorWitnessProgramMismatch:
+		return "Witness program hash mismatch"
+	case ScriptErrorWitnessMalleated:
+		return "Witness requires empty scriptSig"
+	case ScriptErrorWitnessMalleatedP2SH:
+		return "Witness requires only-redeemscript scriptSig"
+	case ScriptErrorWitnessUnexpected:
+		return "Witness provided for non-witness script"
+	case ScriptErrorWitnessPubkeyType:
+		return "Using non-compressed keys in segwit"
+	case ScriptErrorSchnorrSigSize:
+		return "Invalid Schnorr signature size"
+	case ScriptErrorSchnorrSigHashType:
+		return "Invalid Schnorr signature hash type"
+	case ScriptErrorSchnorrSig:
+		return "Invalid Schnorr signature"
+	case ScriptErrorTaprootWrongControlSize:
+		return "Invalid Taproot control block size"
+	case ScriptErrorTapscriptValidationWeight:
+		return "Too much signature validation relative to witness weight"
+	case ScriptErrorTapscriptCheckMultisig:
+		return "OP_CHECKMULTISIG(VERIFY) is not available in tapscript"
+	case ScriptErrorTapscriptMinimalIf:
+		return "OP_IF/NOTIF argument must be minimal in tapscript"
+	case ScriptErrorTapscriptEmptyPubkey:
+		return "Empty public key in tapscript"
+	case ScriptErrorOpCodeSeparator:
+		return "Using OP_CODESEPARATOR in non-witness script"
+	case ScriptErrorSigFindAndDelete:
+		return "Signature is found in scriptCode"
+	}
+	return "unknown error"
+}
@@ -86,45 +86,71 @@ func (s *scriptPubkeyApi) Bytes() ([]byte, error) {
 //   - flags: ScriptFlags controlling validation constraints.
 //
 // Returns:
-//   - bool: true if the script is valid, false if invalid (only meaningful when error is nil)
-//   - error: non-nil if verification could not be performed due to malformed input;
-//     nil if verification completed successfully (check bool for validity result)
+//   - bool: true if the script is valid, false otherwise
+//   - error: a *ScriptExecutionError with the reason if the script is invalid, a
+//     *ScriptVerifyError if verification could not be performed due to malformed
+//     input, nil if the script is valid
 func (s *scriptPubkeyApi) Verify(amount int64, txTo *Transaction, spentOutputs []*TransactionOutput, inputIndex uint, flags ScriptFlags) (bool, error) {
+	cSpentOutputs, err := verifyArgs(txTo, spentOutputs, inputIndex, flags)
+	if err != nil {
+		return false, err
+	}
+	return verifyScript(s.ptr, amount, (*C.btck_Transaction)(txTo.handle.ptr), cSpentOutputs, inputIndex, flags)
+}
+
+// verifyArgs validates the arguments of Verify and Trace and returns the spent
+// outputs as C pointers.
+func verifyArgs(txTo *Transaction, spentOutputs []*TransactionOutput, inputIndex uint, flags ScriptFlags) ([]*C.btck_TransactionOutput, error) {
 	inputCount := txTo.CountInputs()
 	if inputIndex >= uint(inputCount) {
-		return false, ErrVerifyScriptVerifyTxInputIndex
+		return nil, ErrVerifyScriptVerifyTxInputIndex
 	}
 
 	if len(spentOutputs) > 0 && uint64(len(spentOutputs)) != inputCount {
-		return false, ErrVerifyScriptVerifySpentOutputsMismatch
+		return nil, ErrVerifyScriptVerifySpentOutputsMismatch
 	}
 
 	allFlags := ScriptFlagsVerifyAll
 	if (flags & ^ScriptFlags(allFlags)) != 0 {
-		return false, ErrVerifyScriptVerifyInvalidFlags
+		return nil, ErrVerifyScriptVerifyInvalidFlags
 	}
 
-	var cSpentOutputsPtr **C.btck_TransactionOutput
+	var cSpentOutputs []*C.btck_TransactionOutput
 	if len(spentOutputs) > 0 {
-		cSpentOutputs := make([]*C.btck_TransactionOutput, len(spentOutputs))
+		cSpentOutputs = make([]*C.btck_TransactionOutput, len(spentOutputs))
 		for i, output := range spentOutputs {
 			cSpentOutputs[i] = (*C.btck_TransactionOutput)(output.handle.ptr)
 		}
-		cSpentOutputsPtr = (**C.btck_TransactionOutput)(unsafe.Pointer(&cSpentOutputs[0]))
+	}
+	return cSpentOutputs, nil
+}
+
+// verifyScript calls the C verification function on already validated arguments.
+func verifyScript(scriptPubkey *C.btck_ScriptPubkey, amount int64, txTo *C.btck_Transaction,
+	spentOutputs []*C.btck_TransactionOutput, inputIndex uint, flags ScriptFlags) (bool, error) {
+	var cSpentOutputsPtr **C.btck_TransactionOutput
+	if len(spentOutputs) > 0 {
+		cSpentOutputsPtr = (**C.btck_TransactionOutput)(unsafe.Pointer(&spentOutputs[0]))
 	}
 
 	var cStatus C.btck_ScriptVerifyStatus
-	result := C.btck_script_pubkey_verify(
-		s.ptr,
+	var cScriptError C.btck_ScriptError
+	result := C.btck_script_pubkey_verify_with_error(
+		scriptPubkey,
 		C.int64_t(amount),
-		(*C.btck_Transaction)(txTo.handle.ptr),
+		txTo,
 		cSpentOutputsPtr,
 		C.size_t(len(spentOutputs)),
 		C.uint(inputIndex),
 		C.btck_ScriptVerificationFlags(flags),
 		&cStatus,
+		&cScriptError,
 	)
+	return verifyResult(result, cStatus, cScriptError)
+}
 
+// verifyResult converts the outputs of the C verification functions.
+func verifyResult(result C.int, cStatus C.btck_ScriptVerifyStatus, cScriptError C.btck_ScriptError) (bool, error) {
 	// Check for errors that prevented verification
 	if cStatus == C.btck_ScriptVerifyStatus_ERROR_INVALID_FLAGS_COMBINATION {
 		return false, ErrVerifyScriptVerifyInvalidFlagsCombination
@@ -135,8 +161,130 @@ func (s *scriptPubkeyApi) Verify(amount int64, txTo *Transaction, spentOutputs [
 
 	// Verification completed: result indicates validity
 	// result == 1: script is valid
-	// result != 1: script is invalid
-	return result == 1, nil
+	// result != 1: script is invalid, the script error holds the reason
+	if result != 1 {
+		return false, &ScriptExecutionError{Code: ScriptErrorCode(cScriptError)}
+	}
+	return true, nil
+}
+
+// Type returns the standard template the script pubkey matches.
+func (s *scriptPubkeyApi) Type() ScriptType {
+	return ScriptType(C.btck_script_pubkey_get_type(s.ptr))
+}
+
+// CountSolutions returns the number of data elements extracted from the script
+// pubkey by the template solver. See Solutions for their layout.
+func (s *scriptPubkeyApi) CountSolutions() uint64 {
+	return uint64(C.btck_script_pubkey_count_solutions(s.ptr))
+}
+
+// GetSolution retrieves the data element at the specified index extracted by the
+// template solver.
+//
+// Parameters:
+//   - index: Index of the data element to retrieve
+//
+// Returns an error if the index is out of bounds or the serialization fails.
+func (s *scriptPubkeyApi) GetSolution(index uint64) ([]byte, error) {
+	if index >= s.CountSolutions() {
+		return nil, ErrKernelIndexOutOfBounds
+	}
+	bytes, ok := writeToBytes(func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+		return C.btck_script_pubkey_solution_to_bytes(s.ptr, C.size_t(index), writer, userData)
+	})
+	if !ok {
+		return nil, &SerializationError{"Failed to serialize script solution"}
+	}
+	return bytes, nil
+}
+
+// Solutions returns the data extracted from the script pubkey by the template
+// solver, depending on its Type:
+//   - ScriptTypePubkey: the public key
+//   - ScriptTypePubkeyHash, ScriptTypeScriptHash, ScriptTypeWitnessV0KeyHash,
+//     ScriptTypeWitnessV0ScriptHash: the hash
+//   - ScriptTypeMultisig: the required signature count as a single byte, the public
+//     keys, and the public key count as a single byte
+//   - ScriptTypeNullData: the payloads pushed after OP_RETURN
+//   - ScriptTypeWitnessV1Taproot: the 32-byte output key
+//   - ScriptTypeWitnessUnknown: the witness version as a single byte and the witness program
+//
+// Nonstandard and anchor scripts have no solutions. Returns an error if the
+// serialization fails.
+func (s *scriptPubkeyApi) Solutions() ([][]byte, error) {
+	count := s.CountSolutions()
+	solutions := make([][]byte, 0, count)
+	for i := uint64(0); i < count; i++ {
+		solution, err := s.GetSolution(i)
+		if err != nil {
+			return nil, err
+		}
+		solutions = append(solutions, solution)
+	}
+	return solutions, nil
+}
+
+// Disassemble returns the script pubkey in the "asm" format used by Bitcoin Core's
+// RPC interface, e.g. "OP_DUP OP_HASH160 <hash> OP_EQUALVERIFY OP_CHECKSIG".
+// Pushes of up to four bytes are shown as numbers, larger pushes in hex. A
+// malformed push is shown as "[error]" and ends the disassembly.
+//
+// Returns an error if the serialization fails.
+func (s *scriptPubkeyApi) Disassemble() (string, error) {
+	bytes, ok := writeToBytes(func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+		return C.btck_script_pubkey_to_asm(s.ptr, writer, userData)
+	})
+	if !ok {
+		return "", &SerializationError{"Failed to disassemble script pubkey"}
+	}
+	return string(bytes), nil
+}
+
+// ScriptType identifies the standard template a script pubkey matches.
+type ScriptType C.btck_ScriptType
+
+const (
+	ScriptTypeNonstandard         ScriptType = C.btck_ScriptType_NONSTANDARD           // Does not match any standard template
+	ScriptTypeAnchor              ScriptType = C.btck_ScriptType_ANCHOR                // Anyone-can-spend pay-to-anchor output
+	ScriptTypePubkey              ScriptType = C.btck_ScriptType_PUBKEY                // Pay to public key
+	ScriptTypePubkeyHash          ScriptType = C.btck_ScriptType_PUBKEYHASH            // Pay to public key hash
+	ScriptTypeScriptHash          ScriptType = C.btck_ScriptType_SCRIPTHASH            // Pay to script hash (BIP16)
+	ScriptTypeMultisig            ScriptType = C.btck_ScriptType_MULTISIG              // Bare multisig
+	ScriptTypeNullData            ScriptType = C.btck_ScriptType_NULL_DATA             // Unspendable OP_RETURN output carrying data
+	ScriptTypeWitnessV0ScriptHash ScriptType = C.btck_ScriptType_WITNESS_V0_SCRIPTHASH // Pay to witness script hash (BIP141)
+	ScriptTypeWitnessV0KeyHash    ScriptType = C.btck_ScriptType_WITNESS_V0_KEYHASH    // Pay to witness public key hash (BIP141)
+	ScriptTypeWitnessV1Taproot    ScriptType = C.btck_ScriptType_WITNESS_V1_TAPROOT    // Pay to taproot (BIP341)
+	ScriptTypeWitnessUnknown      ScriptType = C.btck_ScriptType_WITNESS_UNKNOWN       // Witness program of an undefined version
+)
+
+// String returns the name Bitcoin Core uses for the script type, e.g. "witness_v0_keyhash".
+func (t ScriptType) String() string {
+	switch t {
+	case ScriptTypeNonstandard:
+		return "nonstandard"
+	case ScriptTypeAnchor:
+		return "anchor"
+	case ScriptTypePubkey:
+		return "pubkey"
+	case ScriptTypePubkeyHash:
+		return "pubkeyhash"
+	case ScriptTypeScriptHash:
+		return "scripthash"
+	case ScriptTypeMultisig:
+		return "multisig"
+	case ScriptTypeNullData:
+		return "nulldata"
+	case ScriptTypeWitnessV0ScriptHash:
+		return "witness_v0_scripthash"
+	case ScriptTypeWitnessV0KeyHash:
+		return "witness_v0_keyhash"
+	case ScriptTypeWitnessV1Taproot:
+		return "witness_v1_taproot"
+	case ScriptTypeWitnessUnknown:
+		return "witness_unknown"
+	}
+	return "unknown"
 }
 
 // ScriptFlags represents script verification flags that may be composed with each other.
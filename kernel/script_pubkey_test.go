@@ -165,6 +165,7 @@ func TestInvalidScripts(t *testing.T) {
 		amount          int64
 		txToHex         string
 		inputIndex      uint
+		expectedCode    ScriptErrorCode
 		description     string
 	}{
 		{
@@ -173,6 +174,7 @@ func TestInvalidScripts(t *testing.T) {
 			amount:          0,
 			txToHex:         "02000000013f7cebd65c27431a90bba7f796914fe8cc2ddfc3f2cbd6f7e5f2fc854534da95000000006b483045022100de1ac3bcdfb0332207c4a91f3832bd2c2915840165f876ab47c5f8996b971c3602201c6c053d750fadde599e6f5c4e1963df0f01fc0d97815e8157e3d59fe09ca30d012103699b464d1d8bc9e47d4fb1cdaa89a1c5783d68363c4dbc4b524ed3d857148617feffffff02836d3c01000000001976a914fc25d6d5c94003bf5b0c7b640a248e2c637fcfb088ac7ada8202000000001976a914fbed3d9b11183209a57999d54d59f67c019e756c88ac6acb0700",
 			inputIndex:      0,
+			expectedCode:    ScriptErrorBadOpcode,
 			description:     "a random old-style transaction from the blockchain - WITH WRONG SIGNATURE for the address",
 		},
 		{
@@ -181,6 +183,7 @@ func TestInvalidScripts(t *testing.T) {
 			amount:          900000, // Wrong amount, should be 1900000
 			txToHex:         "01000000000101d9fd94d0ff0026d307c994d0003180a5f248146efb6371d040c5973f5f66d9df0400000017160014b31b31a6cb654cfab3c50567bcf124f48a0beaecffffffff012cbd1c000000000017a914233b74bf0823fa58bbbd26dfc3bb4ae715547167870247304402206f60569cac136c114a58aedd80f6fa1c51b49093e7af883e605c212bdafcd8d202200e91a55f408a021ad2631bc29a67bd6915b2d7e9ef0265627eabd7f7234455f6012103e7e802f50344303c76d12c089c8724c1b230e3b745693bbe16aad536293d15e300000000",
 			inputIndex:      0,
+			expectedCode:    ScriptErrorEvalFalse,
 			description:     "a random segwit transaction from the blockchain using P2SH - WITH WRONG AMOUNT",
 		},
 		{
@@ -189,6 +192,7 @@ func TestInvalidScripts(t *testing.T) {
 			amount:          18393430,
 			txToHex:         "010000000001011f97548fbbe7a0db7588a66e18d803d0089315aa7d4cc28360b6ec50ef36718a0100000000ffffffff02df1776000000000017a9146c002a686959067f4866b8fb493ad7970290ab728757d29f0000000000220020701a8d401c84fb13e6baf169d59684e17abd9fa216c8cc5b9fc63d622ff8c58d04004730440220565d170eed95ff95027a69b313758450ba84a01224e1f7f130dda46e94d13f8602207bdd20e307f062594022f12ed5017bbf4a055a06aea91c10110a0e3bb23117fc014730440220647d2dc5b15f60bc37dc42618a370b2a1490293f9e5c8464f53ec4fe1dfe067302203598773895b4b16d37485cbe21b337f4e4b650739880098c592553add7dd4355016952210375e00eb72e29da82b89367947f29ef34afb75e8654f6ea368e0acdfd92976b7c2103a1b26313f430c4b15bb1fdce663207659d8cac749a0e53d70eff01874496feff2103c96d495bfdd5ba4145e3e046fee45e84a8a48ad05bd8dbb395c011a32cf9f88053ae00000000",
 			inputIndex:      0,
+			expectedCode:    ScriptErrorWitnessProgramMismatch,
 			description:     "a random segwit transaction from the blockchain using native segwit - WITH WRONG SEGWIT",
 		},
 		{
@@ -197,19 +201,25 @@ func TestInvalidScripts(t *testing.T) {
 			amount:          0,
 			// Minimal coinbase-style transaction with a single empty scriptSig and zero-value output;
 			// used to trigger verification paths.
-			txToHex:     "01000000010000000000000000000000000000000000000000000000000000000000000000ffffffff00ffffffff0100000000000000000000000000",
-			inputIndex:  0,
-			description: "empty scriptPubkey should fail verification",
+			txToHex:      "01000000010000000000000000000000000000000000000000000000000000000000000000ffffffff00ffffffff0100000000000000000000000000",
+			inputIndex:   0,
+			expectedCode: ScriptErrorEvalFalse,
+			description:  "empty scriptPubkey should fail verification",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			valid, err := testVerifyScript(t, tt.scriptPubkeyHex, tt.amount, tt.txToHex, tt.inputIndex)
-			if err != nil {
-				t.Errorf("testVerifyScript() unexpected error = %v", err)
-			} else if valid {
-				t.Errorf("testVerifyScript() expected invalid script, got valid")
+			if valid {
+				t.Fatalf("testVerifyScript() expected invalid script, got valid")
+			}
+			var execErr *ScriptExecutionError
+			if !errors.As(err, &execErr) {
+				t.Fatalf("testVerifyScript() expected *ScriptExecutionError, got %v", err)
+			}
+			if execErr.Code != tt.expectedCode {
+				t.Errorf("Expected script error %q, got %q", tt.expectedCode, execErr.Code)
 			}
 		})
 	}
@@ -279,6 +289,149 @@ func TestScriptVerifyErrors(t *testing.T) {
 	}
 }
 
+func TestScriptPubkeyAnalysis(t *testing.T) {
+	const (
+		uncompressedKey = "04678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5f"
+		key1            = "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+		key2            = "02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5"
+		hash20          = "62e907b15cbf27d5425399ebf6f0fb50ebb88f18"
+		hash32          = "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+	)
+
+	tests := []struct {
+		name         string
+		scriptHex    string
+		expectedType ScriptType
+		expectedAsm  string
+		solutions    []string
+	}{
+		{
+			name:         "p2pk",
+			scriptHex:    "41" + uncompressedKey + "ac",
+			expectedType: ScriptTypePubkey,
+			expectedAsm:  uncompressedKey + " OP_CHECKSIG",
+			solutions:    []string{uncompressedKey},
+		},
+		{
+			name:         "p2pkh",
+			scriptHex:    "76a914" + hash20 + "88ac",
+			expectedType: ScriptTypePubkeyHash,
+			expectedAsm:  "OP_DUP OP_HASH160 " + hash20 + " OP_EQUALVERIFY OP_CHECKSIG",
+			solutions:    []string{hash20},
+		},
+		{
+			name:         "p2sh",
+			scriptHex:    "a914" + hash20 + "87",
+			expectedType: ScriptTypeScriptHash,
+			expectedAsm:  "OP_HASH160 " + hash20 + " OP_EQUAL",
+			solutions:    []string{hash20},
+		},
+		{
+			name:         "multisig",
+			scriptHex:    "5121" + key1 + "21" + key2 + "52ae",
+			expectedType: ScriptTypeMultisig,
+			expectedAsm:  "1 " + key1 + " " + key2 + " 2 OP_CHECKMULTISIG",
+			solutions:    []string{"01", key1, key2, "02"},
+		},
+		{
+			name:         "nulldata",
+			scriptHex:    "6a0b68656c6c6f20776f726c6451",
+			expectedType: ScriptTypeNullData,
+			expectedAsm:  "OP_RETURN 68656c6c6f20776f726c64 1",
+			solutions:    []string{"68656c6c6f20776f726c64", "01"},
+		},
+		{
+			name:         "p2wpkh",
+			scriptHex:    "0014" + hash20,
+			expectedType: ScriptTypeWitnessV0KeyHash,
+			expectedAsm:  "0 " + hash20,
+			solutions:    []string{hash20},
+		},
+		{
+			name:         "p2wsh",
+			scriptHex:    "0020" + hash32,
+			expectedType: ScriptTypeWitnessV0ScriptHash,
+			expectedAsm:  "0 " + hash32,
+			solutions:    []string{hash32},
+		},
+		{
+			name:         "p2tr",
+			scriptHex:    "5120" + hash32,
+			expectedType: ScriptTypeWitnessV1Taproot,
+			expectedAsm:  "1 " + hash32,
+			solutions:    []string{hash32},
+		},
+		{
+			name:         "anchor",
+			scriptHex:    "51024e73",
+			expectedType: ScriptTypeAnchor,
+			expectedAsm:  "1 29518",
+			solutions:    []string{},
+		},
+		{
+			name:         "witness_unknown",
+			scriptHex:    "5220" + hash32,
+			expectedType: ScriptTypeWitnessUnknown,
+			expectedAsm:  "2 " + hash32,
+			solutions:    []string{"02", hash32},
+		},
+		{
+			name:         "empty",
+			scriptHex:    "",
+			expectedType: ScriptTypeNonstandard,
+			expectedAsm:  "",
+			solutions:    []string{},
+		},
+		{
+			name:         "truncated_push",
+			scriptHex:    "76a94c",
+			expectedType: ScriptTypeNonstandard,
+			expectedAsm:  "OP_DUP OP_HASH160 [error]",
+			solutions:    []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scriptBytes, err := hex.DecodeString(tt.scriptHex)
+			if err != nil {
+				t.Fatalf("Failed to decode script hex: %v", err)
+			}
+			scriptPubkey := NewScriptPubkey(scriptBytes)
+			defer scriptPubkey.Destroy()
+
+			if scriptType := scriptPubkey.Type(); scriptType != tt.expectedType {
+				t.Errorf("Expected type %s, got %s", tt.expectedType, scriptType)
+			}
+
+			asm, err := scriptPubkey.Disassemble()
+			if err != nil {
+				t.Fatalf("Disassemble() error = %v", err)
+			}
+			if asm != tt.expectedAsm {
+				t.Errorf("Expected asm %q, got %q", tt.expectedAsm, asm)
+			}
+
+			solutions, err := scriptPubkey.Solutions()
+			if err != nil {
+				t.Fatalf("Solutions() error = %v", err)
+			}
+			if len(solutions) != len(tt.solutions) {
+				t.Fatalf("Expected %d solutions, got %d", len(tt.solutions), len(solutions))
+			}
+			for i, solution := range solutions {
+				if hex.EncodeToString(solution) != tt.solutions[i] {
+					t.Errorf("Expected solution %d to be %s, got %x", i, tt.solutions[i], solution)
+				}
+			}
+
+			if _, err := scriptPubkey.GetSolution(uint64(len(solutions))); !errors.Is(err, ErrKernelIndexOutOfBounds) {
+				t.Errorf("Expected ErrKernelIndexOutOfBounds, got %v", err)
+			}
+		})
+	}
+}
+
 // testVerifyScript is a helper function that creates the necessary objects and calls VerifyScript
 func testVerifyScript(t *testing.T, scriptPubkeyHex string, amount int64, txToHex string, inputIndex uint) (bool, error) {
 	scriptPubkeyBytes, err := hex.DecodeString(scriptPubkeyHex)
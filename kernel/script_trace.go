@@ -0,0 +1,316 @@
+package kernel
+
+/*
+#include "bitcoinkernel.h"
+#include <stdint.h>
+
+extern void go_script_trace_script_start_bridge(void* user_data, btck_ScriptPhase phase, void* script, size_t script_len);
+extern void go_script_trace_step_bridge(void* user_data, btck_ScriptTraceStep* step);
+*/
+import "C"
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/cgo"
+	"strings"
+	"unsafe"
+)
+
+// ScriptPhase identifies which script of a spend is being evaluated.
+type ScriptPhase C.btck_ScriptPhase
+
+const (
+	ScriptPhaseScriptSig     ScriptPhase = C.btck_ScriptPhase_SCRIPT_SIG     // The scriptSig of the spending input
+	ScriptPhaseScriptPubkey  ScriptPhase = C.btck_ScriptPhase_SCRIPT_PUBKEY  // The script pubkey being spent
+	ScriptPhaseRedeemScript  ScriptPhase = C.btck_ScriptPhase_REDEEM_SCRIPT  // The redeem script of a P2SH output
+	ScriptPhaseWitnessScript ScriptPhase = C.btck_ScriptPhase_WITNESS_SCRIPT // The segwit v0 witness script, or the implied script of P2WPKH
+	ScriptPhaseTapscript     ScriptPhase = C.btck_ScriptPhase_TAPSCRIPT      // The tapscript of a taproot script path spend
+)
+
+func (p ScriptPhase) String() string {
+	switch p {
+	case ScriptPhaseScriptSig:
+		return "scriptSig"
+	case ScriptPhaseScriptPubkey:
+		return "scriptPubKey"
+	case ScriptPhaseRedeemScript:
+		return "redeemScript"
+	case ScriptPhaseWitnessScript:
+		return "witnessScript"
+	case ScriptPhaseTapscript:
+		return "tapscript"
+	}
+	return "unknown"
+}
+
+// ScriptTrace is the record of the interpreter executing the scripts of one input.
+type ScriptTrace struct {
+	Scripts []TracedScript        // Evaluated scripts, in evaluation order
+	Valid   bool                  // Whether the input is valid
+	Err     *ScriptExecutionError // Reason the input is invalid, nil if valid
+}
+
+// TracedScript is the record of a single script evaluation.
+type TracedScript struct {
+	Phase  ScriptPhase       // Role of the script in the spend
+	Script []byte            // Serialized script
+	Steps  []ScriptTraceStep // Opcodes read by the interpreter, in order
+}
+
+// ScriptTraceStep is the interpreter state after one opcode.
+//
+// If an opcode fails, evaluation stops before its step is recorded, so the
+// failing opcode is the one following the last step of the last script.
+type ScriptTraceStep struct {
+	OpcodeIndex uint32   // Position of the opcode in the script, counted in opcodes
+	Opcode      byte     // Opcode byte
+	Executed    bool     // Whether the opcode was executed, false in an unexecuted branch
+	Stack       [][]byte // Main stack after the opcode, bottom first
+	Altstack    [][]byte // Alt stack after the opcode, bottom first
+}
+
+// OpcodeName returns the name of the step's opcode, using Bitcoin Core's names.
+// Direct pushes of n bytes, which Core has no name for, are named "OP_PUSHBYTES_n".
+func (s *ScriptTraceStep) OpcodeName() string {
+	return opcodeName(s.Opcode)
+}
+
+//export go_script_trace_script_start_bridge
+func go_script_trace_script_start_bridge(user_data unsafe.Pointer, phase C.btck_ScriptPhase, script unsafe.Pointer, script_len C.size_t) {
+	trace := cgo.Handle(user_data).Value().(*ScriptTrace)
+	trace.Scripts = append(trace.Scripts, TracedScript{
+		Phase:  ScriptPhase(phase),
+		Script: C.GoBytes(script, C.int(script_len)),
+	})
+}
+
+//export go_script_trace_step_bridge
+func go_script_trace_step_bridge(user_data unsafe.Pointer, step *C.btck_ScriptTraceStep) {
+	trace := cgo.Handle(user_data).Value().(*ScriptTrace)
+	script := &trace.Scripts[len(trace.Scripts)-1]
+	script.Steps = append(script.Steps, ScriptTraceStep{
+		OpcodeIndex: uint32(C.btck_script_trace_step_get_opcode_position(step)),
+		Opcode:      byte(C.btck_script_trace_step_get_opcode(step)),
+		Executed:    C.btck_script_trace_step_is_executed(step) != 0,
+		Stack: traceStackItems(uint64(C.btck_script_trace_step_count_stack_items(step)),
+			func(index C.size_t, writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+				return C.btck_script_trace_step_stack_item_to_bytes(step, index, writer, userData)
+			}),
+		Altstack: traceStackItems(uint64(C.btck_script_trace_step_count_altstack_items(step)),
+			func(index C.size_t, writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+				return C.btck_script_trace_step_altstack_item_to_bytes(step, index, writer, userData)
+			}),
+	})
+}
+
+func traceStackItems(count uint64, itemToBytes func(C.size_t, C.btck_WriteBytes, unsafe.Pointer) C.int) [][]byte {
+	items := make([][]byte, count)
+	for i := range items {
+		item, ok := writeToBytes(func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+			return itemToBytes(C.size_t(i), writer, userData)
+		})
+		if !ok {
+			panic("Failed to copy stack item")
+		}
+		items[i] = item
+	}
+	return items
+}
+
+// Trace verifies the input like Verify, recording every opcode the interpreter
+// reads together with the stack and alt stack after it. It is meant for
+// debugging and is considerably slower than Verify.
+//
+// Parameters:
+//   - amount: Amount of the script pubkey's associated output. May be zero if the witness flag is not set.
+//   - txTo: Transaction spending the script pubkey.
+//   - spentOutputs: Outputs spent by the transaction. May be nil if the taproot flag is not set.
+//   - inputIndex: Index of the input in txTo spending the script pubkey.
+//   - flags: ScriptFlags controlling validation constraints.
+//
+// Returns the trace, which also holds the verification result, or a
+// *ScriptVerifyError if verification could not be performed due to malformed input.
+func (s *scriptPubkeyApi) Trace(amount int64, txTo *Transaction, spentOutputs []*TransactionOutput, inputIndex uint, flags ScriptFlags) (*ScriptTrace, error) {
+	cSpentOutputs, err := verifyArgs(txTo, spentOutputs, inputIndex, flags)
+	if err != nil {
+		return nil, err
+	}
+	var cSpentOutputsPtr **C.btck_TransactionOutput
+	if len(cSpentOutputs) > 0 {
+		cSpentOutputsPtr = (**C.btck_TransactionOutput)(unsafe.Pointer(&cSpentOutputs[0]))
+	}
+
+	trace := &ScriptTrace{}
+	traceHandle := cgo.NewHandle(trace)
+	defer traceHandle.Delete()
+
+	var cStatus C.btck_ScriptVerifyStatus
+	var cScriptError C.btck_ScriptError
+	result := C.btck_script_pubkey_verify_with_trace(
+		s.ptr,
+		C.int64_t(amount),
+		(*C.btck_Transaction)(txTo.handle.ptr),
+		cSpentOutputsPtr,
+		C.size_t(len(cSpentOutputs)),
+		C.uint(inputIndex),
+		C.btck_ScriptVerificationFlags(flags),
+		&cStatus,
+		&cScriptError,
+		C.btck_ScriptTraceCallbacks{
+			user_data:    unsafe.Pointer(traceHandle),
+			script_start: (C.btck_ScriptTraceScriptStart)(C.go_script_trace_script_start_bridge),
+			step:         (C.btck_ScriptTraceStepDone)(C.go_script_trace_step_bridge),
+		},
+	)
+	runtime.KeepAlive(txTo)
+	runtime.KeepAlive(spentOutputs)
+
+	if cStatus == C.btck_ScriptVerifyStatus_ERROR_INVALID_FLAGS_COMBINATION {
+		return nil, ErrVerifyScriptVerifyInvalidFlagsCombination
+	}
+	if cStatus == C.btck_ScriptVerifyStatus_ERROR_SPENT_OUTPUTS_REQUIRED {
+		return nil, ErrVerifyScriptVerifySpentOutputsRequired
+	}
+	trace.Valid = result == 1
+	if !trace.Valid {
+		trace.Err = &ScriptExecutionError{Code: ScriptErrorCode(cScriptError)}
+	}
+	return trace, nil
+}
+
+// String renders the trace as text, one line per step, e.g.
+//
+//	scriptPubKey 76a914...88ac
+//	  #0 OP_DUP [3045...01 02ab...]
+//
+// Steps in unexecuted branches are marked "(skipped)". The alt stack is only
+// shown when it is not empty.
+func (t *ScriptTrace) String() string {
+	var sb strings.Builder
+	for _, script := range t.Scripts {
+		fmt.Fprintf(&sb, "%s %x\n", script.Phase, script.Script)
+		for _, step := range script.Steps {
+			fmt.Fprintf(&sb, "  #%d %s", step.OpcodeIndex, step.OpcodeName())
+			if !step.Executed {
+				sb.WriteString(" (skipped)")
+			}
+			fmt.Fprintf(&sb, " %s", formatStack(step.Stack))
+			if len(step.Altstack) > 0 {
+				fmt.Fprintf(&sb, " alt %s", formatStack(step.Altstack))
+			}
+			sb.WriteByte('\n')
+		}
+	}
+	if t.Valid {
+		sb.WriteString("result: valid\n")
+	} else {
+		fmt.Fprintf(&sb, "result: invalid (%s)\n", t.Err.Code)
+	}
+	return sb.String()
+}
+
+func formatStack(stack [][]byte) string {
+	return "[" + strings.Join(hexStack(stack), " ") + "]"
+}
+
+type scriptTraceStepJSON struct {
+	OpcodeIndex uint32   `json:"opcode_index"`
+	Opcode      string   `json:"opcode"`
+	Executed    bool     `json:"executed"`
+	Stack       []string `json:"stack"`
+	Altstack    []string `json:"altstack"`
+}
+
+type tracedScriptJSON struct {
+	Phase  string                `json:"phase"`
+	Script string                `json:"script"`
+	Steps  []scriptTraceStepJSON `json:"steps"`
+}
+
+type scriptTraceJSON struct {
+	Scripts []tracedScriptJSON `json:"scripts"`
+	Valid   bool               `json:"valid"`
+	Error   string             `json:"error,omitempty"`
+}
+
+// MarshalJSON renders the trace as JSON with scripts and stack items as hex
+// strings and opcodes by name.
+func (t *ScriptTrace) MarshalJSON() ([]byte, error) {
+	out := scriptTraceJSON{Scripts: make([]tracedScriptJSON, len(t.Scripts)), Valid: t.Valid}
+	if t.Err != nil {
+		out.Error = t.Err.Code.String()
+	}
+	for i, script := range t.Scripts {
+		steps := make([]scriptTraceStepJSON, len(script.Steps))
+		for j, step := range script.Steps {
+			steps[j] = scriptTraceStepJSON{
+				OpcodeIndex: step.OpcodeIndex,
+				Opcode:      step.OpcodeName(),
+				Executed:    step.Executed,
+				Stack:       hexStack(step.Stack),
+				Altstack:    hexStack(step.Altstack),
+			}
+		}
+		out.Scripts[i] = tracedScriptJSON{
+			Phase:  script.Phase.String(),
+			Script: hex.EncodeToString(script.Script),
+			Steps:  steps,
+		}
+	}
+	return json.Marshal(out)
+}
+
+func hexStack(stack [][]byte) []string {
+	items := make([]string, len(stack))
+	for i, item := range stack {
+		items[i] = hex.EncodeToString(item)
+	}
+	return items
+}
+
+// opcodeNames holds the names of the opcodes from OP_NOP (0x61) to OP_CHECKSIGADD (0xba).
+var opcodeNames = [...]string{
+	"OP_NOP", "OP_VER", "OP_IF", "OP_NOTIF", "OP_VERIF", "OP_VERNOTIF",
+	"OP_ELSE", "OP_ENDIF", "OP_VERIFY", "OP_RETURN", "OP_TOALTSTACK", "OP_FROMALTSTACK",
+	"OP_2DROP", "OP_2DUP", "OP_3DUP", "OP_2OVER", "OP_2ROT", "OP_2SWAP",
+	"OP_IFDUP", "OP_DEPTH", "OP_DROP", "OP_DUP", "OP_NIP", "OP_OVER",
+	"OP_PICK", "OP_ROLL", "OP_ROT", "OP_SWAP", "OP_TUCK", "OP_CAT",
+	"OP_SUBSTR", "OP_LEFT", "OP_RIGHT", "OP_SIZE", "OP_INVERT", "OP_AND",
+	"OP_OR", "OP_XOR", "OP_EQUAL", "OP_EQUALVERIFY", "OP_RESERVED1", "OP_RESERVED2",
+	"OP_1ADD", "OP_1SUB", "OP_2MUL", "OP_2DIV", "OP_NEGATE", "OP_ABS",
+	"OP_NOT", "OP_0NOTEQUAL", "OP_ADD", "OP_SUB", "OP_MUL", "OP_DIV",
+	"OP_MOD", "OP_LSHIFT", "OP_RSHIFT", "OP_BOOLAND", "OP_BOOLOR", "OP_NUMEQUAL",
+	"OP_NUMEQUALVERIFY", "OP_NUMNOTEQUAL", "OP_LESSTHAN", "OP_GREATERTHAN", "OP_LESSTHANOREQUAL", "OP_GREATERTHANOREQUAL",
+	"OP_MIN", "OP_MAX", "OP_WITHIN", "OP_RIPEMD160", "OP_SHA1", "OP_SHA256",
+	"OP_HASH160", "OP_HASH256", "OP_CODESEPARATOR", "OP_CHECKSIG", "OP_CHECKSIGVERIFY", "OP_CHECKMULTISIG",
+	"OP_CHECKMULTISIGVERIFY", "OP_NOP1", "OP_CHECKLOCKTIMEVERIFY", "OP_CHECKSEQUENCEVERIFY", "OP_NOP4", "OP_NOP5",
+	"OP_NOP6", "OP_NOP7", "OP_NOP8", "OP_NOP9", "OP_NOP10", "OP_CHECKSIGADD",
+}
+
+func opcodeName(op byte) string {
+	switch {
+	case op == 0x00:
+		return "0"
+	case op <= 0x4b:
+		return fmt.Sprintf("OP_PUSHBYTES_%d", op)
+	case op == 0x4c:
+		return "OP_PUSHDATA1"
+	case op == 0x4d:
+		return "OP_PUSHDATA2"
+	case op == 0x4e:
+		return "OP_PUSHDATA4"
+	case op == 0x4f:
+		return "-1"
+	case op == 0x50:
+		return "OP_RESERVED"
+	case op <= 0x60:
+		return fmt.Sprint(op - 0x50)
+	case op <= 0xba:
+		return opcodeNames[op-0x61]
+	case op == 0xff:
+		return "OP_INVALIDOPCODE"
+	}
+	return "OP_UNKNOWN"
+}
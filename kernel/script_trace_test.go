@@ -0,0 +1,182 @@
+package kernel
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestScriptTrace(t *testing.T) {
+	tests := []struct {
+		name            string
+		scriptPubkeyHex string
+		amount          int64
+		txToHex         string
+		expectedPhases  []ScriptPhase
+		expectedOps     [][]string
+		expectedCode    ScriptErrorCode
+	}{
+		{
+			name:            "p2pkh",
+			scriptPubkeyHex: "76a9144bfbaf6afb76cc5771bc6404810d1cc041a6933988ac",
+			txToHex:         "02000000013f7cebd65c27431a90bba7f796914fe8cc2ddfc3f2cbd6f7e5f2fc854534da95000000006b483045022100de1ac3bcdfb0332207c4a91f3832bd2c2915840165f876ab47c5f8996b971c3602201c6c053d750fadde599e6f5c4e1963df0f01fc0d97815e8157e3d59fe09ca30d012103699b464d1d8bc9e47d4fb1cdaa89a1c5783d68363c4dbc4b524ed3d857148617feffffff02836d3c01000000001976a914fc25d6d5c94003bf5b0c7b640a248e2c637fcfb088ac7ada8202000000001976a914fbed3d9b11183209a57999d54d59f67c019e756c88ac6acb0700",
+			expectedPhases:  []ScriptPhase{ScriptPhaseScriptSig, ScriptPhaseScriptPubkey},
+			expectedOps: [][]string{
+				{"OP_PUSHBYTES_72", "OP_PUSHBYTES_33"},
+				{"OP_DUP", "OP_HASH160", "OP_PUSHBYTES_20", "OP_EQUALVERIFY", "OP_CHECKSIG"},
+			},
+			expectedCode: ScriptErrorOK,
+		},
+		{
+			name:            "p2wsh_multisig",
+			scriptPubkeyHex: "0020701a8d401c84fb13e6baf169d59684e17abd9fa216c8cc5b9fc63d622ff8c58d",
+			amount:          18393430,
+			txToHex:         "010000000001011f97548fbbe7a0db7588a66e18d803d0089315aa7d4cc28360b6ec50ef36718a0100000000ffffffff02df1776000000000017a9146c002a686959067f4866b8fb493ad7970290ab728757d29f0000000000220020701a8d401c84fb13e6baf169d59684e17abd9fa216c8cc5b9fc63d622ff8c58d04004730440220565d170eed95ff95027a69b313758450ba84a01224e1f7f130dda46e94d13f8602207bdd20e307f062594022f12ed5017bbf4a055a06aea91c10110a0e3bb23117fc014730440220647d2dc5b15f60bc37dc42618a370b2a1490293f9e5c8464f53ec4fe1dfe067302203598773895b4b16d37485cbe21b337f4e4b650739880098c592553add7dd4355016952210375e00eb72e29da82b89367947f29ef34afb75e8654f6ea368e0acdfd92976b7c2103a1b26313f430c4b15bb1fdce663207659d8cac749a0e53d70eff01874496feff2103c96d495bfdd5ba4145e3e046fee45e84a8a48ad05bd8dbb395c011a32cf9f88053ae00000000",
+			expectedPhases:  []ScriptPhase{ScriptPhaseScriptSig, ScriptPhaseScriptPubkey, ScriptPhaseWitnessScript},
+			expectedOps: [][]string{
+				{},
+				{"0", "OP_PUSHBYTES_32"},
+				{"2", "OP_PUSHBYTES_33", "OP_PUSHBYTES_33", "OP_PUSHBYTES_33", "3", "OP_CHECKMULTISIG"},
+			},
+			expectedCode: ScriptErrorOK,
+		},
+		{
+			name:            "p2pkh_bad_opcode",
+			scriptPubkeyHex: "76a9144bfbaf6afb76cc5771bc6404810d1cc041a6933988ff",
+			txToHex:         "02000000013f7cebd65c27431a90bba7f796914fe8cc2ddfc3f2cbd6f7e5f2fc854534da95000000006b483045022100de1ac3bcdfb0332207c4a91f3832bd2c2915840165f876ab47c5f8996b971c3602201c6c053d750fadde599e6f5c4e1963df0f01fc0d97815e8157e3d59fe09ca30d012103699b464d1d8bc9e47d4fb1cdaa89a1c5783d68363c4dbc4b524ed3d857148617feffffff02836d3c01000000001976a914fc25d6d5c94003bf5b0c7b640a248e2c637fcfb088ac7ada8202000000001976a914fbed3d9b11183209a57999d54d59f67c019e756c88ac6acb0700",
+			expectedPhases:  []ScriptPhase{ScriptPhaseScriptSig, ScriptPhaseScriptPubkey},
+			expectedOps: [][]string{
+				{"OP_PUSHBYTES_72", "OP_PUSHBYTES_33"},
+				{"OP_DUP", "OP_HASH160", "OP_PUSHBYTES_20", "OP_EQUALVERIFY"},
+			},
+			expectedCode: ScriptErrorBadOpcode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scriptPubkeyBytes, err := hex.DecodeString(tt.scriptPubkeyHex)
+			if err != nil {
+				t.Fatalf("Failed to decode script pubkey hex: %v", err)
+			}
+			scriptPubkey := NewScriptPubkey(scriptPubkeyBytes)
+			defer scriptPubkey.Destroy()
+
+			txToBytes, err := hex.DecodeString(tt.txToHex)
+			if err != nil {
+				t.Fatalf("Failed to decode transaction hex: %v", err)
+			}
+			txTo, err := NewTransaction(txToBytes)
+			if err != nil {
+				t.Fatalf("Failed to create transaction: %v", err)
+			}
+			defer txTo.Destroy()
+
+			flags := ScriptFlags(ScriptFlagsVerifyAll &^ ScriptFlagsVerifyTaproot)
+			trace, err := scriptPubkey.Trace(tt.amount, txTo, nil, 0, flags)
+			if err != nil {
+				t.Fatalf("Trace() error = %v", err)
+			}
+
+			valid, _ := scriptPubkey.Verify(tt.amount, txTo, nil, 0, flags)
+			if trace.Valid != valid {
+				t.Errorf("Trace() valid = %v, Verify() valid = %v", trace.Valid, valid)
+			}
+			if tt.expectedCode == ScriptErrorOK {
+				if trace.Err != nil {
+					t.Errorf("Expected no script error, got %v", trace.Err)
+				}
+			} else if trace.Err == nil || trace.Err.Code != tt.expectedCode {
+				t.Errorf("Expected script error %q, got %v", tt.expectedCode, trace.Err)
+			}
+
+			if len(trace.Scripts) != len(tt.expectedPhases) {
+				t.Fatalf("Expected %d traced scripts, got %d", len(tt.expectedPhases), len(trace.Scripts))
+			}
+			for i, script := range trace.Scripts {
+				if script.Phase != tt.expectedPhases[i] {
+					t.Errorf("Script %d: expected phase %s, got %s", i, tt.expectedPhases[i], script.Phase)
+				}
+				if len(script.Steps) != len(tt.expectedOps[i]) {
+					t.Fatalf("Script %d: expected %d steps, got %d", i, len(tt.expectedOps[i]), len(script.Steps))
+				}
+				for j, step := range script.Steps {
+					if step.OpcodeIndex != uint32(j) {
+						t.Errorf("Script %d step %d: expected opcode index %d, got %d", i, j, j, step.OpcodeIndex)
+					}
+					if step.OpcodeName() != tt.expectedOps[i][j] {
+						t.Errorf("Script %d step %d: expected %s, got %s", i, j, tt.expectedOps[i][j], step.OpcodeName())
+					}
+					if !step.Executed {
+						t.Errorf("Script %d step %d: expected executed step", i, j)
+					}
+				}
+			}
+			if hex.EncodeToString(trace.Scripts[1].Script) != tt.scriptPubkeyHex {
+				t.Errorf("Expected traced script pubkey %s, got %x", tt.scriptPubkeyHex, trace.Scripts[1].Script)
+			}
+
+			if trace.Valid {
+				last := trace.Scripts[len(trace.Scripts)-1]
+				finalStack := last.Steps[len(last.Steps)-1].Stack
+				if len(finalStack) != 1 || hex.EncodeToString(finalStack[0]) != "01" {
+					t.Errorf("Expected final stack [01], got %s", formatStack(finalStack))
+				}
+			}
+
+			if !strings.Contains(trace.String(), "scriptPubKey "+tt.scriptPubkeyHex) {
+				t.Errorf("String() does not contain the script pubkey:\n%s", trace)
+			}
+
+			data, err := json.Marshal(trace)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			var decoded struct {
+				Scripts []struct {
+					Phase string `json:"phase"`
+					Steps []struct {
+						Opcode string   `json:"opcode"`
+						Stack  []string `json:"stack"`
+					} `json:"steps"`
+				} `json:"scripts"`
+				Valid bool   `json:"valid"`
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if decoded.Valid != trace.Valid || len(decoded.Scripts) != len(trace.Scripts) {
+				t.Fatalf("Unexpected JSON trace: %s", data)
+			}
+			if decoded.Scripts[1].Phase != "scriptPubKey" || decoded.Scripts[1].Steps[0].Opcode != tt.expectedOps[1][0] {
+				t.Errorf("Unexpected JSON script pubkey trace: %s", data)
+			}
+		})
+	}
+}
+
+func TestOpcodeName(t *testing.T) {
+	tests := map[byte]string{
+		0x00: "0",
+		0x14: "OP_PUSHBYTES_20",
+		0x4c: "OP_PUSHDATA1",
+		0x4f: "-1",
+		0x51: "1",
+		0x60: "16",
+		0x61: "OP_NOP",
+		0x76: "OP_DUP",
+		0xa9: "OP_HASH160",
+		0xac: "OP_CHECKSIG",
+		0xb1: "OP_CHECKLOCKTIMEVERIFY",
+		0xba: "OP_CHECKSIGADD",
+		0xbb: "OP_UNKNOWN",
+		0xff: "OP_INVALIDOPCODE",
+	}
+	for op, expected := range tests {
+		if name := opcodeName(op); name != expected {
+			t.Errorf("opcodeName(0x%02x) = %s, expected %s", op, name, expected)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package kernel
+
+/*
+#include "bitcoinkernel.h"
+*/
+import "C"
+import (
+	"crypto/sha256"
+	"runtime"
+	"unsafe"
+
+	"github.com/stringintech/go-bitcoinkernel/wire"
+)
+
+var (
+	ErrSighashTxInputIndex         = &SighashError{"Transaction input index out of range"}
+	ErrSighashSpentOutputsMismatch = &SighashError{"Spent outputs count mismatch"}
+	ErrSighashInvalidHashType      = &SighashError{"Invalid hash type for the input"}
+)
+
+const (
+	// NoCodeSeparator is the code separator position of a tapscript signature
+	// when no OP_CODESEPARATOR was executed.
+	NoCodeSeparator uint32 = 0xFFFFFFFF
+
+	// TapscriptLeafVersion is the BIP342 leaf version of tapscript leaves.
+	TapscriptLeafVersion byte = 0xc0
+)
+
+// SighashType selects the parts of a transaction a signature commits to.
+type SighashType uint8
+
+const (
+	SighashDefault      SighashType = 0x00 // Taproot only: same as SighashAll, without a sighash byte in the signature
+	SighashAll          SighashType = 0x01 // Commit to all inputs and outputs
+	SighashNone         SighashType = 0x02 // Commit to all inputs and no outputs
+	SighashSingle       SighashType = 0x03 // Commit to all inputs and the output at the same index as the input
+	SighashAnyoneCanPay SighashType = 0x80 // Modifier: commit to the signed input only
+)
+
+// String returns the name Bitcoin Core uses for the sighash type, e.g. "ALL|ANYONECANPAY".
+func (t SighashType) String() string {
+	if t == SighashDefault {
+		return "DEFAULT"
+	}
+	var name string
+	switch t &^ SighashAnyoneCanPay {
+	case SighashAll:
+		name = "ALL"
+	case SighashNone:
+		name = "NONE"
+	case SighashSingle:
+		name = "SINGLE"
+	default:
+		return "UNKNOWN"
+	}
+	if t&SighashAnyoneCanPay != 0 {
+		name += "|ANYONECANPAY"
+	}
+	return name
+}
+
+// SignatureHashLegacy computes the signature hash that ECDSA signatures of the
+// input at inputIndex commit to when spending a non-segwit output.
+//
+// As required by consensus, the SIGHASH_SINGLE hash of an input without a
+// corresponding output is the number one.
+//
+// Parameters:
+//   - inputIndex: Index of the input being signed
+//   - scriptCode: Script pubkey, or redeem script for P2SH, of the spent output
+//   - hashType: Sighash type of the signature
+//
+// Returns the hash in internal byte order, or an error if inputIndex is out of range.
+func (t *transactionApi) SignatureHashLegacy(inputIndex uint, scriptCode []byte, hashType SighashType) ([32]byte, error) {
+	var hash [32]byte
+	if uint64(inputIndex) >= t.CountInputs() {
+		return hash, ErrSighashTxInputIndex
+	}
+	C.btck_transaction_signature_hash_legacy(t.ptr, C.uint(inputIndex),
+		unsafe.Pointer(unsafe.SliceData(scriptCode)), C.size_t(len(scriptCode)),
+		C.int32_t(hashType), (*C.uchar)(&hash[0]))
+	return hash, nil
+}
+
+// SignatureHashWitnessV0 computes the BIP143 signature hash that ECDSA signatures
+// of the input at inputIndex commit to when spending a segwit v0 output.
+//
+// Parameters:
+//   - inputIndex: Index of the input being signed
+//   - scriptCode: BIP143 script code, i.e. the witness script for P2WSH, or
+//     OP_DUP OP_HASH160 <pubkey hash> OP_EQUALVERIFY OP_CHECKSIG for P2WPKH
+//   - amount: Amount of the spent output
+//   - hashType: Sighash type of the signature
+//
+// Returns the hash in internal byte order, or an error if inputIndex is out of range.
+func (t *transactionApi) SignatureHashWitnessV0(inputIndex uint, scriptCode []byte, amount int64, hashType SighashType) ([32]byte, error) {
+	var hash [32]byte
+	if uint64(inputIndex) >= t.CountInputs() {
+		return hash, ErrSighashTxInputIndex
+	}
+	C.btck_transaction_signature_hash_witness_v0(t.ptr, C.uint(inputIndex),
+		unsafe.Pointer(unsafe.SliceData(scriptCode)), C.size_t(len(scriptCode)),
+		C.int64_t(amount), C.int32_t(hashType), (*C.uchar)(&hash[0]))
+	return hash, nil
+}
+
+// SignatureHashTaproot computes the BIP341 signature hash that Schnorr signatures
+// of the input at inputIndex commit to in a taproot key path spend.
+//
+// If the witness of the input already ends with an annex, the annex is committed to.
+//
+// Parameters:
+//   - inputIndex: Index of the input being signed
+//   - spentOutputs: Outputs spent by the transaction, one per input in input order
+//   - hashType: Sighash type of the signature
+//
+// Returns the hash in internal byte order, or an error if the arguments are
+// malformed or the hash type is invalid for the input.
+func (t *transactionApi) SignatureHashTaproot(inputIndex uint, spentOutputs []*TransactionOutput, hashType SighashType) ([32]byte, error) {
+	return t.signatureHashSchnorr(inputIndex, spentOutputs, hashType, nil, NoCodeSeparator)
+}
+
+// SignatureHashTapscript computes the BIP342 signature hash that Schnorr
+// signatures of the input at inputIndex commit to when executing a tapscript
+// in a taproot script path spend.
+//
+// If the witness of the input already ends with an annex, the annex is committed to.
+//
+// Parameters:
+//   - inputIndex: Index of the input being signed
+//   - spentOutputs: Outputs spent by the transaction, one per input in input order
+//   - hashType: Sighash type of the signature
+//   - tapleafHash: Tapleaf hash of the executed script, see TapleafHash
+//   - codeSeparatorPos: Opcode position of the last executed OP_CODESEPARATOR, or NoCodeSeparator
+//
+// Returns the hash in internal byte order, or an error if the arguments are
+// malformed or the hash type is invalid for the input.
+func (t *transactionApi) SignatureHashTapscript(inputIndex uint, spentOutputs []*TransactionOutput, hashType SighashType,
+	tapleafHash [32]byte, codeSeparatorPos uint32) ([32]byte, error) {
+	return t.signatureHashSchnorr(inputIndex, spentOutputs, hashType, &tapleafHash, codeSeparatorPos)
+}
+
+func (t *transactionApi) signatureHashSchnorr(inputIndex uint, spentOutputs []*TransactionOutput, hashType SighashType,
+	tapleafHash *[32]byte, codeSeparatorPos uint32) ([32]byte, error) {
+	var hash [32]byte
+	inputCount := t.CountInputs()
+	if uint64(inputIndex) >= inputCount {
+		return hash, ErrSighashTxInputIndex
+	}
+	if uint64(len(spentOutputs)) != inputCount {
+		return hash, ErrSighashSpentOutputsMismatch
+	}
+
+	cSpentOutputs := make([]*C.btck_TransactionOutput, len(spentOutputs))
+	for i, output := range spentOutputs {
+		cSpentOutputs[i] = (*C.btck_TransactionOutput)(output.handle.ptr)
+	}
+	var cTapleafHash *C.uchar
+	if tapleafHash != nil {
+		cTapleafHash = (*C.uchar)(&tapleafHash[0])
+	}
+	result := C.btck_transaction_signature_hash_taproot(t.ptr, C.uint(inputIndex),
+		(**C.btck_TransactionOutput)(unsafe.Pointer(&cSpentOutputs[0])), C.size_t(len(cSpentOutputs)),
+		C.uint8_t(hashType), cTapleafHash, C.uint32_t(codeSeparatorPos), (*C.uchar)(&hash[0]))
+	runtime.KeepAlive(spentOutputs)
+	if result != 0 {
+		return hash, ErrSighashInvalidHashType
+	}
+	return hash, nil
+}
+
+// TapleafHash computes the BIP341 tapleaf hash of a script, as committed to by
+// the taproot output key and by tapscript signature hashes.
+//
+// Parameters:
+//   - leafVersion: Leaf version of the script, TapscriptLeafVersion for tapscript
+//   - script: The leaf script
+func TapleafHash(leafVersion byte, script []byte) [32]byte {
+	tag := sha256.Sum256([]byte("TapLeaf"))
+	h := sha256.New()
+	h.Write(tag[:])
+	h.Write(tag[:])
+	h.Write([]byte{leafVersion})
+	wire.WriteVarBytes(h, script)
+	var hash [32]byte
+	copy(hash[:], h.Sum(nil))
+	return hash
+}
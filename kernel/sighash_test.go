@@ -0,0 +1,177 @@
+package kernel
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stringintech/go-bitcoinkernel/wire"
+)
+
+// sighashTestTxHex is the unsigned native P2WPKH transaction of the BIP143 test vectors.
+const sighashTestTxHex = "0100000002fff7f7881a8099afa6940d42d1e7f6362bec38171ea3edf433541db4e4ad969f0000000000eeffffffef51e1b804cc89d182d279655c3aa89e815b1b309fe287d9b2b55d57b90ec68a0100000000ffffffff02202cb206000000001976a9148280b37df378db99f66f85c95a783a76ac7a6d5988ac9093510d000000001976a9143bde42dbee7e4dbe6a21b2d50ce2f0167faa815988ac11000000"
+
+func newSighashTestTx(t *testing.T) *Transaction {
+	t.Helper()
+	txBytes, err := hex.DecodeString(sighashTestTxHex)
+	if err != nil {
+		t.Fatalf("Failed to decode transaction hex: %v", err)
+	}
+	tx, err := NewTransaction(txBytes)
+	if err != nil {
+		t.Fatalf("NewTransaction() error = %v", err)
+	}
+	return tx
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("Failed to decode hex %q: %v", s, err)
+	}
+	return b
+}
+
+func TestSignatureHashWitnessV0(t *testing.T) {
+	tx := newSighashTestTx(t)
+	defer tx.Destroy()
+
+	// Second input of the BIP143 native P2WPKH example
+	scriptCode := mustDecodeHex(t, "76a9141d0f172a0ecb48aee1be1f2687d2963ae33f71a188ac")
+	hash, err := tx.SignatureHashWitnessV0(1, scriptCode, 600000000, SighashAll)
+	if err != nil {
+		t.Fatalf("SignatureHashWitnessV0() error = %v", err)
+	}
+	expected := "c37af31116d1b27caf68aae9e3ac82f1477929014d5b917657d0eb49478cb670"
+	if hex.EncodeToString(hash[:]) != expected {
+		t.Errorf("Expected sighash %s, got %x", expected, hash)
+	}
+
+	if _, err := tx.SignatureHashWitnessV0(2, scriptCode, 600000000, SighashAll); !errors.Is(err, ErrSighashTxInputIndex) {
+		t.Errorf("Expected ErrSighashTxInputIndex, got %v", err)
+	}
+}
+
+func TestSignatureHashLegacy(t *testing.T) {
+	tx := newSighashTestTx(t)
+	defer tx.Destroy()
+
+	scriptCode := mustDecodeHex(t, "76a9141d0f172a0ecb48aee1be1f2687d2963ae33f71a188ac")
+	hash, err := tx.SignatureHashLegacy(0, scriptCode, SighashAll)
+	if err != nil {
+		t.Fatalf("SignatureHashLegacy() error = %v", err)
+	}
+	expected := "47194bc3c303a30aa5f78e45c7c2980b3be1284a9d69b1ea9ec0d29aac5f6848"
+	if hex.EncodeToString(hash[:]) != expected {
+		t.Errorf("Expected sighash %s, got %x", expected, hash)
+	}
+
+	// SIGHASH_SINGLE for an input without a corresponding output hashes to one
+	msg := wire.NewMsgTx(1)
+	msg.AddTxIn(wire.NewTxIn(wire.NewOutPoint(wire.Hash{1}, 0), nil, nil))
+	msg.AddTxIn(wire.NewTxIn(wire.NewOutPoint(wire.Hash{2}, 0), nil, nil))
+	msg.AddTxOut(wire.NewTxOut(1000, scriptCode))
+	singleTx, err := NewTransactionFromWire(msg)
+	if err != nil {
+		t.Fatalf("NewTransactionFromWire() error = %v", err)
+	}
+	defer singleTx.Destroy()
+
+	hash, err = singleTx.SignatureHashLegacy(1, scriptCode, SighashSingle)
+	if err != nil {
+		t.Fatalf("SignatureHashLegacy() error = %v", err)
+	}
+	if hash != [32]byte{1} {
+		t.Errorf("Expected SIGHASH_SINGLE bug hash, got %x", hash)
+	}
+}
+
+func TestSignatureHashTaproot(t *testing.T) {
+	tx := newSighashTestTx(t)
+	defer tx.Destroy()
+
+	spentOutputs := []*TransactionOutput{
+		NewTransactionOutput(NewScriptPubkey(mustDecodeHex(t, "51200101010101010101010101010101010101010101010101010101010101010101")), 625000000),
+		NewTransactionOutput(NewScriptPubkey(mustDecodeHex(t, "00141d0f172a0ecb48aee1be1f2687d2963ae33f71a1")), 600000000),
+	}
+	defer func() {
+		for _, output := range spentOutputs {
+			output.Destroy()
+		}
+	}()
+
+	tests := []struct {
+		name       string
+		inputIndex uint
+		hashType   SighashType
+		expected   string
+	}{
+		{"default", 0, SighashDefault, "6d5e8ad7bc936d6ce1fb6b4e5761a2bd4f8db8b86dcddabce8aef8a08469d27a"},
+		{"all", 0, SighashAll, "d5f38962b0f4c37a05db7b4747bd9dc2080993f521aaae3acbdc67484c62db1c"},
+		{"all_anyonecanpay", 1, SighashAll | SighashAnyoneCanPay, "6261f37433ce2a2e0bafac6afdc2e5fd741962458fadabc74771b96aa344594b"},
+		{"single", 0, SighashSingle, "6ab56718a29840cdabd14dcf45e1e45fff3dc3eeac7f9aef1ff30c5eca1e7880"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := tx.SignatureHashTaproot(tt.inputIndex, spentOutputs, tt.hashType)
+			if err != nil {
+				t.Fatalf("SignatureHashTaproot() error = %v", err)
+			}
+			if hex.EncodeToString(hash[:]) != tt.expected {
+				t.Errorf("Expected sighash %s, got %x", tt.expected, hash)
+			}
+		})
+	}
+
+	t.Run("tapscript", func(t *testing.T) {
+		leafHash := TapleafHash(TapscriptLeafVersion, mustDecodeHex(t, "20d85a959b0290bf19bb89ed43c916be835475d013da4b362117393e25a48229b8ac"))
+		hash, err := tx.SignatureHashTapscript(0, spentOutputs, SighashDefault, leafHash, NoCodeSeparator)
+		if err != nil {
+			t.Fatalf("SignatureHashTapscript() error = %v", err)
+		}
+		expected := "8635b0f440e5937bc3c4d163175dde729463441791bc761e911bc33fcab5f848"
+		if hex.EncodeToString(hash[:]) != expected {
+			t.Errorf("Expected sighash %s, got %x", expected, hash)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := tx.SignatureHashTaproot(0, spentOutputs, SighashType(0x04)); !errors.Is(err, ErrSighashInvalidHashType) {
+			t.Errorf("Expected ErrSighashInvalidHashType for hash type 0x04, got %v", err)
+		}
+		if _, err := tx.SignatureHashTaproot(0, spentOutputs[:1], SighashDefault); !errors.Is(err, ErrSighashSpentOutputsMismatch) {
+			t.Errorf("Expected ErrSighashSpentOutputsMismatch, got %v", err)
+		}
+		if _, err := tx.SignatureHashTaproot(2, spentOutputs, SighashDefault); !errors.Is(err, ErrSighashTxInputIndex) {
+			t.Errorf("Expected ErrSighashTxInputIndex, got %v", err)
+		}
+	})
+}
+
+func TestTapleafHash(t *testing.T) {
+	// BIP341 wallet test vector
+	script := mustDecodeHex(t, "20d85a959b0290bf19bb89ed43c916be835475d013da4b362117393e25a48229b8ac")
+	hash := TapleafHash(TapscriptLeafVersion, script)
+	expected := "5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21"
+	if hex.EncodeToString(hash[:]) != expected {
+		t.Errorf("Expected leaf hash %s, got %x", expected, hash)
+	}
+}
+
+func TestSighashTypeString(t *testing.T) {
+	tests := map[SighashType]string{
+		SighashDefault:                      "DEFAULT",
+		SighashAll:                          "ALL",
+		SighashNone | SighashAnyoneCanPay:   "NONE|ANYONECANPAY",
+		SighashSingle:                       "SINGLE",
+		SighashAnyoneCanPay:                 "UNKNOWN",
+		SighashType(0x04):                   "UNKNOWN",
+		SighashSingle | SighashAnyoneCanPay: "SINGLE|ANYONECANPAY",
+	}
+	for hashType, expected := range tests {
+		if hashType.String() != expected {
+			t.Errorf("SighashType(0x%02x).String() = %s, expected %s", uint8(hashType), hashType.String(), expected)
+		}
+	}
+}
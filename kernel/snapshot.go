@@ -0,0 +1,106 @@
+package kernel
+
+/*
+#include "bitcoinkernel.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+// DumpSnapshot writes the UTXO set of the active chainstate to w as an assumeutxo
+// snapshot in the format of Bitcoin Core's dumptxoutset.
+//
+// The chainstate is flushed to disk first. Block processing is only blocked while
+// the coins are counted, the snapshot is then written from a consistent view of
+// the coins database.
+//
+// Returns the error of w if writing to it failed, or an error if the UTXO set
+// could not be read.
+func (cm *ChainstateManager) DumpSnapshot(w io.Writer) error {
+	ok, err := writeToWriter(w, func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+		return C.btck_chainstate_manager_dump_snapshot((*C.btck_ChainstateManager)(cm.ptr), writer, userData)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if !ok {
+		return &InternalError{"Failed to dump snapshot"}
+	}
+	return nil
+}
+
+// LoadSnapshot reads an assumeutxo snapshot in the format of Bitcoin Core's
+// dumptxoutset from r and activates a chainstate based on it, see
+// LoadSnapshotFile.
+//
+// The snapshot is streamed into a temporary file in the data directory of the
+// chainstate manager, which is removed once the snapshot is loaded. Use
+// LoadSnapshotFile to load a snapshot that is already stored in a file.
+//
+// Returns the error of r if reading from it failed, or an error if the snapshot
+// could not be activated.
+func (cm *ChainstateManager) LoadSnapshot(r io.Reader) error {
+	ok, err := readFromReader(r, func(reader C.btck_ReadBytes, userData unsafe.Pointer) C.int {
+		return C.btck_chainstate_manager_load_snapshot_from_reader((*C.btck_ChainstateManager)(cm.ptr), reader, userData)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if !ok {
+		return &InternalError{"Failed to load snapshot"}
+	}
+	return nil
+}
+
+// LoadSnapshotFile loads an assumeutxo snapshot in the format of Bitcoin Core's
+// dumptxoutset from the file at path and activates a chainstate based on it.
+//
+// The base block of the snapshot must be one of the assumeutxo entries of the
+// chain parameters and its header must already be known to the chainstate
+// manager. Once the snapshot chainstate is active, blocks passed to ProcessBlock
+// extend it, while the blocks up to its base are validated in the background.
+// The OnSnapshotActivated and OnSnapshotValidated notification callbacks report
+// the progress of the snapshot, a snapshot failing background validation is
+// reported through OnFatalError.
+//
+// Returns an error if the snapshot could not be loaded or does not match the
+// assumeutxo entry of its base block.
+func (cm *ChainstateManager) LoadSnapshotFile(path string) error {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	if C.btck_chainstate_manager_load_snapshot((*C.btck_ChainstateManager)(cm.ptr), cPath, C.size_t(len(path))) != 0 {
+		return &InternalError{"Failed to load snapshot"}
+	}
+	return nil
+}
+
+// SnapshotBaseBlock returns the base block of the snapshot the chainstate manager
+// uses, or nil if no snapshot was loaded.
+//
+// The returned BlockTreeEntry is a non-owned pointer valid for the lifetime of
+// this chainstate manager.
+func (cm *ChainstateManager) SnapshotBaseBlock() *BlockTreeEntry {
+	ptr := C.btck_chainstate_manager_get_snapshot_base_block((*C.btck_ChainstateManager)(cm.ptr))
+	if ptr == nil {
+		return nil
+	}
+	return &BlockTreeEntry{ptr: ptr}
+}
+
+// BackgroundValidationTip returns the tip of the background chainstate validating
+// the loaded snapshot, or nil if no background validation is in progress.
+//
+// The returned BlockTreeEntry is a non-owned pointer valid for the lifetime of
+// this chainstate manager.
+func (cm *ChainstateManager) BackgroundValidationTip() *BlockTreeEntry {
+	ptr := C.btck_chainstate_manager_get_background_validation_tip((*C.btck_ChainstateManager)(cm.ptr))
+	if ptr == nil {
+		return nil
+	}
+	return &BlockTreeEntry{ptr: ptr}
+}
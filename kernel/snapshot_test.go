@@ -0,0 +1,206 @@
+package kernel
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type failingWriter struct{ err error }
+
+func (w failingWriter) Write([]byte) (int, error) { return 0, w.err }
+
+type failingReader struct{ err error }
+
+func (r failingReader) Read([]byte) (int, error) { return 0, r.err }
+
+func (s *ChainstateManagerTestSuite) TestSnapshot(t *testing.T) {
+	chain := s.Manager.GetActiveChain()
+	tip := chain.GetByHeight(chain.GetHeight())
+
+	stats, err := s.Manager.UTXOSetStats()
+	if err != nil {
+		t.Fatalf("UTXOSetStats() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := s.Manager.DumpSnapshot(&buf); err != nil {
+		t.Fatalf("DumpSnapshot() error = %v", err)
+	}
+	data := buf.Bytes()
+
+	// Metadata: magic, version, network magic, base block hash and coin count
+	if len(data) < 51 {
+		t.Fatalf("Expected snapshot with metadata, got %d bytes", len(data))
+	}
+	if !bytes.Equal(data[:5], []byte("utxo\xff")) || binary.LittleEndian.Uint16(data[5:7]) != 2 {
+		t.Errorf("Expected snapshot magic and version 2, got %x", data[:7])
+	}
+	if !bytes.Equal(data[7:11], []byte{0xfa, 0xbf, 0xb5, 0xda}) {
+		t.Errorf("Expected regtest network magic, got %x", data[7:11])
+	}
+	if [32]byte(data[11:43]) != tip.Hash().Bytes() {
+		t.Error("Expected snapshot to be based on the chain tip")
+	}
+	if coins := binary.LittleEndian.Uint64(data[43:51]); coins != stats.Coins {
+		t.Errorf("Expected %d coins, got %d", stats.Coins, coins)
+	}
+
+	writeErr := errors.New("disk full")
+	if err := s.Manager.DumpSnapshot(failingWriter{writeErr}); !errors.Is(err, writeErr) {
+		t.Errorf("Expected DumpSnapshot() to return the writer error, got %v", err)
+	}
+
+	// The test chain is not among the assumeutxo entries of the regtest parameters
+	var internalErr *InternalError
+	if err := s.Manager.LoadSnapshot(bytes.NewReader(data)); !errors.As(err, &internalErr) {
+		t.Errorf("Expected LoadSnapshot() to fail with *InternalError, got %v", err)
+	}
+	if s.Manager.SnapshotBaseBlock() != nil || s.Manager.BackgroundValidationTip() != nil {
+		t.Error("Expected no snapshot to be in use")
+	}
+	if err := s.Manager.LoadSnapshotFile(t.TempDir() + "/missing.dat"); err == nil {
+		t.Error("Expected LoadSnapshotFile() of a missing file to fail")
+	}
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	const baseHeight = 110
+	source := ChainstateManagerTestSuite{MaxBlockHeightToImport: baseHeight}
+	source.Setup(t)
+
+	var snapshot bytes.Buffer
+	if err := source.Manager.DumpSnapshot(&snapshot); err != nil {
+		t.Fatalf("DumpSnapshot() error = %v", err)
+	}
+	stats, err := source.Manager.UTXOSetStats()
+	if err != nil {
+		t.Fatalf("UTXOSetStats() error = %v", err)
+	}
+
+	// Register the snapshot of the test chain like the hardcoded assumeutxo
+	// entries, counting the genesis coinbase towards the transactions
+	blocks := make([]*Block, baseHeight)
+	headers := make([]*BlockHeader, baseHeight)
+	chainTxCount := uint64(1)
+	for i := range blocks {
+		blocks[i] = readRegtestBlock(t, i+1)
+		defer blocks[i].Destroy()
+		headers[i] = blocks[i].Header()
+		defer headers[i].Destroy()
+		chainTxCount += blocks[i].CountTransactions()
+	}
+	baseHash := blocks[baseHeight-1].Hash()
+	defer baseHash.Destroy()
+
+	chainParams, err := NewChainParameters(ChainTypeRegtest)
+	if err != nil {
+		t.Fatalf("NewChainParameters() error = %v", err)
+	}
+	defer chainParams.Destroy()
+	if err := chainParams.AddAssumeutxo(baseHeight, baseHash, stats.HashSerialized, chainTxCount); err != nil {
+		t.Fatalf("AddAssumeutxo() error = %v", err)
+	}
+	if err := chainParams.AddAssumeutxo(baseHeight, baseHash, stats.HashSerialized, chainTxCount); err == nil {
+		t.Error("Expected AddAssumeutxo() of a duplicate entry to fail")
+	}
+
+	var activated, validated []int32
+	ctx, err := NewContext(WithChainParameters(chainParams), WithNotifications(&NotificationCallbacks{
+		OnSnapshotActivated: func(base *BlockTreeEntry) { activated = append(activated, base.Height()) },
+		OnSnapshotValidated: func(base *BlockTreeEntry) { validated = append(validated, base.Height()) },
+	}))
+	if err != nil {
+		t.Fatalf("NewContext() error = %v", err)
+	}
+	defer ctx.Destroy()
+	tempDir := t.TempDir()
+	manager, err := NewChainstateManager(ctx, filepath.Join(tempDir, "data"), filepath.Join(tempDir, "blocks"),
+		WithBlockTreeDBInMemory(true), WithChainstateDBInMemory(), WithWipeDBs(true, true))
+	if err != nil {
+		t.Fatalf("NewChainstateManager() error = %v", err)
+	}
+	defer manager.Destroy()
+	if err := manager.ImportBlocks(nil); err != nil {
+		t.Fatalf("ImportBlocks() error = %v", err)
+	}
+
+	// The base block header has to be known before the snapshot is loaded
+	if err := manager.ProcessHeaders(headers); err != nil {
+		t.Fatalf("ProcessHeaders() error = %v", err)
+	}
+	readErr := errors.New("connection reset")
+	if err := manager.LoadSnapshot(io.MultiReader(bytes.NewReader(snapshot.Bytes()[:100]), failingReader{readErr})); !errors.Is(err, readErr) {
+		t.Errorf("Expected LoadSnapshot() to return the reader error, got %v", err)
+	}
+	if manager.SnapshotBaseBlock() != nil {
+		t.Error("Expected no snapshot to be loaded from a failing reader")
+	}
+	if err := manager.LoadSnapshot(bytes.NewReader(snapshot.Bytes())); err != nil {
+		t.Fatalf("LoadSnapshot() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "data", "utxo-snapshot.dat.incomplete")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected the temporary snapshot file to be removed, got %v", err)
+	}
+	chain := manager.GetActiveChain()
+	base := manager.SnapshotBaseBlock()
+	if base == nil || base.Height() != baseHeight || base.Hash().Bytes() != baseHash.Bytes() {
+		t.Fatal("Expected the snapshot to be based on the registered block")
+	}
+	if len(activated) != 1 || activated[0] != baseHeight {
+		t.Errorf("Expected OnSnapshotActivated for height %d, got %v", baseHeight, activated)
+	}
+	if height := chain.GetHeight(); height != baseHeight {
+		t.Errorf("Expected active chain at the snapshot base, got height %d", height)
+	}
+	if tip := manager.BackgroundValidationTip(); tip == nil || tip.Height() != 0 {
+		t.Fatal("Expected background validation to start at genesis")
+	}
+	loaded, err := manager.UTXOSetStats()
+	if err != nil {
+		t.Fatalf("UTXOSetStats() error = %v", err)
+	}
+	if loaded.HashSerialized != stats.HashSerialized || loaded.Coins != stats.Coins {
+		t.Error("Expected the active UTXO set to match the snapshot")
+	}
+	if err := manager.LoadSnapshot(bytes.NewReader(snapshot.Bytes())); err == nil {
+		t.Error("Expected a second LoadSnapshot() to fail")
+	}
+
+	// Processing the blocks below the base validates the snapshot in the background
+	for i, block := range blocks[:baseHeight-1] {
+		if _, err := manager.ProcessBlock(block); err != nil {
+			t.Fatalf("ProcessBlock() error = %v at height %d", err, i+1)
+		}
+	}
+	if tip := manager.BackgroundValidationTip(); tip == nil || tip.Height() != baseHeight-1 {
+		t.Fatal("Expected background validation to follow the processed blocks")
+	}
+	if len(validated) != 0 {
+		t.Errorf("Expected no OnSnapshotValidated before reaching the base, got %v", validated)
+	}
+	if _, err := manager.ProcessBlock(blocks[baseHeight-1]); err != nil {
+		t.Fatalf("ProcessBlock() error = %v", err)
+	}
+	if len(validated) != 1 || validated[0] != baseHeight {
+		t.Errorf("Expected OnSnapshotValidated for height %d, got %v", baseHeight, validated)
+	}
+	if manager.BackgroundValidationTip() != nil {
+		t.Error("Expected background validation to be finished")
+	}
+
+	// The snapshot chainstate keeps extending the active chain
+	next := readRegtestBlock(t, baseHeight+1)
+	defer next.Destroy()
+	if _, err := manager.ProcessBlock(next); err != nil {
+		t.Fatalf("ProcessBlock() error = %v", err)
+	}
+	if height := chain.GetHeight(); height != baseHeight+1 {
+		t.Errorf("Expected active chain height %d, got %d", baseHeight+1, height)
+	}
+}
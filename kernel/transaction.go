@@ -6,6 +6,7 @@ package kernel
 import "C"
 import (
 	"iter"
+	"runtime"
 	"unsafe"
 )
 
@@ -43,6 +44,24 @@ func NewTransaction(rawTransaction []byte) (*Transaction, error) {
 	return newTransaction(ptr, true), nil
 }
 
+// CheckTransaction checks the transaction against the consensus rules that do
+// not depend on the chain: it has inputs and outputs, its output values are in
+// range, it spends no output twice and it does not exceed the size limit.
+// Scripts are not verified.
+//
+// Returns a *TransactionValidationError if the transaction violates a rule.
+func CheckTransaction(tx *Transaction) error {
+	statePtr := C.btck_tx_validation_state_create()
+	defer C.btck_tx_validation_state_destroy(statePtr)
+
+	result := C.btck_transaction_check((*C.btck_Transaction)(tx.handle.ptr), statePtr)
+	runtime.KeepAlive(tx)
+	if result != 0 {
+		return newTransactionValidationError(&TxValidationState{ptr: statePtr})
+	}
+	return nil
+}
+
 type TransactionView struct {
 	transactionApi
 	ptr *C.btck_Transaction
@@ -86,6 +105,46 @@ func (t *transactionApi) GetTxid() *TxidView {
 	return newTxidView(check(ptr))
 }
 
+// GetWtxid returns the 32-byte witness hash (wtxid) of this transaction.
+//
+// For transactions without witness data, the wtxid is equal to the txid.
+func (t *transactionApi) GetWtxid() [32]byte {
+	var output [32]C.uchar
+	C.btck_transaction_get_wtxid(t.ptr, &output[0])
+	return *(*[32]byte)(unsafe.Pointer(&output[0]))
+}
+
+// Version returns the transaction version.
+func (t *transactionApi) Version() uint32 {
+	return uint32(C.btck_transaction_get_version(t.ptr))
+}
+
+// LockTime returns the transaction lock time (nLockTime).
+func (t *transactionApi) LockTime() uint32 {
+	return uint32(C.btck_transaction_get_locktime(t.ptr))
+}
+
+// IsCoinbase returns true if this is a coinbase transaction.
+func (t *transactionApi) IsCoinbase() bool {
+	return C.btck_transaction_is_coinbase(t.ptr) != 0
+}
+
+// HasWitness returns true if any of the transaction's inputs carry witness data.
+func (t *transactionApi) HasWitness() bool {
+	return C.btck_transaction_has_witness(t.ptr) != 0
+}
+
+// Weight returns the transaction weight as defined in BIP141.
+func (t *transactionApi) Weight() int64 {
+	return int64(C.btck_transaction_get_weight(t.ptr))
+}
+
+// VSize returns the virtual size of the transaction, which is its weight divided
+// by four and rounded up.
+func (t *transactionApi) VSize() int64 {
+	return (t.Weight() + 3) / 4
+}
+
 // CountInputs returns the number of inputs in the transaction.
 func (t *transactionApi) CountInputs() uint64 {
 	return uint64(C.btck_transaction_count_inputs(t.ptr))
@@ -18,7 +18,8 @@ func (transactionInputCFuncs) copy(ptr unsafe.Pointer) unsafe.Pointer {
 	return unsafe.Pointer(C.btck_transaction_input_copy((*C.btck_TransactionInput)(ptr)))
 }
 
-// TransactionInput holds information on the TransactionOutPoint held within.
+// TransactionInput holds the TransactionOutPoint it spends along with its sequence
+// number, script sig and witness stack.
 type TransactionInput struct {
 	*handle
 	transactionInputApi
@@ -29,7 +30,8 @@ func newTransactionInput(ptr *C.btck_TransactionInput, fromOwned bool) *Transact
 	return &TransactionInput{handle: h, transactionInputApi: transactionInputApi{(*C.btck_TransactionInput)(h.ptr)}}
 }
 
-// TransactionInputView holds information on the TransactionOutPoint held within.
+// TransactionInputView holds the TransactionOutPoint it spends along with its sequence
+// number, script sig and witness stack.
 type TransactionInputView struct {
 	transactionInputApi
 	ptr *C.btck_TransactionInput
@@ -56,3 +58,62 @@ func (t *transactionInputApi) GetOutPoint() *TransactionOutPointView {
 	ptr := C.btck_transaction_input_get_out_point(t.ptr)
 	return newTransactionOutPointView(check(ptr))
 }
+
+// Sequence returns the sequence number (nSequence) of the input.
+func (t *transactionInputApi) Sequence() uint32 {
+	return uint32(C.btck_transaction_input_get_sequence(t.ptr))
+}
+
+// ScriptSig returns the serialized script sig of the input.
+//
+// Returns an error if the serialization fails.
+func (t *transactionInputApi) ScriptSig() ([]byte, error) {
+	bytes, ok := writeToBytes(func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+		return C.btck_transaction_input_script_sig_to_bytes(t.ptr, writer, userData)
+	})
+	if !ok {
+		return nil, &SerializationError{"Failed to serialize script sig"}
+	}
+	return bytes, nil
+}
+
+// CountWitnessItems returns the number of items on the witness stack of the input.
+func (t *transactionInputApi) CountWitnessItems() uint64 {
+	return uint64(C.btck_transaction_input_count_witness_items(t.ptr))
+}
+
+// GetWitnessItem retrieves the witness stack item at the specified index.
+//
+// Parameters:
+//   - index: Index of the witness stack item to retrieve
+//
+// Returns an error if the index is out of bounds or the serialization fails.
+func (t *transactionInputApi) GetWitnessItem(index uint64) ([]byte, error) {
+	if index >= t.CountWitnessItems() {
+		return nil, ErrKernelIndexOutOfBounds
+	}
+	bytes, ok := writeToBytes(func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+		return C.btck_transaction_input_witness_item_to_bytes(t.ptr, C.size_t(index), writer, userData)
+	})
+	if !ok {
+		return nil, &SerializationError{"Failed to serialize witness item"}
+	}
+	return bytes, nil
+}
+
+// Witness returns all items on the witness stack of the input, bottom first.
+//
+// Returns an empty stack for inputs without witness data, or an error if the
+// serialization fails.
+func (t *transactionInputApi) Witness() ([][]byte, error) {
+	count := t.CountWitnessItems()
+	witness := make([][]byte, 0, count)
+	for i := uint64(0); i < count; i++ {
+		item, err := t.GetWitnessItem(i)
+		if err != nil {
+			return nil, err
+		}
+		witness = append(witness, item)
+	}
+	return witness, nil
+}
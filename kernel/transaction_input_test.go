@@ -1,7 +1,9 @@
 package kernel
 
 import (
+	"bytes"
 	"encoding/hex"
+	"errors"
 	"testing"
 )
 
@@ -58,3 +60,87 @@ func TestTransactionInput(t *testing.T) {
 		t.Errorf("OutPoint indices differ: %d != %d", outPoint.GetIndex(), copiedOutPoint.GetIndex())
 	}
 }
+
+func TestTransactionInputFields(t *testing.T) {
+	txBytes, err := hex.DecodeString(coinbaseTxHex)
+	if err != nil {
+		t.Fatalf("Failed to decode transaction hex: %v", err)
+	}
+
+	tx, err := NewTransaction(txBytes)
+	if err != nil {
+		t.Fatalf("NewTransaction() error = %v", err)
+	}
+	defer tx.Destroy()
+
+	input, err := tx.GetInput(0)
+	if err != nil {
+		t.Fatalf("GetInput(0) error = %v", err)
+	}
+
+	if input.Sequence() != 0xffffffff {
+		t.Errorf("Expected sequence 0xffffffff, got %#x", input.Sequence())
+	}
+
+	scriptSig, err := input.ScriptSig()
+	if err != nil {
+		t.Fatalf("ScriptSig() error = %v", err)
+	}
+	if hex.EncodeToString(scriptSig) != "044c86041b020602" {
+		t.Errorf("Expected script sig 044c86041b020602, got %x", scriptSig)
+	}
+
+	if input.CountWitnessItems() != 0 {
+		t.Errorf("Expected 0 witness items, got %d", input.CountWitnessItems())
+	}
+	witness, err := input.Witness()
+	if err != nil {
+		t.Fatalf("Witness() error = %v", err)
+	}
+	if len(witness) != 0 {
+		t.Errorf("Expected empty witness, got %d items", len(witness))
+	}
+}
+
+func TestTransactionInputWitness(t *testing.T) {
+	txBytes, err := hex.DecodeString(segwitCoinbaseTxHex)
+	if err != nil {
+		t.Fatalf("Failed to decode transaction hex: %v", err)
+	}
+
+	tx, err := NewTransaction(txBytes)
+	if err != nil {
+		t.Fatalf("NewTransaction() error = %v", err)
+	}
+	defer tx.Destroy()
+
+	input, err := tx.GetInput(0)
+	if err != nil {
+		t.Fatalf("GetInput(0) error = %v", err)
+	}
+
+	if input.CountWitnessItems() != 1 {
+		t.Fatalf("Expected 1 witness item, got %d", input.CountWitnessItems())
+	}
+
+	item, err := input.GetWitnessItem(0)
+	if err != nil {
+		t.Fatalf("GetWitnessItem(0) error = %v", err)
+	}
+	if !bytes.Equal(item, make([]byte, 32)) {
+		t.Errorf("Expected 32-byte zero witness nonce, got %x", item)
+	}
+
+	_, err = input.GetWitnessItem(input.CountWitnessItems())
+	if !errors.Is(err, ErrKernelIndexOutOfBounds) {
+		t.Errorf("Expected ErrKernelIndexOutOfBounds for out of bounds witness item, got %v", err)
+	}
+
+	witness, err := input.Witness()
+	if err != nil {
+		t.Fatalf("Witness() error = %v", err)
+	}
+	if len(witness) != 1 || !bytes.Equal(witness[0], item) {
+		t.Errorf("Witness() = %x, expected [%x]", witness, item)
+	}
+}
@@ -29,6 +29,16 @@ func newTransactionOutPoint(ptr *C.btck_TransactionOutPoint, fromOwned bool) *Tr
 	return &TransactionOutPoint{handle: h, transactionOutPointApi: transactionOutPointApi{(*C.btck_TransactionOutPoint)(h.ptr)}}
 }
 
+// NewTransactionOutPoint creates a new out point referencing an output of a transaction.
+//
+// Parameters:
+//   - txid: 32-byte txid in internal byte order, as returned by Txid.Bytes
+//   - index: Index of the output in the transaction
+func NewTransactionOutPoint(txid [32]byte, index uint32) *TransactionOutPoint {
+	ptr := C.btck_transaction_out_point_create((*C.uchar)(unsafe.Pointer(&txid[0])), C.uint32_t(index))
+	return newTransactionOutPoint(check(ptr), true)
+}
+
 // TransactionOutPointView holds the txid and output index it is pointing to.
 type TransactionOutPointView struct {
 	transactionOutPointApi
@@ -46,6 +56,15 @@ type transactionOutPointApi struct {
 	ptr *C.btck_TransactionOutPoint
 }
 
+func (t *transactionOutPointApi) outPointPtr() *C.btck_TransactionOutPoint {
+	return t.ptr
+}
+
+// TransactionOutPointLike is an interface for types that can provide a transaction out point pointer.
+type TransactionOutPointLike interface {
+	outPointPtr() *C.btck_TransactionOutPoint
+}
+
 // Copy creates a copy of the transaction out point.
 func (t *transactionOutPointApi) Copy() *TransactionOutPoint {
 	return newTransactionOutPoint(t.ptr, false)
@@ -5,11 +5,16 @@ import (
 	"errors"
 	"slices"
 	"testing"
+
+	"github.com/stringintech/go-bitcoinkernel/wire"
 )
 
 // coinbaseTxHex is a serialized coinbase transaction for testing
 const coinbaseTxHex = "01000000010000000000000000000000000000000000000000000000000000000000000000ffffffff08044c86041b020602ffffffff0100f2052a010000004341041b0e8c2567c12536aa13357b79a073dc4444acb83c4ec7a0e2f99dd7457516c5817242da796924ca4e99947d087fedf9ce467cb9f7c6287078f801df276fdf84ac00000000"
 
+// segwitCoinbaseTxHex is the serialized coinbase transaction of the second regtest block, carrying a witness nonce
+const segwitCoinbaseTxHex = "020000000001010000000000000000000000000000000000000000000000000000000000000000ffffffff025200ffffffff0200f2052a010000001600141409745405c4e8310a875bcd602db6b9b3dc0cf90000000000000000266a24aa21a9ede2f61c3f71d1defd3fa999dfa36953755c690689799962b48bebd836974e8cf90120000000000000000000000000000000000000000000000000000000000000000000000000"
+
 func TestInvalidTransactionData(t *testing.T) {
 	tests := []struct {
 		name string
@@ -87,6 +92,38 @@ func TestTransaction(t *testing.T) {
 		}
 	})
 
+	t.Run("Fields", func(t *testing.T) {
+		if tx.Version() != 1 {
+			t.Errorf("Expected version 1, got %d", tx.Version())
+		}
+		if tx.LockTime() != 0 {
+			t.Errorf("Expected lock time 0, got %d", tx.LockTime())
+		}
+		if !tx.IsCoinbase() {
+			t.Error("Expected transaction to be a coinbase")
+		}
+		if tx.HasWitness() {
+			t.Error("Expected transaction to have no witness")
+		}
+	})
+
+	t.Run("GetWtxid", func(t *testing.T) {
+		// Without witness data the wtxid equals the txid
+		if tx.GetWtxid() != tx.GetTxid().Bytes() {
+			t.Errorf("Expected wtxid %x to equal txid %x", tx.GetWtxid(), tx.GetTxid().Bytes())
+		}
+	})
+
+	t.Run("Weight", func(t *testing.T) {
+		size := int64(len(coinbaseTxHex) / 2)
+		if tx.Weight() != 4*size {
+			t.Errorf("Expected weight %d, got %d", 4*size, tx.Weight())
+		}
+		if tx.VSize() != size {
+			t.Errorf("Expected vsize %d, got %d", size, tx.VSize())
+		}
+	})
+
 	t.Run("CountInputs", func(t *testing.T) {
 		// This is a coinbase transaction with 1 input
 		inputCount := tx.CountInputs()
@@ -195,3 +232,91 @@ func TestTransaction(t *testing.T) {
 		}
 	})
 }
+
+func TestSegwitTransaction(t *testing.T) {
+	txBytes, err := hex.DecodeString(segwitCoinbaseTxHex)
+	if err != nil {
+		t.Fatalf("Failed to decode transaction hex: %v", err)
+	}
+
+	tx, err := NewTransaction(txBytes)
+	if err != nil {
+		t.Fatalf("NewTransaction() error = %v", err)
+	}
+	defer tx.Destroy()
+
+	if tx.Version() != 2 {
+		t.Errorf("Expected version 2, got %d", tx.Version())
+	}
+	if !tx.HasWitness() {
+		t.Error("Expected transaction to have a witness")
+	}
+
+	expectedTxid := "f3ac0618ad042336fbec1f88a4e965481b46cd3381a807591c78c75fdbae7d67"
+	if tx.GetTxid().String() != expectedTxid {
+		t.Errorf("Expected txid %s, got %s", expectedTxid, tx.GetTxid().String())
+	}
+
+	wtxid := tx.GetWtxid()
+	expectedWtxid := "9eb8796ec3c27865f48c961b3a26854efe2608a9c1764fe5f60483149f9ad225"
+	if hex.EncodeToString(ReverseBytes(wtxid[:])) != expectedWtxid {
+		t.Errorf("Expected wtxid %s, got %x", expectedWtxid, ReverseBytes(wtxid[:]))
+	}
+
+	// 131 bytes without witness, 167 bytes with witness
+	if tx.Weight() != 560 {
+		t.Errorf("Expected weight 560, got %d", tx.Weight())
+	}
+	if tx.VSize() != 140 {
+		t.Errorf("Expected vsize 140, got %d", tx.VSize())
+	}
+}
+
+func TestCheckTransaction(t *testing.T) {
+	txBytes, err := hex.DecodeString(segwitCoinbaseTxHex)
+	if err != nil {
+		t.Fatalf("Failed to decode transaction hex: %v", err)
+	}
+	coinbase, err := NewTransaction(txBytes)
+	if err != nil {
+		t.Fatalf("NewTransaction() error = %v", err)
+	}
+	defer coinbase.Destroy()
+	if err := CheckTransaction(coinbase); err != nil {
+		t.Errorf("CheckTransaction() error = %v", err)
+	}
+
+	prevOut := wire.NewOutPoint(wire.Hash{1}, 0)
+	tests := []struct {
+		name         string
+		modify       func(msg *wire.MsgTx)
+		rejectReason string
+	}{
+		{"duplicate inputs", func(msg *wire.MsgTx) { msg.AddTxIn(wire.NewTxIn(prevOut, nil, nil)) }, "bad-txns-inputs-duplicate"},
+		{"negative output", func(msg *wire.MsgTx) { msg.TxOut[0].Value = -1 }, "bad-txns-vout-negative"},
+		{"output above money range", func(msg *wire.MsgTx) { msg.TxOut[0].Value = 21_000_000*100_000_000 + 1 }, "bad-txns-vout-toolarge"},
+		{"null input", func(msg *wire.MsgTx) { msg.AddTxIn(wire.NewTxIn(wire.NewOutPoint(wire.Hash{}, 0xffffffff), nil, nil)) }, "bad-txns-prevout-null"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := wire.NewMsgTx(2)
+			msg.AddTxIn(wire.NewTxIn(prevOut, nil, nil))
+			msg.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+			tt.modify(msg)
+			tx, err := NewTransactionFromWire(msg)
+			if err != nil {
+				t.Fatalf("NewTransactionFromWire() error = %v", err)
+			}
+			defer tx.Destroy()
+
+			var validationErr *TransactionValidationError
+			if err := CheckTransaction(tx); !errors.As(err, &validationErr) {
+				t.Fatalf("Expected *TransactionValidationError, got %v", err)
+			}
+			if validationErr.Mode != ValidationStateInvalid || validationErr.Result != TxConsensus || validationErr.RejectReason != tt.rejectReason {
+				t.Errorf("Expected invalid consensus result %q, got mode %d, result %d, reason %q",
+					tt.rejectReason, validationErr.Mode, validationErr.Result, validationErr.RejectReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package kernel
+
+/*
+#include "bitcoinkernel.h"
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+// TxValidationState holds the state of a transaction during validation.
+//
+// Contains information about whether validation was successful, and if not,
+// which rule the transaction violated.
+type TxValidationState struct {
+	ptr *C.btck_TxValidationState
+}
+
+// ValidationMode returns whether the transaction is valid, invalid, or encountered an error.
+func (tvs *TxValidationState) ValidationMode() ValidationMode {
+	mode := C.btck_tx_validation_state_get_validation_mode(tvs.ptr)
+	return ValidationMode(mode)
+}
+
+// ValidationResult returns a granular reason for why the transaction was invalid.
+func (tvs *TxValidationState) ValidationResult() TxValidationResult {
+	result := C.btck_tx_validation_state_get_tx_validation_result(tvs.ptr)
+	return TxValidationResult(result)
+}
+
+// RejectReason returns the short reject reason if the transaction was rejected,
+// e.g. "bad-txns-inputs-missingorspent". Returns an empty string otherwise.
+func (tvs *TxValidationState) RejectReason() string {
+	bytes, _ := writeToBytes(func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+		return C.btck_tx_validation_state_get_reject_reason(tvs.ptr, writer, userData)
+	})
+	return string(bytes)
+}
+
+// DebugMessage returns additional details on why the transaction was rejected, if any.
+func (tvs *TxValidationState) DebugMessage() string {
+	bytes, _ := writeToBytes(func(writer C.btck_WriteBytes, userData unsafe.Pointer) C.int {
+		return C.btck_tx_validation_state_get_debug_message(tvs.ptr, writer, userData)
+	})
+	return string(bytes)
+}
+
+// TxValidationResult provides a granular reason why a transaction was invalid.
+type TxValidationResult C.btck_TxValidationResult
+
+const (
+	TxResultUnset       TxValidationResult = C.btck_TxValidationResult_UNSET               // Initial value, transaction has not yet been rejected
+	TxConsensus         TxValidationResult = C.btck_TxValidationResult_CONSENSUS           // Invalid by consensus rules
+	TxInputsNotStandard TxValidationResult = C.btck_TxValidationResult_INPUTS_NOT_STANDARD // Inputs failed policy rules
+	TxNotStandard       TxValidationResult = C.btck_TxValidationResult_NOT_STANDARD        // Otherwise didn't meet the local policy rules
+	TxMissingInputs     TxValidationResult = C.btck_TxValidationResult_MISSING_INPUTS      // Transaction was missing some of its inputs
+	TxPrematureSpend    TxValidationResult = C.btck_TxValidationResult_PREMATURE_SPEND     // Spends a coinbase too early, or violates locktime/sequence locks
+	TxWitnessMutated    TxValidationResult = C.btck_TxValidationResult_WITNESS_MUTATED     // Witness may have been malleated, or present prior to segwit activation
+	TxWitnessStripped   TxValidationResult = C.btck_TxValidationResult_WITNESS_STRIPPED    // Transaction is missing a witness
+	TxConflict          TxValidationResult = C.btck_TxValidationResult_CONFLICT            // Already in the mempool or conflicts with a transaction in the chain
+	TxMempoolPolicy     TxValidationResult = C.btck_TxValidationResult_MEMPOOL_POLICY      // Violated the mempool's fee, size, descendant or replacement limits
+	TxNoMempool         TxValidationResult = C.btck_TxValidationResult_NO_MEMPOOL          // No mempool is available to validate the transaction
+	TxReconsiderable    TxValidationResult = C.btck_TxValidationResult_RECONSIDERABLE      // Fails some policy, but might be acceptable as part of a package
+	TxUnknown           TxValidationResult = C.btck_TxValidationResult_UNKNOWN             // Transaction was not validated
+)
@@ -45,6 +45,15 @@ type txidApi struct {
 	ptr *C.btck_Txid
 }
 
+func (t *txidApi) txidPtr() *C.btck_Txid {
+	return t.ptr
+}
+
+// TxidLike is an interface for types that can provide a txid pointer.
+type TxidLike interface {
+	txidPtr() *C.btck_Txid
+}
+
 // Copy creates a copy of the txid.
 func (t *txidApi) Copy() *Txid {
 	return newTxid(t.ptr, false)
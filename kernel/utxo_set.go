@@ -0,0 +1,146 @@
+package kernel
+
+/*
+#include "bitcoinkernel.h"
+*/
+import "C"
+import (
+	"iter"
+	"unsafe"
+)
+
+type coinsCursorCFuncs struct{}
+
+func (coinsCursorCFuncs) destroy(ptr unsafe.Pointer) {
+	C.btck_coins_cursor_destroy((*C.btck_CoinsCursor)(ptr))
+}
+
+// CoinsCursor iterates over a snapshot of the UTXO set of the active chainstate,
+// taken when the cursor was created. Coins are ordered by out point.
+//
+// The cursor must be destroyed before the chainstate manager it was created from.
+type CoinsCursor struct {
+	*uniqueHandle
+	manager *ChainstateManager // Keeps the chainstate manager reachable while the cursor is alive
+	err     error
+}
+
+// NewCoinsCursor creates a cursor over the UTXO set of the active chainstate.
+//
+// The chainstate is flushed to disk first, so that the cursor covers all blocks
+// connected so far. Blocks processed afterwards are not reflected in its results.
+//
+// Returns an error if the cursor could not be created.
+func (cm *ChainstateManager) NewCoinsCursor() (*CoinsCursor, error) {
+	ptr := C.btck_coins_cursor_create((*C.btck_ChainstateManager)(cm.ptr))
+	if ptr == nil {
+		return nil, &InternalError{"Failed to create coins cursor"}
+	}
+	h := newUniqueHandle(unsafe.Pointer(ptr), coinsCursorCFuncs{})
+	return &CoinsCursor{uniqueHandle: h, manager: cm}, nil
+}
+
+// BlockHash returns the hash of the block the UTXO set of the cursor corresponds to.
+func (c *CoinsCursor) BlockHash() *BlockHash {
+	ptr := C.btck_coins_cursor_get_block_hash((*C.btck_CoinsCursor)(c.ptr))
+	return newBlockHash(check(ptr), true)
+}
+
+// Next reads the coin at the current position of the cursor and advances it.
+//
+// Returns nil values once the cursor is exhausted, or an error if the coins
+// database could not be read.
+func (c *CoinsCursor) Next() (*TransactionOutPoint, *Coin, error) {
+	var outPoint *C.btck_TransactionOutPoint
+	var coin *C.btck_Coin
+	result := C.btck_coins_cursor_next((*C.btck_CoinsCursor)(c.ptr), &outPoint, &coin)
+	switch {
+	case result < 0:
+		return nil, nil, &InternalError{"Failed to read coin from the coins database"}
+	case result == 0:
+		return nil, nil, nil
+	}
+	return newTransactionOutPoint(outPoint, true), newCoin(coin, true), nil
+}
+
+// Coins returns an iterator over the remaining coins of the cursor and their out points.
+//
+// Iteration stops early if the coins database cannot be read, in which case Err
+// returns the error.
+func (c *CoinsCursor) Coins() iter.Seq2[*TransactionOutPoint, *Coin] {
+	return func(yield func(*TransactionOutPoint, *Coin) bool) {
+		for {
+			outPoint, coin, err := c.Next()
+			if err != nil {
+				c.err = err
+				return
+			}
+			if outPoint == nil || !yield(outPoint, coin) {
+				return
+			}
+		}
+	}
+}
+
+// Err returns the error that stopped the iteration of Coins, if any.
+func (c *CoinsCursor) Err() error {
+	return c.err
+}
+
+type utxoSetStatsCFuncs struct{}
+
+func (utxoSetStatsCFuncs) destroy(ptr unsafe.Pointer) {
+	C.btck_utxo_set_stats_destroy((*C.btck_UtxoSetStats)(ptr))
+}
+
+// UTXOSetStats holds statistics of the UTXO set at a block, as reported by
+// Bitcoin Core's gettxoutsetinfo.
+type UTXOSetStats struct {
+	Height         int32    // Height of the block the statistics correspond to
+	BlockHash      [32]byte // Hash of the block the statistics correspond to
+	Transactions   uint64   // Number of transactions with unspent outputs
+	Coins          uint64   // Number of unspent outputs
+	TotalAmount    int64    // Total amount of all unspent outputs in satoshis
+	BogoSize       uint64   // Database-independent size metric of the UTXO set
+	DiskSize       uint64   // Estimated size of the coins database on disk
+	MuHash         [32]byte // MuHash commitment to the UTXO set
+	HashSerialized [32]byte // hash_serialized_3 commitment to the UTXO set, as used by assumeutxo
+}
+
+// UTXOSetStats computes statistics of the UTXO set of the active chainstate,
+// including both its MuHash and hash_serialized_3 commitments.
+//
+// The chainstate is flushed to disk first. The statistics are then computed in a
+// single pass over a snapshot of the coins database, so block processing is only
+// blocked while flushing and the statistics correspond to the tip at that time.
+//
+// Returns an error if the statistics could not be computed.
+func (cm *ChainstateManager) UTXOSetStats() (*UTXOSetStats, error) {
+	ptr := C.btck_chainstate_manager_get_utxo_set_stats((*C.btck_ChainstateManager)(cm.ptr))
+	if ptr == nil {
+		return nil, &InternalError{"Failed to compute UTXO set statistics"}
+	}
+	h := newUniqueHandle(unsafe.Pointer(ptr), utxoSetStatsCFuncs{})
+	defer h.Destroy()
+	cStats := (*C.btck_UtxoSetStats)(h.ptr)
+
+	var totalAmount C.int64_t
+	if C.btck_utxo_set_stats_get_total_amount(cStats, &totalAmount) != 0 {
+		return nil, &InternalError{"UTXO set total amount overflowed"}
+	}
+	blockHash := newBlockHash(check(C.btck_utxo_set_stats_get_block_hash(cStats)), true)
+	defer blockHash.Destroy()
+
+	stats := &UTXOSetStats{
+		Height:       int32(C.btck_utxo_set_stats_get_height(cStats)),
+		BlockHash:    blockHash.Bytes(),
+		Transactions: uint64(C.btck_utxo_set_stats_get_transaction_count(cStats)),
+		Coins:        uint64(C.btck_utxo_set_stats_get_coin_count(cStats)),
+		TotalAmount:  int64(totalAmount),
+		BogoSize:     uint64(C.btck_utxo_set_stats_get_bogo_size(cStats)),
+		DiskSize:     uint64(C.btck_utxo_set_stats_get_disk_size(cStats)),
+	}
+	C.btck_utxo_set_stats_get_muhash(cStats, (*C.uchar)(unsafe.Pointer(&stats.MuHash[0])))
+	C.btck_utxo_set_stats_get_hash_serialized(cStats, (*C.uchar)(unsafe.Pointer(&stats.HashSerialized[0])))
+	return stats, nil
+}
@@ -0,0 +1,80 @@
+package kernel
+
+import "testing"
+
+func (s *ChainstateManagerTestSuite) TestUTXOSet(t *testing.T) {
+	chain := s.Manager.GetActiveChain()
+	tip := chain.GetByHeight(chain.GetHeight())
+
+	cursor, err := s.Manager.NewCoinsCursor()
+	if err != nil {
+		t.Fatalf("NewCoinsCursor() error = %v", err)
+	}
+	defer cursor.Destroy()
+
+	cursorHash := cursor.BlockHash()
+	defer cursorHash.Destroy()
+	if cursorHash.Bytes() != tip.Hash().Bytes() {
+		t.Error("Expected cursor to correspond to the chain tip")
+	}
+
+	var (
+		coins, bogoSize uint64
+		totalAmount     int64
+		txids           = make(map[[32]byte]bool)
+	)
+	for outPoint, coin := range cursor.Coins() {
+		if coins == 0 {
+			// Every coin returned by the cursor is in the UTXO set
+			found := s.Manager.GetCoin(outPoint)
+			if found == nil || found.ConfirmationHeight() != coin.ConfirmationHeight() {
+				t.Error("Expected GetCoin() to find the first coin of the cursor")
+			}
+		}
+		script, err := coin.GetOutput().ScriptPubkey().Bytes()
+		if err != nil {
+			t.Fatalf("ScriptPubkey().Bytes() error = %v", err)
+		}
+		coins++
+		totalAmount += coin.GetOutput().Amount()
+		bogoSize += 50 + uint64(len(script))
+		txids[outPoint.GetTxid().Bytes()] = true
+		outPoint.Destroy()
+		coin.Destroy()
+	}
+	if err := cursor.Err(); err != nil {
+		t.Fatalf("Coins() error = %v", err)
+	}
+	if coins == 0 {
+		t.Fatal("Expected the UTXO set to contain coins")
+	}
+	if outPoint, coin, err := cursor.Next(); outPoint != nil || coin != nil || err != nil {
+		t.Error("Expected exhausted cursor to return nil values")
+	}
+
+	stats, err := s.Manager.UTXOSetStats()
+	if err != nil {
+		t.Fatalf("UTXOSetStats() error = %v", err)
+	}
+	if stats.Height != tip.Height() || stats.BlockHash != tip.Hash().Bytes() {
+		t.Errorf("Expected statistics at height %d, got %d", tip.Height(), stats.Height)
+	}
+	if stats.Coins != coins || stats.TotalAmount != totalAmount || stats.BogoSize != bogoSize {
+		t.Errorf("Expected %d coins, amount %d and bogosize %d, got %d, %d and %d",
+			coins, totalAmount, bogoSize, stats.Coins, stats.TotalAmount, stats.BogoSize)
+	}
+	if stats.Transactions != uint64(len(txids)) {
+		t.Errorf("Expected %d transactions, got %d", len(txids), stats.Transactions)
+	}
+	if stats.MuHash == ([32]byte{}) || stats.HashSerialized == ([32]byte{}) || stats.MuHash == stats.HashSerialized {
+		t.Error("Expected distinct non-zero MuHash and hash_serialized commitments")
+	}
+
+	again, err := s.Manager.UTXOSetStats()
+	if err != nil {
+		t.Fatalf("UTXOSetStats() error = %v", err)
+	}
+	if again.MuHash != stats.MuHash || again.HashSerialized != stats.HashSerialized {
+		t.Error("Expected commitments to be deterministic")
+	}
+}
@@ -17,40 +17,89 @@ type ValidationInterfaceCallbacks struct {
 	OnPoWValidBlock     func(block *Block, entry *BlockTreeEntry)       // Called when a new block extends the header chain and has a valid transaction and segwit merkle root.
 	OnBlockConnected    func(block *Block, entry *BlockTreeEntry)       // Called when a block is valid and has now been connected to the best chain.
 	OnBlockDisconnected func(block *Block, entry *BlockTreeEntry)       // Called during a re-org when a block has been removed from the best chain.
+
+	// Called when a transaction was added to the mempool. The sequence is increased
+	// with every mempool addition and removal.
+	OnTransactionAddedToMempool func(entry *MempoolEntry, sequence uint64)
+	// Called when a transaction was removed from the mempool for any reason but its
+	// inclusion in a connected block, e.g. when it was replaced.
+	OnTransactionRemovedFromMempool func(tx *Transaction, reason MempoolRemovalReason, sequence uint64)
+}
+
+// validationInterfaceBridge is passed to the validation interface bridges, which
+// dispatch each event to the user-supplied callbacks and the listeners of the
+// context.
+type validationInterfaceBridge struct {
+	callbacks *ValidationInterfaceCallbacks
+	listeners *contextListeners
+}
+
+// blockCheckedEvent is passed to the block checked listeners of a context.
+type blockCheckedEvent struct {
+	block *Block
+	state *BlockValidationState
 }
 
 //export go_validation_interface_block_checked_bridge
 func go_validation_interface_block_checked_bridge(user_data unsafe.Pointer, block *C.btck_Block, state *C.btck_BlockValidationState) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*ValidationInterfaceCallbacks)
+	bridge := cgo.Handle(user_data).Value().(*validationInterfaceBridge)
+	callbacks := bridge.callbacks
+	goBlock := newBlock(block, true)
+	goState := &BlockValidationState{ptr: state}
 	if callbacks.OnBlockChecked != nil {
-		callbacks.OnBlockChecked(newBlock(block, true), &BlockValidationState{ptr: state})
+		callbacks.OnBlockChecked(goBlock, goState)
 	}
+	bridge.listeners.blockChecked.notify(func() blockCheckedEvent {
+		return blockCheckedEvent{block: goBlock, state: goState}
+	})
 }
 
 //export go_validation_interface_pow_valid_block_bridge
 func go_validation_interface_pow_valid_block_bridge(user_data unsafe.Pointer, block *C.btck_Block, entry *C.btck_BlockTreeEntry) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*ValidationInterfaceCallbacks)
+	bridge := cgo.Handle(user_data).Value().(*validationInterfaceBridge)
+	callbacks := bridge.callbacks
+	// The block is owned by Go and released by its finalizer if no callback is set
+	goBlock := newBlock(block, true)
 	if callbacks.OnPoWValidBlock != nil {
-		callbacks.OnPoWValidBlock(newBlock(block, true), &BlockTreeEntry{ptr: entry})
+		callbacks.OnPoWValidBlock(goBlock, &BlockTreeEntry{ptr: entry})
 	}
 }
 
 //export go_validation_interface_block_connected_bridge
 func go_validation_interface_block_connected_bridge(user_data unsafe.Pointer, block *C.btck_Block, entry *C.btck_BlockTreeEntry) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*ValidationInterfaceCallbacks)
+	bridge := cgo.Handle(user_data).Value().(*validationInterfaceBridge)
+	callbacks := bridge.callbacks
+	goBlock := newBlock(block, true)
 	if callbacks.OnBlockConnected != nil {
-		callbacks.OnBlockConnected(newBlock(block, true), &BlockTreeEntry{ptr: entry})
+		callbacks.OnBlockConnected(goBlock, &BlockTreeEntry{ptr: entry})
 	}
 }
 
 //export go_validation_interface_block_disconnected_bridge
 func go_validation_interface_block_disconnected_bridge(user_data unsafe.Pointer, block *C.btck_Block, entry *C.btck_BlockTreeEntry) {
-	handle := cgo.Handle(user_data)
-	callbacks := handle.Value().(*ValidationInterfaceCallbacks)
+	bridge := cgo.Handle(user_data).Value().(*validationInterfaceBridge)
+	callbacks := bridge.callbacks
+	goBlock := newBlock(block, true)
 	if callbacks.OnBlockDisconnected != nil {
-		callbacks.OnBlockDisconnected(newBlock(block, true), &BlockTreeEntry{ptr: entry})
+		callbacks.OnBlockDisconnected(goBlock, &BlockTreeEntry{ptr: entry})
+	}
+}
+
+//export go_validation_interface_transaction_added_to_mempool_bridge
+func go_validation_interface_transaction_added_to_mempool_bridge(user_data unsafe.Pointer, entry *C.btck_MempoolEntry, sequence C.uint64_t) {
+	bridge := cgo.Handle(user_data).Value().(*validationInterfaceBridge)
+	callbacks := bridge.callbacks
+	if callbacks.OnTransactionAddedToMempool != nil {
+		callbacks.OnTransactionAddedToMempool(newMempoolEntry(entry), uint64(sequence))
+	}
+}
+
+//export go_validation_interface_transaction_removed_from_mempool_bridge
+func go_validation_interface_transaction_removed_from_mempool_bridge(user_data unsafe.Pointer, tx *C.btck_Transaction, reason C.btck_MempoolRemovalReason, sequence C.uint64_t) {
+	bridge := cgo.Handle(user_data).Value().(*validationInterfaceBridge)
+	callbacks := bridge.callbacks
+	goTx := newTransaction(tx, true)
+	if callbacks.OnTransactionRemovedFromMempool != nil {
+		callbacks.OnTransactionRemovedFromMempool(goTx, MempoolRemovalReason(reason), uint64(sequence))
 	}
 }
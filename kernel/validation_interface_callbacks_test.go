@@ -59,3 +59,32 @@ func TestValidationInterfaceCallbacks(t *testing.T) {
 		t.Errorf("Unexpected block data for connected block")
 	}
 }
+
+func TestValidationInterfaceCallbacksSharedByContexts(t *testing.T) {
+	var checked int
+	callbacks := &ValidationInterfaceCallbacks{
+		OnBlockChecked: func(*Block, *BlockValidationState) { checked++ },
+	}
+	first := ChainstateManagerTestSuite{MaxBlockHeightToImport: 1, ValidationCallbacks: callbacks}
+	first.Setup(t)
+	second := ChainstateManagerTestSuite{MaxBlockHeightToImport: 1, ValidationCallbacks: callbacks}
+	second.Setup(t)
+
+	// Listeners belong to a context, even if its callbacks are shared
+	var events int
+	remove := first.Manager.context.listeners.blockChecked.add(func(blockCheckedEvent) { events++ })
+	defer remove()
+
+	block := readRegtestBlock(t, 2)
+	defer block.Destroy()
+	checked = 0
+	if _, err := second.Manager.ProcessBlock(block); err != nil {
+		t.Fatalf("ProcessBlock() error = %v", err)
+	}
+	if checked != 1 {
+		t.Errorf("Expected OnBlockChecked to be called once, got %d", checked)
+	}
+	if events != 0 {
+		t.Errorf("Expected no events for a listener of another context, got %d", events)
+	}
+}
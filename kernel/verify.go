@@ -0,0 +1,208 @@
+package kernel
+
+/*
+#include "bitcoinkernel.h"
+*/
+import "C"
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// InputVerifyResult is the outcome of verifying the script of a single transaction input.
+type InputVerifyResult struct {
+	TxIndex    uint64                // Index of the transaction in the block, 0 for VerifyTransaction
+	InputIndex uint64                // Index of the input in the transaction
+	Valid      bool                  // Whether the script of the input is valid
+	Err        *ScriptExecutionError // Reason the script failed to verify, nil if valid
+}
+
+// ScriptVerifyReport holds the per-input results of VerifyTransaction or VerifyBlockScripts.
+type ScriptVerifyReport struct {
+	Results []InputVerifyResult // Ordered by transaction, then by input
+}
+
+// Valid returns true if the scripts of all verified inputs are valid.
+func (r *ScriptVerifyReport) Valid() bool {
+	for _, result := range r.Results {
+		if !result.Valid {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns the results of the inputs whose scripts are invalid.
+func (r *ScriptVerifyReport) Failed() []InputVerifyResult {
+	var failed []InputVerifyResult
+	for _, result := range r.Results {
+		if !result.Valid {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// verifyTxJob holds the arguments shared by the inputs of one transaction.
+type verifyTxJob struct {
+	txIndex      uint64
+	tx           *C.btck_Transaction
+	spentOutputs []*C.btck_TransactionOutput
+	txdata       *C.btck_PrecomputedTransactionData
+}
+
+// VerifyTransaction verifies the scripts of all inputs of tx concurrently.
+//
+// Parameters:
+//   - tx: Transaction to verify
+//   - spentOutputs: Outputs spent by the transaction, one per input in input order
+//   - flags: ScriptFlags controlling validation constraints
+//
+// Returns a report with the validity of every input, or an error if verification
+// could not be performed due to malformed input.
+func VerifyTransaction(tx *Transaction, spentOutputs []*TransactionOutput, flags ScriptFlags) (*ScriptVerifyReport, error) {
+	if uint64(len(spentOutputs)) != tx.CountInputs() {
+		return nil, ErrVerifyScriptVerifySpentOutputsMismatch
+	}
+	cSpentOutputs := make([]*C.btck_TransactionOutput, len(spentOutputs))
+	for i, output := range spentOutputs {
+		cSpentOutputs[i] = (*C.btck_TransactionOutput)(output.handle.ptr)
+	}
+	report, err := verifyScripts([]verifyTxJob{{
+		tx:           (*C.btck_Transaction)(tx.handle.ptr),
+		spentOutputs: cSpentOutputs,
+	}}, flags)
+	runtime.KeepAlive(tx)
+	runtime.KeepAlive(spentOutputs)
+	return report, err
+}
+
+// VerifyBlockScripts verifies the scripts of all non-coinbase inputs of block
+// concurrently, taking the spent outputs from the block's undo data as returned by
+// ChainstateManager.ReadBlockSpentOutputs.
+//
+// This allows re-auditing historical blocks under flags that differ from the ones
+// active when the block was connected.
+//
+// Parameters:
+//   - block: Block to verify
+//   - spentOutputs: Spent outputs of the block
+//   - flags: ScriptFlags controlling validation constraints
+//
+// Returns a report with the validity of every input, or an error if the spent
+// outputs do not match the block or verification could not be performed.
+func VerifyBlockScripts(block *Block, spentOutputs *BlockSpentOutputs, flags ScriptFlags) (*ScriptVerifyReport, error) {
+	txCount := block.CountTransactions()
+	if txCount == 0 || spentOutputs.Count() != txCount-1 {
+		return nil, ErrVerifyScriptVerifySpentOutputsMismatch
+	}
+
+	jobs := make([]verifyTxJob, 0, txCount-1)
+	for txIndex := uint64(1); txIndex < txCount; txIndex++ {
+		tx, err := block.GetTransactionAt(txIndex)
+		if err != nil {
+			return nil, err
+		}
+		txSpentOutputs, err := spentOutputs.GetTransactionSpentOutputsAt(txIndex - 1)
+		if err != nil {
+			return nil, err
+		}
+		if txSpentOutputs.Count() != tx.CountInputs() {
+			return nil, ErrVerifyScriptVerifySpentOutputsMismatch
+		}
+		cSpentOutputs := make([]*C.btck_TransactionOutput, 0, txSpentOutputs.Count())
+		for coin := range txSpentOutputs.Coins() {
+			cSpentOutputs = append(cSpentOutputs, coin.GetOutput().ptr)
+		}
+		jobs = append(jobs, verifyTxJob{
+			txIndex:      txIndex,
+			tx:           tx.ptr,
+			spentOutputs: cSpentOutputs,
+		})
+	}
+
+	report, err := verifyScripts(jobs, flags)
+	runtime.KeepAlive(block)
+	runtime.KeepAlive(spentOutputs)
+	return report, err
+}
+
+// verifyScripts verifies every input of the given transactions on a pool of
+// GOMAXPROCS workers. The signature hash data of each transaction is computed
+// once and shared by the verification of its inputs.
+func verifyScripts(jobs []verifyTxJob, flags ScriptFlags) (*ScriptVerifyReport, error) {
+	if (flags & ^ScriptFlags(ScriptFlagsVerifyAll)) != 0 {
+		return nil, ErrVerifyScriptVerifyInvalidFlags
+	}
+
+	type inputJob struct {
+		tx          *verifyTxJob
+		resultIndex int
+	}
+	report := &ScriptVerifyReport{}
+	var inputs []inputJob
+	for i := range jobs {
+		txdata := C.btck_precomputed_transaction_data_create(jobs[i].tx,
+			unsafe.SliceData(jobs[i].spentOutputs), C.size_t(len(jobs[i].spentOutputs)))
+		if txdata == nil {
+			return nil, ErrVerifyScriptVerifySpentOutputsMismatch
+		}
+		defer C.btck_precomputed_transaction_data_destroy(txdata)
+		jobs[i].txdata = txdata
+		for inputIndex := range jobs[i].spentOutputs {
+			inputs = append(inputs, inputJob{tx: &jobs[i], resultIndex: len(report.Results)})
+			report.Results = append(report.Results, InputVerifyResult{
+				TxIndex:    jobs[i].txIndex,
+				InputIndex: uint64(inputIndex),
+			})
+		}
+	}
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	work := make(chan inputJob)
+	for range min(runtime.GOMAXPROCS(0), len(inputs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range work {
+				result := &report.Results[job.resultIndex]
+				spent := newTransactionOutputView(job.tx.spentOutputs[result.InputIndex])
+				var cStatus C.btck_ScriptVerifyStatus
+				var cScriptError C.btck_ScriptError
+				valid, err := verifyResult(C.btck_script_pubkey_verify_with_precomputed(
+					spent.ScriptPubkey().ptr,
+					C.int64_t(spent.Amount()),
+					job.tx.tx,
+					job.tx.txdata,
+					C.uint(result.InputIndex),
+					C.btck_ScriptVerificationFlags(flags),
+					&cStatus,
+					&cScriptError,
+				), cStatus, cScriptError)
+				var execErr *ScriptExecutionError
+				if errors.As(err, &execErr) {
+					result.Err = execErr
+				} else if err != nil {
+					errOnce.Do(func() { firstErr = err })
+				}
+				result.Valid = valid
+			}
+		}()
+	}
+	for _, job := range inputs {
+		work <- job
+	}
+	close(work)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return report, nil
+}
@@ -0,0 +1,134 @@
+package kernel
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+const nativeSegwitTxHex = "010000000001011f97548fbbe7a0db7588a66e18d803d0089315aa7d4cc28360b6ec50ef36718a0100000000ffffffff02df1776000000000017a9146c002a686959067f4866b8fb493ad7970290ab728757d29f0000000000220020701a8d401c84fb13e6baf169d59684e17abd9fa216c8cc5b9fc63d622ff8c58d04004730440220565d170eed95ff95027a69b313758450ba84a01224e1f7f130dda46e94d13f8602207bdd20e307f062594022f12ed5017bbf4a055a06aea91c10110a0e3bb23117fc014730440220647d2dc5b15f60bc37dc42618a370b2a1490293f9e5c8464f53ec4fe1dfe067302203598773895b4b16d37485cbe21b337f4e4b650739880098c592553add7dd4355016952210375e00eb72e29da82b89367947f29ef34afb75e8654f6ea368e0acdfd92976b7c2103a1b26313f430c4b15bb1fdce663207659d8cac749a0e53d70eff01874496feff2103c96d495bfdd5ba4145e3e046fee45e84a8a48ad05bd8dbb395c011a32cf9f88053ae00000000"
+
+func TestVerifyTransaction(t *testing.T) {
+	txBytes, err := hex.DecodeString(nativeSegwitTxHex)
+	if err != nil {
+		t.Fatalf("Failed to decode transaction hex: %v", err)
+	}
+	tx, err := NewTransaction(txBytes)
+	if err != nil {
+		t.Fatalf("NewTransaction() error = %v", err)
+	}
+	defer tx.Destroy()
+
+	scriptBytes, err := hex.DecodeString("0020701a8d401c84fb13e6baf169d59684e17abd9fa216c8cc5b9fc63d622ff8c58d")
+	if err != nil {
+		t.Fatalf("Failed to decode script hex: %v", err)
+	}
+	scriptPubkey := NewScriptPubkey(scriptBytes)
+	defer scriptPubkey.Destroy()
+
+	t.Run("valid", func(t *testing.T) {
+		spent := NewTransactionOutput(scriptPubkey, 18393430)
+		defer spent.Destroy()
+
+		report, err := VerifyTransaction(tx, []*TransactionOutput{spent}, ScriptFlagsVerifyAll)
+		if err != nil {
+			t.Fatalf("VerifyTransaction() error = %v", err)
+		}
+		if len(report.Results) != 1 {
+			t.Fatalf("Expected 1 result, got %d", len(report.Results))
+		}
+		if !report.Valid() {
+			t.Error("Expected the transaction to be valid")
+		}
+		if len(report.Failed()) != 0 {
+			t.Errorf("Expected no failed inputs, got %d", len(report.Failed()))
+		}
+	})
+
+	t.Run("wrong amount", func(t *testing.T) {
+		spent := NewTransactionOutput(scriptPubkey, 18393431)
+		defer spent.Destroy()
+
+		report, err := VerifyTransaction(tx, []*TransactionOutput{spent}, ScriptFlagsVerifyAll)
+		if err != nil {
+			t.Fatalf("VerifyTransaction() error = %v", err)
+		}
+		if report.Valid() {
+			t.Error("Expected the transaction to be invalid")
+		}
+		failed := report.Failed()
+		if len(failed) != 1 || failed[0].InputIndex != 0 {
+			t.Fatalf("Expected input 0 to fail, got %+v", failed)
+		}
+		if failed[0].Err == nil || failed[0].Err.Code != ScriptErrorEvalFalse {
+			t.Errorf("Expected ScriptErrorEvalFalse, got %v", failed[0].Err)
+		}
+	})
+
+	t.Run("spent outputs mismatch", func(t *testing.T) {
+		_, err := VerifyTransaction(tx, nil, ScriptFlagsVerifyAll)
+		if !errors.Is(err, ErrVerifyScriptVerifySpentOutputsMismatch) {
+			t.Errorf("Expected ErrVerifyScriptVerifySpentOutputsMismatch, got %v", err)
+		}
+	})
+
+	t.Run("invalid flags", func(t *testing.T) {
+		spent := NewTransactionOutput(scriptPubkey, 18393430)
+		defer spent.Destroy()
+
+		_, err := VerifyTransaction(tx, []*TransactionOutput{spent}, ScriptFlagsVerifyAll+1)
+		if !errors.Is(err, ErrVerifyScriptVerifyInvalidFlags) {
+			t.Errorf("Expected ErrVerifyScriptVerifyInvalidFlags, got %v", err)
+		}
+	})
+}
+
+func (s *ChainstateManagerTestSuite) TestVerifyBlockScripts(t *testing.T) {
+	chain := s.Manager.GetActiveChain()
+	entry := chain.GetByHeight(202)
+
+	block, err := s.Manager.ReadBlock(entry)
+	if err != nil {
+		t.Fatalf("ReadBlock() error = %v", err)
+	}
+	defer block.Destroy()
+
+	spentOutputs, err := s.Manager.ReadBlockSpentOutputs(entry)
+	if err != nil {
+		t.Fatalf("ReadBlockSpentOutputs() error = %v", err)
+	}
+	defer spentOutputs.Destroy()
+
+	t.Run("valid", func(t *testing.T) {
+		report, err := VerifyBlockScripts(block, spentOutputs, ScriptFlagsVerifyAll)
+		if err != nil {
+			t.Fatalf("VerifyBlockScripts() error = %v", err)
+		}
+		var inputCount uint64
+		for txSpentOutputs := range spentOutputs.TransactionsSpentOutputs() {
+			inputCount += txSpentOutputs.Count()
+		}
+		if uint64(len(report.Results)) != inputCount {
+			t.Errorf("Expected %d input results, got %d", inputCount, len(report.Results))
+		}
+		if !report.Valid() {
+			t.Errorf("Expected all inputs to be valid, failed: %+v", report.Failed())
+		}
+		if first := report.Results[0]; first.TxIndex != 1 || first.InputIndex != 0 {
+			t.Errorf("Expected the first result to be input 0 of transaction 1, got %+v", first)
+		}
+	})
+
+	t.Run("spent outputs mismatch", func(t *testing.T) {
+		otherBlock, err := s.Manager.ReadBlock(chain.GetByHeight(1))
+		if err != nil {
+			t.Fatalf("ReadBlock() error = %v", err)
+		}
+		defer otherBlock.Destroy()
+
+		_, err = VerifyBlockScripts(otherBlock, spentOutputs, ScriptFlagsVerifyAll)
+		if !errors.Is(err, ErrVerifyScriptVerifySpentOutputsMismatch) {
+			t.Errorf("Expected ErrVerifyScriptVerifySpentOutputsMismatch, got %v", err)
+		}
+	})
+}
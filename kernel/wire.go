@@ -0,0 +1,104 @@
+package kernel
+
+import (
+	"github.com/stringintech/go-bitcoinkernel/wire"
+)
+
+// NewBlockFromWire creates a new block from its native Go representation.
+//
+// Returns an error if the block cannot be serialized or is rejected by the kernel parser.
+func NewBlockFromWire(msg *wire.MsgBlock) (*Block, error) {
+	raw, err := msg.Bytes()
+	if err != nil {
+		return nil, &SerializationError{"Failed to serialize wire block: " + err.Error()}
+	}
+	return NewBlock(raw)
+}
+
+// ToWire converts the block to its native Go representation.
+//
+// Returns an error if the serialization fails.
+func (b *Block) ToWire() (*wire.MsgBlock, error) {
+	raw, err := b.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	msg, err := wire.NewMsgBlockFromBytes(raw)
+	if err != nil {
+		return nil, &SerializationError{"Failed to decode block: " + err.Error()}
+	}
+	return msg, nil
+}
+
+// NewBlockHeaderFromWire creates a new block header from its native Go representation.
+func NewBlockHeaderFromWire(header *wire.BlockHeader) (*BlockHeader, error) {
+	raw, err := header.Bytes()
+	if err != nil {
+		return nil, &SerializationError{"Failed to serialize wire block header: " + err.Error()}
+	}
+	return NewBlockHeader(raw)
+}
+
+// ToWire converts the block header to its native Go representation.
+//
+// Returns an error if the serialization fails.
+func (bh *BlockHeader) ToWire() (*wire.BlockHeader, error) {
+	raw, err := bh.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	header, err := wire.NewBlockHeaderFromBytes(raw)
+	if err != nil {
+		return nil, &SerializationError{"Failed to decode block header: " + err.Error()}
+	}
+	return header, nil
+}
+
+// NewTransactionFromWire creates a new transaction from its native Go representation.
+//
+// Returns an error if the transaction cannot be serialized or is rejected by the kernel parser.
+func NewTransactionFromWire(msg *wire.MsgTx) (*Transaction, error) {
+	raw, err := msg.Bytes()
+	if err != nil {
+		return nil, &SerializationError{"Failed to serialize wire transaction: " + err.Error()}
+	}
+	return NewTransaction(raw)
+}
+
+// ToWire converts the transaction to its native Go representation.
+//
+// Returns an error if the serialization fails.
+func (t *transactionApi) ToWire() (*wire.MsgTx, error) {
+	raw, err := t.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	msg, err := wire.NewMsgTxFromBytes(raw)
+	if err != nil {
+		return nil, &SerializationError{"Failed to decode transaction: " + err.Error()}
+	}
+	return msg, nil
+}
+
+// NewTransactionOutputFromWire creates a transaction output from its native Go representation.
+func NewTransactionOutputFromWire(out *wire.TxOut) *TransactionOutput {
+	scriptPubkey := NewScriptPubkey(out.PkScript)
+	defer scriptPubkey.Destroy()
+	return NewTransactionOutput(scriptPubkey, out.Value)
+}
+
+// NewTransactionOutPointFromWire creates a transaction out point from its native Go representation.
+func NewTransactionOutPointFromWire(outPoint wire.OutPoint) *TransactionOutPoint {
+	return NewTransactionOutPoint(outPoint.Hash, outPoint.Index)
+}
+
+// ToWire converts the transaction output to its native Go representation.
+//
+// Returns an error if the script pubkey serialization fails.
+func (t *transactionOutputApi) ToWire() (*wire.TxOut, error) {
+	pkScript, err := t.ScriptPubkey().Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return wire.NewTxOut(t.Amount(), pkScript), nil
+}
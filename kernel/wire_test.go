@@ -0,0 +1,144 @@
+package kernel
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stringintech/go-bitcoinkernel/wire"
+)
+
+func TestTransactionWireRoundTrip(t *testing.T) {
+	for _, txHex := range []string{coinbaseTxHex, segwitCoinbaseTxHex} {
+		raw, err := hex.DecodeString(txHex)
+		if err != nil {
+			t.Fatalf("Failed to decode transaction hex: %v", err)
+		}
+
+		tx, err := NewTransaction(raw)
+		if err != nil {
+			t.Fatalf("NewTransaction() error = %v", err)
+		}
+		defer tx.Destroy()
+
+		msg, err := tx.ToWire()
+		if err != nil {
+			t.Fatalf("ToWire() error = %v", err)
+		}
+		if msg.TxHash() != wire.Hash(tx.GetTxid().Bytes()) {
+			t.Errorf("Expected txid %s, got %s", tx.GetTxid().String(), msg.TxHash())
+		}
+		if msg.WitnessHash() != wire.Hash(tx.GetWtxid()) {
+			t.Errorf("Expected wtxid %x, got %s", tx.GetWtxid(), msg.WitnessHash())
+		}
+		if msg.Weight() != tx.Weight() {
+			t.Errorf("Expected weight %d, got %d", tx.Weight(), msg.Weight())
+		}
+
+		// Mutate in Go and hand back to the kernel
+		msg.LockTime = 500
+		msg.TxOut[0].Value = 1
+		mutated, err := NewTransactionFromWire(msg)
+		if err != nil {
+			t.Fatalf("NewTransactionFromWire() error = %v", err)
+		}
+		defer mutated.Destroy()
+
+		if mutated.LockTime() != 500 {
+			t.Errorf("Expected lock time 500, got %d", mutated.LockTime())
+		}
+		output, err := mutated.GetOutput(0)
+		if err != nil {
+			t.Fatalf("GetOutput(0) error = %v", err)
+		}
+		if output.Amount() != 1 {
+			t.Errorf("Expected amount 1, got %d", output.Amount())
+		}
+		if mutated.HasWitness() != tx.HasWitness() {
+			t.Errorf("Expected HasWitness() %v, got %v", tx.HasWitness(), mutated.HasWitness())
+		}
+	}
+}
+
+func TestTransactionOutputWire(t *testing.T) {
+	pkScript := []byte{0x00, 0x14, 0x14, 0x09, 0x74, 0x54, 0x05, 0xc4, 0xe8, 0x31, 0x0a, 0x87,
+		0x5b, 0xcd, 0x60, 0x2d, 0xb6, 0xb9, 0xb3, 0xdc, 0x0c, 0xf9}
+	output := NewTransactionOutputFromWire(wire.NewTxOut(5000000000, pkScript))
+	defer output.Destroy()
+
+	if output.Amount() != 5000000000 {
+		t.Errorf("Expected amount 5000000000, got %d", output.Amount())
+	}
+
+	txOut, err := output.ToWire()
+	if err != nil {
+		t.Fatalf("ToWire() error = %v", err)
+	}
+	if txOut.Value != 5000000000 || !bytes.Equal(txOut.PkScript, pkScript) {
+		t.Errorf("Expected output {5000000000 %x}, got {%d %x}", pkScript, txOut.Value, txOut.PkScript)
+	}
+}
+
+func TestTransactionOutPointWire(t *testing.T) {
+	outPoint := NewTransactionOutPointFromWire(*wire.NewOutPoint(wire.Hash{0x01, 0x02}, 7))
+	defer outPoint.Destroy()
+
+	if outPoint.GetIndex() != 7 {
+		t.Errorf("Expected index 7, got %d", outPoint.GetIndex())
+	}
+	if outPoint.GetTxid().Bytes() != [32]byte{0x01, 0x02} {
+		t.Errorf("Expected txid bytes 0102..., got %x", outPoint.GetTxid().Bytes())
+	}
+}
+
+func TestBlockWireRoundTrip(t *testing.T) {
+	raw, err := hex.DecodeString(genesisHeaderHex + "01" + coinbaseTxHex)
+	if err != nil {
+		t.Fatalf("Failed to decode block hex: %v", err)
+	}
+
+	block, err := NewBlock(raw)
+	if err != nil {
+		t.Fatalf("NewBlock() error = %v", err)
+	}
+	defer block.Destroy()
+
+	msg, err := block.ToWire()
+	if err != nil {
+		t.Fatalf("ToWire() error = %v", err)
+	}
+	if msg.BlockHash() != wire.Hash(block.Hash().Bytes()) {
+		t.Errorf("Expected block hash %s, got %s", block.Hash().String(), msg.BlockHash())
+	}
+	if len(msg.Transactions) != int(block.CountTransactions()) {
+		t.Errorf("Expected %d transactions, got %d", block.CountTransactions(), len(msg.Transactions))
+	}
+
+	msg.Header.Nonce++
+	mutated, err := NewBlockFromWire(msg)
+	if err != nil {
+		t.Fatalf("NewBlockFromWire() error = %v", err)
+	}
+	defer mutated.Destroy()
+
+	if mutated.Header().Nonce() != msg.Header.Nonce {
+		t.Errorf("Expected nonce %d, got %d", msg.Header.Nonce, mutated.Header().Nonce())
+	}
+	if mutated.Hash().Bytes() != msg.BlockHash() {
+		t.Errorf("Expected block hash %s, got %s", msg.BlockHash(), mutated.Hash().String())
+	}
+
+	header, err := NewBlockHeaderFromWire(&msg.Header)
+	if err != nil {
+		t.Fatalf("NewBlockHeaderFromWire() error = %v", err)
+	}
+	defer header.Destroy()
+
+	wireHeader, err := header.ToWire()
+	if err != nil {
+		t.Fatalf("ToWire() error = %v", err)
+	}
+	if *wireHeader != msg.Header {
+		t.Errorf("Expected header %+v, got %+v", msg.Header, *wireHeader)
+	}
+}
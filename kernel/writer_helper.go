@@ -6,9 +6,11 @@ package kernel
 #include <string.h>
 
 extern int go_writer_callback_bridge(void* bytes, size_t size, void* userdata);
+extern int go_io_writer_callback_bridge(void* bytes, size_t size, void* userdata);
 */
 import "C"
 import (
+	"io"
 	"runtime/cgo"
 	"unsafe"
 )
@@ -42,3 +44,35 @@ func writeToBytes(writerFunc func(C.btck_WriteBytes, unsafe.Pointer) C.int) (byt
 	}
 	return callbackData.buffer, true
 }
+
+// ioWriterCallbackData holds the writer that written bytes are passed on to and
+// the first error it returned
+type ioWriterCallbackData struct {
+	w   io.Writer
+	err error
+}
+
+//export go_io_writer_callback_bridge
+func go_io_writer_callback_bridge(bytes unsafe.Pointer, size C.size_t, userdata unsafe.Pointer) C.int {
+	data := cgo.Handle(userdata).Value().(*ioWriterCallbackData)
+	if size > 0 {
+		// The writer must not retain the slice, it is a view of C memory
+		cBytes := unsafe.Slice((*byte)(bytes), int(size))
+		if _, err := data.w.Write(cBytes); err != nil {
+			data.err = err
+			return -1
+		}
+	}
+	return 0
+}
+
+// writeToWriter is like writeToBytes but streams the written bytes to w instead
+// of collecting them. It returns the error of w if writing was aborted because of it.
+func writeToWriter(w io.Writer, writerFunc func(C.btck_WriteBytes, unsafe.Pointer) C.int) (ok bool, err error) {
+	callbackData := &ioWriterCallbackData{w: w}
+	handle := cgo.NewHandle(callbackData)
+	defer handle.Delete()
+
+	result := writerFunc((C.btck_WriteBytes)(C.go_io_writer_callback_bridge), unsafe.Pointer(handle))
+	return result == 0, callbackData.err
+}
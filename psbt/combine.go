@@ -0,0 +1,144 @@
+package psbt
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Combine merges packets for the same transaction into a new packet holding the
+// union of their fields, as done by the BIP174 combiner role.
+//
+// When packets disagree on a field with a single value, the value of the first
+// packet that has it is kept. The inputs are not modified.
+//
+// Returns ErrDifferentTransaction if the packets differ in version or unsigned
+// transaction.
+func Combine(packets ...*Packet) (*Packet, error) {
+	if len(packets) == 0 {
+		return nil, fmt.Errorf("%w: no packets to combine", ErrDifferentTransaction)
+	}
+	first := packets[0]
+	firstTx, err := first.Tx()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range packets[1:] {
+		tx, err := p.Tx()
+		if err != nil {
+			return nil, err
+		}
+		if p.Version != first.Version || tx.TxHash() != firstTx.TxHash() ||
+			len(p.Inputs) != len(first.Inputs) || len(p.Outputs) != len(first.Outputs) {
+			return nil, ErrDifferentTransaction
+		}
+	}
+
+	combined := &Packet{
+		Version:   first.Version,
+		TxVersion: first.TxVersion,
+		Inputs:    make([]*Input, len(first.Inputs)),
+		Outputs:   make([]*Output, len(first.Outputs)),
+	}
+	if first.UnsignedTx != nil {
+		combined.UnsignedTx = first.UnsignedTx.Copy()
+	}
+	for i := range combined.Inputs {
+		combined.Inputs[i] = &Input{}
+	}
+	for i := range combined.Outputs {
+		combined.Outputs[i] = &Output{}
+	}
+
+	for _, p := range packets {
+		combined.FallbackLocktime = firstSet(combined.FallbackLocktime, p.FallbackLocktime)
+		combined.TxModifiable = firstSet(combined.TxModifiable, p.TxModifiable)
+		combined.XPubs = mergeByKey(combined.XPubs, p.XPubs, func(x XPub) []byte { return x.ExtendedKey })
+		combined.Unknowns = mergeUnknowns(combined.Unknowns, p.Unknowns)
+		for i, in := range p.Inputs {
+			combined.Inputs[i].merge(in)
+		}
+		for i, out := range p.Outputs {
+			combined.Outputs[i].merge(out)
+		}
+	}
+	return combined, nil
+}
+
+func (in *Input) merge(other *Input) {
+	in.NonWitnessUtxo = firstSet(in.NonWitnessUtxo, other.NonWitnessUtxo)
+	in.WitnessUtxo = firstSet(in.WitnessUtxo, other.WitnessUtxo)
+	in.PartialSigs = mergeByKey(in.PartialSigs, other.PartialSigs, func(s PartialSig) []byte { return s.PubKey })
+	in.SighashType = firstSet(in.SighashType, other.SighashType)
+	in.RedeemScript = firstBytes(in.RedeemScript, other.RedeemScript)
+	in.WitnessScript = firstBytes(in.WitnessScript, other.WitnessScript)
+	in.Bip32Derivations = mergeByKey(in.Bip32Derivations, other.Bip32Derivations, func(d Bip32Derivation) []byte { return d.PubKey })
+	in.FinalScriptSig = firstBytes(in.FinalScriptSig, other.FinalScriptSig)
+	if in.FinalScriptWitness == nil {
+		in.FinalScriptWitness = other.FinalScriptWitness
+	}
+
+	// The previous output and sequence fields are equal, they are part of the
+	// transaction compared by Combine
+	in.PreviousTxid = other.PreviousTxid
+	in.OutputIndex = other.OutputIndex
+	in.Sequence = firstSet(in.Sequence, other.Sequence)
+	in.RequiredTimeLocktime = firstSet(in.RequiredTimeLocktime, other.RequiredTimeLocktime)
+	in.RequiredHeightLocktime = firstSet(in.RequiredHeightLocktime, other.RequiredHeightLocktime)
+
+	in.TapKeySig = firstBytes(in.TapKeySig, other.TapKeySig)
+	in.TapScriptSigs = mergeByKey(in.TapScriptSigs, other.TapScriptSigs, func(s TapScriptSig) []byte {
+		return append(append([]byte{}, s.XOnlyPubKey...), s.LeafHash[:]...)
+	})
+	in.TapLeafScripts = mergeByKey(in.TapLeafScripts, other.TapLeafScripts, func(l TapLeafScript) []byte { return l.ControlBlock })
+	in.TapBip32Derivations = mergeByKey(in.TapBip32Derivations, other.TapBip32Derivations, func(d TapBip32Derivation) []byte { return d.XOnlyPubKey })
+	in.TapInternalKey = firstBytes(in.TapInternalKey, other.TapInternalKey)
+	in.TapMerkleRoot = firstBytes(in.TapMerkleRoot, other.TapMerkleRoot)
+	in.Unknowns = mergeUnknowns(in.Unknowns, other.Unknowns)
+}
+
+func (out *Output) merge(other *Output) {
+	out.RedeemScript = firstBytes(out.RedeemScript, other.RedeemScript)
+	out.WitnessScript = firstBytes(out.WitnessScript, other.WitnessScript)
+	out.Bip32Derivations = mergeByKey(out.Bip32Derivations, other.Bip32Derivations, func(d Bip32Derivation) []byte { return d.PubKey })
+	out.Amount = other.Amount
+	out.Script = firstBytes(out.Script, other.Script)
+	out.TapInternalKey = firstBytes(out.TapInternalKey, other.TapInternalKey)
+	out.TapTree = firstBytes(out.TapTree, other.TapTree)
+	out.TapBip32Derivations = mergeByKey(out.TapBip32Derivations, other.TapBip32Derivations, func(d TapBip32Derivation) []byte { return d.XOnlyPubKey })
+	out.Unknowns = mergeUnknowns(out.Unknowns, other.Unknowns)
+}
+
+func firstSet[T any](current, other *T) *T {
+	if current != nil {
+		return current
+	}
+	return other
+}
+
+func firstBytes(current, other []byte) []byte {
+	if current != nil {
+		return current
+	}
+	return other
+}
+
+// mergeByKey appends the entries of other whose key is not yet in current.
+func mergeByKey[T any](current, other []T, key func(T) []byte) []T {
+	for _, entry := range other {
+		found := false
+		for _, existing := range current {
+			if bytes.Equal(key(existing), key(entry)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			current = append(current, entry)
+		}
+	}
+	return current
+}
+
+func mergeUnknowns(current, other []Unknown) []Unknown {
+	return mergeByKey(current, other, func(u Unknown) []byte { return u.Key })
+}
@@ -0,0 +1,820 @@
+package psbt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/stringintech/go-bitcoinkernel/wire"
+)
+
+// magic prefixes every serialized packet.
+var magic = []byte{'p', 's', 'b', 't', 0xff}
+
+// Global key types.
+const (
+	globalUnsignedTx       = 0x00
+	globalXPub             = 0x01
+	globalTxVersion        = 0x02
+	globalFallbackLocktime = 0x03
+	globalInputCount       = 0x04
+	globalOutputCount      = 0x05
+	globalTxModifiable     = 0x06
+	globalVersion          = 0xfb
+)
+
+// Input key types.
+const (
+	inNonWitnessUtxo         = 0x00
+	inWitnessUtxo            = 0x01
+	inPartialSig             = 0x02
+	inSighashType            = 0x03
+	inRedeemScript           = 0x04
+	inWitnessScript          = 0x05
+	inBip32Derivation        = 0x06
+	inFinalScriptSig         = 0x07
+	inFinalScriptWitness     = 0x08
+	inPreviousTxid           = 0x0e
+	inOutputIndex            = 0x0f
+	inSequence               = 0x10
+	inRequiredTimeLocktime   = 0x11
+	inRequiredHeightLocktime = 0x12
+	inTapKeySig              = 0x13
+	inTapScriptSig           = 0x14
+	inTapLeafScript          = 0x15
+	inTapBip32Derivation     = 0x16
+	inTapInternalKey         = 0x17
+	inTapMerkleRoot          = 0x18
+)
+
+// Output key types.
+const (
+	outRedeemScript       = 0x00
+	outWitnessScript      = 0x01
+	outBip32Derivation    = 0x02
+	outAmount             = 0x03
+	outScript             = 0x04
+	outTapInternalKey     = 0x05
+	outTapTree            = 0x06
+	outTapBip32Derivation = 0x07
+)
+
+// keyValue is a raw key-value pair of a PSBT map.
+type keyValue struct {
+	keyType uint64
+	keyData []byte
+	key     []byte // Full key, including the key type
+	value   []byte
+}
+
+// fieldSet records the key types of the fields present in a map.
+type fieldSet map[uint64]bool
+
+// Decode parses a binary packet and checks that it is well-formed for its version.
+//
+// Returns an error wrapping one of the package's errors if the packet is malformed.
+func Decode(b []byte) (*Packet, error) {
+	if !bytes.HasPrefix(b, magic) {
+		return nil, ErrInvalidMagic
+	}
+	r := bytes.NewReader(b[len(magic):])
+
+	p, counts, err := decodeGlobal(r)
+	if err != nil {
+		return nil, err
+	}
+	inputCount, outputCount := counts[0], counts[1]
+	// Every map takes at least its separator byte. The counts are bounded one at a
+	// time, as their sum can overflow.
+	if inputCount > uint64(r.Len()) || outputCount > uint64(r.Len())-inputCount {
+		return nil, fmt.Errorf("%w: %d input and %d output maps expected", ErrCountMismatch, inputCount, outputCount)
+	}
+
+	inputFields := make([]fieldSet, inputCount)
+	for i := range inputCount {
+		in, fields, err := decodeInput(r)
+		if err != nil {
+			return nil, &InputError{Index: int(i), Err: err}
+		}
+		p.Inputs = append(p.Inputs, in)
+		inputFields[i] = fields
+	}
+	outputFields := make([]fieldSet, outputCount)
+	for i := range outputCount {
+		out, fields, err := decodeOutput(r)
+		if err != nil {
+			return nil, fmt.Errorf("output %d: %w", i, err)
+		}
+		p.Outputs = append(p.Outputs, out)
+		outputFields[i] = fields
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%w: unexpected data after the output maps", ErrCountMismatch)
+	}
+
+	if err := p.checkVersionFields(inputFields, outputFields); err != nil {
+		return nil, err
+	}
+	tx, err := p.Tx()
+	if err != nil {
+		return nil, err
+	}
+	for i, in := range p.Inputs {
+		if in.NonWitnessUtxo == nil {
+			continue
+		}
+		if _, err := p.spentOutput(tx, i); err != nil {
+			return nil, &InputError{Index: i, Err: err}
+		}
+	}
+	return p, nil
+}
+
+// DecodeBase64 parses a base64 encoded packet, the format used by Bitcoin Core's RPC interface.
+func DecodeBase64(s string) (*Packet, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(b)
+}
+
+// Encode serializes the packet.
+//
+// Returns an error if the packet lacks the fields required by its version or the
+// number of maps does not match the unsigned transaction.
+func (p *Packet) Encode() ([]byte, error) {
+	if p.Version == Version0 && p.UnsignedTx != nil &&
+		(len(p.Inputs) != len(p.UnsignedTx.TxIn) || len(p.Outputs) != len(p.UnsignedTx.TxOut)) {
+		return nil, ErrCountMismatch
+	}
+	var buf bytes.Buffer
+	buf.Write(magic)
+	if err := p.encodeGlobal(&buf); err != nil {
+		return nil, err
+	}
+	for _, in := range p.Inputs {
+		if err := in.encode(&buf, p.Version); err != nil {
+			return nil, err
+		}
+	}
+	for _, out := range p.Outputs {
+		if err := out.encode(&buf, p.Version); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeBase64 serializes the packet and encodes it as base64.
+func (p *Packet) EncodeBase64() (string, error) {
+	b, err := p.Encode()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// checkVersionFields checks that the maps contain the fields required by the
+// packet version and none of the fields reserved for the other version.
+func (p *Packet) checkVersionFields(inputFields, outputFields []fieldSet) error {
+	var required, forbidden []uint64
+	var outRequired, outForbidden []uint64
+	if p.Version == Version0 {
+		forbidden = []uint64{inPreviousTxid, inOutputIndex, inSequence, inRequiredTimeLocktime, inRequiredHeightLocktime}
+		outForbidden = []uint64{outAmount, outScript}
+	} else {
+		required = []uint64{inPreviousTxid, inOutputIndex}
+		outRequired = []uint64{outAmount, outScript}
+	}
+	for i, fields := range inputFields {
+		if err := checkFields(fields, required, forbidden); err != nil {
+			return &InputError{Index: i, Err: err}
+		}
+	}
+	for i, fields := range outputFields {
+		if err := checkFields(fields, outRequired, outForbidden); err != nil {
+			return fmt.Errorf("output %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func checkFields(fields fieldSet, required, forbidden []uint64) error {
+	for _, keyType := range required {
+		if !fields[keyType] {
+			return fmt.Errorf("%w: key type 0x%02x", ErrMissingField, keyType)
+		}
+	}
+	for _, keyType := range forbidden {
+		if fields[keyType] {
+			return fmt.Errorf("%w: key type 0x%02x", ErrFieldNotAllowed, keyType)
+		}
+	}
+	return nil
+}
+
+// readMap reads the key-value pairs of a map up to its separator.
+func readMap(r *bytes.Reader) ([]keyValue, error) {
+	var pairs []keyValue
+	seen := make(map[string]bool)
+	for {
+		key, err := wire.ReadVarBytes(r)
+		if err != nil {
+			return nil, noEOF(err)
+		}
+		if len(key) == 0 {
+			return pairs, nil
+		}
+		if seen[string(key)] {
+			return nil, fmt.Errorf("%w %x", ErrDuplicateKey, key)
+		}
+		seen[string(key)] = true
+
+		value, err := wire.ReadVarBytes(r)
+		if err != nil {
+			return nil, noEOF(err)
+		}
+		keyReader := bytes.NewReader(key)
+		keyType, err := wire.ReadVarInt(keyReader)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+		}
+		pairs = append(pairs, keyValue{
+			keyType: keyType,
+			keyData: key[len(key)-keyReader.Len():],
+			key:     key,
+			value:   value,
+		})
+	}
+}
+
+func decodeGlobal(r *bytes.Reader) (*Packet, [2]uint64, error) {
+	var counts [2]uint64
+	pairs, err := readMap(r)
+	if err != nil {
+		return nil, counts, err
+	}
+
+	p := &Packet{}
+	fields := make(fieldSet)
+	for _, kv := range pairs {
+		fields[kv.keyType] = true
+		switch kv.keyType {
+		case globalUnsignedTx:
+			err = noKeyData(kv)
+			if err == nil {
+				p.UnsignedTx, err = decodeUnsignedTx(kv.value)
+			}
+		case globalXPub:
+			if len(kv.keyData) != 78 {
+				err = ErrInvalidKey
+				break
+			}
+			var origin KeyOrigin
+			origin, err = decodeKeyOrigin(kv.value)
+			p.XPubs = append(p.XPubs, XPub{ExtendedKey: kv.keyData, Origin: origin})
+		case globalTxVersion:
+			err = decodeUint32(kv, &p.TxVersion)
+		case globalFallbackLocktime:
+			p.FallbackLocktime = new(uint32)
+			err = decodeUint32(kv, p.FallbackLocktime)
+		case globalInputCount, globalOutputCount:
+			err = noKeyData(kv)
+			if err == nil {
+				counts[kv.keyType-globalInputCount], err = decodeCompactSize(kv.value)
+			}
+		case globalTxModifiable:
+			err = noKeyData(kv)
+			if err == nil && len(kv.value) != 1 {
+				err = ErrInvalidValue
+			}
+			if err == nil {
+				p.TxModifiable = &kv.value[0]
+			}
+		case globalVersion:
+			err = decodeUint32(kv, &p.Version)
+		default:
+			p.Unknowns = append(p.Unknowns, Unknown{Key: kv.key, Value: kv.value})
+		}
+		if err != nil {
+			return nil, counts, fmt.Errorf("global key type 0x%02x: %w", kv.keyType, err)
+		}
+	}
+
+	switch p.Version {
+	case Version0:
+		err = checkFields(fields, []uint64{globalUnsignedTx},
+			[]uint64{globalTxVersion, globalFallbackLocktime, globalInputCount, globalOutputCount, globalTxModifiable})
+		if err == nil {
+			counts = [2]uint64{uint64(len(p.UnsignedTx.TxIn)), uint64(len(p.UnsignedTx.TxOut))}
+		}
+	case Version2:
+		err = checkFields(fields, []uint64{globalTxVersion, globalInputCount, globalOutputCount}, []uint64{globalUnsignedTx})
+	default:
+		err = fmt.Errorf("%w %d", ErrUnsupportedVersion, p.Version)
+	}
+	if err != nil {
+		return nil, counts, err
+	}
+	return p, counts, nil
+}
+
+func decodeInput(r *bytes.Reader) (*Input, fieldSet, error) {
+	pairs, err := readMap(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	in := &Input{}
+	fields := make(fieldSet)
+	for _, kv := range pairs {
+		fields[kv.keyType] = true
+		switch kv.keyType {
+		case inNonWitnessUtxo:
+			err = noKeyData(kv)
+			if err == nil {
+				in.NonWitnessUtxo, err = wire.NewMsgTxFromBytes(kv.value)
+			}
+		case inWitnessUtxo:
+			err = noKeyData(kv)
+			if err == nil {
+				in.WitnessUtxo, err = decodeTxOut(kv.value)
+			}
+		case inPartialSig:
+			if !isPubKey(kv.keyData) {
+				err = ErrInvalidKey
+				break
+			}
+			in.PartialSigs = append(in.PartialSigs, PartialSig{PubKey: kv.keyData, Signature: kv.value})
+		case inSighashType:
+			in.SighashType = new(uint32)
+			err = decodeUint32(kv, in.SighashType)
+		case inRedeemScript:
+			err = noKeyData(kv)
+			in.RedeemScript = kv.value
+		case inWitnessScript:
+			err = noKeyData(kv)
+			in.WitnessScript = kv.value
+		case inBip32Derivation:
+			var derivation Bip32Derivation
+			derivation, err = decodeBip32Derivation(kv)
+			in.Bip32Derivations = append(in.Bip32Derivations, derivation)
+		case inFinalScriptSig:
+			err = noKeyData(kv)
+			in.FinalScriptSig = kv.value
+		case inFinalScriptWitness:
+			err = noKeyData(kv)
+			if err == nil {
+				in.FinalScriptWitness, err = decodeWitness(kv.value)
+			}
+		case inPreviousTxid:
+			err = noKeyData(kv)
+			if err == nil && len(kv.value) != wire.HashSize {
+				err = ErrInvalidValue
+			}
+			if err == nil {
+				copy(in.PreviousTxid[:], kv.value)
+			}
+		case inOutputIndex:
+			err = decodeUint32(kv, &in.OutputIndex)
+		case inSequence:
+			in.Sequence = new(uint32)
+			err = decodeUint32(kv, in.Sequence)
+		case inRequiredTimeLocktime:
+			in.RequiredTimeLocktime = new(uint32)
+			err = decodeUint32(kv, in.RequiredTimeLocktime)
+			if err == nil && *in.RequiredTimeLocktime < 500000000 {
+				err = ErrInvalidValue
+			}
+		case inRequiredHeightLocktime:
+			in.RequiredHeightLocktime = new(uint32)
+			err = decodeUint32(kv, in.RequiredHeightLocktime)
+			if err == nil && (*in.RequiredHeightLocktime == 0 || *in.RequiredHeightLocktime >= 500000000) {
+				err = ErrInvalidValue
+			}
+		case inTapKeySig:
+			err = noKeyData(kv)
+			if err == nil && !isSchnorrSig(kv.value) {
+				err = ErrInvalidValue
+			}
+			in.TapKeySig = kv.value
+		case inTapScriptSig:
+			if len(kv.keyData) != 64 {
+				err = ErrInvalidKey
+				break
+			}
+			if !isSchnorrSig(kv.value) {
+				err = ErrInvalidValue
+				break
+			}
+			sig := TapScriptSig{XOnlyPubKey: kv.keyData[:32], Signature: kv.value}
+			copy(sig.LeafHash[:], kv.keyData[32:])
+			in.TapScriptSigs = append(in.TapScriptSigs, sig)
+		case inTapLeafScript:
+			if len(kv.keyData) < 33 || (len(kv.keyData)-33)%32 != 0 {
+				err = ErrInvalidKey
+				break
+			}
+			if len(kv.value) == 0 {
+				err = ErrInvalidValue
+				break
+			}
+			in.TapLeafScripts = append(in.TapLeafScripts, TapLeafScript{
+				ControlBlock: kv.keyData,
+				Script:       kv.value[:len(kv.value)-1],
+				LeafVersion:  kv.value[len(kv.value)-1],
+			})
+		case inTapBip32Derivation:
+			var derivation TapBip32Derivation
+			derivation, err = decodeTapBip32Derivation(kv)
+			in.TapBip32Derivations = append(in.TapBip32Derivations, derivation)
+		case inTapInternalKey:
+			err = decode32Bytes(kv, &in.TapInternalKey)
+		case inTapMerkleRoot:
+			err = decode32Bytes(kv, &in.TapMerkleRoot)
+		default:
+			in.Unknowns = append(in.Unknowns, Unknown{Key: kv.key, Value: kv.value})
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("key type 0x%02x: %w", kv.keyType, err)
+		}
+	}
+	return in, fields, nil
+}
+
+func decodeOutput(r *bytes.Reader) (*Output, fieldSet, error) {
+	pairs, err := readMap(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := &Output{}
+	fields := make(fieldSet)
+	for _, kv := range pairs {
+		fields[kv.keyType] = true
+		switch kv.keyType {
+		case outRedeemScript:
+			err = noKeyData(kv)
+			out.RedeemScript = kv.value
+		case outWitnessScript:
+			err = noKeyData(kv)
+			out.WitnessScript = kv.value
+		case outBip32Derivation:
+			var derivation Bip32Derivation
+			derivation, err = decodeBip32Derivation(kv)
+			out.Bip32Derivations = append(out.Bip32Derivations, derivation)
+		case outAmount:
+			err = noKeyData(kv)
+			if err == nil && len(kv.value) != 8 {
+				err = ErrInvalidValue
+			}
+			if err == nil {
+				out.Amount = int64(binary.LittleEndian.Uint64(kv.value))
+			}
+		case outScript:
+			err = noKeyData(kv)
+			out.Script = kv.value
+		case outTapInternalKey:
+			err = decode32Bytes(kv, &out.TapInternalKey)
+		case outTapTree:
+			err = noKeyData(kv)
+			out.TapTree = kv.value
+		case outTapBip32Derivation:
+			var derivation TapBip32Derivation
+			derivation, err = decodeTapBip32Derivation(kv)
+			out.TapBip32Derivations = append(out.TapBip32Derivations, derivation)
+		default:
+			out.Unknowns = append(out.Unknowns, Unknown{Key: kv.key, Value: kv.value})
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("key type 0x%02x: %w", kv.keyType, err)
+		}
+	}
+	return out, fields, nil
+}
+
+func decodeUnsignedTx(b []byte) (*wire.MsgTx, error) {
+	tx, err := wire.NewMsgTxFromBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	for _, in := range tx.TxIn {
+		if len(in.SignatureScript) != 0 || len(in.Witness) != 0 {
+			return nil, ErrUnsignedTxNotEmpty
+		}
+	}
+	return tx, nil
+}
+
+func decodeTxOut(b []byte) (*wire.TxOut, error) {
+	r := bytes.NewReader(b)
+	var out wire.TxOut
+	if err := out.Deserialize(r); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidValue, err)
+	}
+	if r.Len() != 0 {
+		return nil, wire.ErrTrailingData
+	}
+	return &out, nil
+}
+
+func decodeWitness(b []byte) (wire.TxWitness, error) {
+	r := bytes.NewReader(b)
+	count, err := wire.ReadVarInt(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidValue, err)
+	}
+	witness := make(wire.TxWitness, 0, min(count, 64))
+	for range count {
+		item, err := wire.ReadVarBytes(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidValue, err)
+		}
+		witness = append(witness, item)
+	}
+	if r.Len() != 0 {
+		return nil, wire.ErrTrailingData
+	}
+	return witness, nil
+}
+
+func decodeKeyOrigin(b []byte) (KeyOrigin, error) {
+	var origin KeyOrigin
+	if len(b) < 4 || len(b)%4 != 0 {
+		return origin, ErrInvalidValue
+	}
+	copy(origin.Fingerprint[:], b[:4])
+	for i := 4; i < len(b); i += 4 {
+		origin.Path = append(origin.Path, binary.LittleEndian.Uint32(b[i:]))
+	}
+	return origin, nil
+}
+
+func decodeBip32Derivation(kv keyValue) (Bip32Derivation, error) {
+	if !isPubKey(kv.keyData) {
+		return Bip32Derivation{}, ErrInvalidKey
+	}
+	origin, err := decodeKeyOrigin(kv.value)
+	return Bip32Derivation{PubKey: kv.keyData, Origin: origin}, err
+}
+
+func decodeTapBip32Derivation(kv keyValue) (TapBip32Derivation, error) {
+	derivation := TapBip32Derivation{XOnlyPubKey: kv.keyData}
+	if len(kv.keyData) != 32 {
+		return derivation, ErrInvalidKey
+	}
+	r := bytes.NewReader(kv.value)
+	count, err := wire.ReadVarInt(r)
+	if err != nil || count > uint64(r.Len()/32) {
+		return derivation, ErrInvalidValue
+	}
+	for range count {
+		var leafHash [32]byte
+		if _, err := io.ReadFull(r, leafHash[:]); err != nil {
+			return derivation, ErrInvalidValue
+		}
+		derivation.LeafHashes = append(derivation.LeafHashes, leafHash)
+	}
+	derivation.Origin, err = decodeKeyOrigin(kv.value[len(kv.value)-r.Len():])
+	return derivation, err
+}
+
+func decodeUint32(kv keyValue, v *uint32) error {
+	if err := noKeyData(kv); err != nil {
+		return err
+	}
+	if len(kv.value) != 4 {
+		return ErrInvalidValue
+	}
+	*v = binary.LittleEndian.Uint32(kv.value)
+	return nil
+}
+
+func decode32Bytes(kv keyValue, v *[]byte) error {
+	if err := noKeyData(kv); err != nil {
+		return err
+	}
+	if len(kv.value) != 32 {
+		return ErrInvalidValue
+	}
+	*v = kv.value
+	return nil
+}
+
+func decodeCompactSize(b []byte) (uint64, error) {
+	r := bytes.NewReader(b)
+	v, err := wire.ReadVarInt(r)
+	if err != nil || r.Len() != 0 {
+		return 0, ErrInvalidValue
+	}
+	return v, nil
+}
+
+func noKeyData(kv keyValue) error {
+	if len(kv.keyData) != 0 {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+// isPubKey reports whether b has the size of a compressed or uncompressed public key.
+func isPubKey(b []byte) bool {
+	return (len(b) == 33 && (b[0] == 0x02 || b[0] == 0x03)) || (len(b) == 65 && b[0] == 0x04)
+}
+
+// isSchnorrSig reports whether b has the size of a Schnorr signature, with or without sighash byte.
+func isSchnorrSig(b []byte) bool {
+	return len(b) == 64 || len(b) == 65
+}
+
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// writePair writes a key-value pair with a key consisting of keyType followed by keyData.
+func writePair(w *bytes.Buffer, keyType uint64, keyData []byte, value []byte) {
+	var key bytes.Buffer
+	wire.WriteVarInt(&key, keyType)
+	key.Write(keyData)
+	wire.WriteVarBytes(w, key.Bytes())
+	wire.WriteVarBytes(w, value)
+}
+
+func writeUint32Pair(w *bytes.Buffer, keyType uint64, v uint32) {
+	writePair(w, keyType, nil, binary.LittleEndian.AppendUint32(nil, v))
+}
+
+func encodeKeyOrigin(origin KeyOrigin) []byte {
+	b := append([]byte{}, origin.Fingerprint[:]...)
+	for _, index := range origin.Path {
+		b = binary.LittleEndian.AppendUint32(b, index)
+	}
+	return b
+}
+
+func encodeTapBip32Derivation(derivation TapBip32Derivation) []byte {
+	var b bytes.Buffer
+	wire.WriteVarInt(&b, uint64(len(derivation.LeafHashes)))
+	for _, leafHash := range derivation.LeafHashes {
+		b.Write(leafHash[:])
+	}
+	b.Write(encodeKeyOrigin(derivation.Origin))
+	return b.Bytes()
+}
+
+func writeUnknowns(w *bytes.Buffer, unknowns []Unknown) {
+	for _, u := range unknowns {
+		wire.WriteVarBytes(w, u.Key)
+		wire.WriteVarBytes(w, u.Value)
+	}
+}
+
+func (p *Packet) encodeGlobal(w *bytes.Buffer) error {
+	switch p.Version {
+	case Version0:
+		if p.UnsignedTx == nil {
+			return fmt.Errorf("%w: unsigned transaction", ErrMissingField)
+		}
+		var tx bytes.Buffer
+		if err := p.UnsignedTx.SerializeNoWitness(&tx); err != nil {
+			return err
+		}
+		writePair(w, globalUnsignedTx, nil, tx.Bytes())
+	case Version2:
+		writeUint32Pair(w, globalTxVersion, p.TxVersion)
+		if p.FallbackLocktime != nil {
+			writeUint32Pair(w, globalFallbackLocktime, *p.FallbackLocktime)
+		}
+		var count bytes.Buffer
+		wire.WriteVarInt(&count, uint64(len(p.Inputs)))
+		writePair(w, globalInputCount, nil, count.Bytes())
+		count.Reset()
+		wire.WriteVarInt(&count, uint64(len(p.Outputs)))
+		writePair(w, globalOutputCount, nil, count.Bytes())
+		if p.TxModifiable != nil {
+			writePair(w, globalTxModifiable, nil, []byte{*p.TxModifiable})
+		}
+	default:
+		return fmt.Errorf("%w %d", ErrUnsupportedVersion, p.Version)
+	}
+	for _, xpub := range p.XPubs {
+		writePair(w, globalXPub, xpub.ExtendedKey, encodeKeyOrigin(xpub.Origin))
+	}
+	if p.Version != Version0 {
+		writeUint32Pair(w, globalVersion, p.Version)
+	}
+	writeUnknowns(w, p.Unknowns)
+	w.WriteByte(0x00)
+	return nil
+}
+
+func (in *Input) encode(w *bytes.Buffer, version uint32) error {
+	if in.NonWitnessUtxo != nil {
+		var tx bytes.Buffer
+		if err := in.NonWitnessUtxo.Serialize(&tx); err != nil {
+			return err
+		}
+		writePair(w, inNonWitnessUtxo, nil, tx.Bytes())
+	}
+	if in.WitnessUtxo != nil {
+		var out bytes.Buffer
+		if err := in.WitnessUtxo.Serialize(&out); err != nil {
+			return err
+		}
+		writePair(w, inWitnessUtxo, nil, out.Bytes())
+	}
+	for _, sig := range in.PartialSigs {
+		writePair(w, inPartialSig, sig.PubKey, sig.Signature)
+	}
+	if in.SighashType != nil {
+		writeUint32Pair(w, inSighashType, *in.SighashType)
+	}
+	if in.RedeemScript != nil {
+		writePair(w, inRedeemScript, nil, in.RedeemScript)
+	}
+	if in.WitnessScript != nil {
+		writePair(w, inWitnessScript, nil, in.WitnessScript)
+	}
+	for _, derivation := range in.Bip32Derivations {
+		writePair(w, inBip32Derivation, derivation.PubKey, encodeKeyOrigin(derivation.Origin))
+	}
+	if in.FinalScriptSig != nil {
+		writePair(w, inFinalScriptSig, nil, in.FinalScriptSig)
+	}
+	if in.FinalScriptWitness != nil {
+		var witness bytes.Buffer
+		wire.WriteVarInt(&witness, uint64(len(in.FinalScriptWitness)))
+		for _, item := range in.FinalScriptWitness {
+			wire.WriteVarBytes(&witness, item)
+		}
+		writePair(w, inFinalScriptWitness, nil, witness.Bytes())
+	}
+	if version == Version2 {
+		writePair(w, inPreviousTxid, nil, in.PreviousTxid[:])
+		writeUint32Pair(w, inOutputIndex, in.OutputIndex)
+		if in.Sequence != nil {
+			writeUint32Pair(w, inSequence, *in.Sequence)
+		}
+		if in.RequiredTimeLocktime != nil {
+			writeUint32Pair(w, inRequiredTimeLocktime, *in.RequiredTimeLocktime)
+		}
+		if in.RequiredHeightLocktime != nil {
+			writeUint32Pair(w, inRequiredHeightLocktime, *in.RequiredHeightLocktime)
+		}
+	}
+	if in.TapKeySig != nil {
+		writePair(w, inTapKeySig, nil, in.TapKeySig)
+	}
+	for _, sig := range in.TapScriptSigs {
+		writePair(w, inTapScriptSig, append(append([]byte{}, sig.XOnlyPubKey...), sig.LeafHash[:]...), sig.Signature)
+	}
+	for _, leaf := range in.TapLeafScripts {
+		writePair(w, inTapLeafScript, leaf.ControlBlock, append(append([]byte{}, leaf.Script...), leaf.LeafVersion))
+	}
+	for _, derivation := range in.TapBip32Derivations {
+		writePair(w, inTapBip32Derivation, derivation.XOnlyPubKey, encodeTapBip32Derivation(derivation))
+	}
+	if in.TapInternalKey != nil {
+		writePair(w, inTapInternalKey, nil, in.TapInternalKey)
+	}
+	if in.TapMerkleRoot != nil {
+		writePair(w, inTapMerkleRoot, nil, in.TapMerkleRoot)
+	}
+	writeUnknowns(w, in.Unknowns)
+	w.WriteByte(0x00)
+	return nil
+}
+
+func (out *Output) encode(w *bytes.Buffer, version uint32) error {
+	if out.RedeemScript != nil {
+		writePair(w, outRedeemScript, nil, out.RedeemScript)
+	}
+	if out.WitnessScript != nil {
+		writePair(w, outWitnessScript, nil, out.WitnessScript)
+	}
+	for _, derivation := range out.Bip32Derivations {
+		writePair(w, outBip32Derivation, derivation.PubKey, encodeKeyOrigin(derivation.Origin))
+	}
+	if version == Version2 {
+		writePair(w, outAmount, nil, binary.LittleEndian.AppendUint64(nil, uint64(out.Amount)))
+		writePair(w, outScript, nil, out.Script)
+	}
+	if out.TapInternalKey != nil {
+		writePair(w, outTapInternalKey, nil, out.TapInternalKey)
+	}
+	if out.TapTree != nil {
+		writePair(w, outTapTree, nil, out.TapTree)
+	}
+	for _, derivation := range out.TapBip32Derivations {
+		writePair(w, outTapBip32Derivation, derivation.XOnlyPubKey, encodeTapBip32Derivation(derivation))
+	}
+	writeUnknowns(w, out.Unknowns)
+	w.WriteByte(0x00)
+	return nil
+}
@@ -0,0 +1,300 @@
+package psbt
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+	"github.com/stringintech/go-bitcoinkernel/wire"
+)
+
+// candidate is a possible final script sig and witness of an input.
+type candidate struct {
+	scriptSig []byte
+	witness   wire.TxWitness
+}
+
+// Finalize builds the final script sig and witness of every input from its
+// signatures and scripts, as done by the BIP174 finalizer role.
+//
+// Supported are P2PK, P2PKH, bare multisig, P2SH, P2WPKH, P2WSH, P2SH wrapped
+// segwit and P2TR outputs, the latter spent through the key path or through a
+// single key script path leaf. Every input, including inputs that are already
+// finalized, is verified with kernel.ScriptPubkey.Verify against the spent outputs
+// under all verification flags. The signing fields of the inputs are cleared once
+// all inputs are finalized. If any input cannot be finalized, the packet is left
+// unchanged.
+//
+// Returns an *InputError for the first input that has no valid final scripts.
+func (p *Packet) Finalize() error {
+	tx, err := p.Tx()
+	if err != nil {
+		return err
+	}
+	spentOutputs := make([]*wire.TxOut, len(p.Inputs))
+	kernelSpentOutputs := make([]*kernel.TransactionOutput, len(p.Inputs))
+	defer func() {
+		for _, output := range kernelSpentOutputs {
+			if output != nil {
+				output.Destroy()
+			}
+		}
+	}()
+	for i := range p.Inputs {
+		spentOutputs[i], err = p.spentOutput(tx, i)
+		if err != nil {
+			return &InputError{Index: i, Err: err}
+		}
+		kernelSpentOutputs[i] = kernel.NewTransactionOutputFromWire(spentOutputs[i])
+	}
+
+	for i, in := range p.Inputs {
+		candidates := []candidate{{scriptSig: in.FinalScriptSig, witness: in.FinalScriptWitness}}
+		if !in.IsFinalized() {
+			candidates, err = in.candidates(spentOutputs[i].PkScript)
+			if err != nil {
+				return &InputError{Index: i, Err: err}
+			}
+		}
+
+		lastErr := ErrMissingSignature
+		valid := false
+		for _, c := range candidates {
+			tx.TxIn[i].SignatureScript, tx.TxIn[i].Witness = c.scriptSig, c.witness
+			valid, err = verifyInput(tx, spentOutputs[i], kernelSpentOutputs, i)
+			if valid {
+				break
+			}
+			if err != nil {
+				lastErr = err
+			}
+		}
+		if !valid {
+			return &InputError{Index: i, Err: lastErr}
+		}
+	}
+
+	for i, in := range p.Inputs {
+		*in = Input{
+			NonWitnessUtxo:         in.NonWitnessUtxo,
+			WitnessUtxo:            in.WitnessUtxo,
+			PreviousTxid:           in.PreviousTxid,
+			OutputIndex:            in.OutputIndex,
+			Sequence:               in.Sequence,
+			RequiredTimeLocktime:   in.RequiredTimeLocktime,
+			RequiredHeightLocktime: in.RequiredHeightLocktime,
+			Unknowns:               in.Unknowns,
+		}
+		if len(tx.TxIn[i].SignatureScript) != 0 {
+			in.FinalScriptSig = tx.TxIn[i].SignatureScript
+		}
+		if len(tx.TxIn[i].Witness) != 0 {
+			in.FinalScriptWitness = tx.TxIn[i].Witness
+		}
+	}
+	return nil
+}
+
+// Extract returns the network serializable transaction of a finalized packet.
+//
+// Returns an *InputError wrapping ErrNotFinalized if any input is not finalized.
+func (p *Packet) Extract() (*kernel.Transaction, error) {
+	tx, err := p.Tx()
+	if err != nil {
+		return nil, err
+	}
+	for i, in := range p.Inputs {
+		if !in.IsFinalized() {
+			return nil, &InputError{Index: i, Err: ErrNotFinalized}
+		}
+		tx.TxIn[i].SignatureScript = in.FinalScriptSig
+		tx.TxIn[i].Witness = in.FinalScriptWitness
+	}
+	return kernel.NewTransactionFromWire(tx)
+}
+
+// verifyInput verifies the script of the input at index of tx.
+func verifyInput(tx *wire.MsgTx, spentOutput *wire.TxOut, spentOutputs []*kernel.TransactionOutput, index int) (bool, error) {
+	kernelTx, err := kernel.NewTransactionFromWire(tx)
+	if err != nil {
+		return false, err
+	}
+	defer kernelTx.Destroy()
+	scriptPubkey := kernel.NewScriptPubkey(spentOutput.PkScript)
+	defer scriptPubkey.Destroy()
+	return scriptPubkey.Verify(spentOutput.Value, kernelTx, spentOutputs, uint(index), kernel.ScriptFlagsVerifyAll)
+}
+
+// candidates returns the possible final scripts of the input spending an output
+// with scriptPubkey. Templates that commit to a key hash yield one candidate per
+// partial signature, as the matching key is only determined by verification.
+func (in *Input) candidates(scriptPubkey []byte) ([]candidate, error) {
+	scriptType, _, err := classify(scriptPubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	switch scriptType {
+	case kernel.ScriptTypeWitnessV0KeyHash, kernel.ScriptTypeWitnessV0ScriptHash, kernel.ScriptTypeWitnessV1Taproot:
+		return in.witnessCandidates(scriptType)
+	case kernel.ScriptTypeScriptHash:
+		if in.RedeemScript == nil {
+			return nil, ErrMissingScript
+		}
+		redeemType, _, err := classify(in.RedeemScript)
+		if err != nil {
+			return nil, err
+		}
+		if redeemType == kernel.ScriptTypeWitnessV0KeyHash || redeemType == kernel.ScriptTypeWitnessV0ScriptHash {
+			candidates, err := in.witnessCandidates(redeemType)
+			for i := range candidates {
+				candidates[i].scriptSig = pushData(in.RedeemScript)
+			}
+			return candidates, err
+		}
+		stacks, err := in.solve(in.RedeemScript)
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]candidate, len(stacks))
+		for i, stack := range stacks {
+			candidates[i].scriptSig = pushAll(append(stack, in.RedeemScript))
+		}
+		return candidates, nil
+	}
+
+	stacks, err := in.solve(scriptPubkey)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]candidate, len(stacks))
+	for i, stack := range stacks {
+		candidates[i].scriptSig = pushAll(stack)
+	}
+	return candidates, nil
+}
+
+// witnessCandidates returns the possible final witnesses of a native or P2SH
+// wrapped witness program of the given type.
+func (in *Input) witnessCandidates(scriptType kernel.ScriptType) ([]candidate, error) {
+	var candidates []candidate
+	switch scriptType {
+	case kernel.ScriptTypeWitnessV0KeyHash:
+		for _, sig := range in.PartialSigs {
+			candidates = append(candidates, candidate{witness: wire.TxWitness{sig.Signature, sig.PubKey}})
+		}
+	case kernel.ScriptTypeWitnessV0ScriptHash:
+		if in.WitnessScript == nil {
+			return nil, ErrMissingScript
+		}
+		stacks, err := in.solve(in.WitnessScript)
+		if err != nil {
+			return nil, err
+		}
+		for _, stack := range stacks {
+			candidates = append(candidates, candidate{witness: append(stack, in.WitnessScript)})
+		}
+	case kernel.ScriptTypeWitnessV1Taproot:
+		if in.TapKeySig != nil {
+			candidates = append(candidates, candidate{witness: wire.TxWitness{in.TapKeySig}})
+		}
+		for _, leaf := range in.TapLeafScripts {
+			// <32-byte x-only key> OP_CHECKSIG
+			if len(leaf.Script) != 34 || leaf.Script[0] != 0x20 || leaf.Script[33] != 0xac {
+				continue
+			}
+			leafHash := kernel.TapleafHash(leaf.LeafVersion, leaf.Script)
+			for _, sig := range in.TapScriptSigs {
+				if sig.LeafHash == leafHash && bytes.Equal(sig.XOnlyPubKey, leaf.Script[1:33]) {
+					candidates = append(candidates, candidate{witness: wire.TxWitness{sig.Signature, leaf.Script, leaf.ControlBlock}})
+				}
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, ErrMissingSignature
+	}
+	return candidates, nil
+}
+
+// solve returns the possible stacks satisfying a P2PK, P2PKH or multisig script
+// with the partial signatures of the input.
+func (in *Input) solve(script []byte) ([][][]byte, error) {
+	scriptType, solutions, err := classify(script)
+	if err != nil {
+		return nil, err
+	}
+
+	var stacks [][][]byte
+	switch scriptType {
+	case kernel.ScriptTypePubkey:
+		for _, sig := range in.PartialSigs {
+			if bytes.Equal(sig.PubKey, solutions[0]) {
+				stacks = append(stacks, [][]byte{sig.Signature})
+			}
+		}
+	case kernel.ScriptTypePubkeyHash:
+		for _, sig := range in.PartialSigs {
+			stacks = append(stacks, [][]byte{sig.Signature, sig.PubKey})
+		}
+	case kernel.ScriptTypeMultisig:
+		required := int(solutions[0][0])
+		// CHECKMULTISIG pops one element too many, satisfied by an empty push
+		stack := [][]byte{nil}
+		for _, pubKey := range solutions[1 : len(solutions)-1] {
+			for _, sig := range in.PartialSigs {
+				if len(stack) <= required && bytes.Equal(sig.PubKey, pubKey) {
+					stack = append(stack, sig.Signature)
+				}
+			}
+		}
+		if len(stack) > required {
+			stacks = append(stacks, stack)
+		}
+	default:
+		return nil, ErrUnsupportedScript
+	}
+	if len(stacks) == 0 {
+		return nil, ErrMissingSignature
+	}
+	return stacks, nil
+}
+
+// classify returns the template type and solutions of script.
+func classify(script []byte) (kernel.ScriptType, [][]byte, error) {
+	scriptPubkey := kernel.NewScriptPubkey(script)
+	defer scriptPubkey.Destroy()
+	solutions, err := scriptPubkey.Solutions()
+	if err != nil {
+		return 0, nil, err
+	}
+	return scriptPubkey.Type(), solutions, nil
+}
+
+// pushAll returns a script pushing the items in order.
+func pushAll(items [][]byte) []byte {
+	var script []byte
+	for _, item := range items {
+		script = append(script, pushData(item)...)
+	}
+	return script
+}
+
+// pushData returns the minimal push of data.
+func pushData(data []byte) []byte {
+	switch {
+	case len(data) == 0:
+		return []byte{0x00} // OP_0
+	case len(data) == 1 && data[0] >= 1 && data[0] <= 16:
+		return []byte{0x50 + data[0]} // OP_1 to OP_16
+	case len(data) == 1 && data[0] == 0x81:
+		return []byte{0x4f} // OP_1NEGATE
+	case len(data) < 0x4c:
+		return append([]byte{byte(len(data))}, data...)
+	case len(data) <= 0xff:
+		return append([]byte{0x4c, byte(len(data))}, data...) // OP_PUSHDATA1
+	case len(data) <= 0xffff:
+		return append(binary.LittleEndian.AppendUint16([]byte{0x4d}, uint16(len(data))), data...) // OP_PUSHDATA2
+	}
+	return append(binary.LittleEndian.AppendUint32([]byte{0x4e}, uint32(len(data))), data...) // OP_PUSHDATA4
+}
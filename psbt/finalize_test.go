@@ -0,0 +1,190 @@
+package psbt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+	"github.com/stringintech/go-bitcoinkernel/wire"
+)
+
+// Signed transactions from the blockchain, also used by the kernel script tests
+const (
+	p2pkhTxHex      = "02000000013f7cebd65c27431a90bba7f796914fe8cc2ddfc3f2cbd6f7e5f2fc854534da95000000006b483045022100de1ac3bcdfb0332207c4a91f3832bd2c2915840165f876ab47c5f8996b971c3602201c6c053d750fadde599e6f5c4e1963df0f01fc0d97815e8157e3d59fe09ca30d012103699b464d1d8bc9e47d4fb1cdaa89a1c5783d68363c4dbc4b524ed3d857148617feffffff02836d3c01000000001976a914fc25d6d5c94003bf5b0c7b640a248e2c637fcfb088ac7ada8202000000001976a914fbed3d9b11183209a57999d54d59f67c019e756c88ac6acb0700"
+	p2shP2wpkhTxHex = "01000000000101d9fd94d0ff0026d307c994d0003180a5f248146efb6371d040c5973f5f66d9df0400000017160014b31b31a6cb654cfab3c50567bcf124f48a0beaecffffffff012cbd1c000000000017a914233b74bf0823fa58bbbd26dfc3bb4ae715547167870247304402206f60569cac136c114a58aedd80f6fa1c51b49093e7af883e605c212bdafcd8d202200e91a55f408a021ad2631bc29a67bd6915b2d7e9ef0265627eabd7f7234455f6012103e7e802f50344303c76d12c089c8724c1b230e3b745693bbe16aad536293d15e300000000"
+	p2wshTxHex      = "010000000001011f97548fbbe7a0db7588a66e18d803d0089315aa7d4cc28360b6ec50ef36718a0100000000ffffffff02df1776000000000017a9146c002a686959067f4866b8fb493ad7970290ab728757d29f0000000000220020701a8d401c84fb13e6baf169d59684e17abd9fa216c8cc5b9fc63d622ff8c58d04004730440220565d170eed95ff95027a69b313758450ba84a01224e1f7f130dda46e94d13f8602207bdd20e307f062594022f12ed5017bbf4a055a06aea91c10110a0e3bb23117fc014730440220647d2dc5b15f60bc37dc42618a370b2a1490293f9e5c8464f53ec4fe1dfe067302203598773895b4b16d37485cbe21b337f4e4b650739880098c592553add7dd4355016952210375e00eb72e29da82b89367947f29ef34afb75e8654f6ea368e0acdfd92976b7c2103a1b26313f430c4b15bb1fdce663207659d8cac749a0e53d70eff01874496feff2103c96d495bfdd5ba4145e3e046fee45e84a8a48ad05bd8dbb395c011a32cf9f88053ae00000000"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("Failed to decode hex: %v", err)
+	}
+	return b
+}
+
+// unsignedPacket decodes a signed transaction and returns it together with a
+// packet for the same transaction without script sigs and witnesses.
+func unsignedPacket(t *testing.T, txHex string) (*wire.MsgTx, *Packet) {
+	t.Helper()
+	signed, err := wire.NewMsgTxFromBytes(decodeHex(t, txHex))
+	if err != nil {
+		t.Fatalf("NewMsgTxFromBytes() error = %v", err)
+	}
+	unsigned := signed.Copy()
+	for _, in := range unsigned.TxIn {
+		in.SignatureScript, in.Witness = nil, nil
+	}
+	p, err := New(unsigned)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return signed, p
+}
+
+// scriptPushes returns the data of a script consisting of direct pushes only.
+func scriptPushes(t *testing.T, script []byte) [][]byte {
+	t.Helper()
+	var pushes [][]byte
+	for len(script) > 0 {
+		n := int(script[0])
+		if n == 0 || n >= 0x4c || n >= len(script) {
+			t.Fatalf("Unexpected script %x", script)
+		}
+		pushes = append(pushes, script[1:1+n])
+		script = script[1+n:]
+	}
+	return pushes
+}
+
+func TestFinalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		txHex string
+		setup func(t *testing.T, signed *wire.MsgTx, in *Input)
+	}{
+		{
+			name:  "p2pkh",
+			txHex: p2pkhTxHex,
+			setup: func(t *testing.T, signed *wire.MsgTx, in *Input) {
+				in.WitnessUtxo = wire.NewTxOut(0, decodeHex(t, "76a9144bfbaf6afb76cc5771bc6404810d1cc041a6933988ac"))
+				pushes := scriptPushes(t, signed.TxIn[0].SignatureScript)
+				// The key of a P2PKH output is found by verification, so a signature
+				// by an unrelated key is skipped
+				in.PartialSigs = []PartialSig{
+					{PubKey: decodeHex(t, "03e7e802f50344303c76d12c089c8724c1b230e3b745693bbe16aad536293d15e3"), Signature: pushes[0]},
+					{PubKey: pushes[1], Signature: pushes[0]},
+				}
+			},
+		},
+		{
+			name:  "p2sh_p2wpkh",
+			txHex: p2shP2wpkhTxHex,
+			setup: func(t *testing.T, signed *wire.MsgTx, in *Input) {
+				in.WitnessUtxo = wire.NewTxOut(1900000, decodeHex(t, "a91434c06f8c87e355e123bdc6dda4ffabc64b6989ef87"))
+				in.RedeemScript = decodeHex(t, "0014b31b31a6cb654cfab3c50567bcf124f48a0beaec")
+				witness := signed.TxIn[0].Witness
+				in.PartialSigs = []PartialSig{{PubKey: witness[1], Signature: witness[0]}}
+				in.SighashType = uint32Ptr(1)
+			},
+		},
+		{
+			name:  "p2wsh_multisig",
+			txHex: p2wshTxHex,
+			setup: func(t *testing.T, signed *wire.MsgTx, in *Input) {
+				in.WitnessUtxo = wire.NewTxOut(18393430, decodeHex(t, "0020701a8d401c84fb13e6baf169d59684e17abd9fa216c8cc5b9fc63d622ff8c58d"))
+				witness := signed.TxIn[0].Witness
+				in.WitnessScript = witness[3]
+				// Signatures are ordered like the keys of the script, so either
+				// assignment to the first two keys yields the same witness
+				in.PartialSigs = []PartialSig{
+					{PubKey: decodeHex(t, "03a1b26313f430c4b15bb1fdce663207659d8cac749a0e53d70eff01874496feff"), Signature: witness[2]},
+					{PubKey: decodeHex(t, "0375e00eb72e29da82b89367947f29ef34afb75e8654f6ea368e0acdfd92976b7c"), Signature: witness[1]},
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signed, p := unsignedPacket(t, tt.txHex)
+			tt.setup(t, signed, p.Inputs[0])
+
+			if _, err := p.Extract(); !errors.Is(err, ErrNotFinalized) {
+				t.Errorf("Expected ErrNotFinalized before finalizing, got %v", err)
+			}
+			if err := p.Finalize(); err != nil {
+				t.Fatalf("Finalize() error = %v", err)
+			}
+			in := p.Inputs[0]
+			if !p.IsComplete() || in.PartialSigs != nil || in.RedeemScript != nil || in.WitnessScript != nil || in.SighashType != nil {
+				t.Error("Expected input to be finalized with its signing fields cleared")
+			}
+			if in.WitnessUtxo == nil {
+				t.Error("Expected the witness UTXO to be kept")
+			}
+
+			tx, err := p.Extract()
+			if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			defer tx.Destroy()
+			raw, err := tx.Bytes()
+			if err != nil {
+				t.Fatalf("Bytes() error = %v", err)
+			}
+			if !bytes.Equal(raw, decodeHex(t, tt.txHex)) {
+				t.Errorf("Expected extracted transaction %s, got %x", tt.txHex, raw)
+			}
+
+			// A finalized packet is verified again and stays unchanged
+			if err := p.Finalize(); err != nil {
+				t.Errorf("Finalize() of finalized packet error = %v", err)
+			}
+		})
+	}
+}
+
+func TestFinalizeErrors(t *testing.T) {
+	signed, p := unsignedPacket(t, p2wshTxHex)
+	witness := signed.TxIn[0].Witness
+	in := p.Inputs[0]
+
+	var inputErr *InputError
+	if err := p.Finalize(); !errors.As(err, &inputErr) || !errors.Is(err, ErrMissingUtxo) {
+		t.Errorf("Expected ErrMissingUtxo, got %v", err)
+	}
+
+	in.WitnessUtxo = wire.NewTxOut(18393430, decodeHex(t, "0020701a8d401c84fb13e6baf169d59684e17abd9fa216c8cc5b9fc63d622ff8c58d"))
+	in.PartialSigs = []PartialSig{{PubKey: decodeHex(t, "0375e00eb72e29da82b89367947f29ef34afb75e8654f6ea368e0acdfd92976b7c"), Signature: witness[1]}}
+	if err := p.Finalize(); !errors.Is(err, ErrMissingScript) {
+		t.Errorf("Expected ErrMissingScript, got %v", err)
+	}
+
+	in.WitnessScript = witness[3]
+	if err := p.Finalize(); !errors.Is(err, ErrMissingSignature) {
+		t.Errorf("Expected ErrMissingSignature with one of two signatures, got %v", err)
+	}
+
+	// Both signatures assigned to keys in the wrong order fail verification
+	in.PartialSigs = []PartialSig{
+		{PubKey: decodeHex(t, "0375e00eb72e29da82b89367947f29ef34afb75e8654f6ea368e0acdfd92976b7c"), Signature: witness[2]},
+		{PubKey: decodeHex(t, "03a1b26313f430c4b15bb1fdce663207659d8cac749a0e53d70eff01874496feff"), Signature: witness[1]},
+	}
+	err := p.Finalize()
+	var execErr *kernel.ScriptExecutionError
+	if !errors.As(err, &execErr) {
+		t.Errorf("Expected *kernel.ScriptExecutionError, got %v", err)
+	}
+	if in.IsFinalized() || len(in.PartialSigs) != 2 {
+		t.Error("Expected a failed Finalize to leave the packet unchanged")
+	}
+
+	in.WitnessUtxo.PkScript = []byte{0x6a}
+	if err := p.Finalize(); !errors.Is(err, ErrUnsupportedScript) {
+		t.Errorf("Expected ErrUnsupportedScript for an OP_RETURN output, got %v", err)
+	}
+}
@@ -0,0 +1,304 @@
+// Package psbt implements Partially Signed Bitcoin Transactions as specified by
+// BIP174 (version 0) and BIP370 (version 2).
+//
+// A Packet can be decoded from and encoded to its binary or base64 form, merged
+// with other packets for the same transaction with Combine, finalized with
+// Finalize and turned into a network serializable kernel.Transaction with Extract.
+// Finalization verifies every input with the kernel's script interpreter, so a
+// finalized packet is guaranteed to spend its inputs validly.
+//
+// Fields without a typed representation, such as hash preimages and proprietary
+// fields, are preserved as Unknowns.
+package psbt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/stringintech/go-bitcoinkernel/wire"
+)
+
+const (
+	// Version0 is the original PSBT version of BIP174, which carries the unsigned transaction.
+	Version0 uint32 = 0
+
+	// Version2 is the PSBT version of BIP370, which carries the transaction fields individually.
+	Version2 uint32 = 2
+)
+
+var (
+	ErrInvalidMagic         = errors.New("invalid PSBT magic bytes")
+	ErrUnsupportedVersion   = errors.New("unsupported PSBT version")
+	ErrDuplicateKey         = errors.New("duplicate key")
+	ErrInvalidKey           = errors.New("invalid key data")
+	ErrInvalidValue         = errors.New("invalid value")
+	ErrFieldNotAllowed      = errors.New("field not allowed in this PSBT version")
+	ErrMissingField         = errors.New("missing required field")
+	ErrUnsignedTxNotEmpty   = errors.New("unsigned transaction has script sigs or witnesses")
+	ErrCountMismatch        = errors.New("number of maps does not match the transaction")
+	ErrUtxoMismatch         = errors.New("non-witness UTXO does not match the spent outpoint")
+	ErrLocktimeConflict     = errors.New("inputs require both a height and a time based lock time")
+	ErrDifferentTransaction = errors.New("PSBTs are for different transactions")
+	ErrMissingUtxo          = errors.New("spent output is unknown")
+	ErrMissingScript        = errors.New("redeem or witness script is missing")
+	ErrMissingSignature     = errors.New("not enough signatures")
+	ErrUnsupportedScript    = errors.New("script type cannot be finalized")
+	ErrNotFinalized         = errors.New("input is not finalized")
+)
+
+// InputError reports an error concerning a specific input of a packet.
+type InputError struct {
+	Index int
+	Err   error
+}
+
+func (e *InputError) Error() string {
+	return fmt.Sprintf("input %d: %v", e.Index, e.Err)
+}
+
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
+
+// KeyOrigin is the BIP32 origin of a key: the fingerprint of the master key and
+// the derivation path from it.
+type KeyOrigin struct {
+	Fingerprint [4]byte
+	Path        []uint32
+}
+
+// XPub is an extended public key used by the inputs or outputs of a packet.
+type XPub struct {
+	ExtendedKey []byte // 78-byte serialized BIP32 extended public key
+	Origin      KeyOrigin
+}
+
+// Bip32Derivation is the origin of a public key used by an input or output.
+type Bip32Derivation struct {
+	PubKey []byte
+	Origin KeyOrigin
+}
+
+// TapBip32Derivation is the origin of an x-only public key used by a taproot
+// input or output, and the leaves it appears in.
+type TapBip32Derivation struct {
+	XOnlyPubKey []byte
+	LeafHashes  [][32]byte
+	Origin      KeyOrigin
+}
+
+// PartialSig is an ECDSA signature, including its sighash byte, by PubKey.
+type PartialSig struct {
+	PubKey    []byte
+	Signature []byte
+}
+
+// TapScriptSig is a Schnorr signature by XOnlyPubKey for the leaf with LeafHash.
+type TapScriptSig struct {
+	XOnlyPubKey []byte
+	LeafHash    [32]byte
+	Signature   []byte
+}
+
+// TapLeafScript is a leaf script of a taproot output, with the control block
+// proving its inclusion.
+type TapLeafScript struct {
+	ControlBlock []byte
+	Script       []byte
+	LeafVersion  byte
+}
+
+// Unknown is a key-value pair without a typed representation. Key includes the
+// key type.
+type Unknown struct {
+	Key   []byte
+	Value []byte
+}
+
+// Packet is a partially signed transaction.
+//
+// Optional fields are nil when absent. Fields marked as version 0 or version 2
+// only must not be set in packets of the other version.
+type Packet struct {
+	Version uint32
+
+	// UnsignedTx is the transaction being signed, with empty script sigs and
+	// witnesses. Version 0 only.
+	UnsignedTx *wire.MsgTx
+
+	TxVersion        uint32  // Version 2 only
+	FallbackLocktime *uint32 // Version 2 only
+	TxModifiable     *byte   // Version 2 only
+
+	XPubs    []XPub
+	Unknowns []Unknown
+	Inputs   []*Input
+	Outputs  []*Output
+}
+
+// Input holds the signing data of a transaction input.
+type Input struct {
+	NonWitnessUtxo     *wire.MsgTx
+	WitnessUtxo        *wire.TxOut
+	PartialSigs        []PartialSig
+	SighashType        *uint32
+	RedeemScript       []byte
+	WitnessScript      []byte
+	Bip32Derivations   []Bip32Derivation
+	FinalScriptSig     []byte
+	FinalScriptWitness wire.TxWitness
+
+	PreviousTxid           wire.Hash // Version 2 only
+	OutputIndex            uint32    // Version 2 only
+	Sequence               *uint32   // Version 2 only
+	RequiredTimeLocktime   *uint32   // Version 2 only
+	RequiredHeightLocktime *uint32   // Version 2 only
+
+	TapKeySig           []byte
+	TapScriptSigs       []TapScriptSig
+	TapLeafScripts      []TapLeafScript
+	TapBip32Derivations []TapBip32Derivation
+	TapInternalKey      []byte
+	TapMerkleRoot       []byte
+
+	Unknowns []Unknown
+}
+
+// Output holds the data describing a transaction output.
+type Output struct {
+	RedeemScript     []byte
+	WitnessScript    []byte
+	Bip32Derivations []Bip32Derivation
+
+	Amount int64  // Version 2 only
+	Script []byte // Version 2 only
+
+	TapInternalKey      []byte
+	TapTree             []byte // Serialized PSBT_OUT_TAP_TREE value
+	TapBip32Derivations []TapBip32Derivation
+
+	Unknowns []Unknown
+}
+
+// New creates a version 0 packet for the unsigned transaction tx, with empty
+// input and output maps.
+//
+// Returns ErrUnsignedTxNotEmpty if tx has script sigs or witnesses.
+func New(tx *wire.MsgTx) (*Packet, error) {
+	for _, in := range tx.TxIn {
+		if len(in.SignatureScript) != 0 || len(in.Witness) != 0 {
+			return nil, ErrUnsignedTxNotEmpty
+		}
+	}
+	p := &Packet{Version: Version0, UnsignedTx: tx.Copy()}
+	for range tx.TxIn {
+		p.Inputs = append(p.Inputs, &Input{})
+	}
+	for range tx.TxOut {
+		p.Outputs = append(p.Outputs, &Output{})
+	}
+	return p, nil
+}
+
+// IsFinalized reports whether the input has a final script sig or witness.
+func (in *Input) IsFinalized() bool {
+	return in.FinalScriptSig != nil || in.FinalScriptWitness != nil
+}
+
+// IsComplete reports whether all inputs of the packet are finalized.
+func (p *Packet) IsComplete() bool {
+	for _, in := range p.Inputs {
+		if !in.IsFinalized() {
+			return false
+		}
+	}
+	return true
+}
+
+// Tx returns the unsigned transaction of the packet, a copy of UnsignedTx for
+// version 0 packets and the transaction built from the individual fields for
+// version 2 packets.
+//
+// Returns an error if the lock time requirements of version 2 inputs conflict.
+func (p *Packet) Tx() (*wire.MsgTx, error) {
+	if p.Version == Version0 {
+		if p.UnsignedTx == nil {
+			return nil, fmt.Errorf("%w: unsigned transaction", ErrMissingField)
+		}
+		return p.UnsignedTx.Copy(), nil
+	}
+
+	locktime, err := p.locktime()
+	if err != nil {
+		return nil, err
+	}
+	tx := wire.NewMsgTx(p.TxVersion)
+	tx.LockTime = locktime
+	for _, in := range p.Inputs {
+		txIn := wire.NewTxIn(wire.NewOutPoint(in.PreviousTxid, in.OutputIndex), nil, nil)
+		if in.Sequence != nil {
+			txIn.Sequence = *in.Sequence
+		}
+		tx.AddTxIn(txIn)
+	}
+	for _, out := range p.Outputs {
+		tx.AddTxOut(wire.NewTxOut(out.Amount, out.Script))
+	}
+	return tx, nil
+}
+
+// locktime determines the lock time of a version 2 packet as specified by BIP370.
+func (p *Packet) locktime() (uint32, error) {
+	var (
+		maxTime, maxHeight   uint32
+		anyRequired          bool
+		heightOnly, timeOnly bool
+	)
+	for _, in := range p.Inputs {
+		if in.RequiredTimeLocktime != nil {
+			maxTime = max(maxTime, *in.RequiredTimeLocktime)
+		}
+		if in.RequiredHeightLocktime != nil {
+			maxHeight = max(maxHeight, *in.RequiredHeightLocktime)
+		}
+		switch {
+		case in.RequiredTimeLocktime != nil && in.RequiredHeightLocktime != nil:
+			anyRequired = true
+		case in.RequiredHeightLocktime != nil:
+			anyRequired, heightOnly = true, true
+		case in.RequiredTimeLocktime != nil:
+			anyRequired, timeOnly = true, true
+		}
+	}
+
+	switch {
+	case !anyRequired:
+		if p.FallbackLocktime != nil {
+			return *p.FallbackLocktime, nil
+		}
+		return 0, nil
+	case heightOnly && timeOnly:
+		return 0, ErrLocktimeConflict
+	case !timeOnly:
+		// Height is preferred when all inputs allow it
+		return maxHeight, nil
+	}
+	return maxTime, nil
+}
+
+// spentOutput returns the output spent by the input at index of tx, taken from
+// its non-witness UTXO if present, and from its witness UTXO otherwise.
+func (p *Packet) spentOutput(tx *wire.MsgTx, index int) (*wire.TxOut, error) {
+	in := p.Inputs[index]
+	if in.NonWitnessUtxo != nil {
+		prevOut := tx.TxIn[index].PreviousOutPoint
+		if in.NonWitnessUtxo.TxHash() != prevOut.Hash || int(prevOut.Index) >= len(in.NonWitnessUtxo.TxOut) {
+			return nil, ErrUtxoMismatch
+		}
+		return in.NonWitnessUtxo.TxOut[prevOut.Index], nil
+	}
+	if in.WitnessUtxo != nil {
+		return in.WitnessUtxo, nil
+	}
+	return nil, ErrMissingUtxo
+}
@@ -0,0 +1,433 @@
+package psbt
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stringintech/go-bitcoinkernel/wire"
+)
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+// testUnsignedTx returns an unsigned transaction with two inputs and two outputs.
+func testUnsignedTx() *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(wire.Hash{0x01}, 0), nil, nil))
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(wire.Hash{0x02}, 3), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(50_000, []byte{0x00, 0x14, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa,
+		0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa}))
+	tx.AddTxOut(wire.NewTxOut(20_000, []byte{0x51}))
+	tx.LockTime = 800_000
+	return tx
+}
+
+func testPubKey(b byte) []byte {
+	return append([]byte{0x02}, bytes.Repeat([]byte{b}, 32)...)
+}
+
+// encodeMaps serializes raw maps, each a list of key type, key data and value triples.
+func encodeMaps(maps ...[][3][]byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(magic)
+	for _, m := range maps {
+		for _, kv := range m {
+			writePair(&buf, uint64(kv[0][0]), kv[1], kv[2])
+		}
+		buf.WriteByte(0x00)
+	}
+	return buf.Bytes()
+}
+
+func TestEncodeDecodeV0(t *testing.T) {
+	p, err := New(testUnsignedTx())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	p.XPubs = []XPub{{ExtendedKey: bytes.Repeat([]byte{0x04}, 78), Origin: KeyOrigin{Fingerprint: [4]byte{1, 2, 3, 4}}}}
+	p.Unknowns = []Unknown{{Key: []byte{0xfc, 0x01}, Value: []byte{0xff}}}
+
+	in := p.Inputs[0]
+	in.WitnessUtxo = wire.NewTxOut(100_000, []byte{0x00, 0x14, 0xbb, 0xbb, 0xbb, 0xbb, 0xbb, 0xbb, 0xbb, 0xbb, 0xbb,
+		0xbb, 0xbb, 0xbb, 0xbb, 0xbb, 0xbb, 0xbb, 0xbb, 0xbb, 0xbb, 0xbb})
+	in.PartialSigs = []PartialSig{{PubKey: testPubKey(0x11), Signature: []byte{0x30, 0x01}}}
+	in.SighashType = uint32Ptr(1)
+	in.Bip32Derivations = []Bip32Derivation{{PubKey: testPubKey(0x11), Origin: KeyOrigin{Fingerprint: [4]byte{1, 2, 3, 4}, Path: []uint32{0x80000054, 0}}}}
+	in.TapScriptSigs = []TapScriptSig{{XOnlyPubKey: bytes.Repeat([]byte{0x22}, 32), LeafHash: [32]byte{0x33}, Signature: bytes.Repeat([]byte{0x44}, 64)}}
+	in.TapLeafScripts = []TapLeafScript{{ControlBlock: bytes.Repeat([]byte{0xc0}, 33), Script: []byte{0x51}, LeafVersion: 0xc0}}
+	in.TapBip32Derivations = []TapBip32Derivation{{XOnlyPubKey: bytes.Repeat([]byte{0x22}, 32), LeafHashes: [][32]byte{{0x33}}, Origin: KeyOrigin{Path: []uint32{1}}}}
+	in.TapInternalKey = bytes.Repeat([]byte{0x55}, 32)
+	p.Inputs[1].FinalScriptWitness = wire.TxWitness{{0x01}, {}}
+
+	p.Outputs[0].RedeemScript = []byte{0x00, 0x14}
+	p.Outputs[1].TapTree = []byte{0x00, 0xc0, 0x01, 0x51}
+
+	encoded, err := p.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	decoded, err := Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	reencoded, err := decoded.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if !bytes.Equal(encoded, reencoded) {
+		t.Errorf("Expected re-encoding to round trip\n%x\n%x", encoded, reencoded)
+	}
+
+	if decoded.UnsignedTx.TxHash() != p.UnsignedTx.TxHash() {
+		t.Error("Expected decoded unsigned transaction to match")
+	}
+	if len(decoded.Inputs[0].PartialSigs) != 1 || !bytes.Equal(decoded.Inputs[0].PartialSigs[0].PubKey, testPubKey(0x11)) {
+		t.Errorf("Expected one partial signature, got %v", decoded.Inputs[0].PartialSigs)
+	}
+	if path := decoded.Inputs[0].Bip32Derivations[0].Origin.Path; len(path) != 2 || path[0] != 0x80000054 {
+		t.Errorf("Expected derivation path [0x80000054 0], got %v", path)
+	}
+	if leaf := decoded.Inputs[0].TapLeafScripts[0]; !bytes.Equal(leaf.Script, []byte{0x51}) || leaf.LeafVersion != 0xc0 {
+		t.Errorf("Expected leaf script 51 with version c0, got %x with version %x", leaf.Script, leaf.LeafVersion)
+	}
+	if !decoded.Inputs[1].IsFinalized() || decoded.Inputs[0].IsFinalized() || decoded.IsComplete() {
+		t.Error("Expected only the second input to be finalized")
+	}
+
+	b64, err := p.EncodeBase64()
+	if err != nil {
+		t.Fatalf("EncodeBase64() error = %v", err)
+	}
+	fromBase64, err := DecodeBase64(b64)
+	if err != nil {
+		t.Fatalf("DecodeBase64() error = %v", err)
+	}
+	if fromBase64.Inputs[0].WitnessUtxo.Value != 100_000 {
+		t.Errorf("Expected witness UTXO value 100000, got %d", fromBase64.Inputs[0].WitnessUtxo.Value)
+	}
+}
+
+func TestEncodeDecodeV2(t *testing.T) {
+	p := &Packet{
+		Version:          Version2,
+		TxVersion:        2,
+		FallbackLocktime: uint32Ptr(100),
+		Inputs: []*Input{
+			{PreviousTxid: wire.Hash{0x01}, OutputIndex: 0, Sequence: uint32Ptr(0xfffffffd), RequiredHeightLocktime: uint32Ptr(1000)},
+			{PreviousTxid: wire.Hash{0x02}, OutputIndex: 3, RequiredHeightLocktime: uint32Ptr(2000), RequiredTimeLocktime: uint32Ptr(600_000_000)},
+		},
+		Outputs: []*Output{{Amount: 50_000, Script: []byte{0x51}}},
+	}
+
+	encoded, err := p.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	decoded, err := Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	reencoded, err := decoded.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if !bytes.Equal(encoded, reencoded) {
+		t.Errorf("Expected re-encoding to round trip\n%x\n%x", encoded, reencoded)
+	}
+
+	tx, err := decoded.Tx()
+	if err != nil {
+		t.Fatalf("Tx() error = %v", err)
+	}
+	if tx.Version != 2 || tx.LockTime != 2000 {
+		t.Errorf("Expected version 2 and lock time 2000, got %d and %d", tx.Version, tx.LockTime)
+	}
+	if len(tx.TxIn) != 2 || tx.TxIn[0].Sequence != 0xfffffffd || tx.TxIn[1].Sequence != wire.MaxTxInSequenceNum {
+		t.Errorf("Expected sequences fffffffd and ffffffff, got %v", tx.TxIn)
+	}
+	if tx.TxIn[1].PreviousOutPoint != *wire.NewOutPoint(wire.Hash{0x02}, 3) {
+		t.Errorf("Expected second input to spend %s:3, got %s", wire.Hash{0x02}, tx.TxIn[1].PreviousOutPoint)
+	}
+	if len(tx.TxOut) != 1 || tx.TxOut[0].Value != 50_000 {
+		t.Errorf("Expected one output of 50000, got %v", tx.TxOut)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	var unsignedTx bytes.Buffer
+	if err := testUnsignedTx().SerializeNoWitness(&unsignedTx); err != nil {
+		t.Fatalf("SerializeNoWitness() error = %v", err)
+	}
+	signedTx := testUnsignedTx()
+	signedTx.TxIn[0].SignatureScript = []byte{0x51}
+	var signedTxBytes bytes.Buffer
+	if err := signedTx.SerializeNoWitness(&signedTxBytes); err != nil {
+		t.Fatalf("SerializeNoWitness() error = %v", err)
+	}
+
+	type kv = [3][]byte
+	globalV0 := []kv{{{globalUnsignedTx}, nil, unsignedTx.Bytes()}}
+	globalV2 := []kv{
+		{{globalTxVersion}, nil, []byte{2, 0, 0, 0}},
+		{{globalInputCount}, nil, []byte{1}},
+		{{globalOutputCount}, nil, []byte{0}},
+		{{globalVersion}, nil, []byte{2, 0, 0, 0}},
+	}
+	// v2Counts returns v2 globals declaring the given input and output counts
+	v2Counts := func(inputCount, outputCount uint64) []kv {
+		compactSize := func(n uint64) []byte {
+			var b bytes.Buffer
+			if err := wire.WriteVarInt(&b, n); err != nil {
+				t.Fatalf("WriteVarInt() error = %v", err)
+			}
+			return b.Bytes()
+		}
+		return []kv{
+			{{globalTxVersion}, nil, []byte{2, 0, 0, 0}},
+			{{globalInputCount}, nil, compactSize(inputCount)},
+			{{globalOutputCount}, nil, compactSize(outputCount)},
+			{{globalVersion}, nil, []byte{2, 0, 0, 0}},
+		}
+	}
+	v2Input := []kv{
+		{{inPreviousTxid}, nil, make([]byte, 32)},
+		{{inOutputIndex}, nil, []byte{0, 0, 0, 0}},
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{
+			name:    "invalid_magic",
+			data:    []byte("psbu\xff\x00"),
+			wantErr: ErrInvalidMagic,
+		},
+		{
+			name:    "unsupported_version",
+			data:    encodeMaps(append(globalV0, kv{{globalVersion}, nil, []byte{1, 0, 0, 0}})),
+			wantErr: ErrUnsupportedVersion,
+		},
+		{
+			name:    "duplicate_key",
+			data:    encodeMaps(append(globalV0, globalV0...)),
+			wantErr: ErrDuplicateKey,
+		},
+		{
+			name:    "v0_missing_unsigned_tx",
+			data:    encodeMaps(nil),
+			wantErr: ErrMissingField,
+		},
+		{
+			name:    "v0_unsigned_tx_with_script_sig",
+			data:    encodeMaps([]kv{{{globalUnsignedTx}, nil, signedTxBytes.Bytes()}}, nil, nil, nil, nil),
+			wantErr: ErrUnsignedTxNotEmpty,
+		},
+		{
+			name:    "v0_missing_maps",
+			data:    encodeMaps(globalV0, nil, nil, nil),
+			wantErr: ErrCountMismatch,
+		},
+		{
+			name:    "v0_extra_map",
+			data:    encodeMaps(globalV0, nil, nil, nil, nil, nil),
+			wantErr: ErrCountMismatch,
+		},
+		{
+			name:    "v0_input_with_v2_field",
+			data:    encodeMaps(globalV0, []kv{{{inSequence}, nil, []byte{0, 0, 0, 0}}}, nil, nil, nil),
+			wantErr: ErrFieldNotAllowed,
+		},
+		{
+			name:    "v0_with_v2_global",
+			data:    encodeMaps(append(globalV0, kv{{globalTxVersion}, nil, []byte{2, 0, 0, 0}}), nil, nil, nil, nil),
+			wantErr: ErrFieldNotAllowed,
+		},
+		{
+			name:    "v2_oversized_counts_overflowing_sum",
+			data:    encodeMaps(v2Counts(1<<63, 1<<63), v2Input),
+			wantErr: ErrCountMismatch,
+		},
+		{
+			name:    "v2_oversized_input_count",
+			data:    encodeMaps(v2Counts(math.MaxUint64, 0), v2Input),
+			wantErr: ErrCountMismatch,
+		},
+		{
+			name:    "v2_oversized_output_count",
+			data:    encodeMaps(v2Counts(1, math.MaxUint64), v2Input),
+			wantErr: ErrCountMismatch,
+		},
+		{
+			name:    "v2_missing_previous_txid",
+			data:    encodeMaps(globalV2, v2Input[1:]),
+			wantErr: ErrMissingField,
+		},
+		{
+			name:    "v2_with_unsigned_tx",
+			data:    encodeMaps(append(globalV2, globalV0...), v2Input),
+			wantErr: ErrFieldNotAllowed,
+		},
+		{
+			name:    "partial_sig_invalid_pubkey",
+			data:    encodeMaps(globalV2, append(v2Input, kv{{inPartialSig}, []byte{0x02, 0x01}, []byte{0x30}})),
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "sighash_type_key_data",
+			data:    encodeMaps(globalV2, append(v2Input, kv{{inSighashType}, []byte{0x00}, []byte{1, 0, 0, 0}})),
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "tap_key_sig_invalid_size",
+			data:    encodeMaps(globalV2, append(v2Input, kv{{inTapKeySig}, nil, make([]byte, 63)})),
+			wantErr: ErrInvalidValue,
+		},
+		{
+			name:    "required_time_locktime_below_threshold",
+			data:    encodeMaps(globalV2, append(v2Input, kv{{inRequiredTimeLocktime}, nil, []byte{1, 0, 0, 0}})),
+			wantErr: ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDecodeNonWitnessUtxoMismatch(t *testing.T) {
+	p, err := New(testUnsignedTx())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	p.Inputs[1].NonWitnessUtxo = testUnsignedTx()
+	encoded, err := p.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	_, err = Decode(encoded)
+	var inputErr *InputError
+	if !errors.As(err, &inputErr) || inputErr.Index != 1 || !errors.Is(err, ErrUtxoMismatch) {
+		t.Errorf("Expected ErrUtxoMismatch for input 1, got %v", err)
+	}
+}
+
+func TestLocktime(t *testing.T) {
+	tests := []struct {
+		name     string
+		fallback *uint32
+		inputs   [][2]*uint32 // Required time and height lock times
+		want     uint32
+		wantErr  error
+	}{
+		{
+			name:   "no_requirements",
+			inputs: [][2]*uint32{{nil, nil}},
+			want:   0,
+		},
+		{
+			name:     "fallback",
+			fallback: uint32Ptr(123),
+			inputs:   [][2]*uint32{{nil, nil}},
+			want:     123,
+		},
+		{
+			name:     "max_height",
+			fallback: uint32Ptr(123),
+			inputs:   [][2]*uint32{{nil, uint32Ptr(10)}, {nil, uint32Ptr(20)}, {nil, nil}},
+			want:     20,
+		},
+		{
+			name:   "max_time",
+			inputs: [][2]*uint32{{uint32Ptr(500_000_001), nil}, {uint32Ptr(500_000_002), uint32Ptr(5)}},
+			want:   500_000_002,
+		},
+		{
+			name:   "height_preferred",
+			inputs: [][2]*uint32{{uint32Ptr(500_000_001), uint32Ptr(5)}, {nil, uint32Ptr(7)}},
+			want:   7,
+		},
+		{
+			name:    "conflict",
+			inputs:  [][2]*uint32{{uint32Ptr(500_000_001), nil}, {nil, uint32Ptr(7)}},
+			wantErr: ErrLocktimeConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Packet{Version: Version2, TxVersion: 2, FallbackLocktime: tt.fallback}
+			for _, required := range tt.inputs {
+				p.Inputs = append(p.Inputs, &Input{RequiredTimeLocktime: required[0], RequiredHeightLocktime: required[1]})
+			}
+			tx, err := p.Tx()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && tx.LockTime != tt.want {
+				t.Errorf("Expected lock time %d, got %d", tt.want, tx.LockTime)
+			}
+		})
+	}
+}
+
+func TestCombine(t *testing.T) {
+	first, err := New(testUnsignedTx())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	second, err := New(testUnsignedTx())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	first.Inputs[0].PartialSigs = []PartialSig{{PubKey: testPubKey(0x11), Signature: []byte{0x01}}}
+	first.Inputs[0].WitnessScript = []byte{0x51}
+	second.Inputs[0].PartialSigs = []PartialSig{
+		{PubKey: testPubKey(0x11), Signature: []byte{0x02}},
+		{PubKey: testPubKey(0x22), Signature: []byte{0x03}},
+	}
+	second.Inputs[0].WitnessScript = []byte{0x52}
+	second.Inputs[1].SighashType = uint32Ptr(1)
+	second.Outputs[1].Unknowns = []Unknown{{Key: []byte{0xfc}, Value: []byte{0x01}}}
+
+	combined, err := Combine(first, second)
+	if err != nil {
+		t.Fatalf("Combine() error = %v", err)
+	}
+	sigs := combined.Inputs[0].PartialSigs
+	if len(sigs) != 2 || !bytes.Equal(sigs[0].Signature, []byte{0x01}) || !bytes.Equal(sigs[1].PubKey, testPubKey(0x22)) {
+		t.Errorf("Expected the union of partial signatures, got %v", sigs)
+	}
+	if !bytes.Equal(combined.Inputs[0].WitnessScript, []byte{0x51}) {
+		t.Errorf("Expected the witness script of the first packet, got %x", combined.Inputs[0].WitnessScript)
+	}
+	if combined.Inputs[1].SighashType == nil || *combined.Inputs[1].SighashType != 1 {
+		t.Error("Expected the sighash type of the second packet")
+	}
+	if len(combined.Outputs[1].Unknowns) != 1 {
+		t.Errorf("Expected one unknown output field, got %d", len(combined.Outputs[1].Unknowns))
+	}
+	if len(first.Inputs[0].PartialSigs) != 1 {
+		t.Error("Expected Combine to leave its inputs unmodified")
+	}
+
+	other := testUnsignedTx()
+	other.LockTime++
+	third, err := New(other)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := Combine(first, third); !errors.Is(err, ErrDifferentTransaction) {
+		t.Errorf("Expected ErrDifferentTransaction, got %v", err)
+	}
+}
@@ -0,0 +1,325 @@
+// Package regtest mines valid regtest blocks on top of a kernel.ChainstateManager,
+// so that consensus tests can build the chains they need instead of relying on
+// pre-generated block data.
+//
+// Blocks are built deterministically: given the same sequence of calls on a fresh
+// chainstate, a Generator produces the same blocks.
+package regtest
+
+import (
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+	"github.com/stringintech/go-bitcoinkernel/wire"
+)
+
+const (
+	// CoinbaseMaturity is the number of confirmations a coinbase output needs before it can be spent.
+	CoinbaseMaturity = 100
+
+	// initialSubsidy is the block reward in satoshis before the first halving.
+	initialSubsidy = 50 * 100_000_000
+
+	// subsidyHalvingInterval is the number of blocks between subsidy halvings on regtest.
+	subsidyHalvingInterval = 150
+
+	// blockVersion is the version of generated block headers, signalling BIP9 with no deployments.
+	blockVersion = 0x20000000
+)
+
+var (
+	// OpTrueScript is an anyone-can-spend script. It is the default coinbase script
+	// pubkey, so that coinbase outputs can be spent without signatures.
+	OpTrueScript = []byte{0x51}
+
+	ErrInsufficientFunds = errors.New("outputs exceed the value of the spent coinbase")
+	ErrNotOpTrue         = errors.New("coinbase output is not spendable without a signature")
+)
+
+// witnessCommitmentHeader prefixes the BIP141 witness commitment in the coinbase.
+var witnessCommitmentHeader = []byte{0x6a, 0x24, 0xaa, 0x21, 0xa9, 0xed}
+
+// Generator builds, mines and submits regtest blocks.
+type Generator struct {
+	manager        *kernel.ChainstateManager
+	coinbaseScript []byte
+	extraNonce     int64
+}
+
+// Option configures a Generator.
+type Option func(*Generator)
+
+// WithCoinbaseScript sets the script pubkey that coinbase rewards are paid to.
+//
+// Coinbase outputs that do not pay to OpTrueScript cannot be spent with SpendCoinbase.
+func WithCoinbaseScript(script []byte) Option {
+	return func(g *Generator) {
+		g.coinbaseScript = script
+	}
+}
+
+// NewGenerator returns a generator that submits blocks to manager.
+//
+// The chainstate manager must be configured for regtest.
+func NewGenerator(manager *kernel.ChainstateManager, options ...Option) *Generator {
+	g := &Generator{
+		manager:        manager,
+		coinbaseScript: OpTrueScript,
+	}
+	for _, opt := range options {
+		opt(g)
+	}
+	return g
+}
+
+// Tip returns the block tree entry of the active chain tip.
+func (g *Generator) Tip() *kernel.BlockTreeEntry {
+	chain := g.manager.GetActiveChain()
+	return chain.GetByHeight(chain.GetHeight())
+}
+
+// Generate mines n empty blocks on top of the active chain tip.
+//
+// Returns the submitted blocks in order.
+func (g *Generator) Generate(n int) ([]*kernel.Block, error) {
+	return g.GenerateOn(g.Tip(), n)
+}
+
+// GenerateOn mines n empty blocks on top of parent, which does not have to be the
+// active chain tip. Building on an earlier block creates a fork, which triggers a
+// reorg once it has more work than the active chain.
+//
+// Returns the submitted blocks in order.
+func (g *Generator) GenerateOn(parent *kernel.BlockTreeEntry, n int) ([]*kernel.Block, error) {
+	blocks := make([]*kernel.Block, 0, n)
+	prev, err := entryHeader(parent)
+	if err != nil {
+		return nil, err
+	}
+	height := parent.Height()
+	for range n {
+		height++
+		msg := g.buildBlock(prev, height, nil)
+		block, err := g.Submit(msg)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+		prev = &msg.Header
+	}
+	return blocks, nil
+}
+
+// GenerateWithTransactions mines a single block containing txs on top of the active
+// chain tip and submits it.
+func (g *Generator) GenerateWithTransactions(txs ...*wire.MsgTx) (*kernel.Block, error) {
+	msg, err := g.BuildBlock(g.Tip(), txs...)
+	if err != nil {
+		return nil, err
+	}
+	return g.Submit(msg)
+}
+
+// BuildBlock builds and mines a block containing txs on top of parent without
+// submitting it. The block can be modified before calling Submit, e.g. to construct
+// invalid blocks, in which case it has to be re-mined with Mine.
+//
+// Transaction fees are not claimed by the coinbase.
+func (g *Generator) BuildBlock(parent *kernel.BlockTreeEntry, txs ...*wire.MsgTx) (*wire.MsgBlock, error) {
+	prev, err := entryHeader(parent)
+	if err != nil {
+		return nil, err
+	}
+	return g.buildBlock(prev, parent.Height()+1, txs), nil
+}
+
+// Submit processes the block with the chainstate manager.
+//
+// Returns the kernel block, or the error reported by ChainstateManager.ProcessBlock.
+func (g *Generator) Submit(msg *wire.MsgBlock) (*kernel.Block, error) {
+	block, err := kernel.NewBlockFromWire(msg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := g.manager.ProcessBlock(block); err != nil {
+		block.Destroy()
+		return nil, err
+	}
+	return block, nil
+}
+
+// SpendCoinbase returns a transaction spending the coinbase output of the block at
+// entry to the given outputs. The difference between the coinbase value and the
+// outputs is left as fee.
+//
+// The coinbase must pay to OpTrueScript and be mature, i.e. have at least
+// CoinbaseMaturity confirmations, by the time the transaction is included in a block.
+func (g *Generator) SpendCoinbase(entry *kernel.BlockTreeEntry, outputs ...*wire.TxOut) (*wire.MsgTx, error) {
+	block, err := g.manager.ReadBlock(entry)
+	if err != nil {
+		return nil, err
+	}
+	defer block.Destroy()
+	msg, err := block.ToWire()
+	if err != nil {
+		return nil, err
+	}
+
+	coinbase := msg.Transactions[0]
+	coinbaseOut := coinbase.TxOut[0]
+	if string(coinbaseOut.PkScript) != string(OpTrueScript) {
+		return nil, ErrNotOpTrue
+	}
+
+	var total int64
+	for _, out := range outputs {
+		total += out.Value
+	}
+	if total > coinbaseOut.Value {
+		return nil, ErrInsufficientFunds
+	}
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(coinbase.TxHash(), 0), nil, nil))
+	for _, out := range outputs {
+		tx.AddTxOut(out)
+	}
+	return tx, nil
+}
+
+// Mine updates the merkle root and witness commitment of the block and grinds
+// the header nonce until the proof of work is valid.
+func Mine(msg *wire.MsgBlock) {
+	updateWitnessCommitment(msg)
+	msg.UpdateMerkleRoot()
+
+	target := compactToBig(msg.Header.Bits)
+	for msg.Header.Nonce = 0; ; msg.Header.Nonce++ {
+		if hashToBig(msg.Header.BlockHash()).Cmp(target) <= 0 {
+			return
+		}
+		if msg.Header.Nonce == math.MaxUint32 {
+			// Nonce space exhausted, which is practically impossible at regtest difficulty
+			msg.Header.Timestamp++
+		}
+	}
+}
+
+// Subsidy returns the block reward in satoshis at the given regtest height.
+func Subsidy(height int32) int64 {
+	halvings := height / subsidyHalvingInterval
+	if halvings >= 64 {
+		return 0
+	}
+	return initialSubsidy >> halvings
+}
+
+func (g *Generator) buildBlock(prev *wire.BlockHeader, height int32, txs []*wire.MsgTx) *wire.MsgBlock {
+	msg := wire.NewMsgBlock(&wire.BlockHeader{
+		Version:   blockVersion,
+		PrevBlock: prev.BlockHash(),
+		Timestamp: prev.Timestamp + 1,
+		Bits:      prev.Bits,
+	})
+	msg.AddTransaction(g.coinbase(height))
+	for _, tx := range txs {
+		msg.AddTransaction(tx)
+	}
+	Mine(msg)
+	return msg
+}
+
+// coinbase builds a coinbase transaction for the given height. The extra nonce makes
+// sibling blocks built on the same parent distinct.
+func (g *Generator) coinbase(height int32) *wire.MsgTx {
+	scriptSig := append(scriptNum(int64(height)), scriptNum(g.extraNonce)...)
+	g.extraNonce++
+
+	// BIP141 witness reserved value
+	witness := [][]byte{make([]byte, 32)}
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(wire.Hash{}, wire.MaxPrevOutIndex), scriptSig, witness))
+	tx.AddTxOut(wire.NewTxOut(Subsidy(height), g.coinbaseScript))
+	tx.AddTxOut(wire.NewTxOut(0, nil))
+	return tx
+}
+
+// updateWitnessCommitment sets the witness commitment output of the coinbase, the
+// last output of the coinbase, to commit to the block's witness merkle root.
+func updateWitnessCommitment(msg *wire.MsgBlock) {
+	coinbase := msg.Transactions[0]
+	root := msg.WitnessMerkleRoot()
+	reserved := coinbase.TxIn[0].Witness[0]
+
+	var preimage []byte
+	preimage = append(preimage, root[:]...)
+	preimage = append(preimage, reserved...)
+	commitment := wire.DoubleHashH(preimage)
+
+	script := append([]byte{}, witnessCommitmentHeader...)
+	script = append(script, commitment[:]...)
+	coinbase.TxOut[len(coinbase.TxOut)-1].PkScript = script
+}
+
+func entryHeader(entry *kernel.BlockTreeEntry) (*wire.BlockHeader, error) {
+	if entry == nil {
+		return nil, errors.New("parent block tree entry is nil")
+	}
+	header := entry.Header()
+	defer header.Destroy()
+	return header.ToWire()
+}
+
+// scriptNum returns the minimal script push of n, as CScript() << n does in Core.
+func scriptNum(n int64) []byte {
+	if n == 0 {
+		return []byte{0x00}
+	}
+	if n == -1 || (n >= 1 && n <= 16) {
+		return []byte{byte(0x50 + n)}
+	}
+
+	negative := n < 0
+	abs := n
+	if negative {
+		abs = -n
+	}
+	var num []byte
+	for abs > 0 {
+		num = append(num, byte(abs&0xff))
+		abs >>= 8
+	}
+	if num[len(num)-1]&0x80 != 0 {
+		if negative {
+			num = append(num, 0x80)
+		} else {
+			num = append(num, 0x00)
+		}
+	} else if negative {
+		num[len(num)-1] |= 0x80
+	}
+	return append([]byte{byte(len(num))}, num...)
+}
+
+// compactToBig converts a compact difficulty target to a big integer.
+func compactToBig(compact uint32) *big.Int {
+	mantissa := int64(compact & 0x007fffff)
+	exponent := uint(compact >> 24)
+	target := big.NewInt(mantissa)
+	if exponent <= 3 {
+		return target.Rsh(target, 8*(3-exponent))
+	}
+	return target.Lsh(target, 8*(exponent-3))
+}
+
+// hashToBig interprets a hash in internal byte order as a little-endian number.
+func hashToBig(hash wire.Hash) *big.Int {
+	var reversed [wire.HashSize]byte
+	for i := range hash {
+		reversed[wire.HashSize-1-i] = hash[i]
+	}
+	return new(big.Int).SetBytes(reversed[:])
+}
@@ -0,0 +1,168 @@
+package regtest
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stringintech/go-bitcoinkernel/internal/testutil"
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+	"github.com/stringintech/go-bitcoinkernel/wire"
+)
+
+func TestGenerate(t *testing.T) {
+	manager := testutil.NewChainstateManager(t, testutil.NewContext(t), t.TempDir())
+	gen := NewGenerator(manager)
+
+	blocks, err := gen.Generate(10)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(blocks) != 10 {
+		t.Fatalf("Expected 10 blocks, got %d", len(blocks))
+	}
+	if height := manager.GetActiveChain().GetHeight(); height != 10 {
+		t.Errorf("Expected chain height 10, got %d", height)
+	}
+	if gen.Tip().Hash().Bytes() != blocks[9].Hash().Bytes() {
+		t.Error("Expected the last generated block to be the tip")
+	}
+
+	// A second generator on a fresh chainstate produces the same blocks
+	other, err := NewGenerator(testutil.NewChainstateManager(t, testutil.NewContext(t), t.TempDir())).Generate(10)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for i := range blocks {
+		if blocks[i].Hash().Bytes() != other[i].Hash().Bytes() {
+			t.Fatalf("Expected deterministic block %d", i+1)
+		}
+	}
+}
+
+func TestSpendCoinbase(t *testing.T) {
+	manager := testutil.NewChainstateManager(t, testutil.NewContext(t), t.TempDir())
+	gen := NewGenerator(manager)
+
+	if _, err := gen.Generate(CoinbaseMaturity + 1); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	p2wpkh := append([]byte{0x00, 0x14}, bytes.Repeat([]byte{0xab}, 20)...)
+	spendable := manager.GetActiveChain().GetByHeight(1)
+
+	_, err := gen.SpendCoinbase(spendable, wire.NewTxOut(Subsidy(1)+1, p2wpkh))
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("Expected ErrInsufficientFunds, got %v", err)
+	}
+
+	tx, err := gen.SpendCoinbase(spendable, wire.NewTxOut(Subsidy(1)-1000, p2wpkh))
+	if err != nil {
+		t.Fatalf("SpendCoinbase() error = %v", err)
+	}
+
+	block, err := gen.GenerateWithTransactions(tx)
+	if err != nil {
+		t.Fatalf("GenerateWithTransactions() error = %v", err)
+	}
+	if block.CountTransactions() != 2 {
+		t.Errorf("Expected 2 transactions, got %d", block.CountTransactions())
+	}
+	if height := manager.GetActiveChain().GetHeight(); height != CoinbaseMaturity+2 {
+		t.Errorf("Expected chain height %d, got %d", CoinbaseMaturity+2, height)
+	}
+
+	// Spending the same coinbase again is rejected
+	_, err = gen.GenerateWithTransactions(tx)
+	var validationErr *kernel.BlockValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *BlockValidationError for double spend, got %v", err)
+	}
+	if validationErr.RejectReason != "bad-txns-inputs-missingorspent" {
+		t.Errorf("Expected reject reason bad-txns-inputs-missingorspent, got %q", validationErr.RejectReason)
+	}
+}
+
+func TestImmatureCoinbaseSpend(t *testing.T) {
+	manager := testutil.NewChainstateManager(t, testutil.NewContext(t), t.TempDir())
+	gen := NewGenerator(manager)
+
+	if _, err := gen.Generate(10); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	tx, err := gen.SpendCoinbase(manager.GetActiveChain().GetByHeight(1), wire.NewTxOut(1000, OpTrueScript))
+	if err != nil {
+		t.Fatalf("SpendCoinbase() error = %v", err)
+	}
+
+	_, err = gen.GenerateWithTransactions(tx)
+	var validationErr *kernel.BlockValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *BlockValidationError, got %v", err)
+	}
+	if validationErr.RejectReason != "bad-txns-premature-spend-of-coinbase" {
+		t.Errorf("Expected reject reason bad-txns-premature-spend-of-coinbase, got %q", validationErr.RejectReason)
+	}
+}
+
+func TestReorg(t *testing.T) {
+	manager := testutil.NewChainstateManager(t, testutil.NewContext(t), t.TempDir())
+	gen := NewGenerator(manager)
+
+	if _, err := gen.Generate(5); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	chain := manager.GetActiveChain()
+	forkPoint := chain.GetByHeight(3)
+	oldTip := gen.Tip()
+
+	// A shorter fork does not become active
+	fork, err := gen.GenerateOn(forkPoint, 2)
+	if err != nil {
+		t.Fatalf("GenerateOn() error = %v", err)
+	}
+	if gen.Tip().Hash().Bytes() != oldTip.Hash().Bytes() {
+		t.Error("Expected the tip to remain unchanged for a fork with equal work")
+	}
+
+	// Extending the fork beyond the active chain triggers a reorg
+	forkTip := manager.GetBlockTreeEntryByHash(fork[1].Hash())
+	if forkTip == nil {
+		t.Fatal("Expected the fork tip to be known")
+	}
+	extended, err := gen.GenerateOn(forkTip, 1)
+	if err != nil {
+		t.Fatalf("GenerateOn() error = %v", err)
+	}
+	if height := chain.GetHeight(); height != 6 {
+		t.Errorf("Expected chain height 6 after reorg, got %d", height)
+	}
+	if gen.Tip().Hash().Bytes() != extended[0].Hash().Bytes() {
+		t.Error("Expected the extended fork to become the active chain")
+	}
+	if chain.Contains(oldTip) {
+		t.Error("Expected the old tip to be disconnected")
+	}
+}
+
+func TestScriptNum(t *testing.T) {
+	tests := []struct {
+		n        int64
+		expected []byte
+	}{
+		{0, []byte{0x00}},
+		{1, []byte{0x51}},
+		{16, []byte{0x60}},
+		{17, []byte{0x01, 0x11}},
+		{127, []byte{0x01, 0x7f}},
+		{128, []byte{0x02, 0x80, 0x00}},
+		{256, []byte{0x02, 0x00, 0x01}},
+		{-1, []byte{0x4f}},
+		{-128, []byte{0x02, 0x80, 0x80}},
+	}
+	for _, tt := range tests {
+		if got := scriptNum(tt.n); !bytes.Equal(got, tt.expected) {
+			t.Errorf("scriptNum(%d) = %x, expected %x", tt.n, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package regtest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stringintech/go-bitcoinkernel/internal/testutil"
+	"github.com/stringintech/go-bitcoinkernel/kernel"
+)
+
+func TestPruneBlockFiles(t *testing.T) {
+	// Small block files let a chain of empty blocks span several files, as only
+	// whole files are pruned and the file being written is never pruned
+	manager := testutil.NewChainstateManager(t, testutil.NewContext(t), t.TempDir(),
+		kernel.WithPruneTarget(kernel.PruneTargetManual),
+		kernel.WithFastPrune(),
+	)
+	gen := NewGenerator(manager)
+
+	// Regtest chains are only pruned once they are 1000 blocks long
+	if _, err := gen.Generate(999); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	chain := manager.GetActiveChain()
+	if err := manager.PruneBlockFilesUpTo(500); err == nil {
+		t.Error("Expected PruneBlockFilesUpTo() to fail for a chain of 999 blocks")
+	}
+	if _, err := gen.Generate(101); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if err := manager.PruneBlockFilesUpTo(chain.GetHeight() + 1); err == nil {
+		t.Error("Expected PruneBlockFilesUpTo() to fail above the tip")
+	}
+
+	pruned, kept := chain.GetByHeight(100), chain.GetByHeight(900)
+	if manager.IsBlockPruned(pruned) {
+		t.Fatal("Expected block to not be pruned before pruning")
+	}
+	if err := manager.PruneBlockFilesUpTo(500); err != nil {
+		t.Fatalf("PruneBlockFilesUpTo() error = %v", err)
+	}
+
+	if !manager.IsBlockPruned(pruned) {
+		t.Error("Expected block at height 100 to be pruned")
+	}
+	if status := pruned.Status(); status&(kernel.BlockStatusHaveData|kernel.BlockStatusHaveUndo) != 0 {
+		t.Errorf("Expected pruned block without data and undo data, got status %#x", status)
+	}
+	if _, err := manager.ReadBlock(pruned); !errors.Is(err, kernel.ErrBlockPruned) {
+		t.Errorf("Expected ErrBlockPruned from ReadBlock(), got %v", err)
+	}
+	if _, err := manager.ReadBlockSpentOutputs(pruned); !errors.Is(err, kernel.ErrBlockPruned) {
+		t.Errorf("Expected ErrBlockPruned from ReadBlockSpentOutputs(), got %v", err)
+	}
+
+	// Blocks above the prune height keep their data
+	if manager.IsBlockPruned(kept) || !kept.Status().Has(kernel.BlockStatusHaveData|kernel.BlockStatusHaveUndo) {
+		t.Errorf("Expected block at height 900 to keep its data, got status %#x", kept.Status())
+	}
+	block, err := manager.ReadBlock(kept)
+	if err != nil {
+		t.Fatalf("ReadBlock() error = %v", err)
+	}
+	block.Destroy()
+	spentOutputs, err := manager.ReadBlockSpentOutputs(kept)
+	if err != nil {
+		t.Fatalf("ReadBlockSpentOutputs() error = %v", err)
+	}
+	spentOutputs.Destroy()
+
+	// Pruned blocks stay part of the chain, which can be extended
+	if _, err := gen.Generate(1); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if height := chain.GetHeight(); height != 1101 {
+		t.Errorf("Expected chain height 1101, got %d", height)
+	}
+}
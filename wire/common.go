@@ -0,0 +1,215 @@
+// Package wire provides native Go representations of Bitcoin blocks and transactions
+// together with their consensus serialization, including the BIP144 witness encoding.
+//
+// The types in this package do not depend on the kernel library, so they can be freely
+// constructed and mutated in Go before being handed to the kernel package for validation.
+package wire
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// HashSize is the size in bytes of a double-SHA256 hash.
+	HashSize = 32
+
+	// MaxBlockSize is the maximum serialized size of a block, including witness data.
+	// It bounds every length prefix read while decoding.
+	MaxBlockSize = 4_000_000
+)
+
+var (
+	ErrNonCanonicalVarInt = errors.New("non-canonical variable length integer")
+	ErrSizeTooLarge       = errors.New("length prefix exceeds maximum block size")
+	ErrTrailingData       = errors.New("unexpected trailing data after message")
+)
+
+// Hash is a 32-byte double-SHA256 hash stored in internal byte order.
+type Hash [HashSize]byte
+
+// DoubleHashH returns sha256(sha256(b)).
+func DoubleHashH(b []byte) Hash {
+	first := sha256.Sum256(b)
+	return sha256.Sum256(first[:])
+}
+
+// NewHashFromStr decodes a hash from its hex string representation, which uses
+// the reversed (display) byte order.
+func NewHashFromStr(s string) (Hash, error) {
+	var h Hash
+	if len(s) != HashSize*2 {
+		return h, fmt.Errorf("invalid hash length %d, expected %d hex characters", len(s), HashSize*2)
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return h, err
+	}
+	for i := range HashSize {
+		h[i] = b[HashSize-1-i]
+	}
+	return h, nil
+}
+
+// String returns the hash as a hex string in reversed (display) byte order.
+func (h Hash) String() string {
+	var reversed Hash
+	for i := range HashSize {
+		reversed[i] = h[HashSize-1-i]
+	}
+	return hex.EncodeToString(reversed[:])
+}
+
+// ReadVarInt reads a Bitcoin CompactSize unsigned integer.
+//
+// Non-canonical encodings are rejected, matching the consensus rules.
+func ReadVarInt(r io.Reader) (uint64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:1]); err != nil {
+		return 0, err
+	}
+	switch prefix := buf[0]; prefix {
+	case 0xfd:
+		if _, err := io.ReadFull(r, buf[:2]); err != nil {
+			return 0, err
+		}
+		v := uint64(binary.LittleEndian.Uint16(buf[:2]))
+		if v < 0xfd {
+			return 0, ErrNonCanonicalVarInt
+		}
+		return v, nil
+	case 0xfe:
+		if _, err := io.ReadFull(r, buf[:4]); err != nil {
+			return 0, err
+		}
+		v := uint64(binary.LittleEndian.Uint32(buf[:4]))
+		if v <= 0xffff {
+			return 0, ErrNonCanonicalVarInt
+		}
+		return v, nil
+	case 0xff:
+		if _, err := io.ReadFull(r, buf[:8]); err != nil {
+			return 0, err
+		}
+		v := binary.LittleEndian.Uint64(buf[:8])
+		if v <= 0xffffffff {
+			return 0, ErrNonCanonicalVarInt
+		}
+		return v, nil
+	default:
+		return uint64(prefix), nil
+	}
+}
+
+// WriteVarInt writes v as a Bitcoin CompactSize unsigned integer.
+func WriteVarInt(w io.Writer, v uint64) error {
+	var buf [9]byte
+	var n int
+	switch {
+	case v < 0xfd:
+		buf[0] = byte(v)
+		n = 1
+	case v <= 0xffff:
+		buf[0] = 0xfd
+		binary.LittleEndian.PutUint16(buf[1:], uint16(v))
+		n = 3
+	case v <= 0xffffffff:
+		buf[0] = 0xfe
+		binary.LittleEndian.PutUint32(buf[1:], uint32(v))
+		n = 5
+	default:
+		buf[0] = 0xff
+		binary.LittleEndian.PutUint64(buf[1:], v)
+		n = 9
+	}
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+// VarIntSerializeSize returns the number of bytes needed to encode v as a CompactSize.
+func VarIntSerializeSize(v uint64) int {
+	switch {
+	case v < 0xfd:
+		return 1
+	case v <= 0xffff:
+		return 3
+	case v <= 0xffffffff:
+		return 5
+	default:
+		return 9
+	}
+}
+
+// ReadVarBytes reads a CompactSize length prefix followed by that many bytes.
+func ReadVarBytes(r io.Reader) ([]byte, error) {
+	n, err := readCount(r)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// WriteVarBytes writes a CompactSize length prefix followed by b.
+func WriteVarBytes(w io.Writer, b []byte) error {
+	if err := WriteVarInt(w, uint64(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// readCount reads a CompactSize used as a length or element count and bounds it
+// by MaxBlockSize so that malformed input cannot trigger huge allocations.
+func readCount(r io.Reader) (uint64, error) {
+	n, err := ReadVarInt(r)
+	if err != nil {
+		return 0, err
+	}
+	if n > MaxBlockSize {
+		return 0, ErrSizeTooLarge
+	}
+	return n, nil
+}
+
+func readUint32(r io.Reader) (uint32, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(buf[:]), nil
+}
+
+func writeUint32(w io.Writer, v uint32) error {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	_, err := w.Write(buf[:])
+	return err
+}
+
+func readUint64(r io.Reader) (uint64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buf[:]), nil
+}
+
+func writeUint64(w io.Writer, v uint64) error {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	_, err := w.Write(buf[:])
+	return err
+}
+
+func readHash(r io.Reader, h *Hash) error {
+	_, err := io.ReadFull(r, h[:])
+	return err
+}
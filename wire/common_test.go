@@ -0,0 +1,81 @@
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestVarInt(t *testing.T) {
+	tests := []struct {
+		value   uint64
+		encoded []byte
+	}{
+		{0, []byte{0x00}},
+		{0xfc, []byte{0xfc}},
+		{0xfd, []byte{0xfd, 0xfd, 0x00}},
+		{0xffff, []byte{0xfd, 0xff, 0xff}},
+		{0x10000, []byte{0xfe, 0x00, 0x00, 0x01, 0x00}},
+		{0xffffffff, []byte{0xfe, 0xff, 0xff, 0xff, 0xff}},
+		{0x100000000, []byte{0xff, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00}},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := WriteVarInt(&buf, tt.value); err != nil {
+			t.Fatalf("WriteVarInt(%d) error = %v", tt.value, err)
+		}
+		if !bytes.Equal(buf.Bytes(), tt.encoded) {
+			t.Errorf("WriteVarInt(%d) = %x, expected %x", tt.value, buf.Bytes(), tt.encoded)
+		}
+		if VarIntSerializeSize(tt.value) != len(tt.encoded) {
+			t.Errorf("VarIntSerializeSize(%d) = %d, expected %d", tt.value, VarIntSerializeSize(tt.value), len(tt.encoded))
+		}
+		got, err := ReadVarInt(bytes.NewReader(tt.encoded))
+		if err != nil {
+			t.Fatalf("ReadVarInt(%x) error = %v", tt.encoded, err)
+		}
+		if got != tt.value {
+			t.Errorf("ReadVarInt(%x) = %d, expected %d", tt.encoded, got, tt.value)
+		}
+	}
+}
+
+func TestVarIntNonCanonical(t *testing.T) {
+	tests := [][]byte{
+		{0xfd, 0xfc, 0x00},
+		{0xfe, 0xff, 0xff, 0x00, 0x00},
+		{0xff, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00},
+	}
+	for _, encoded := range tests {
+		if _, err := ReadVarInt(bytes.NewReader(encoded)); !errors.Is(err, ErrNonCanonicalVarInt) {
+			t.Errorf("ReadVarInt(%x) error = %v, expected ErrNonCanonicalVarInt", encoded, err)
+		}
+	}
+}
+
+func TestReadVarBytesTooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	_ = WriteVarInt(&buf, MaxBlockSize+1)
+	if _, err := ReadVarBytes(&buf); !errors.Is(err, ErrSizeTooLarge) {
+		t.Errorf("Expected ErrSizeTooLarge, got %v", err)
+	}
+}
+
+func TestHashString(t *testing.T) {
+	s := "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"
+	h, err := NewHashFromStr(s)
+	if err != nil {
+		t.Fatalf("NewHashFromStr() error = %v", err)
+	}
+	if h[0] != 0x6f || h[31] != 0x00 {
+		t.Errorf("Expected internal byte order to be reversed, got %x", h[:])
+	}
+	if h.String() != s {
+		t.Errorf("Expected %s, got %s", s, h.String())
+	}
+
+	if _, err := NewHashFromStr("abcd"); err == nil {
+		t.Error("Expected error for short hash string")
+	}
+}
@@ -0,0 +1,271 @@
+package wire
+
+import (
+	"bytes"
+	"io"
+	"time"
+)
+
+// BlockHeaderSize is the size in bytes of a serialized block header.
+const BlockHeaderSize = 80
+
+// BlockHeader is the 80-byte header of a block.
+type BlockHeader struct {
+	Version    int32
+	PrevBlock  Hash
+	MerkleRoot Hash
+	Timestamp  uint32
+	Bits       uint32
+	Nonce      uint32
+}
+
+// NewBlockHeaderFromBytes decodes a block header from its 80-byte serialization.
+//
+// Returns an error if the data is malformed or has trailing bytes.
+func NewBlockHeaderFromBytes(b []byte) (*BlockHeader, error) {
+	r := bytes.NewReader(b)
+	var h BlockHeader
+	if err := h.Deserialize(r); err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, ErrTrailingData
+	}
+	return &h, nil
+}
+
+// Time returns the block timestamp as a time.Time.
+func (h *BlockHeader) Time() time.Time {
+	return time.Unix(int64(h.Timestamp), 0)
+}
+
+// BlockHash returns the double-SHA256 hash of the serialized header.
+func (h *BlockHeader) BlockHash() Hash {
+	var buf bytes.Buffer
+	buf.Grow(BlockHeaderSize)
+	_ = h.Serialize(&buf)
+	return DoubleHashH(buf.Bytes())
+}
+
+// Bytes returns the 80-byte serialization of the header.
+func (h *BlockHeader) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(BlockHeaderSize)
+	if err := h.Serialize(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Serialize encodes the header to w.
+func (h *BlockHeader) Serialize(w io.Writer) error {
+	if err := writeUint32(w, uint32(h.Version)); err != nil {
+		return err
+	}
+	if _, err := w.Write(h.PrevBlock[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(h.MerkleRoot[:]); err != nil {
+		return err
+	}
+	if err := writeUint32(w, h.Timestamp); err != nil {
+		return err
+	}
+	if err := writeUint32(w, h.Bits); err != nil {
+		return err
+	}
+	return writeUint32(w, h.Nonce)
+}
+
+// Deserialize decodes a header from r.
+func (h *BlockHeader) Deserialize(r io.Reader) error {
+	var buf [BlockHeaderSize]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return err
+	}
+	br := bytes.NewReader(buf[:])
+	version, _ := readUint32(br)
+	h.Version = int32(version)
+	_ = readHash(br, &h.PrevBlock)
+	_ = readHash(br, &h.MerkleRoot)
+	h.Timestamp, _ = readUint32(br)
+	h.Bits, _ = readUint32(br)
+	h.Nonce, _ = readUint32(br)
+	return nil
+}
+
+// MsgBlock is a Bitcoin block.
+type MsgBlock struct {
+	Header       BlockHeader
+	Transactions []*MsgTx
+}
+
+// NewMsgBlock returns a new block with the given header and no transactions.
+func NewMsgBlock(header *BlockHeader) *MsgBlock {
+	return &MsgBlock{Header: *header}
+}
+
+// NewMsgBlockFromBytes decodes a block from its consensus serialization.
+//
+// Returns an error if the data is malformed or has trailing bytes.
+func NewMsgBlockFromBytes(b []byte) (*MsgBlock, error) {
+	r := bytes.NewReader(b)
+	var block MsgBlock
+	if err := block.Deserialize(r); err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, ErrTrailingData
+	}
+	return &block, nil
+}
+
+// AddTransaction appends a transaction to the block.
+//
+// The merkle root in the header is not updated; call UpdateMerkleRoot when done.
+func (msg *MsgBlock) AddTransaction(tx *MsgTx) {
+	msg.Transactions = append(msg.Transactions, tx)
+}
+
+// BlockHash returns the hash of the block header.
+func (msg *MsgBlock) BlockHash() Hash {
+	return msg.Header.BlockHash()
+}
+
+// MerkleRoot computes the merkle root of the block's transaction ids.
+func (msg *MsgBlock) MerkleRoot() Hash {
+	hashes := make([]Hash, len(msg.Transactions))
+	for i, tx := range msg.Transactions {
+		hashes[i] = tx.TxHash()
+	}
+	return merkleRoot(hashes)
+}
+
+// WitnessMerkleRoot computes the BIP141 witness merkle root of the block, in which
+// the coinbase wtxid is replaced by zero.
+func (msg *MsgBlock) WitnessMerkleRoot() Hash {
+	hashes := make([]Hash, len(msg.Transactions))
+	for i, tx := range msg.Transactions {
+		if i == 0 {
+			continue
+		}
+		hashes[i] = tx.WitnessHash()
+	}
+	return merkleRoot(hashes)
+}
+
+// UpdateMerkleRoot recomputes the merkle root and stores it in the header.
+func (msg *MsgBlock) UpdateMerkleRoot() {
+	msg.Header.MerkleRoot = msg.MerkleRoot()
+}
+
+// SerializeSize returns the number of bytes of the full serialization, including witness data.
+func (msg *MsgBlock) SerializeSize() int {
+	n := BlockHeaderSize + VarIntSerializeSize(uint64(len(msg.Transactions)))
+	for _, tx := range msg.Transactions {
+		n += tx.SerializeSize()
+	}
+	return n
+}
+
+// SerializeSizeStripped returns the number of bytes of the serialization without witness data.
+func (msg *MsgBlock) SerializeSizeStripped() int {
+	n := BlockHeaderSize + VarIntSerializeSize(uint64(len(msg.Transactions)))
+	for _, tx := range msg.Transactions {
+		n += tx.SerializeSizeStripped()
+	}
+	return n
+}
+
+// Weight returns the BIP141 weight of the block.
+func (msg *MsgBlock) Weight() int64 {
+	return int64(msg.SerializeSizeStripped()*(WitnessScaleFactor-1) + msg.SerializeSize())
+}
+
+// Bytes returns the full consensus serialization of the block.
+func (msg *MsgBlock) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(msg.SerializeSize())
+	if err := msg.Serialize(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Serialize encodes the block to w, including witness data.
+func (msg *MsgBlock) Serialize(w io.Writer) error {
+	if err := msg.Header.Serialize(w); err != nil {
+		return err
+	}
+	if err := WriteVarInt(w, uint64(len(msg.Transactions))); err != nil {
+		return err
+	}
+	for _, tx := range msg.Transactions {
+		if err := tx.Serialize(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SerializeNoWitness encodes the block to w, omitting witness data.
+func (msg *MsgBlock) SerializeNoWitness(w io.Writer) error {
+	if err := msg.Header.Serialize(w); err != nil {
+		return err
+	}
+	if err := WriteVarInt(w, uint64(len(msg.Transactions))); err != nil {
+		return err
+	}
+	for _, tx := range msg.Transactions {
+		if err := tx.SerializeNoWitness(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deserialize decodes a block from r.
+func (msg *MsgBlock) Deserialize(r io.Reader) error {
+	var header BlockHeader
+	if err := header.Deserialize(r); err != nil {
+		return err
+	}
+	count, err := readCount(r)
+	if err != nil {
+		return err
+	}
+	txs := make([]*MsgTx, 0, min(count, 4096))
+	for range count {
+		var tx MsgTx
+		if err := tx.Deserialize(r); err != nil {
+			return err
+		}
+		txs = append(txs, &tx)
+	}
+	msg.Header = header
+	msg.Transactions = txs
+	return nil
+}
+
+// merkleRoot computes the merkle root of hashes, duplicating the last hash of
+// each level with an odd number of entries as Bitcoin Core does.
+func merkleRoot(hashes []Hash) Hash {
+	if len(hashes) == 0 {
+		return Hash{}
+	}
+	level := append([]Hash(nil), hashes...)
+	var buf [HashSize * 2]byte
+	for len(level) > 1 {
+		if len(level)%2 != 0 {
+			level = append(level, level[len(level)-1])
+		}
+		next := level[:0]
+		for i := 0; i < len(level); i += 2 {
+			copy(buf[:HashSize], level[i][:])
+			copy(buf[HashSize:], level[i+1][:])
+			next = append(next, DoubleHashH(buf[:]))
+		}
+		level = next
+	}
+	return level[0]
+}
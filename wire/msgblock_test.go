@@ -0,0 +1,119 @@
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// genesisHeaderHex is the serialized header of the mainnet genesis block
+const genesisHeaderHex = "0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c"
+
+func TestBlockHeader(t *testing.T) {
+	raw := decodeHex(t, genesisHeaderHex)
+	header, err := NewBlockHeaderFromBytes(raw)
+	if err != nil {
+		t.Fatalf("NewBlockHeaderFromBytes() error = %v", err)
+	}
+
+	if header.Version != 1 {
+		t.Errorf("Expected version 1, got %d", header.Version)
+	}
+	if header.PrevBlock != (Hash{}) {
+		t.Errorf("Expected zero previous block hash, got %s", header.PrevBlock)
+	}
+	if header.Timestamp != 1231006505 || header.Bits != 0x1d00ffff || header.Nonce != 2083236893 {
+		t.Errorf("Unexpected header fields: timestamp %d, bits %#x, nonce %d", header.Timestamp, header.Bits, header.Nonce)
+	}
+
+	expectedHash := "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"
+	if header.BlockHash().String() != expectedHash {
+		t.Errorf("Expected hash %s, got %s", expectedHash, header.BlockHash())
+	}
+
+	encoded, err := header.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	if !bytes.Equal(encoded, raw) {
+		t.Errorf("Round trip mismatch:\n got %x\nwant %x", encoded, raw)
+	}
+
+	if _, err := NewBlockHeaderFromBytes(raw[:79]); err == nil {
+		t.Error("Expected error for truncated header")
+	}
+	if _, err := NewBlockHeaderFromBytes(append(bytes.Clone(raw), 0x00)); !errors.Is(err, ErrTrailingData) {
+		t.Errorf("Expected ErrTrailingData, got %v", err)
+	}
+}
+
+func TestMsgBlock(t *testing.T) {
+	raw := decodeHex(t, genesisHeaderHex+"01"+genesisCoinbaseTxHex)
+	block, err := NewMsgBlockFromBytes(raw)
+	if err != nil {
+		t.Fatalf("NewMsgBlockFromBytes() error = %v", err)
+	}
+
+	if len(block.Transactions) != 1 {
+		t.Fatalf("Expected 1 transaction, got %d", len(block.Transactions))
+	}
+	if block.MerkleRoot() != block.Header.MerkleRoot {
+		t.Errorf("Expected merkle root %s, got %s", block.Header.MerkleRoot, block.MerkleRoot())
+	}
+	if block.SerializeSize() != len(raw) || block.Weight() != int64(4*len(raw)) {
+		t.Errorf("Expected size %d and weight %d, got %d and %d", len(raw), 4*len(raw), block.SerializeSize(), block.Weight())
+	}
+
+	encoded, err := block.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	if !bytes.Equal(encoded, raw) {
+		t.Error("Round trip mismatch")
+	}
+
+	// Adding a transaction changes the merkle root until it is recomputed
+	segwitTx, err := NewMsgTxFromBytes(decodeHex(t, segwitCoinbaseTxHex))
+	if err != nil {
+		t.Fatalf("NewMsgTxFromBytes() error = %v", err)
+	}
+	block.AddTransaction(segwitTx)
+	block.UpdateMerkleRoot()
+
+	hashes := []Hash{block.Transactions[0].TxHash(), segwitTx.TxHash()}
+	var pair [HashSize * 2]byte
+	copy(pair[:HashSize], hashes[0][:])
+	copy(pair[HashSize:], hashes[1][:])
+	if block.Header.MerkleRoot != DoubleHashH(pair[:]) {
+		t.Error("Expected merkle root of two transactions to be the hash of their txids")
+	}
+
+	// The witness merkle root replaces the coinbase wtxid with zero
+	copy(pair[:HashSize], make([]byte, HashSize))
+	copy(pair[HashSize:], func() []byte { h := segwitTx.WitnessHash(); return h[:] }())
+	if block.WitnessMerkleRoot() != DoubleHashH(pair[:]) {
+		t.Error("Unexpected witness merkle root")
+	}
+
+	if block.SerializeSize() <= block.SerializeSizeStripped() {
+		t.Error("Expected witness data to increase the serialized size")
+	}
+}
+
+func TestMerkleRootOddCount(t *testing.T) {
+	a, b, c := Hash{1}, Hash{2}, Hash{3}
+	var pair [HashSize * 2]byte
+	hashPair := func(x, y Hash) Hash {
+		copy(pair[:HashSize], x[:])
+		copy(pair[HashSize:], y[:])
+		return DoubleHashH(pair[:])
+	}
+
+	expected := hashPair(hashPair(a, b), hashPair(c, c))
+	if got := merkleRoot([]Hash{a, b, c}); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+	if got := merkleRoot([]Hash{a}); got != a {
+		t.Errorf("Expected single hash to be its own root, got %s", got)
+	}
+}
@@ -0,0 +1,449 @@
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// witnessMarker and witnessFlag precede the inputs of a transaction that
+	// is serialized in the BIP144 extended format.
+	witnessMarker = 0x00
+	witnessFlag   = 0x01
+
+	// WitnessScaleFactor is the weight multiplier applied to non-witness data.
+	WitnessScaleFactor = 4
+
+	// MaxTxInSequenceNum is the default and final sequence number of an input.
+	MaxTxInSequenceNum uint32 = 0xffffffff
+
+	// MaxPrevOutIndex is the output index used by the null outpoint of a coinbase input.
+	MaxPrevOutIndex uint32 = 0xffffffff
+)
+
+var (
+	ErrSuperfluousWitness = errors.New("superfluous witness record")
+	ErrUnknownTxFlags     = errors.New("unknown transaction optional data")
+)
+
+// OutPoint references a specific output of a previous transaction.
+type OutPoint struct {
+	Hash  Hash
+	Index uint32
+}
+
+// NewOutPoint returns a new outpoint referencing output index of the transaction with the given txid.
+func NewOutPoint(hash Hash, index uint32) *OutPoint {
+	return &OutPoint{Hash: hash, Index: index}
+}
+
+// IsNull reports whether the outpoint is the null outpoint used by coinbase inputs.
+func (o OutPoint) IsNull() bool {
+	return o.Index == MaxPrevOutIndex && o.Hash == Hash{}
+}
+
+// String returns the outpoint in "txid:index" form.
+func (o OutPoint) String() string {
+	return fmt.Sprintf("%s:%d", o.Hash, o.Index)
+}
+
+// TxWitness is the witness stack of a transaction input.
+type TxWitness [][]byte
+
+// SerializeSize returns the number of bytes needed to serialize the witness stack.
+func (w TxWitness) SerializeSize() int {
+	n := VarIntSerializeSize(uint64(len(w)))
+	for _, item := range w {
+		n += VarIntSerializeSize(uint64(len(item))) + len(item)
+	}
+	return n
+}
+
+// TxIn is a transaction input.
+type TxIn struct {
+	PreviousOutPoint OutPoint
+	SignatureScript  []byte
+	Witness          TxWitness
+	Sequence         uint32
+}
+
+// NewTxIn returns a new input spending prevOut with the given signature script and
+// witness, using the final sequence number.
+func NewTxIn(prevOut *OutPoint, signatureScript []byte, witness [][]byte) *TxIn {
+	return &TxIn{
+		PreviousOutPoint: *prevOut,
+		SignatureScript:  signatureScript,
+		Witness:          witness,
+		Sequence:         MaxTxInSequenceNum,
+	}
+}
+
+// SerializeSize returns the number of bytes needed to serialize the input, excluding its witness.
+func (t *TxIn) SerializeSize() int {
+	// Outpoint hash, outpoint index, sequence
+	return HashSize + 4 + 4 + VarIntSerializeSize(uint64(len(t.SignatureScript))) + len(t.SignatureScript)
+}
+
+// TxOut is a transaction output.
+type TxOut struct {
+	Value    int64
+	PkScript []byte
+}
+
+// NewTxOut returns a new output paying value satoshis to pkScript.
+func NewTxOut(value int64, pkScript []byte) *TxOut {
+	return &TxOut{Value: value, PkScript: pkScript}
+}
+
+// SerializeSize returns the number of bytes needed to serialize the output.
+func (t *TxOut) SerializeSize() int {
+	return 8 + VarIntSerializeSize(uint64(len(t.PkScript))) + len(t.PkScript)
+}
+
+// Serialize encodes the output to w in consensus format.
+func (t *TxOut) Serialize(w io.Writer) error {
+	if err := writeUint64(w, uint64(t.Value)); err != nil {
+		return err
+	}
+	return WriteVarBytes(w, t.PkScript)
+}
+
+// Deserialize decodes an output from r in consensus format.
+func (t *TxOut) Deserialize(r io.Reader) error {
+	value, err := readUint64(r)
+	if err != nil {
+		return err
+	}
+	pkScript, err := ReadVarBytes(r)
+	if err != nil {
+		return err
+	}
+	t.Value = int64(value)
+	t.PkScript = pkScript
+	return nil
+}
+
+// MsgTx is a Bitcoin transaction.
+type MsgTx struct {
+	Version  uint32
+	TxIn     []*TxIn
+	TxOut    []*TxOut
+	LockTime uint32
+}
+
+// NewMsgTx returns a new empty transaction with the given version.
+func NewMsgTx(version uint32) *MsgTx {
+	return &MsgTx{Version: version}
+}
+
+// NewMsgTxFromBytes decodes a transaction from its consensus serialization,
+// accepting both the legacy and the BIP144 witness format.
+//
+// Returns an error if the data is malformed or has trailing bytes.
+func NewMsgTxFromBytes(b []byte) (*MsgTx, error) {
+	r := bytes.NewReader(b)
+	var tx MsgTx
+	if err := tx.Deserialize(r); err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, ErrTrailingData
+	}
+	return &tx, nil
+}
+
+// AddTxIn appends an input to the transaction.
+func (msg *MsgTx) AddTxIn(ti *TxIn) {
+	msg.TxIn = append(msg.TxIn, ti)
+}
+
+// AddTxOut appends an output to the transaction.
+func (msg *MsgTx) AddTxOut(to *TxOut) {
+	msg.TxOut = append(msg.TxOut, to)
+}
+
+// HasWitness reports whether any input of the transaction carries witness data.
+func (msg *MsgTx) HasWitness() bool {
+	for _, ti := range msg.TxIn {
+		if len(ti.Witness) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCoinbase reports whether the transaction is a coinbase, i.e. has a single input
+// spending the null outpoint.
+func (msg *MsgTx) IsCoinbase() bool {
+	return len(msg.TxIn) == 1 && msg.TxIn[0].PreviousOutPoint.IsNull()
+}
+
+// TxHash returns the txid, the double-SHA256 of the serialization without witness data.
+func (msg *MsgTx) TxHash() Hash {
+	var buf bytes.Buffer
+	buf.Grow(msg.SerializeSizeStripped())
+	_ = msg.SerializeNoWitness(&buf)
+	return DoubleHashH(buf.Bytes())
+}
+
+// WitnessHash returns the wtxid, the double-SHA256 of the full serialization.
+//
+// For transactions without witness data this equals TxHash.
+func (msg *MsgTx) WitnessHash() Hash {
+	if !msg.HasWitness() {
+		return msg.TxHash()
+	}
+	var buf bytes.Buffer
+	buf.Grow(msg.SerializeSize())
+	_ = msg.Serialize(&buf)
+	return DoubleHashH(buf.Bytes())
+}
+
+// Copy returns a deep copy of the transaction.
+func (msg *MsgTx) Copy() *MsgTx {
+	tx := &MsgTx{
+		Version:  msg.Version,
+		TxIn:     make([]*TxIn, 0, len(msg.TxIn)),
+		TxOut:    make([]*TxOut, 0, len(msg.TxOut)),
+		LockTime: msg.LockTime,
+	}
+	for _, ti := range msg.TxIn {
+		in := &TxIn{
+			PreviousOutPoint: ti.PreviousOutPoint,
+			SignatureScript:  bytes.Clone(ti.SignatureScript),
+			Sequence:         ti.Sequence,
+		}
+		if ti.Witness != nil {
+			in.Witness = make(TxWitness, len(ti.Witness))
+			for i, item := range ti.Witness {
+				in.Witness[i] = bytes.Clone(item)
+			}
+		}
+		tx.TxIn = append(tx.TxIn, in)
+	}
+	for _, to := range msg.TxOut {
+		tx.TxOut = append(tx.TxOut, &TxOut{Value: to.Value, PkScript: bytes.Clone(to.PkScript)})
+	}
+	return tx
+}
+
+// SerializeSize returns the number of bytes of the full serialization, including witness data.
+func (msg *MsgTx) SerializeSize() int {
+	n := msg.SerializeSizeStripped()
+	if msg.HasWitness() {
+		// Marker and flag bytes
+		n += 2
+		for _, ti := range msg.TxIn {
+			n += ti.Witness.SerializeSize()
+		}
+	}
+	return n
+}
+
+// SerializeSizeStripped returns the number of bytes of the serialization without witness data.
+func (msg *MsgTx) SerializeSizeStripped() int {
+	// Version and lock time
+	n := 8 + VarIntSerializeSize(uint64(len(msg.TxIn))) + VarIntSerializeSize(uint64(len(msg.TxOut)))
+	for _, ti := range msg.TxIn {
+		n += ti.SerializeSize()
+	}
+	for _, to := range msg.TxOut {
+		n += to.SerializeSize()
+	}
+	return n
+}
+
+// Weight returns the BIP141 weight of the transaction.
+func (msg *MsgTx) Weight() int64 {
+	return int64(msg.SerializeSizeStripped()*(WitnessScaleFactor-1) + msg.SerializeSize())
+}
+
+// Bytes returns the full consensus serialization of the transaction.
+func (msg *MsgTx) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(msg.SerializeSize())
+	if err := msg.Serialize(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Serialize encodes the transaction to w, using the BIP144 extended format if any
+// input carries witness data.
+func (msg *MsgTx) Serialize(w io.Writer) error {
+	return msg.encode(w, msg.HasWitness())
+}
+
+// SerializeNoWitness encodes the transaction to w in the legacy format, omitting witness data.
+func (msg *MsgTx) SerializeNoWitness(w io.Writer) error {
+	return msg.encode(w, false)
+}
+
+func (msg *MsgTx) encode(w io.Writer, withWitness bool) error {
+	if err := writeUint32(w, msg.Version); err != nil {
+		return err
+	}
+	if withWitness {
+		if _, err := w.Write([]byte{witnessMarker, witnessFlag}); err != nil {
+			return err
+		}
+	}
+	if err := WriteVarInt(w, uint64(len(msg.TxIn))); err != nil {
+		return err
+	}
+	for _, ti := range msg.TxIn {
+		if _, err := w.Write(ti.PreviousOutPoint.Hash[:]); err != nil {
+			return err
+		}
+		if err := writeUint32(w, ti.PreviousOutPoint.Index); err != nil {
+			return err
+		}
+		if err := WriteVarBytes(w, ti.SignatureScript); err != nil {
+			return err
+		}
+		if err := writeUint32(w, ti.Sequence); err != nil {
+			return err
+		}
+	}
+	if err := WriteVarInt(w, uint64(len(msg.TxOut))); err != nil {
+		return err
+	}
+	for _, to := range msg.TxOut {
+		if err := to.Serialize(w); err != nil {
+			return err
+		}
+	}
+	if withWitness {
+		for _, ti := range msg.TxIn {
+			if err := WriteVarInt(w, uint64(len(ti.Witness))); err != nil {
+				return err
+			}
+			for _, item := range ti.Witness {
+				if err := WriteVarBytes(w, item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return writeUint32(w, msg.LockTime)
+}
+
+// Deserialize decodes a transaction from r, accepting both the legacy and the
+// BIP144 witness format.
+//
+// Like Bitcoin Core, an empty input vector is interpreted as the BIP144 marker,
+// and a witness flag without any witness data is rejected.
+func (msg *MsgTx) Deserialize(r io.Reader) error {
+	version, err := readUint32(r)
+	if err != nil {
+		return err
+	}
+
+	var flags byte
+	txIns, err := readTxIns(r)
+	if err != nil {
+		return err
+	}
+	var txOuts []*TxOut
+	if len(txIns) == 0 {
+		var flagBuf [1]byte
+		if _, err := io.ReadFull(r, flagBuf[:]); err != nil {
+			return err
+		}
+		flags = flagBuf[0]
+		if flags != 0 {
+			if txIns, err = readTxIns(r); err != nil {
+				return err
+			}
+			if txOuts, err = readTxOuts(r); err != nil {
+				return err
+			}
+		}
+	} else {
+		if txOuts, err = readTxOuts(r); err != nil {
+			return err
+		}
+	}
+
+	if flags&witnessFlag != 0 {
+		flags ^= witnessFlag
+		hasWitness := false
+		for _, ti := range txIns {
+			count, err := readCount(r)
+			if err != nil {
+				return err
+			}
+			ti.Witness = make(TxWitness, 0, min(count, 64))
+			for range count {
+				item, err := ReadVarBytes(r)
+				if err != nil {
+					return err
+				}
+				ti.Witness = append(ti.Witness, item)
+			}
+			if count != 0 {
+				hasWitness = true
+			}
+		}
+		if !hasWitness {
+			return ErrSuperfluousWitness
+		}
+	}
+	if flags != 0 {
+		return ErrUnknownTxFlags
+	}
+
+	lockTime, err := readUint32(r)
+	if err != nil {
+		return err
+	}
+
+	msg.Version = version
+	msg.TxIn = txIns
+	msg.TxOut = txOuts
+	msg.LockTime = lockTime
+	return nil
+}
+
+func readTxIns(r io.Reader) ([]*TxIn, error) {
+	count, err := readCount(r)
+	if err != nil {
+		return nil, err
+	}
+	txIns := make([]*TxIn, 0, min(count, 1024))
+	for range count {
+		var ti TxIn
+		if err := readHash(r, &ti.PreviousOutPoint.Hash); err != nil {
+			return nil, err
+		}
+		if ti.PreviousOutPoint.Index, err = readUint32(r); err != nil {
+			return nil, err
+		}
+		if ti.SignatureScript, err = ReadVarBytes(r); err != nil {
+			return nil, err
+		}
+		if ti.Sequence, err = readUint32(r); err != nil {
+			return nil, err
+		}
+		txIns = append(txIns, &ti)
+	}
+	return txIns, nil
+}
+
+func readTxOuts(r io.Reader) ([]*TxOut, error) {
+	count, err := readCount(r)
+	if err != nil {
+		return nil, err
+	}
+	txOuts := make([]*TxOut, 0, min(count, 1024))
+	for range count {
+		var to TxOut
+		if err := to.Deserialize(r); err != nil {
+			return nil, err
+		}
+		txOuts = append(txOuts, &to)
+	}
+	return txOuts, nil
+}
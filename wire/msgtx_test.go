@@ -0,0 +1,204 @@
+package wire
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// genesisCoinbaseTxHex is the serialized coinbase transaction of the mainnet genesis block
+const genesisCoinbaseTxHex = "01000000010000000000000000000000000000000000000000000000000000000000000000ffffffff4d04ffff001d0104455468652054696d65732030332f4a616e2f32303039204368616e63656c6c6f72206f6e206272696e6b206f66207365636f6e64206261696c6f757420666f722062616e6b73ffffffff0100f2052a01000000434104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac00000000"
+
+// segwitCoinbaseTxHex is the serialized coinbase transaction of a regtest block, carrying a witness nonce
+const segwitCoinbaseTxHex = "020000000001010000000000000000000000000000000000000000000000000000000000000000ffffffff025200ffffffff0200f2052a010000001600141409745405c4e8310a875bcd602db6b9b3dc0cf90000000000000000266a24aa21a9ede2f61c3f71d1defd3fa999dfa36953755c690689799962b48bebd836974e8cf90120000000000000000000000000000000000000000000000000000000000000000000000000"
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("Failed to decode hex: %v", err)
+	}
+	return b
+}
+
+func TestMsgTxLegacy(t *testing.T) {
+	raw := decodeHex(t, genesisCoinbaseTxHex)
+	tx, err := NewMsgTxFromBytes(raw)
+	if err != nil {
+		t.Fatalf("NewMsgTxFromBytes() error = %v", err)
+	}
+
+	if tx.Version != 1 || tx.LockTime != 0 {
+		t.Errorf("Expected version 1 and lock time 0, got %d and %d", tx.Version, tx.LockTime)
+	}
+	if len(tx.TxIn) != 1 || len(tx.TxOut) != 1 {
+		t.Fatalf("Expected 1 input and 1 output, got %d and %d", len(tx.TxIn), len(tx.TxOut))
+	}
+	if !tx.IsCoinbase() {
+		t.Error("Expected transaction to be a coinbase")
+	}
+	if tx.HasWitness() {
+		t.Error("Expected transaction to have no witness")
+	}
+	if tx.TxOut[0].Value != 5_000_000_000 {
+		t.Errorf("Expected output value 5000000000, got %d", tx.TxOut[0].Value)
+	}
+
+	expectedTxid := "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"
+	if tx.TxHash().String() != expectedTxid {
+		t.Errorf("Expected txid %s, got %s", expectedTxid, tx.TxHash())
+	}
+	if tx.WitnessHash() != tx.TxHash() {
+		t.Error("Expected wtxid to equal txid for a transaction without witness")
+	}
+
+	if tx.SerializeSize() != len(raw) || tx.SerializeSizeStripped() != len(raw) {
+		t.Errorf("Expected serialize size %d, got %d (stripped %d)", len(raw), tx.SerializeSize(), tx.SerializeSizeStripped())
+	}
+	if tx.Weight() != int64(4*len(raw)) {
+		t.Errorf("Expected weight %d, got %d", 4*len(raw), tx.Weight())
+	}
+
+	encoded, err := tx.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	if !bytes.Equal(encoded, raw) {
+		t.Errorf("Round trip mismatch:\n got %x\nwant %x", encoded, raw)
+	}
+}
+
+func TestMsgTxWitness(t *testing.T) {
+	raw := decodeHex(t, segwitCoinbaseTxHex)
+	tx, err := NewMsgTxFromBytes(raw)
+	if err != nil {
+		t.Fatalf("NewMsgTxFromBytes() error = %v", err)
+	}
+
+	if !tx.HasWitness() {
+		t.Fatal("Expected transaction to have a witness")
+	}
+	if len(tx.TxIn[0].Witness) != 1 || !bytes.Equal(tx.TxIn[0].Witness[0], make([]byte, 32)) {
+		t.Errorf("Expected a single 32-byte zero witness item, got %x", tx.TxIn[0].Witness)
+	}
+	if !bytes.Equal(tx.TxIn[0].SignatureScript, []byte{0x52, 0x00}) {
+		t.Errorf("Expected signature script 5200, got %x", tx.TxIn[0].SignatureScript)
+	}
+
+	expectedTxid := "f3ac0618ad042336fbec1f88a4e965481b46cd3381a807591c78c75fdbae7d67"
+	if tx.TxHash().String() != expectedTxid {
+		t.Errorf("Expected txid %s, got %s", expectedTxid, tx.TxHash())
+	}
+	expectedWtxid := "9eb8796ec3c27865f48c961b3a26854efe2608a9c1764fe5f60483149f9ad225"
+	if tx.WitnessHash().String() != expectedWtxid {
+		t.Errorf("Expected wtxid %s, got %s", expectedWtxid, tx.WitnessHash())
+	}
+
+	if tx.SerializeSize() != 167 || tx.SerializeSizeStripped() != 131 {
+		t.Errorf("Expected sizes 167/131, got %d/%d", tx.SerializeSize(), tx.SerializeSizeStripped())
+	}
+	if tx.Weight() != 560 {
+		t.Errorf("Expected weight 560, got %d", tx.Weight())
+	}
+
+	encoded, err := tx.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	if !bytes.Equal(encoded, raw) {
+		t.Errorf("Round trip mismatch:\n got %x\nwant %x", encoded, raw)
+	}
+
+	var stripped bytes.Buffer
+	if err := tx.SerializeNoWitness(&stripped); err != nil {
+		t.Fatalf("SerializeNoWitness() error = %v", err)
+	}
+	if DoubleHashH(stripped.Bytes()) != tx.TxHash() {
+		t.Error("Expected stripped serialization to hash to the txid")
+	}
+}
+
+func TestMsgTxBuild(t *testing.T) {
+	prevHash, err := NewHashFromStr("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
+	if err != nil {
+		t.Fatalf("NewHashFromStr() error = %v", err)
+	}
+
+	tx := NewMsgTx(2)
+	tx.AddTxIn(NewTxIn(NewOutPoint(prevHash, 0), nil, [][]byte{{0x01, 0x02}, {}}))
+	tx.AddTxOut(NewTxOut(1000, []byte{0x51}))
+	tx.LockTime = 100
+
+	raw, err := tx.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	if raw[4] != witnessMarker || raw[5] != witnessFlag {
+		t.Errorf("Expected witness marker and flag, got %x", raw[4:6])
+	}
+
+	decoded, err := NewMsgTxFromBytes(raw)
+	if err != nil {
+		t.Fatalf("NewMsgTxFromBytes() error = %v", err)
+	}
+	if decoded.TxHash() != tx.TxHash() || decoded.WitnessHash() != tx.WitnessHash() {
+		t.Error("Expected decoded transaction hashes to match the original")
+	}
+	if decoded.TxIn[0].PreviousOutPoint != *NewOutPoint(prevHash, 0) {
+		t.Errorf("Expected outpoint %s, got %s", NewOutPoint(prevHash, 0), decoded.TxIn[0].PreviousOutPoint)
+	}
+	if decoded.TxIn[0].Sequence != MaxTxInSequenceNum {
+		t.Errorf("Expected final sequence, got %#x", decoded.TxIn[0].Sequence)
+	}
+
+	// Mutating a copy leaves the original untouched
+	copied := tx.Copy()
+	copied.TxIn[0].Witness[0][0] = 0xff
+	copied.TxOut[0].Value = 1
+	if tx.TxIn[0].Witness[0][0] != 0x01 || tx.TxOut[0].Value != 1000 {
+		t.Error("Expected Copy() to return a deep copy")
+	}
+}
+
+func TestMsgTxInvalid(t *testing.T) {
+	legacy := decodeHex(t, genesisCoinbaseTxHex)
+	segwit := decodeHex(t, segwitCoinbaseTxHex)
+
+	// Witness flag set but every witness stack is empty
+	superfluous := NewMsgTx(1)
+	superfluous.AddTxIn(NewTxIn(NewOutPoint(Hash{}, 0), nil, nil))
+	superfluous.AddTxOut(NewTxOut(0, nil))
+	var buf bytes.Buffer
+	if err := superfluous.encode(&buf, true); err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+
+	// Unknown optional data flag
+	unknownFlag := bytes.Clone(segwit)
+	unknownFlag[5] = 0x03
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{name: "empty data", data: []byte{}},
+		{name: "truncated", data: legacy[:len(legacy)-1]},
+		{name: "trailing data", data: append(bytes.Clone(legacy), 0x00), wantErr: ErrTrailingData},
+		{name: "superfluous witness", data: buf.Bytes(), wantErr: ErrSuperfluousWitness},
+		{name: "unknown flags", data: unknownFlag, wantErr: ErrUnknownTxFlags},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMsgTxFromBytes(tt.data)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}